@@ -7,6 +7,8 @@ import (
 	"github.com/SimonSchneider/goslu/date"
 	"github.com/SimonSchneider/goslu/static/shttp"
 	"github.com/SimonSchneider/pefigo/internal/model"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
 	"github.com/SimonSchneider/pefigo/pkg/ui"
 	"github.com/SimonSchneider/pefigo/pkg/uncertain"
 )
@@ -29,6 +31,8 @@ func (a *accountInputForm) FromForm(r *http.Request) error {
 		a.BudgetCategoryID = &budgetCategoryID
 	}
 	a.IsISK = r.FormValue("is_isk") == "on"
+	a.OwnerID = r.FormValue("owner_id")
+	a.Currency = r.FormValue("currency")
 	return nil
 }
 
@@ -54,6 +58,40 @@ func (s *specialDateInputForm) FromForm(r *http.Request) error {
 		return fmt.Errorf("parsing start date: %w", err)
 	}
 	s.Color = r.FormValue("color")
+	personAge, err := parsePersonAge(r, "person_id", "person_age")
+	if err != nil {
+		return err
+	}
+	s.PersonAge = personAge
+	return nil
+}
+
+// parsePersonAge parses a date relative to a person, which is nil when no
+// person is selected.
+func parsePersonAge(r *http.Request, personField, ageField string) (*model.PersonAge, error) {
+	personID := r.FormValue(personField)
+	if personID == "" {
+		return nil, nil
+	}
+	var age int64
+	if err := shttp.Parse(&age, ui.ParseInt64, r.FormValue(ageField), int64(0)); err != nil {
+		return nil, fmt.Errorf("parsing age: %w", err)
+	}
+	return &model.PersonAge{PersonID: personID, Age: age}, nil
+}
+
+type personInputForm struct {
+	model.Person
+}
+
+func (p *personInputForm) FromForm(r *http.Request) error {
+	p.ID = r.FormValue("id")
+	p.Name = r.FormValue("name")
+	if err := shttp.Parse(&p.BirthDate, date.ParseDate, r.FormValue("birth_date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing birth date: %w", err)
+	}
+	p.IsChild = r.FormValue("is_child") == "on"
+	p.TaxAccountID = r.FormValue("tax_account_id")
 	return nil
 }
 
@@ -65,9 +103,13 @@ func (a *accountGrowthModelInputForm) FromForm(r *http.Request) error {
 	a.ID = r.FormValue("id")
 	a.AccountID = r.FormValue("account_id")
 	a.Type = r.FormValue("type")
-	if a.Type != "fixed" && a.Type != "lognormal" {
+	if a.Type != "fixed" && a.Type != "lognormal" && a.Type != "variable" {
 		return fmt.Errorf("invalid growth model type: %s", a.Type)
 	}
+	a.RateModelID = r.FormValue("rate_model_id")
+	if err := shttp.Parse(&a.BindingYears, ui.ParseInt64, r.FormValue("binding_years"), int64(0)); err != nil {
+		return fmt.Errorf("parsing binding years: %w", err)
+	}
 	if err := shttp.Parse(&a.AnnualRate, ui.ParseUncertainValue, r.FormValue("annual_rate"), uncertain.NewFixed(0)); err != nil {
 		return fmt.Errorf("parsing annual rate: %w", err)
 	}
@@ -105,6 +147,123 @@ func (s *startupShareAccountInputForm) FromForm(r *http.Request) error {
 		return fmt.Errorf("parsing valuation discount factor: %w", err)
 	}
 	s.ValuationDiscountFactor = s.ValuationDiscountFactor / 100.0
+	if err := shttp.Parse(&s.MarginalIncomeTaxRate, shttp.ParseFloat, r.FormValue("marginal_income_tax_rate"), 50.0); err != nil {
+		return fmt.Errorf("parsing marginal income tax rate: %w", err)
+	}
+	s.MarginalIncomeTaxRate = s.MarginalIncomeTaxRate / 100.0
+	return nil
+}
+
+type pensionPayoutInputForm struct {
+	model.PensionPayout
+}
+
+func (p *pensionPayoutInputForm) FromForm(r *http.Request) error {
+	p.AccountID = r.FormValue("account_id")
+	p.ToAccountID = r.FormValue("to_account_id")
+	if err := shttp.Parse(&p.StartDate, date.ParseDate, r.FormValue("start_date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing start date: %w", err)
+	}
+	if birthDateStr := r.FormValue("birth_date"); birthDateStr != "" {
+		birthDate, err := date.ParseDate(birthDateStr)
+		if err != nil {
+			return fmt.Errorf("parsing birth date: %w", err)
+		}
+		p.BirthDate = &birthDate
+	}
+	if startAgeStr := r.FormValue("start_age"); startAgeStr != "" {
+		startAge, err := ui.ParseInt64(startAgeStr)
+		if err != nil {
+			return fmt.Errorf("parsing start age: %w", err)
+		}
+		p.StartAge = &startAge
+	}
+	if err := shttp.Parse(&p.PeriodYears, ui.ParseInt64, r.FormValue("period_years"), int64(0)); err != nil {
+		return fmt.Errorf("parsing period: %w", err)
+	}
+	if p.StartAge != nil && p.BirthDate == nil {
+		return fmt.Errorf("start age requires a birth date")
+	}
+	if p.StartAge == nil && p.StartDate.IsZero() {
+		return fmt.Errorf("either a start date or a start age is required")
+	}
+	p.Kommun = r.FormValue("kommun")
+	p.Forsamling = r.FormValue("forsamling")
+	p.ChurchMember = r.FormValue("church_member") == "on"
+	return nil
+}
+
+type interestDeductionInputForm struct {
+	model.InterestDeduction
+}
+
+func (d *interestDeductionInputForm) FromForm(r *http.Request) error {
+	d.AccountID = r.FormValue("account_id")
+	d.ToAccountID = r.FormValue("to_account_id")
+	if d.ToAccountID == "" {
+		return fmt.Errorf("a refund account is required")
+	}
+	d.SettlementDate = date.Cron(r.FormValue("settlement_date"))
+	if err := shttp.Parse(&d.Borrowers, ui.ParseInt64, r.FormValue("borrowers"), int64(1)); err != nil {
+		return fmt.Errorf("parsing borrowers: %w", err)
+	}
+	return nil
+}
+
+type amortizationRequirementInputForm struct {
+	model.AmortizationRequirement
+}
+
+func (a *amortizationRequirementInputForm) FromForm(r *http.Request) error {
+	a.AccountID = r.FormValue("account_id")
+	a.PropertyAccountID = r.FormValue("property_account_id")
+	a.FromAccountID = r.FormValue("from_account_id")
+	if a.FromAccountID == "" {
+		return fmt.Errorf("an account to pay from is required")
+	}
+	a.Recurrence = date.Cron(r.FormValue("recurrence"))
+	if err := shttp.Parse(&a.ValuationDate, date.ParseDate, r.FormValue("valuation_date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing valuation date: %w", err)
+	}
+	if err := shttp.Parse(&a.RevaluationYears, ui.ParseInt64, r.FormValue("revaluation_years"), int64(swe.DefaultRevaluationYears)); err != nil {
+		return fmt.Errorf("parsing revaluation years: %w", err)
+	}
+	return nil
+}
+
+type csnLoanInputForm struct {
+	model.CSNLoan
+}
+
+func (l *csnLoanInputForm) FromForm(r *http.Request) error {
+	l.AccountID = r.FormValue("account_id")
+	l.FromAccountID = r.FormValue("from_account_id")
+	if l.FromAccountID == "" {
+		return fmt.Errorf("an account to pay from is required")
+	}
+	l.SalaryID = r.FormValue("salary_id")
+	l.Recurrence = date.Cron(r.FormValue("recurrence"))
+	l.Reduction = r.FormValue("reduction") == "on"
+	if err := shttp.Parse(&l.BirthDate, date.ParseDate, r.FormValue("birth_date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing birth date: %w", err)
+	}
+	if err := shttp.Parse(&l.FinalYear, ui.ParseInt64, r.FormValue("final_year"), int64(0)); err != nil {
+		return fmt.Errorf("parsing final year: %w", err)
+	}
+	return nil
+}
+
+type accountOwnerInputForm struct {
+	model.AccountOwner
+}
+
+func (o *accountOwnerInputForm) FromForm(r *http.Request) error {
+	o.AccountID = r.FormValue("account_id")
+	o.PersonID = r.FormValue("person_id")
+	if err := shttp.Parse(&o.Share, shttp.ParseFloat, r.FormValue("share"), 0.0); err != nil {
+		return fmt.Errorf("parsing share: %w", err)
+	}
+	o.Share = o.Share / 100.0
 	return nil
 }
 
@@ -127,6 +286,13 @@ func (i *investmentRoundInputForm) FromForm(r *http.Request) error {
 	if err := shttp.Parse(&i.Investment, shttp.ParseFloat, r.FormValue("investment"), 0.0); err != nil {
 		return fmt.Errorf("parsing investment: %w", err)
 	}
+	if err := shttp.Parse(&i.LiquidationPreference, shttp.ParseFloat, r.FormValue("liquidation_preference"), 0.0); err != nil {
+		return fmt.Errorf("parsing liquidation preference: %w", err)
+	}
+	if err := shttp.Parse(&i.Seniority, ui.ParseInt64, r.FormValue("seniority"), int64(0)); err != nil {
+		return fmt.Errorf("parsing seniority: %w", err)
+	}
+	i.Participating = r.FormValue("participating") == "on"
 	return nil
 }
 
@@ -169,6 +335,62 @@ func (o *startupShareOptionInputForm) FromForm(r *http.Request) error {
 	if err := shttp.Parse(&o.EndDate, date.ParseDate, r.FormValue("end_date"), date.Date(0)); err != nil {
 		return fmt.Errorf("parsing end date: %w", err)
 	}
+	if err := shttp.Parse(&o.CliffMonths, ui.ParseInt64, r.FormValue("cliff_months"), int64(0)); err != nil {
+		return fmt.Errorf("parsing cliff months: %w", err)
+	}
+	if err := shttp.Parse(&o.VestingMonths, ui.ParseInt64, r.FormValue("vesting_months"), int64(0)); err != nil {
+		return fmt.Errorf("parsing vesting months: %w", err)
+	}
+	if err := shttp.Parse(&o.VestingIntervalMonths, ui.ParseInt64, r.FormValue("vesting_interval_months"), int64(1)); err != nil {
+		return fmt.Errorf("parsing vesting interval: %w", err)
+	}
+	o.ExercisePolicy = finance.StartupOptionExercisePolicy(r.FormValue("exercise_policy"))
+	o.TaxTreatment = finance.StartupGrantTax(r.FormValue("tax_treatment"))
+	o.AccelerateOnExit = r.FormValue("accelerate_on_exit") == "on"
+	return nil
+}
+
+type projectedInvestmentRoundInputForm struct {
+	model.ProjectedInvestmentRoundInput
+}
+
+func (p *projectedInvestmentRoundInputForm) FromForm(r *http.Request) error {
+	p.ID = r.FormValue("id")
+	p.AccountID = r.FormValue("account_id")
+	if err := shttp.Parse(&p.Date, date.ParseDate, r.FormValue("date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing date: %w", err)
+	}
+	if err := shttp.Parse(&p.Probability, shttp.ParseFloat, r.FormValue("probability"), 100.0); err != nil {
+		return fmt.Errorf("parsing probability: %w", err)
+	}
+	p.Probability = p.Probability / 100.0
+	if err := shttp.Parse(&p.PreMoneyValuation, ui.ParseUncertainValue, r.FormValue("pre_money_valuation"), uncertain.NewFixed(0)); err != nil {
+		return fmt.Errorf("parsing pre-money valuation: %w", err)
+	}
+	if err := shttp.Parse(&p.Investment, ui.ParseUncertainValue, r.FormValue("investment"), uncertain.NewFixed(0)); err != nil {
+		return fmt.Errorf("parsing investment: %w", err)
+	}
+	return nil
+}
+
+type startupExitInputForm struct {
+	model.StartupExitInput
+}
+
+func (e *startupExitInputForm) FromForm(r *http.Request) error {
+	e.AccountID = r.FormValue("account_id")
+	e.PayoutAccountID = r.FormValue("payout_account_id")
+	e.Kind = finance.StartupExitKind(r.FormValue("kind"))
+	if err := shttp.Parse(&e.Date, date.ParseDate, r.FormValue("date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing date: %w", err)
+	}
+	if err := shttp.Parse(&e.Multiple, ui.ParseUncertainValue, r.FormValue("multiple"), uncertain.NewFixed(1)); err != nil {
+		return fmt.Errorf("parsing multiple: %w", err)
+	}
+	if err := shttp.Parse(&e.FailureProbability, shttp.ParseFloat, r.FormValue("failure_probability"), 0.0); err != nil {
+		return fmt.Errorf("parsing failure probability: %w", err)
+	}
+	e.FailureProbability = e.FailureProbability / 100.0
 	return nil
 }
 
@@ -206,6 +428,7 @@ func (t *transferTemplateForm) FromForm(r *http.Request) error {
 	if t.AmountType != "fixed" && t.AmountType != "percent" {
 		return fmt.Errorf("invalid amount type: %s", t.AmountType)
 	}
+	t.Currency = r.FormValue("currency")
 	if err := shttp.Parse(&t.AmountFixed, ui.ParseUncertainValue, r.FormValue("amount_fixed"), uncertain.NewFixed(0)); err != nil {
 		return fmt.Errorf("parsing amount fixed: %w", err)
 	}
@@ -230,6 +453,13 @@ func (t *transferTemplateForm) FromForm(r *http.Request) error {
 	} else {
 		t.EndDate = nil
 	}
+	var err error
+	if t.StartAtAge, err = parsePersonAge(r, "start_person_id", "start_age"); err != nil {
+		return fmt.Errorf("parsing start: %w", err)
+	}
+	if t.EndAtAge, err = parsePersonAge(r, "end_person_id", "end_age"); err != nil {
+		return fmt.Errorf("parsing end: %w", err)
+	}
 	t.Enabled = r.FormValue("enabled") == "on"
 	budgetCategoryID := r.FormValue("budget_category_id")
 	if budgetCategoryID != "" {
@@ -265,6 +495,7 @@ func (s *salaryInputForm) FromForm(r *http.Request) error {
 	s.Name = r.FormValue("name")
 	s.ToAccountID = r.FormValue("to_account_id")
 	s.PensionAccountID = r.FormValue("pension_account_id")
+	s.OwnerID = r.FormValue("owner_id")
 	if err := shttp.Parse(&s.Priority, ui.ParseInt64, r.FormValue("priority"), int64(0)); err != nil {
 		return fmt.Errorf("parsing priority: %w", err)
 	}
@@ -276,6 +507,37 @@ func (s *salaryInputForm) FromForm(r *http.Request) error {
 	s.Kommun = r.FormValue("kommun")
 	s.Forsamling = r.FormValue("forsamling")
 	s.ChurchMember = r.FormValue("church_member") == "on"
+	s.TaxMethod = r.FormValue("tax_method")
+	if s.TaxMethod != model.TaxMethodTable && s.TaxMethod != model.TaxMethodFormula {
+		s.TaxMethod = model.TaxMethodTable
+	}
+	if err := shttp.Parse(&s.MunicipalTaxRate, shttp.ParseFloat, r.FormValue("municipal_tax_rate"), 0.0); err != nil {
+		return fmt.Errorf("parsing municipal tax rate: %w", err)
+	}
+	s.MunicipalTaxRate = s.MunicipalTaxRate / 100.0
+	if err := shttp.Parse(&s.ParentalPayRate, shttp.ParseFloat, r.FormValue("parental_pay_rate"), 0.0); err != nil {
+		return fmt.Errorf("parsing parental pay rate: %w", err)
+	}
+	s.ParentalPayRate = s.ParentalPayRate / 100.0
+	if err := shttp.Parse(&s.ParentalPayAboveCeilingRate, shttp.ParseFloat, r.FormValue("parental_pay_above_ceiling_rate"), 0.0); err != nil {
+		return fmt.Errorf("parsing parental pay above ceiling rate: %w", err)
+	}
+	s.ParentalPayAboveCeilingRate = s.ParentalPayAboveCeilingRate / 100.0
+	if err := shttp.Parse(&s.ParentalPayMaxDays, ui.ParseInt64, r.FormValue("parental_pay_max_days"), int64(0)); err != nil {
+		return fmt.Errorf("parsing parental pay max days: %w", err)
+	}
+	if err := shttp.Parse(&s.SalaryExchangeAmount, ui.ParseHumanNumber(shttp.ParseFloat), r.FormValue("salary_exchange_amount"), 0.0); err != nil {
+		return fmt.Errorf("parsing salary exchange amount: %w", err)
+	}
+	if err := shttp.Parse(&s.SalaryExchangeUplift, shttp.ParseFloat, r.FormValue("salary_exchange_uplift"), swe.DefaultSalaryExchangeUplift*100); err != nil {
+		return fmt.Errorf("parsing salary exchange uplift: %w", err)
+	}
+	s.SalaryExchangeUplift = s.SalaryExchangeUplift / 100.0
+	s.SecondaryEmployer = r.FormValue("secondary_employer") == "on"
+	if err := shttp.Parse(&s.SecondaryTaxRate, shttp.ParseFloat, r.FormValue("secondary_tax_rate"), 0.0); err != nil {
+		return fmt.Errorf("parsing secondary tax rate: %w", err)
+	}
+	s.SecondaryTaxRate = s.SecondaryTaxRate / 100.0
 	budgetCategoryID := r.FormValue("budget_category_id")
 	if budgetCategoryID != "" {
 		s.BudgetCategoryID = &budgetCategoryID
@@ -370,6 +632,78 @@ func (f *fullParentalLeaveInputForm) FromForm(r *http.Request) error {
 	return nil
 }
 
+type unemploymentInputForm struct {
+	model.Unemployment
+}
+
+func (u *unemploymentInputForm) FromForm(r *http.Request) error {
+	u.ID = r.FormValue("id")
+	u.SalaryID = r.FormValue("salary_id")
+	if err := shttp.Parse(&u.StartDate, date.ParseDate, r.FormValue("start_date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing start_date: %w", err)
+	}
+	if err := shttp.Parse(&u.EndDate, date.ParseDate, r.FormValue("end_date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing end_date: %w", err)
+	}
+	u.AKassaMember = r.FormValue("akassa_member") == "on"
+	if err := shttp.Parse(&u.IncomeInsuranceCeiling, ui.ParseHumanNumber(shttp.ParseFloat), r.FormValue("income_insurance_ceiling"), float64(0)); err != nil {
+		return fmt.Errorf("parsing income_insurance_ceiling: %w", err)
+	}
+	if err := shttp.Parse(&u.IncomeInsuranceDays, ui.ParseInt64, r.FormValue("income_insurance_days"), int64(0)); err != nil {
+		return fmt.Errorf("parsing income_insurance_days: %w", err)
+	}
+	return nil
+}
+
+type salaryBonusInputForm struct {
+	model.SalaryBonus
+}
+
+func (b *salaryBonusInputForm) FromForm(r *http.Request) error {
+	b.ID = r.FormValue("id")
+	b.SalaryID = r.FormValue("salary_id")
+	b.Name = r.FormValue("name")
+	if err := shttp.Parse(&b.Amount, ui.ParseUncertainValue, r.FormValue("amount"), uncertain.NewFixed(0)); err != nil {
+		return fmt.Errorf("parsing amount: %w", err)
+	}
+	if err := shttp.Parse(&b.Probability, shttp.ParseFloat, r.FormValue("probability"), 100.0); err != nil {
+		return fmt.Errorf("parsing probability: %w", err)
+	}
+	b.Probability = b.Probability / 100.0
+	if err := shttp.Parse(&b.PayDate, date.ParseDate, r.FormValue("pay_date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing pay_date: %w", err)
+	}
+	b.Yearly = r.FormValue("yearly") == "on"
+	return nil
+}
+
+type sickLeaveInputForm struct {
+	model.SickLeave
+}
+
+func (f *sickLeaveInputForm) FromForm(r *http.Request) error {
+	f.ID = r.FormValue("id")
+	f.SalaryID = r.FormValue("salary_id")
+	if err := shttp.Parse(&f.StartDate, date.ParseDate, r.FormValue("start_date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing start_date: %w", err)
+	}
+	if err := shttp.Parse(&f.EndDate, date.ParseDate, r.FormValue("end_date"), date.Date(0)); err != nil {
+		return fmt.Errorf("parsing end_date: %w", err)
+	}
+	if err := shttp.Parse(&f.Extent, shttp.ParseFloat, r.FormValue("extent"), float64(100)); err != nil {
+		return fmt.Errorf("parsing extent: %w", err)
+	}
+	f.Extent = f.Extent / 100.0
+	if err := shttp.Parse(&f.EmployerTopUpRate, shttp.ParseFloat, r.FormValue("employer_top_up_rate"), float64(0)); err != nil {
+		return fmt.Errorf("parsing employer_top_up_rate: %w", err)
+	}
+	f.EmployerTopUpRate = f.EmployerTopUpRate / 100.0
+	if err := shttp.Parse(&f.EmployerTopUpDays, ui.ParseInt64, r.FormValue("employer_top_up_days"), int64(0)); err != nil {
+		return fmt.Errorf("parsing employer_top_up_days: %w", err)
+	}
+	return nil
+}
+
 type predictionParamsForm struct {
 	model.PredictionParams
 }
@@ -390,6 +724,7 @@ func (p *predictionParamsForm) FromForm(r *http.Request) error {
 	if err := shttp.Parse(&p.GroupBy, model.ParseGroupBy, r.FormValue("group_by"), model.GroupByType); err != nil {
 		return fmt.Errorf("parsing group by: %w", err)
 	}
+	p.PersonID = r.FormValue("person_id")
 	return nil
 }
 
@@ -408,6 +743,52 @@ func (d *dateInputForm) FromForm(r *http.Request) error {
 	return nil
 }
 
+type interestRateModelInputForm struct {
+	model.InterestRateModel
+}
+
+func (f *interestRateModelInputForm) FromForm(r *http.Request) error {
+	f.ID = r.FormValue("id")
+	f.Name = r.FormValue("name")
+	f.Type = finance.InterestRateModelType(r.FormValue("type"))
+	if err := shttp.Parse(&f.InitialRate, shttp.ParseFloat, r.FormValue("initial_rate"), 0.0); err != nil {
+		return fmt.Errorf("parsing initial rate: %w", err)
+	}
+	f.InitialRate = f.InitialRate / 100.0
+	if err := shttp.Parse(&f.LongTermRate, shttp.ParseFloat, r.FormValue("long_term_rate"), 0.0); err != nil {
+		return fmt.Errorf("parsing long-term rate: %w", err)
+	}
+	f.LongTermRate = f.LongTermRate / 100.0
+	if err := shttp.Parse(&f.ReversionSpeed, shttp.ParseFloat, r.FormValue("reversion_speed"), 0.0); err != nil {
+		return fmt.Errorf("parsing reversion speed: %w", err)
+	}
+	if err := shttp.Parse(&f.Volatility, shttp.ParseFloat, r.FormValue("volatility"), 0.0); err != nil {
+		return fmt.Errorf("parsing volatility: %w", err)
+	}
+	f.Volatility = f.Volatility / 100.0
+	return nil
+}
+
+type fxRateModelInputForm struct {
+	model.FXRateModel
+}
+
+func (f *fxRateModelInputForm) FromForm(r *http.Request) error {
+	f.Currency = r.FormValue("currency")
+	if err := shttp.Parse(&f.InitialRate, shttp.ParseFloat, r.FormValue("initial_rate"), 0.0); err != nil {
+		return fmt.Errorf("parsing initial rate: %w", err)
+	}
+	if err := shttp.Parse(&f.AnnualDrift, shttp.ParseFloat, r.FormValue("annual_drift"), 0.0); err != nil {
+		return fmt.Errorf("parsing annual drift: %w", err)
+	}
+	f.AnnualDrift = f.AnnualDrift / 100.0
+	if err := shttp.Parse(&f.AnnualVolatility, shttp.ParseFloat, r.FormValue("annual_volatility"), 0.0); err != nil {
+		return fmt.Errorf("parsing annual volatility: %w", err)
+	}
+	f.AnnualVolatility = f.AnnualVolatility / 100.0
+	return nil
+}
+
 type sweYearlyParamsInputForm struct {
 	model.SweYearlyParams
 }
@@ -426,6 +807,18 @@ func (f *sweYearlyParamsInputForm) FromForm(r *http.Request) error {
 	if err := shttp.Parse(&f.IskFribelopp, ui.ParseAmount, r.FormValue("isk_fribelopp"), float64(0)); err != nil {
 		return fmt.Errorf("parsing isk_fribelopp: %w", err)
 	}
+	if err := shttp.Parse(&f.StateTaxThreshold, ui.ParseAmount, r.FormValue("state_tax_threshold"), float64(swe.DefaultStateTaxThreshold)); err != nil {
+		return fmt.Errorf("parsing state_tax_threshold: %w", err)
+	}
+	if err := shttp.Parse(&f.PublicServiceFeeMax, ui.ParseAmount, r.FormValue("public_service_fee_max"), float64(swe.DefaultPublicServiceFeeMax)); err != nil {
+		return fmt.Errorf("parsing public_service_fee_max: %w", err)
+	}
+	if err := shttp.Parse(&f.CsnRanta, ui.ParseAmount, r.FormValue("csn_ranta"), float64(0)); err != nil {
+		return fmt.Errorf("parsing csn_ranta: %w", err)
+	}
+	if err := shttp.Parse(&f.Statslaneranta, ui.ParseAmount, r.FormValue("statslaneranta"), float64(0)); err != nil {
+		return fmt.Errorf("parsing statslaneranta: %w", err)
+	}
 	if err := shttp.Parse(&f.ValidFrom, date.ParseDate, r.FormValue("valid_from"), date.Date(0)); err != nil {
 		return fmt.Errorf("parsing valid_from: %w", err)
 	}
@@ -513,3 +906,127 @@ func extractExcludedTypeIDs(r *http.Request) []string {
 	}
 	return ids
 }
+
+type companyInputForm struct {
+	model.Company
+}
+
+func (c *companyInputForm) FromForm(r *http.Request) error {
+	c.ID = r.FormValue("id")
+	c.Name = r.FormValue("name")
+	c.OwnerID = r.FormValue("owner_id")
+	c.AccountID = r.FormValue("account_id")
+	c.DividendAccountID = r.FormValue("dividend_account_id")
+	c.SalaryID = r.FormValue("salary_id")
+	if err := shttp.Parse(&c.OwnershipShare, shttp.ParseFloat, r.FormValue("ownership_share"), 100.0); err != nil {
+		return fmt.Errorf("parsing ownership share: %w", err)
+	}
+	c.OwnershipShare = c.OwnershipShare / 100.0
+	if err := shttp.Parse(&c.Revenue, ui.ParseUncertainValue, r.FormValue("revenue"), uncertain.NewFixed(0)); err != nil {
+		return fmt.Errorf("parsing revenue: %w", err)
+	}
+	if err := shttp.Parse(&c.Expenses, ui.ParseUncertainValue, r.FormValue("expenses"), uncertain.NewFixed(0)); err != nil {
+		return fmt.Errorf("parsing expenses: %w", err)
+	}
+	c.GransbeloppRule = swe.GransbeloppRule(r.FormValue("gransbelopp_rule"))
+	if c.GransbeloppRule != swe.GransbeloppMain {
+		c.GransbeloppRule = swe.GransbeloppSimplified
+	}
+	if err := shttp.Parse(&c.AcquisitionCost, ui.ParseHumanNumber(shttp.ParseFloat), r.FormValue("acquisition_cost"), 0.0); err != nil {
+		return fmt.Errorf("parsing acquisition cost: %w", err)
+	}
+	if err := shttp.Parse(&c.CapitalRate, shttp.ParseFloat, r.FormValue("capital_rate"), swe.DefaultCapitalRate*100); err != nil {
+		return fmt.Errorf("parsing capital rate: %w", err)
+	}
+	c.CapitalRate = c.CapitalRate / 100.0
+	if err := shttp.Parse(&c.SavedDividendSpace, ui.ParseHumanNumber(shttp.ParseFloat), r.FormValue("saved_dividend_space"), 0.0); err != nil {
+		return fmt.Errorf("parsing saved dividend space: %w", err)
+	}
+	if err := shttp.Parse(&c.DividendDate, ui.ParseDateCron, r.FormValue("dividend_date"), date.Cron("*-06-30")); err != nil {
+		return fmt.Errorf("parsing dividend date: %w", err)
+	}
+	if err := shttp.Parse(&c.StartDate, date.ParseDate, r.FormValue("start_date"), date.Today()); err != nil {
+		return fmt.Errorf("parsing start date: %w", err)
+	}
+	return nil
+}
+
+type soleProprietorshipInputForm struct {
+	model.SoleProprietorship
+}
+
+func (sp *soleProprietorshipInputForm) FromForm(r *http.Request) error {
+	sp.ID = r.FormValue("id")
+	sp.Name = r.FormValue("name")
+	sp.OwnerID = r.FormValue("owner_id")
+	sp.ToAccountID = r.FormValue("to_account_id")
+	if err := shttp.Parse(&sp.Profit, ui.ParseUncertainValue, r.FormValue("profit"), uncertain.NewFixed(0)); err != nil {
+		return fmt.Errorf("parsing profit: %w", err)
+	}
+	if err := shttp.Parse(&sp.Recurrence, ui.ParseDateCron, r.FormValue("recurrence"), date.Cron("*-*-25")); err != nil {
+		return fmt.Errorf("parsing recurrence: %w", err)
+	}
+	if err := shttp.Parse(&sp.PeriodiseringsfondShare, shttp.ParseFloat, r.FormValue("periodiseringsfond_share"), 0.0); err != nil {
+		return fmt.Errorf("parsing periodiseringsfond share: %w", err)
+	}
+	sp.PeriodiseringsfondShare = sp.PeriodiseringsfondShare / 100.0
+	if err := shttp.Parse(&sp.Expansionsfond, ui.ParseHumanNumber(shttp.ParseFloat), r.FormValue("expansionsfond"), 0.0); err != nil {
+		return fmt.Errorf("parsing expansionsfond: %w", err)
+	}
+	if err := shttp.Parse(&sp.MunicipalTaxRate, shttp.ParseFloat, r.FormValue("municipal_tax_rate"), 0.0); err != nil {
+		return fmt.Errorf("parsing municipal tax rate: %w", err)
+	}
+	sp.MunicipalTaxRate = sp.MunicipalTaxRate / 100.0
+	if err := shttp.Parse(&sp.StartDate, date.ParseDate, r.FormValue("start_date"), date.Today()); err != nil {
+		return fmt.Errorf("parsing start date: %w", err)
+	}
+	sp.Enabled = r.FormValue("enabled") == "on"
+	return nil
+}
+
+type equityGrantInputForm struct {
+	model.EquityGrant
+}
+
+func (g *equityGrantInputForm) FromForm(r *http.Request) error {
+	g.ID = r.FormValue("id")
+	g.Name = r.FormValue("name")
+	g.OwnerID = r.FormValue("owner_id")
+	g.Kind = model.EquityGrantKind(r.FormValue("kind"))
+	g.HoldingAccountID = r.FormValue("holding_account_id")
+	g.SalaryAccountID = r.FormValue("salary_account_id")
+	if err := shttp.Parse(&g.SharePrice, shttp.ParseFloat, r.FormValue("share_price"), 0.0); err != nil {
+		return fmt.Errorf("parsing share price: %w", err)
+	}
+	if err := shttp.Parse(&g.Shares, ui.ParseHumanNumber(shttp.ParseFloat), r.FormValue("shares"), 0.0); err != nil {
+		return fmt.Errorf("parsing shares: %w", err)
+	}
+	if err := shttp.Parse(&g.CliffMonths, ui.ParseInt64, r.FormValue("cliff_months"), int64(0)); err != nil {
+		return fmt.Errorf("parsing cliff: %w", err)
+	}
+	if err := shttp.Parse(&g.VestingMonths, ui.ParseInt64, r.FormValue("vesting_months"), int64(0)); err != nil {
+		return fmt.Errorf("parsing vesting: %w", err)
+	}
+	if err := shttp.Parse(&g.VestingIntervalMonths, ui.ParseInt64, r.FormValue("vesting_interval_months"), int64(3)); err != nil {
+		return fmt.Errorf("parsing vesting interval: %w", err)
+	}
+	if err := shttp.Parse(&g.Contribution, ui.ParseUncertainValue, r.FormValue("contribution"), uncertain.NewFixed(0)); err != nil {
+		return fmt.Errorf("parsing contribution: %w", err)
+	}
+	if err := shttp.Parse(&g.Discount, shttp.ParseFloat, r.FormValue("discount"), 0.0); err != nil {
+		return fmt.Errorf("parsing discount: %w", err)
+	}
+	g.Discount = g.Discount / 100.0
+	if err := shttp.Parse(&g.Recurrence, ui.ParseDateCron, r.FormValue("recurrence"), date.Cron("*-*-25")); err != nil {
+		return fmt.Errorf("parsing recurrence: %w", err)
+	}
+	if err := shttp.Parse(&g.MunicipalTaxRate, shttp.ParseFloat, r.FormValue("municipal_tax_rate"), 0.0); err != nil {
+		return fmt.Errorf("parsing municipal tax rate: %w", err)
+	}
+	g.MunicipalTaxRate = g.MunicipalTaxRate / 100.0
+	if err := shttp.Parse(&g.StartDate, date.ParseDate, r.FormValue("start_date"), date.Today()); err != nil {
+		return fmt.Errorf("parsing start date: %w", err)
+	}
+	g.Enabled = r.FormValue("enabled") == "on"
+	return nil
+}
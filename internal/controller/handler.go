@@ -14,6 +14,7 @@ import (
 	"github.com/SimonSchneider/goslu/static/shttp"
 	"github.com/SimonSchneider/pefigo/internal/model"
 	"github.com/SimonSchneider/pefigo/internal/view"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
 	"github.com/SimonSchneider/pefigo/pkg/ui"
 )
 
@@ -93,6 +94,20 @@ func NewHandler(svc *model.Service, public fs.FS) http.Handler {
 	mux.Handle("POST /growth-models/", h.accountGrowthModelUpsert())
 	mux.Handle("POST /growth-models/{id}/delete", h.accountGrowthModelDelete())
 
+	mux.Handle("POST /pension-payouts/{$}", h.pensionPayoutUpsert())
+	mux.Handle("POST /pension-payouts/{id}/delete", h.pensionPayoutDelete())
+
+	mux.Handle("POST /interest-deductions/{$}", h.interestDeductionUpsert())
+	mux.Handle("POST /interest-deductions/{id}/delete", h.interestDeductionDelete())
+
+	mux.Handle("POST /amortization-requirements/{$}", h.amortizationRequirementUpsert())
+	mux.Handle("POST /amortization-requirements/{id}/delete", h.amortizationRequirementDelete())
+
+	mux.Handle("POST /csn-loans/{$}", h.csnLoanUpsert())
+	mux.Handle("POST /csn-loans/{id}/delete", h.csnLoanDelete())
+	mux.Handle("POST /account-owners/{$}", h.accountOwnerUpsert())
+	mux.Handle("POST /account-owners/{accountID}/{personID}/delete", h.accountOwnerDelete())
+
 	mux.Handle("POST /startup-share-accounts/", h.startupShareAccountUpsert())
 	mux.Handle("POST /investment-rounds/", h.investmentRoundUpsert())
 	mux.Handle("POST /investment-rounds/{id}/delete", h.investmentRoundDelete())
@@ -100,6 +115,10 @@ func NewHandler(svc *model.Service, public fs.FS) http.Handler {
 	mux.Handle("POST /share-changes/{id}/delete", h.shareChangeDelete())
 	mux.Handle("POST /startup-share-options/", h.startupShareOptionUpsert())
 	mux.Handle("POST /startup-share-options/{id}/delete", h.startupShareOptionDelete())
+	mux.Handle("POST /projected-investment-rounds/", h.projectedInvestmentRoundUpsert())
+	mux.Handle("POST /projected-investment-rounds/{id}/delete", h.projectedInvestmentRoundDelete())
+	mux.Handle("POST /startup-exits/", h.startupExitUpsert())
+	mux.Handle("POST /startup-exits/{id}/delete", h.startupExitDelete())
 
 	mux.Handle("GET /salaries", h.salariesPage())
 	mux.Handle("GET /salaries/new", h.salaryNewPage())
@@ -116,6 +135,12 @@ func NewHandler(svc *model.Service, public fs.FS) http.Handler {
 	mux.Handle("POST /partial-parental-leaves/{id}/delete", h.partialParentalLeaveDelete())
 	mux.Handle("POST /full-parental-leaves/{$}", h.fullParentalLeaveUpsert())
 	mux.Handle("POST /full-parental-leaves/{id}/delete", h.fullParentalLeaveDelete())
+	mux.Handle("POST /unemployments/{$}", h.unemploymentUpsert())
+	mux.Handle("POST /unemployments/{id}/delete", h.unemploymentDelete())
+	mux.Handle("POST /sick-leaves/{$}", h.sickLeaveUpsert())
+	mux.Handle("POST /sick-leaves/{id}/delete", h.sickLeaveDelete())
+	mux.Handle("POST /salary-bonuses/{$}", h.salaryBonusUpsert())
+	mux.Handle("POST /salary-bonuses/{id}/delete", h.salaryBonusDelete())
 
 	mux.Handle("GET /bills", h.billsPage())
 	mux.Handle("GET /bills/new", h.billAccountNewPage())
@@ -127,15 +152,42 @@ func NewHandler(svc *model.Service, public fs.FS) http.Handler {
 	mux.Handle("POST /bill-items/{id}/delete", h.billDelete())
 	mux.Handle("POST /bill-amounts/{$}", h.billAmountUpsert())
 	mux.Handle("POST /bill-amounts/{id}/delete", h.billAmountDelete())
+
+	mux.Handle("GET /companies", h.companiesPage())
+	mux.Handle("GET /companies/new", h.companyEditPage())
+	mux.Handle("GET /companies/{id}/edit", h.companyEditPage())
+	mux.Handle("POST /companies/{$}", h.companyUpsert())
+	mux.Handle("POST /companies/{id}/delete", h.companyDelete())
+
+	mux.Handle("GET /sole-proprietorships", h.soleProprietorshipsPage())
+	mux.Handle("GET /sole-proprietorships/new", h.soleProprietorshipEditPage())
+	mux.Handle("GET /sole-proprietorships/{id}/edit", h.soleProprietorshipEditPage())
+	mux.Handle("POST /sole-proprietorships/{$}", h.soleProprietorshipUpsert())
+	mux.Handle("POST /sole-proprietorships/{id}/delete", h.soleProprietorshipDelete())
+
+	mux.Handle("GET /equity-grants", h.equityGrantsPage())
+	mux.Handle("GET /equity-grants/new", h.equityGrantEditPage())
+	mux.Handle("GET /equity-grants/{id}/edit", h.equityGrantEditPage())
+	mux.Handle("POST /equity-grants/{$}", h.equityGrantUpsert())
+	mux.Handle("POST /equity-grants/{id}/delete", h.equityGrantDelete())
 	mux.Handle("GET /favicons/{domain}", h.faviconHandler())
 
 	mux.Handle("POST /settings/currency", h.currencySettingsSave())
 	mux.Handle("POST /settings/forecast", h.forecastSettingsSave())
+	mux.Handle("POST /settings/child-benefit", h.childBenefitSettingsSave())
 
 	mux.Handle("GET /settings/swe-yearly-params/new", h.sweYearlyParamsNewPage())
 	mux.Handle("GET /settings/swe-yearly-params/{id}/edit", h.sweYearlyParamsEditPage())
 	mux.Handle("POST /settings/swe-yearly-params/{$}", h.sweYearlyParamsUpsert())
 	mux.Handle("POST /settings/swe-yearly-params/{id}/delete", h.sweYearlyParamsDelete())
+	mux.Handle("POST /settings/interest-rate-models/{$}", h.interestRateModelUpsert())
+	mux.Handle("POST /settings/interest-rate-models/{id}/delete", h.interestRateModelDelete())
+	mux.Handle("POST /settings/fx-rate-models/{$}", h.fxRateModelUpsert())
+	mux.Handle("POST /settings/fx-rate-models/{id}/delete", h.fxRateModelDelete())
+	mux.Handle("POST /settings/persons/{$}", h.personUpsert())
+	mux.Handle("POST /settings/persons/{id}/delete", h.personDelete())
+	mux.Handle("POST /settings/tax-data/import", h.taxDataImport())
+	mux.Handle("GET /settings/tax-data/export", h.taxDataExport())
 
 	mux.Handle("POST /transfers/{$}", h.transferTemplateUpsert())
 	mux.Handle("POST /transfers/{id}/duplicate", h.transferTemplateDuplicate())
@@ -159,7 +211,7 @@ func NewHandler(svc *model.Service, public fs.FS) http.Handler {
 
 func (h *Handler) dashboardPage() http.Handler {
 	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		data, err := h.svc.GetDashboardData(ctx)
+		data, err := h.svc.GetDashboardData(ctx, r.URL.Query().Get("person"))
 		if err != nil {
 			return fmt.Errorf("computing dashboard view: %w", err)
 		}
@@ -171,7 +223,7 @@ func (h *Handler) dashboardPage() http.Handler {
 
 func (h *Handler) budgetPage() http.Handler {
 	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		data, err := h.svc.GetBudgetData(ctx)
+		data, err := h.svc.GetBudgetData(ctx, r.URL.Query().Get("person"))
 		if err != nil {
 			return fmt.Errorf("computing budget view: %w", err)
 		}
@@ -266,6 +318,141 @@ func (h *Handler) accountGrowthModelDelete() http.Handler {
 	})
 }
 
+// ---- Pension Payouts ----
+
+func (h *Handler) pensionPayoutUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp pensionPayoutInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertPensionPayout(ctx, inp.PensionPayout); err != nil {
+			return fmt.Errorf("upserting pension payout: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", inp.AccountID))
+		return nil
+	})
+}
+
+func (h *Handler) pensionPayoutDelete() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := r.PathValue("id")
+		if err := h.svc.DeletePensionPayout(ctx, id); err != nil {
+			return err
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", id))
+		return nil
+	})
+}
+
+// ---- Interest Deductions ----
+
+func (h *Handler) interestDeductionUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp interestDeductionInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertInterestDeduction(ctx, inp.InterestDeduction); err != nil {
+			return fmt.Errorf("upserting interest deduction: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", inp.AccountID))
+		return nil
+	})
+}
+
+func (h *Handler) interestDeductionDelete() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := r.PathValue("id")
+		if err := h.svc.DeleteInterestDeduction(ctx, id); err != nil {
+			return err
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", id))
+		return nil
+	})
+}
+
+// ---- Amortization Requirements ----
+
+func (h *Handler) amortizationRequirementUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp amortizationRequirementInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertAmortizationRequirement(ctx, inp.AmortizationRequirement); err != nil {
+			return fmt.Errorf("upserting amortization requirement: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", inp.AccountID))
+		return nil
+	})
+}
+
+func (h *Handler) amortizationRequirementDelete() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := r.PathValue("id")
+		if err := h.svc.DeleteAmortizationRequirement(ctx, id); err != nil {
+			return err
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", id))
+		return nil
+	})
+}
+
+// ---- CSN Loans ----
+
+func (h *Handler) csnLoanUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp csnLoanInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertCSNLoan(ctx, inp.CSNLoan); err != nil {
+			return fmt.Errorf("upserting csn loan: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", inp.AccountID))
+		return nil
+	})
+}
+
+func (h *Handler) csnLoanDelete() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := r.PathValue("id")
+		if err := h.svc.DeleteCSNLoan(ctx, id); err != nil {
+			return err
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", id))
+		return nil
+	})
+}
+
+// ---- Account Owners ----
+
+func (h *Handler) accountOwnerUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp accountOwnerInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertAccountOwner(ctx, inp.AccountOwner); err != nil {
+			return fmt.Errorf("upserting account owner: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", inp.AccountID))
+		return nil
+	})
+}
+
+func (h *Handler) accountOwnerDelete() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		accountID := r.PathValue("accountID")
+		if err := h.svc.DeleteAccountOwner(ctx, accountID, r.PathValue("personID")); err != nil {
+			return err
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", accountID))
+		return nil
+	})
+}
+
 // ---- Startup Shares ----
 
 func (h *Handler) startupShareAccountUpsert() http.Handler {
@@ -346,6 +533,51 @@ func (h *Handler) startupShareOptionDelete() http.Handler {
 	})
 }
 
+func (h *Handler) projectedInvestmentRoundUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp projectedInvestmentRoundInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertProjectedInvestmentRound(ctx, inp.ProjectedInvestmentRoundInput); err != nil {
+			return fmt.Errorf("upserting projected investment round: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", inp.AccountID))
+		return nil
+	})
+}
+
+func (h *Handler) projectedInvestmentRoundDelete() http.Handler {
+	return deleteWithLookupHandler(h.svc.GetProjectedInvestmentRound, h.svc.DeleteProjectedInvestmentRound, func(r model.ProjectedInvestmentRound) string {
+		return fmt.Sprintf("/accounts/%s/edit", r.AccountID)
+	})
+}
+
+func (h *Handler) startupExitUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp startupExitInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertStartupExit(ctx, inp.StartupExitInput); err != nil {
+			return fmt.Errorf("upserting startup exit: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", inp.AccountID))
+		return nil
+	})
+}
+
+func (h *Handler) startupExitDelete() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := r.PathValue("id")
+		if err := h.svc.DeleteStartupExit(ctx, id); err != nil {
+			return err
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/accounts/%s/edit", id))
+		return nil
+	})
+}
+
 // ---- Salaries ----
 
 func (h *Handler) salariesPage() http.Handler {
@@ -479,6 +711,60 @@ func (h *Handler) fullParentalLeaveDelete() http.Handler {
 	return deleteHandler(h.svc.DeleteFullParentalLeave, "/salaries")
 }
 
+func (h *Handler) unemploymentUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp unemploymentInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertUnemployment(ctx, inp.Unemployment); err != nil {
+			return fmt.Errorf("upserting unemployment: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/salaries/%s/edit", inp.SalaryID))
+		return nil
+	})
+}
+
+func (h *Handler) unemploymentDelete() http.Handler {
+	return deleteHandler(h.svc.DeleteUnemployment, "/salaries")
+}
+
+func (h *Handler) salaryBonusUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp salaryBonusInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertSalaryBonus(ctx, inp.SalaryBonus); err != nil {
+			return fmt.Errorf("upserting salary bonus: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/salaries/%s/edit", inp.SalaryID))
+		return nil
+	})
+}
+
+func (h *Handler) salaryBonusDelete() http.Handler {
+	return deleteHandler(h.svc.DeleteSalaryBonus, "/salaries")
+}
+
+func (h *Handler) sickLeaveUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp sickLeaveInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertSickLeave(ctx, inp.SickLeave); err != nil {
+			return fmt.Errorf("upserting sick leave: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/salaries/%s/edit", inp.SalaryID))
+		return nil
+	})
+}
+
+func (h *Handler) sickLeaveDelete() http.Handler {
+	return deleteHandler(h.svc.DeleteSickLeave, "/salaries")
+}
+
 // ---- Bills ----
 
 func (h *Handler) billsPage() http.Handler {
@@ -530,6 +816,123 @@ func (h *Handler) billAccountDelete() http.Handler {
 	return deleteHandler(h.svc.DeleteBillAccount, "/bills")
 }
 
+func (h *Handler) companiesPage() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		companies, err := h.svc.ListCompanies(ctx)
+		if err != nil {
+			return fmt.Errorf("getting companies page data: %w", err)
+		}
+		return view.NewView(ctx, w, r).Render(view.Page("Companies", view.PageCompanies(view.CompaniesListView(companies))))
+	})
+}
+
+func (h *Handler) companyEditPage() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		data, err := h.svc.GetCompanyEditPageData(ctx, r.PathValue("id"))
+		if err != nil {
+			return fmt.Errorf("getting company edit page data: %w", err)
+		}
+		return view.NewView(ctx, w, r).Render(view.Page("Companies", view.PageEditCompany(view.CompanyEditContent(data))))
+	})
+}
+
+func (h *Handler) companyUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp companyInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		c, err := h.svc.UpsertCompany(ctx, inp.Company)
+		if err != nil {
+			return fmt.Errorf("upserting company: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/companies/%s/edit", c.ID))
+		return nil
+	})
+}
+
+func (h *Handler) companyDelete() http.Handler {
+	return deleteHandler(h.svc.DeleteCompany, "/companies")
+}
+
+func (h *Handler) soleProprietorshipsPage() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		sps, err := h.svc.ListSoleProprietorships(ctx)
+		if err != nil {
+			return fmt.Errorf("getting sole proprietorships page data: %w", err)
+		}
+		return view.NewView(ctx, w, r).Render(view.Page("Sole Proprietorships", view.PageSoleProprietorships(view.SoleProprietorshipsListView(sps))))
+	})
+}
+
+func (h *Handler) soleProprietorshipEditPage() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		data, err := h.svc.GetSoleProprietorshipEditPageData(ctx, r.PathValue("id"))
+		if err != nil {
+			return fmt.Errorf("getting sole proprietorship edit page data: %w", err)
+		}
+		return view.NewView(ctx, w, r).Render(view.Page("Sole Proprietorships", view.PageEditSoleProprietorship(view.SoleProprietorshipEditContent(data))))
+	})
+}
+
+func (h *Handler) soleProprietorshipUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp soleProprietorshipInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		sp, err := h.svc.UpsertSoleProprietorship(ctx, inp.SoleProprietorship)
+		if err != nil {
+			return fmt.Errorf("upserting sole proprietorship: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/sole-proprietorships/%s/edit", sp.ID))
+		return nil
+	})
+}
+
+func (h *Handler) soleProprietorshipDelete() http.Handler {
+	return deleteHandler(h.svc.DeleteSoleProprietorship, "/sole-proprietorships")
+}
+
+func (h *Handler) equityGrantsPage() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		grants, err := h.svc.ListEquityGrants(ctx)
+		if err != nil {
+			return fmt.Errorf("getting equity grants page data: %w", err)
+		}
+		return view.NewView(ctx, w, r).Render(view.Page("Equity Grants", view.PageEquityGrants(view.EquityGrantsListView(grants))))
+	})
+}
+
+func (h *Handler) equityGrantEditPage() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		data, err := h.svc.GetEquityGrantEditPageData(ctx, r.PathValue("id"))
+		if err != nil {
+			return fmt.Errorf("getting equity grant edit page data: %w", err)
+		}
+		return view.NewView(ctx, w, r).Render(view.Page("Equity Grants", view.PageEditEquityGrant(view.EquityGrantEditContent(data))))
+	})
+}
+
+func (h *Handler) equityGrantUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp equityGrantInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		g, err := h.svc.UpsertEquityGrant(ctx, inp.EquityGrant)
+		if err != nil {
+			return fmt.Errorf("upserting equity grant: %w", err)
+		}
+		shttp.RedirectToNext(w, r, fmt.Sprintf("/equity-grants/%s/edit", g.ID))
+		return nil
+	})
+}
+
+func (h *Handler) equityGrantDelete() http.Handler {
+	return deleteHandler(h.svc.DeleteEquityGrant, "/equity-grants")
+}
+
 func (h *Handler) billEditPage() http.Handler {
 	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		data, err := h.svc.GetBillEditPageData(ctx, r.PathValue("id"))
@@ -700,6 +1103,8 @@ var validTabs = map[string]bool{
 	"currency":          true,
 	"swe-yearly-params": true,
 	"special-dates":     true,
+	"interest-rates":    true,
+	"household":         true,
 	"forecast":          true,
 }
 
@@ -796,7 +1201,11 @@ func (h *Handler) accountTypeDelete() http.Handler {
 
 func (h *Handler) specialDateNewPage() http.Handler {
 	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		return view.NewView(ctx, w, r).Render(view.Page("Special Dates", view.PageEditSpecialDate(view.SpecialDateEditView(view.SpecialDate{}))))
+		persons, err := h.svc.ListPersons(ctx)
+		if err != nil {
+			return err
+		}
+		return view.NewView(ctx, w, r).Render(view.Page("Special Dates", view.PageEditSpecialDate(view.SpecialDateEditView(view.SpecialDate{}, persons))))
 	})
 }
 
@@ -806,7 +1215,11 @@ func (h *Handler) specialDateEditPage() http.Handler {
 		if err != nil {
 			return fmt.Errorf("getting special date: %w", err)
 		}
-		return view.NewView(ctx, w, r).Render(view.Page("Special Dates", view.PageEditSpecialDate(view.SpecialDateEditView(sd))))
+		persons, err := h.svc.ListPersons(ctx)
+		if err != nil {
+			return err
+		}
+		return view.NewView(ctx, w, r).Render(view.Page("Special Dates", view.PageEditSpecialDate(view.SpecialDateEditView(sd, persons))))
 	})
 }
 
@@ -946,7 +1359,11 @@ func (h *Handler) chartPage() http.Handler {
 		if err := srvu.Decode(r, &p, false); err != nil {
 			return fmt.Errorf("decoding input: %w", err)
 		}
-		return view.NewView(ctx, w, r).Render(view.Page("Chart", view.PageChart(p.PredictionParams)))
+		persons, err := h.svc.ListPersons(ctx)
+		if err != nil {
+			return err
+		}
+		return view.NewView(ctx, w, r).Render(view.Page("Chart", view.PageChart(p.PredictionParams, persons)))
 	})
 }
 
@@ -979,7 +1396,10 @@ func (h *Handler) chartsDataStream() http.Handler {
 
 func (h *Handler) sweYearlyParamsNewPage() http.Handler {
 	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		return view.NewView(ctx, w, r).Render(view.Page("New SWE Yearly Params", view.SweYearlyParamsEditPage(view.SweYearlyParams{}, false)))
+		return view.NewView(ctx, w, r).Render(view.Page("New SWE Yearly Params", view.SweYearlyParamsEditPage(view.SweYearlyParams{
+			StateTaxThreshold:   swe.DefaultStateTaxThreshold,
+			PublicServiceFeeMax: swe.DefaultPublicServiceFeeMax,
+		}, false)))
 	})
 }
 
@@ -1012,6 +1432,93 @@ func (h *Handler) sweYearlyParamsDelete() http.Handler {
 	return deleteHandler(h.svc.DeleteSweYearlyParams, "/settings?tab=swe-yearly-params")
 }
 
+func (h *Handler) interestRateModelUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp interestRateModelInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertInterestRateModel(ctx, inp.InterestRateModel); err != nil {
+			return fmt.Errorf("upserting interest rate model: %w", err)
+		}
+		shttp.RedirectToNext(w, r, "/settings?tab=interest-rates")
+		return nil
+	})
+}
+
+func (h *Handler) interestRateModelDelete() http.Handler {
+	return deleteHandler(h.svc.DeleteInterestRateModel, "/settings?tab=interest-rates")
+}
+
+func (h *Handler) fxRateModelUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp fxRateModelInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertFXRateModel(ctx, inp.FXRateModel); err != nil {
+			return fmt.Errorf("upserting fx rate model: %w", err)
+		}
+		shttp.RedirectToNext(w, r, "/settings?tab=currency")
+		return nil
+	})
+}
+
+func (h *Handler) fxRateModelDelete() http.Handler {
+	return deleteHandler(h.svc.DeleteFXRateModel, "/settings?tab=currency")
+}
+
+func (h *Handler) personUpsert() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var inp personInputForm
+		if err := srvu.Decode(r, &inp, false); err != nil {
+			return fmt.Errorf("decoding input: %w", err)
+		}
+		if _, err := h.svc.UpsertPerson(ctx, inp.Person); err != nil {
+			return fmt.Errorf("upserting person: %w", err)
+		}
+		shttp.RedirectToNext(w, r, "/settings?tab=household")
+		return nil
+	})
+}
+
+func (h *Handler) personDelete() http.Handler {
+	return deleteHandler(h.svc.DeletePerson, "/settings?tab=household")
+}
+
+// maxTaxDataUploadSize bounds tax data uploads, leaving room for the full
+// yearly tax table export.
+const maxTaxDataUploadSize = 256 << 20
+
+func (h *Handler) taxDataImport() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		r.Body = http.MaxBytesReader(w, r.Body, maxTaxDataUploadSize)
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return fmt.Errorf("reading uploaded file: %w", err)
+		}
+		defer file.Close()
+		if _, _, err := h.svc.ImportTaxData(ctx, file); err != nil {
+			return err
+		}
+		shttp.RedirectToNext(w, r, "/settings?tab=swe-yearly-params")
+		return nil
+	})
+}
+
+func (h *Handler) taxDataExport() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		dataset := swe.TaxDataset(r.URL.Query().Get("dataset"))
+		resp, err := h.svc.ExportTaxData(ctx, dataset)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", string(dataset)+".json"))
+		return json.NewEncoder(w).Encode(resp)
+	})
+}
+
 func (h *Handler) currencySettingsSave() http.Handler {
 	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if err := r.ParseForm(); err != nil {
@@ -1065,6 +1572,19 @@ func (h *Handler) forecastSettingsSave() http.Handler {
 	})
 }
 
+func (h *Handler) childBenefitSettingsSave() http.Handler {
+	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if err := r.ParseForm(); err != nil {
+			return fmt.Errorf("parsing form: %w", err)
+		}
+		if err := h.svc.SetChildBenefitAccount(ctx, r.FormValue("account_id")); err != nil {
+			return err
+		}
+		shttp.RedirectToNext(w, r, "/settings?tab=household")
+		return nil
+	})
+}
+
 func (h *Handler) dashboardForecastStream() http.Handler {
 	return srvu.ErrHandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		sse := srvu.SSEResponse(w)
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -15,9 +16,18 @@ import (
 	"github.com/SimonSchneider/goslu/templ"
 	"github.com/SimonSchneider/pefigo"
 	"github.com/SimonSchneider/pefigo/internal/model"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
 )
 
 func Run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer, getEnv func(string) string, getwd func() (string, error)) error {
+	if len(args) > 1 {
+		switch args[1] {
+		case "import-tax-data":
+			return runImportTaxData(ctx, args[2:], stdin, stdout, getEnv)
+		case "export-tax-data":
+			return runExportTaxData(ctx, args[2:], stdout, getEnv)
+		}
+	}
 	cfg, err := model.ParseConfig(args[1:], getEnv)
 	if err != nil {
 		return fmt.Errorf("failed to parse flags: %w", err)
@@ -61,3 +71,50 @@ func Run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer,
 	logger.Printf("starting chore server, listening on %s\n  sqliteDB: %s", cfg.Addr, cfg.DbURL)
 	return srvu.RunServerGracefully(ctx, srv, logger)
 }
+
+// runImportTaxData loads a Skatteverket tax rate or tax table export into the
+// cache. The file is read from stdin when -file is empty or "-".
+func runImportTaxData(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, getEnv func(string) string) error {
+	cfg, err := model.ParseTaxDataConfig(args, getEnv)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+	in := stdin
+	if cfg.File != "" && cfg.File != "-" {
+		f, err := os.Open(cfg.File)
+		if err != nil {
+			return fmt.Errorf("opening tax data: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	db, err := model.GetMigratedDB(ctx, pefigo.StaticEmbeddedFS, "static/migrations", cfg.DbURL)
+	if err != nil {
+		return fmt.Errorf("failed to migrate db: %w", err)
+	}
+	defer db.Close()
+	dataset, n, err := model.New(db).ImportTaxData(ctx, in)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "imported %s into %d cache entries\n", dataset, n)
+	return nil
+}
+
+// runExportTaxData writes the cached dataset to stdout as rowstore JSON.
+func runExportTaxData(ctx context.Context, args []string, stdout io.Writer, getEnv func(string) string) error {
+	cfg, err := model.ParseTaxDataConfig(args, getEnv)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+	db, err := model.GetMigratedDB(ctx, pefigo.StaticEmbeddedFS, "static/migrations", cfg.DbURL)
+	if err != nil {
+		return fmt.Errorf("failed to migrate db: %w", err)
+	}
+	defer db.Close()
+	resp, err := model.New(db).ExportTaxData(ctx, swe.TaxDataset(cfg.Dataset))
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(stdout).Encode(resp)
+}
@@ -23,6 +23,8 @@ type Account struct {
 	TypeID                string
 	BudgetCategoryID      *string
 	IsISK                 bool
+	OwnerID               string
+	Currency              string // Empty for the default currency
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
 }
@@ -43,6 +45,8 @@ type AccountInput struct {
 	TypeID                string
 	BudgetCategoryID      *string
 	IsISK                 bool
+	OwnerID               string
+	Currency              string
 }
 
 func accountFromDB(a pdb.Account) Account {
@@ -55,6 +59,8 @@ func accountFromDB(a pdb.Account) Account {
 		TypeID:                ui.OrDefault(a.TypeID),
 		BudgetCategoryID:      a.BudgetCategoryID,
 		IsISK:                 a.IsIsk != 0,
+		OwnerID:               ui.OrDefault(a.OwnerID),
+		Currency:              a.Currency,
 		CreatedAt:             time.UnixMilli(a.CreatedAt),
 		UpdatedAt:             time.UnixMilli(a.UpdatedAt),
 	}
@@ -88,6 +94,8 @@ func (s *Service) UpsertAccount(ctx context.Context, inp AccountInput) (Account,
 			TypeID:                ui.WithDefaultNull(inp.TypeID),
 			BudgetCategoryID:      inp.BudgetCategoryID,
 			IsIsk:                 isIsk,
+			OwnerID:               ui.WithDefaultNull(inp.OwnerID),
+			Currency:              inp.Currency,
 			UpdatedAt:             time.Now().UnixMilli(),
 		})
 	} else {
@@ -100,6 +108,8 @@ func (s *Service) UpsertAccount(ctx context.Context, inp AccountInput) (Account,
 			TypeID:                ui.WithDefaultNull(inp.TypeID),
 			BudgetCategoryID:      inp.BudgetCategoryID,
 			IsIsk:                 isIsk,
+			OwnerID:               ui.WithDefaultNull(inp.OwnerID),
+			Currency:              inp.Currency,
 			CreatedAt:             time.Now().UnixMilli(),
 			UpdatedAt:             time.Now().UnixMilli(),
 		})
@@ -253,6 +263,10 @@ func (s *Service) ListAccountsDetailed(ctx context.Context, today date.Date) ([]
 			if err != nil {
 				return nil, fmt.Errorf("listing share changes for account %s: %w", acc.ID, err)
 			}
+			rounds, err := s.ListInvestmentRounds(ctx, acc.ID)
+			if err != nil {
+				return nil, fmt.Errorf("listing investment rounds for account %s: %w", acc.ID, err)
+			}
 			sharesOwned, avgPurchasePrice := DeriveShareState(changes, today)
 			balance := CalculateStartupShareBalance(
 				ucfg,
@@ -262,6 +276,7 @@ func (s *Service) ListAccountsDetailed(ctx context.Context, today date.Date) ([]
 				ssa.TaxRate,
 				postMoneyShares,
 				ssa.ValuationDiscountFactor,
+				liquidationPreferences(rounds, today),
 			)
 			encoded, err := balance.Encode()
 			if err != nil {
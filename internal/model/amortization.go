@@ -0,0 +1,270 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/ui"
+)
+
+// DefaultAmortizationRecurrence is the default day of the month amortization is paid.
+const DefaultAmortizationRecurrence = date.Cron("*-*-25")
+
+// AmortizationRequirement applies the amorteringskrav to a loan account. The
+// required yearly amortization is paid from FromAccountID, based on the
+// loan-to-value against PropertyAccountID and the debt-to-income against the
+// household's gross salaries. It is redetermined every RevaluationYears
+// after ValuationDate, when the property is revalued.
+type AmortizationRequirement struct {
+	AccountID         string
+	PropertyAccountID string
+	FromAccountID     string
+	Recurrence        date.Cron
+	ValuationDate     date.Date
+	RevaluationYears  int64
+}
+
+func (a AmortizationRequirement) GetValuationDateString() string {
+	if a.ValuationDate.IsZero() {
+		return ""
+	}
+	return a.ValuationDate.String()
+}
+
+func (a AmortizationRequirement) GetRevaluationYearsString() string {
+	return strconv.FormatInt(a.RevaluationYears, 10)
+}
+
+// Revaluations returns the revaluation dates after the valuation date, up to until.
+func (a AmortizationRequirement) Revaluations(until date.Date) []date.Date {
+	if a.ValuationDate.IsZero() || a.RevaluationYears <= 0 {
+		return nil
+	}
+	var dates []date.Date
+	for k := 1; ; k++ {
+		d := addYears(a.ValuationDate, k*int(a.RevaluationYears))
+		if d.After(until) {
+			return dates
+		}
+		dates = append(dates, d)
+	}
+}
+
+// AmortizationEstimate is the amortization requirement given today's balances.
+type AmortizationEstimate struct {
+	swe.AmortizationInput
+	Rate    float64
+	Yearly  float64
+	Monthly float64
+}
+
+func (e AmortizationEstimate) GetLoanToValueString() string {
+	return fmt.Sprintf("%.1f%%", e.LoanToValue()*100)
+}
+
+func (e AmortizationEstimate) GetDebtToIncomeString() string {
+	if e.GrossYearlyIncome <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", e.DebtToIncome())
+}
+
+func (e AmortizationEstimate) GetRateString() string {
+	return fmt.Sprintf("%.0f%%", e.Rate*100)
+}
+
+func (e AmortizationEstimate) GetMonthlyString() string {
+	return ui.FormatWithThousands(e.Monthly)
+}
+
+func amortizationRequirementFromDB(a pdb.AmortizationRequirement) AmortizationRequirement {
+	return AmortizationRequirement{
+		AccountID:         a.AccountID,
+		PropertyAccountID: ui.OrDefault(a.PropertyAccountID),
+		FromAccountID:     ui.OrDefault(a.FromAccountID),
+		Recurrence:        date.Cron(a.Recurrence),
+		ValuationDate:     date.Date(a.ValuationDate),
+		RevaluationYears:  a.RevaluationYears,
+	}
+}
+
+func (s *Service) UpsertAmortizationRequirement(ctx context.Context, inp AmortizationRequirement) (AmortizationRequirement, error) {
+	if inp.RevaluationYears < 0 {
+		return AmortizationRequirement{}, fmt.Errorf("invalid revaluation interval: %d", inp.RevaluationYears)
+	}
+	if inp.Recurrence == "" {
+		inp.Recurrence = DefaultAmortizationRecurrence
+	}
+	if _, err := s.q.GetCsnLoan(ctx, inp.AccountID); err == nil {
+		return AmortizationRequirement{}, fmt.Errorf("account %s is a csn loan, which is amortized by its annuity", inp.AccountID)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return AmortizationRequirement{}, fmt.Errorf("getting csn loan: %w", err)
+	}
+	now := time.Now().Unix()
+	a, err := s.q.UpsertAmortizationRequirement(ctx, pdb.UpsertAmortizationRequirementParams{
+		AccountID:         inp.AccountID,
+		PropertyAccountID: ui.WithDefaultNull(inp.PropertyAccountID),
+		FromAccountID:     ui.WithDefaultNull(inp.FromAccountID),
+		Recurrence:        string(inp.Recurrence),
+		ValuationDate:     int64(inp.ValuationDate),
+		RevaluationYears:  inp.RevaluationYears,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	})
+	if err != nil {
+		return AmortizationRequirement{}, fmt.Errorf("upserting amortization requirement: %w", err)
+	}
+	s.invalidateForecast()
+	return amortizationRequirementFromDB(a), nil
+}
+
+func (s *Service) GetAmortizationRequirement(ctx context.Context, accountID string) (AmortizationRequirement, error) {
+	a, err := s.q.GetAmortizationRequirement(ctx, accountID)
+	if err != nil {
+		return AmortizationRequirement{}, err
+	}
+	return amortizationRequirementFromDB(a), nil
+}
+
+func (s *Service) ListAmortizationRequirements(ctx context.Context) ([]AmortizationRequirement, error) {
+	rows, err := s.q.ListAmortizationRequirements(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing amortization requirements: %w", err)
+	}
+	reqs := make([]AmortizationRequirement, len(rows))
+	for i, r := range rows {
+		reqs[i] = amortizationRequirementFromDB(r)
+	}
+	return reqs, nil
+}
+
+func (s *Service) DeleteAmortizationRequirement(ctx context.Context, accountID string) error {
+	if err := s.q.DeleteAmortizationRequirement(ctx, accountID); err != nil {
+		return fmt.Errorf("deleting amortization requirement: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// grossIncomeFunc returns the gross yearly income at a date, summed over the
+// given salaries, or over all enabled gross salaries when none are given.
+func (s *Service) grossIncomeFunc(ctx context.Context, salaryIDs ...string) (func(date.Date) float64, error) {
+	salaries, err := s.ListSalaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var amounts [][]SalaryAmount
+	for _, sal := range salaries {
+		if !sal.Enabled {
+			continue
+		}
+		if len(salaryIDs) == 0 && !sal.IsGross || len(salaryIDs) > 0 && !slices.Contains(salaryIDs, sal.ID) {
+			continue
+		}
+		as, err := s.ListSalaryAmounts(ctx, sal.ID)
+		if err != nil {
+			return nil, fmt.Errorf("listing salary amounts: %w", err)
+		}
+		amounts = append(amounts, as)
+	}
+	return func(d date.Date) float64 {
+		var total float64
+		for _, as := range amounts {
+			if a := activeSalaryAmountAt(as, d); a != nil {
+				total += a.Mean() * 12
+			}
+		}
+		return total
+	}, nil
+}
+
+// amortizationInput gathers the amorteringskrav figures for a loan. Loans
+// sharing a property count towards its loan-to-value, and all loans with an
+// amortization requirement count towards the debt-to-income.
+func amortizationInput(req AmortizationRequirement, reqs []AmortizationRequirement, balance func(id string) float64, income float64) swe.AmortizationInput {
+	in := swe.AmortizationInput{GrossYearlyIncome: income}
+	if req.PropertyAccountID != "" {
+		in.PropertyValue = balance(req.PropertyAccountID)
+	}
+	for _, r := range reqs {
+		debt := max(0, -balance(r.AccountID))
+		in.TotalDebt += debt
+		if r.AccountID == req.AccountID || (req.PropertyAccountID != "" && r.PropertyAccountID == req.PropertyAccountID) {
+			in.PropertyLoans += debt
+		}
+	}
+	return in
+}
+
+// amortizationToFinance builds the finance amortization model for a loan.
+func amortizationToFinance(req AmortizationRequirement, reqs []AmortizationRequirement, incomeAt func(date.Date) float64, until date.Date) *finance.AmortizationModel {
+	return &finance.AmortizationModel{
+		Frequency:    req.Recurrence,
+		SourceID:     req.FromAccountID,
+		Revaluations: req.Revaluations(until),
+		YearlyAmount: func(day date.Date, balance func(id string) float64) float64 {
+			in := amortizationInput(req, reqs, balance, incomeAt(day))
+			return swe.RequiredAmortizationRate(in) * max(0, -balance(req.AccountID))
+		},
+	}
+}
+
+// EstimateAmortization returns the amortization requirement for a loan
+// given the latest snapshot balances, in the default currency at the spot
+// rate, and today's salaries.
+func (s *Service) EstimateAmortization(ctx context.Context, req AmortizationRequirement) (AmortizationEstimate, error) {
+	reqs, err := s.ListAmortizationRequirements(ctx)
+	if err != nil {
+		return AmortizationEstimate{}, err
+	}
+	fx, err := s.fxRates(ctx)
+	if err != nil {
+		return AmortizationEstimate{}, fmt.Errorf("getting fx rates: %w", err)
+	}
+	balances := make(map[string]float64)
+	for _, r := range reqs {
+		for _, id := range []string{r.AccountID, r.PropertyAccountID} {
+			if _, ok := balances[id]; ok || id == "" {
+				continue
+			}
+			acc, err := s.GetAccount(ctx, id)
+			if err != nil {
+				return AmortizationEstimate{}, fmt.Errorf("getting account: %w", err)
+			}
+			snaps, err := s.ListAccountSnapshots(ctx, id)
+			if err != nil {
+				return AmortizationEstimate{}, fmt.Errorf("listing snapshots: %w", err)
+			}
+			var latest date.Date
+			for _, snap := range snaps {
+				if snap.Date >= latest {
+					latest = snap.Date
+					balances[id] = fx.spot(acc.Currency, snap.Balance.Mean())
+				}
+			}
+		}
+	}
+	incomeAt, err := s.grossIncomeFunc(ctx)
+	if err != nil {
+		return AmortizationEstimate{}, fmt.Errorf("getting gross income: %w", err)
+	}
+	balance := func(id string) float64 { return balances[id] }
+	in := amortizationInput(req, reqs, balance, incomeAt(date.Today()))
+	rate := swe.RequiredAmortizationRate(in)
+	yearly := rate * max(0, -balance(req.AccountID))
+	return AmortizationEstimate{
+		AmortizationInput: in,
+		Rate:              rate,
+		Yearly:            yearly,
+		Monthly:           yearly / 12,
+	}, nil
+}
@@ -86,7 +86,7 @@ func BenchmarkGetDashboardData(b *testing.B) {
 	ctx := context.Background()
 	b.ResetTimer()
 	for b.Loop() {
-		_, err := svc.GetDashboardData(ctx)
+		_, err := svc.GetDashboardData(ctx, "")
 		if err != nil {
 			b.Fatal(err)
 		}
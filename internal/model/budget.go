@@ -38,9 +38,13 @@ type BudgetView struct {
 	Categories []BudgetCategoryGroup
 	GrandTotal float64
 	ChartData  []BudgetChartEntry
+	PersonID   string
+	Persons    []Person
 }
 
-func (s *Service) GetBudgetData(ctx context.Context) (*BudgetView, error) {
+// GetBudgetData returns the monthly budget of the household, or of a single
+// person's share of it when personID is set.
+func (s *Service) GetBudgetData(ctx context.Context, personID string) (*BudgetView, error) {
 	today := date.Today()
 
 	allTemplates, err := s.ListAllTransferTemplates(ctx)
@@ -63,6 +67,23 @@ func (s *Service) GetBudgetData(ctx context.Context) (*BudgetView, error) {
 		categoriesByID[c.ID] = c
 	}
 
+	ownership, err := s.listOwnership(ctx)
+	if err != nil {
+		return nil, err
+	}
+	salaries, err := s.ListSalaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing salaries: %w", err)
+	}
+	salaryOwners := make(map[string]string, len(salaries))
+	for _, sal := range salaries {
+		salaryOwners[sal.ID] = sal.OwnerID
+	}
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	groupMap := make(map[string]*BudgetCategoryGroup)
 
 	for _, t := range templatesWithAmount {
@@ -72,7 +93,7 @@ func (s *Service) GetBudgetData(ctx context.Context) (*BudgetView, error) {
 		if !strings.Contains(string(t.Recurrence), "*") {
 			continue
 		}
-		amount := math.Abs(t.Amount)
+		amount := math.Abs(t.Amount) * templateShare(t.TransferTemplate, ownership, salaryOwners, personID)
 		if amount == 0 {
 			continue
 		}
@@ -95,7 +116,7 @@ func (s *Service) GetBudgetData(ctx context.Context) (*BudgetView, error) {
 			balance = acc.LastSnapshot.Balance.Mean()
 		}
 		annualRate := acc.GrowthModel.AnnualRate.Mean()
-		monthlyInterest := math.Abs(balance * annualRate / 12.0)
+		monthlyInterest := math.Abs(balance*annualRate/12.0) * ownership.Share(acc.ID, personID)
 		if monthlyInterest == 0 {
 			continue
 		}
@@ -148,9 +169,32 @@ func (s *Service) GetBudgetData(ctx context.Context) (*BudgetView, error) {
 		Categories: categories,
 		GrandTotal: grandTotal,
 		ChartData:  chartEntries,
+		PersonID:   personID,
+		Persons:    persons,
 	}, nil
 }
 
+// templateShare returns the share of a transfer that falls on personID. Salary
+// transfers belong to the salary's owner; other transfers are split like the
+// account they are paid from, or paid into when they have no source account.
+func templateShare(t TransferTemplate, ownership Ownership, salaryOwners map[string]string, personID string) float64 {
+	if personID == "" {
+		return 1
+	}
+	if t.Source.Type == "salary" {
+		if owner := salaryOwners[t.Source.EntityID]; owner != "" {
+			if owner == personID {
+				return 1
+			}
+			return 0
+		}
+	}
+	if t.FromAccountID != "" {
+		return ownership.Share(t.FromAccountID, personID)
+	}
+	return ownership.Share(t.ToAccountID, personID)
+}
+
 func getOrCreateGroup(groupMap map[string]*BudgetCategoryGroup, catID string, categoriesByID map[string]TransferTemplateCategory) *BudgetCategoryGroup {
 	group, exists := groupMap[catID]
 	if !exists {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/SimonSchneider/pefigo/internal/pdb"
@@ -37,6 +38,22 @@ func (c *SQLiteCache) Set(ctx context.Context, key string, value string) error {
 	})
 }
 
+// List returns all entries whose key starts with prefix.
+func (c *SQLiteCache) List(ctx context.Context, prefix string) (map[string]string, error) {
+	rows, err := pdb.New(c.db).ListCacheEntriesByPrefix(ctx, prefix+"%")
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]string, len(rows))
+	for _, r := range rows {
+		// LIKE treats '_' as a wildcard, so check the prefix exactly.
+		if strings.HasPrefix(r.CacheKey, prefix) {
+			entries[r.CacheKey] = r.Value
+		}
+	}
+	return entries, nil
+}
+
 // TTLSQLiteCache implements currency.Cache with TTL-aware reads.
 type TTLSQLiteCache struct {
 	db *sql.DB
@@ -2,6 +2,8 @@ package model
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"slices"
 	"sort"
@@ -21,6 +23,8 @@ type PredictionParams struct {
 	Quantile         float64
 	SnapshotInterval date.Cron
 	GroupBy          GroupBy
+	// PersonID limits the prediction to the person's share of each account.
+	PersonID string
 }
 
 type GroupBy string
@@ -29,6 +33,7 @@ const (
 	GroupByNone  GroupBy = "none"
 	GroupByType  GroupBy = "type"
 	GroupByTotal GroupBy = "total"
+	GroupByOwner GroupBy = "owner"
 )
 
 func ParseGroupBy(val string) (GroupBy, error) {
@@ -39,6 +44,8 @@ func ParseGroupBy(val string) (GroupBy, error) {
 		return GroupByType, nil
 	case "total":
 		return GroupByTotal, nil
+	case "owner":
+		return GroupByOwner, nil
 	default:
 		return GroupByNone, fmt.Errorf("invalid group by: %s", val)
 	}
@@ -103,6 +110,72 @@ func (s *Service) RunPrediction(ctx context.Context, eventHandler PredictionEven
 	if err != nil {
 		return fmt.Errorf("listing swe yearly params for Prediction: %w", err)
 	}
+	pensionPayouts, err := s.ListPensionPayouts(ctx)
+	if err != nil {
+		return fmt.Errorf("listing pension payouts for Prediction: %w", err)
+	}
+	pensionPayoutsByAccount := KeyBy(pensionPayouts, func(p PensionPayout) string { return p.AccountID })
+	interestDeductions, err := s.ListInterestDeductions(ctx)
+	if err != nil {
+		return fmt.Errorf("listing interest deductions for Prediction: %w", err)
+	}
+	deductionsByLoan, deductionsByRefundAccount := interestDeductionsToFinance(interestDeductions)
+	rateModels, err := s.ListInterestRateModels(ctx)
+	if err != nil {
+		return fmt.Errorf("listing interest rate models for Prediction: %w", err)
+	}
+	rates := interestRateProcesses(rateModels)
+	fx, err := s.fxRates(ctx)
+	if err != nil {
+		return fmt.Errorf("setting up fx rates for Prediction: %w", err)
+	}
+	ownership, err := s.listOwnership(ctx)
+	if err != nil {
+		return fmt.Errorf("listing ownership for Prediction: %w", err)
+	}
+	persons, err := s.listPersonsByID(ctx)
+	if err != nil {
+		return fmt.Errorf("listing persons for Prediction: %w", err)
+	}
+	taxReturns, err := s.taxReturnsToFinance(ctx, persons)
+	if err != nil {
+		return fmt.Errorf("setting up tax returns for Prediction: %w", err)
+	}
+	taxReturnsByAccount := make(map[string][]finance2.TaxModel)
+	for id, r := range taxReturns {
+		taxReturnsByAccount[persons[id].TaxAccountID] = append(taxReturnsByAccount[persons[id].TaxAccountID], r)
+	}
+	// declaredShare is the part of an account owned by persons declaring its
+	// capital income and gains in their own tax returns.
+	declaredShare := func(accountID string) float64 {
+		var declared float64
+		for personID, share := range ownership.Owners(accountID) {
+			if _, ok := taxReturns[personID]; ok {
+				declared += share
+			}
+		}
+		return declared
+	}
+	amortizations, err := s.ListAmortizationRequirements(ctx)
+	if err != nil {
+		return fmt.Errorf("listing amortization requirements for Prediction: %w", err)
+	}
+	amortizationsByAccount := KeyBy(amortizations, func(a AmortizationRequirement) string { return a.AccountID })
+	grossIncomeAt, err := s.grossIncomeFunc(ctx)
+	if err != nil {
+		return fmt.Errorf("getting gross income for Prediction: %w", err)
+	}
+	csnLoans, err := s.ListCSNLoans(ctx)
+	if err != nil {
+		return fmt.Errorf("listing csn loans for Prediction: %w", err)
+	}
+	csnLoansByAccount := KeyBy(csnLoans, func(l CSNLoan) string { return l.AccountID })
+	csnIncomeByAccount := make(map[string]func(date.Date) float64, len(csnLoans))
+	for _, l := range csnLoans {
+		if csnIncomeByAccount[l.AccountID], err = s.csnIncomeFunc(ctx, l); err != nil {
+			return fmt.Errorf("building csn loan for account %s: %w", l.AccountID, err)
+		}
+	}
 	specialDates = append(specialDates, SpecialDate{
 		ID:   "today",
 		Name: "Today",
@@ -157,20 +230,39 @@ func (s *Service) RunPrediction(ctx context.Context, eventHandler PredictionEven
 			if err != nil {
 				return fmt.Errorf("listing startup share options for account %s: %w", acc.ID, err)
 			}
+			projected, err := s.ListProjectedInvestmentRounds(ctx, acc.ID)
+			if err != nil {
+				return fmt.Errorf("listing projected investment rounds for account %s: %w", acc.ID, err)
+			}
+			var exit *StartupExit
+			if e, err := s.GetStartupExit(ctx, acc.ID); err == nil {
+				exit = &e
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("getting startup exit for account %s: %w", acc.ID, err)
+			}
 			slices.Reverse(rounds)
 
-			ssResult := buildStartupShareForecastState(ucfg, rounds, shareChanges, opts, ssa, startDate)
+			ssResult := buildStartupShareForecastState(ucfg, rounds, shareChanges, opts, projected, exit, ssa, startDate)
 
+			ssResult.GrowthModel.DeclaredGainShare = declaredShare(acc.ID)
+			// Startup shares are valued in the default currency, so the
+			// entity has no FX process.
 			entity.GrowthModel = ssResult.GrowthModel
 			entity.Snapshots = ssResult.Snapshots
 		} else {
+			entity.FX = fx.process(acc.Currency)
 			for _, snap := range snaps {
 				entity.Snapshots = append(entity.Snapshots, snap.ToFinance())
 				if snap.Date.After(startDate) {
 					startDate = snap.Date
 				}
 			}
-			entity.GrowthModel = GrowthModels(gms).ToFinance()
+			entity.GrowthModel = GrowthModels(gms).ToFinance(rates)
+			if _, ok := csnLoansByAccount[acc.ID]; ok {
+				if g := csnInterestGrowth(sweParams); g != nil {
+					entity.GrowthModel = g
+				}
+			}
 		}
 		if acc.IsIsk != 0 {
 			entity.TaxModel = &swe.ISKTax{
@@ -180,6 +272,31 @@ func (s *Service) RunPrediction(ctx context.Context, eventHandler PredictionEven
 						Fribelopp:     activeISKFribeloppAt(sweParams, d),
 					}
 				},
+				Recorder: swe.ISKTaxRecorderFunc(func(year int, tax uncertain.Value) {
+					for personID, share := range ownership.Owners(acc.ID) {
+						if r, ok := taxReturns[personID]; ok {
+							r.AddISKTax(ucfg, year, scaleByShare(ucfg, tax, share))
+						}
+					}
+				}),
+			}
+		}
+		deductions, hasDeductions := deductionsByRefundAccount[acc.ID]
+		returns, hasReturns := taxReturnsByAccount[acc.ID]
+		if hasDeductions || hasReturns {
+			taxModels := finance2.TaxModels{}
+			if entity.TaxModel != nil {
+				taxModels = append(taxModels, entity.TaxModel)
+			}
+			for _, d := range deductions {
+				taxModels = append(taxModels, d)
+			}
+			entity.TaxModel = append(taxModels, returns...)
+		}
+		if payout, ok := pensionPayoutsByAccount[acc.ID]; ok {
+			entity.Payout, err = s.pensionPayoutToFinance(ctx, payout)
+			if err != nil {
+				return fmt.Errorf("building pension payout for account %s: %w", acc.ID, err)
 			}
 		}
 		if len(entity.Snapshots) > 0 {
@@ -187,17 +304,30 @@ func (s *Service) RunPrediction(ctx context.Context, eventHandler PredictionEven
 		}
 	}
 	for _, t := range trans {
-		transfers = append(transfers, t.ToFinanceTransferTemplate())
+		ft := t.ToFinanceTransferTemplate()
+		ft.FX = fx.process(transferCurrency(t, accsById))
+		transfers = append(transfers, ft)
 	}
 
 	startDate += 1
 	endDate := startDate.Add(params.Duration)
+	for i := range entities {
+		if req, ok := amortizationsByAccount[entities[i].ID]; ok {
+			entities[i].Amortization = amortizationToFinance(req, amortizations, grossIncomeAt, endDate)
+		}
+		if loan, ok := csnLoansByAccount[entities[i].ID]; ok {
+			entities[i].Amortization = csnLoanToFinance(loan, sweParams, csnIncomeByAccount[loan.AccountID], startDate, endDate)
+		}
+	}
 
 	h := &groupingEventHandler{
 		eventHandler:     eventHandler,
 		ucfg:             ucfg,
 		accsById:         accsById,
 		accountTypesById: accountTypesById,
+		ownership:        ownership,
+		persons:          persons,
+		personID:         params.PersonID,
 		groupBy:          params.GroupBy,
 		q1:               q1,
 		q2:               q2,
@@ -210,7 +340,35 @@ func (s *Service) RunPrediction(ctx context.Context, eventHandler PredictionEven
 	snapshotRecorder := finance2.SnapshotRecorderFunc(func(accountID string, day date.Date, balance uncertain.Value) error {
 		return h.snapshot(accountID, day, balance)
 	})
-	if err := finance2.RunPrediction(ctx, ucfg, startDate, endDate, params.SnapshotInterval, entities, transfers, finance2.CompositeRecorder{SnapshotRecorder: snapshotRecorder}); err != nil {
+	interestRecorder := finance2.InterestRecorderFunc(func(accountID, destinationAccountID string, day date.Date, amount uncertain.Value) error {
+		if accsById[accountID].IsIsk != 0 {
+			return nil
+		}
+		// Interest on savings and loans is capital income of the owners with
+		// a tax return, netted there with their other capital income. Only
+		// the rest of the interest on a loan gets the separate deduction.
+		for personID, share := range ownership.Owners(accountID) {
+			if r, ok := taxReturns[personID]; ok {
+				r.AddCapitalIncome(ucfg, day, scaleByShare(ucfg, amount, share))
+			}
+		}
+		if d, ok := deductionsByLoan[accountID]; ok {
+			if rest := 1 - declaredShare(accountID); rest > 0 {
+				return d.OnInterest(accountID, destinationAccountID, day, scaleByShare(ucfg, amount, rest))
+			}
+		}
+		return nil
+	})
+	gainRecorder := finance2.CapitalGainRecorderFunc(func(accountID string, day date.Date, gain uncertain.Value) error {
+		for personID, share := range ownership.Owners(accountID) {
+			if r, ok := taxReturns[personID]; ok {
+				r.AddCapitalGain(ucfg, day, scaleByShare(ucfg, gain, share))
+			}
+		}
+		return nil
+	})
+	recorder := finance2.CompositeRecorder{SnapshotRecorder: snapshotRecorder, InterestRecorder: interestRecorder, CapitalGainRecorder: gainRecorder}
+	if err := finance2.RunPrediction(ctx, ucfg, startDate, endDate, params.SnapshotInterval, entities, transfers, recorder); err != nil {
 		return fmt.Errorf("running prediction for SSE: %w", err)
 	}
 	return h.close()
@@ -226,6 +384,8 @@ func buildStartupShareForecastState(
 	rounds []InvestmentRound,
 	shareChanges []ShareChange,
 	opts []StartupShareOption,
+	projected []ProjectedInvestmentRound,
+	exit *StartupExit,
 	ssa StartupShareAccount,
 	startDate date.Date,
 ) startupShareForecastState {
@@ -243,9 +403,12 @@ func buildStartupShareForecastState(
 	investmentRounds := make(map[date.Date]finance2.StartupGrowthInvestmentRound)
 	for _, round := range rounds {
 		investmentRounds[round.Date] = finance2.StartupGrowthInvestmentRound{
-			PreMoneyValuation: uncertain.NewFixed(round.Valuation),
-			PreMoneyShares:    uncertain.NewFixed(round.PreMoneyShares),
-			Investment:        uncertain.NewFixed(round.Investment),
+			PreMoneyValuation:  uncertain.NewFixed(round.Valuation),
+			PreMoneyShares:     uncertain.NewFixed(round.PreMoneyShares),
+			Investment:         uncertain.NewFixed(round.Investment),
+			PreferenceMultiple: round.LiquidationPreference,
+			Participating:      round.Participating,
+			Seniority:          int(round.Seniority),
 		}
 	}
 
@@ -259,12 +422,38 @@ func buildStartupShareForecastState(
 		}
 	}
 
-	options := make(map[date.Date]finance2.StartupGrowthOption)
+	// Options vested before the start are taken as exercised under the
+	// vesting policy, since the shares bought are recorded as share changes.
+	options := make([]finance2.StartupGrowthOption, 0, len(opts))
 	for _, opt := range opts {
-		options[opt.EndDate] = finance2.StartupGrowthOption{
-			StrikePricePerShare: uncertain.NewFixed(opt.StrikePricePerShare),
-			NumShares:           uncertain.NewFixed(opt.Shares),
-			SourceAccountID:     opt.SourceAccountID,
+		if opt.EndDate < startDate {
+			continue
+		}
+		o := opt.toFinance()
+		if o.ExercisePolicy == finance2.StartupOptionExerciseAtVesting {
+			o.Exercised = uncertain.NewFixed(opt.VestedShares(startDate))
+		}
+		options = append(options, o)
+	}
+
+	projectedRounds := make(map[date.Date]finance2.StartupGrowthProjectedRound)
+	for _, round := range projected {
+		if round.Date >= startDate {
+			projectedRounds[round.Date] = finance2.StartupGrowthProjectedRound{
+				Probability:       round.Probability,
+				PreMoneyValuation: round.PreMoneyValuation,
+				Investment:        round.Investment,
+			}
+		}
+	}
+
+	exits := make(map[date.Date]finance2.StartupGrowthExit)
+	if exit != nil && exit.Date >= startDate {
+		exits[exit.Date] = finance2.StartupGrowthExit{
+			Kind:               exit.Kind,
+			Multiple:           exit.Multiple,
+			FailureProbability: exit.FailureProbability,
+			PayoutAccountID:    exit.PayoutAccountID,
 		}
 	}
 
@@ -278,14 +467,20 @@ func buildStartupShareForecastState(
 		Valuation:   currentValuation,
 
 		TaxRate:               uncertain.NewFixed(ssa.TaxRate),
+		MarginalIncomeTaxRate: uncertain.NewFixed(ssa.MarginalIncomeTaxRate),
 		DiscountFactor:        uncertain.NewFixed(ssa.ValuationDiscountFactor),
 		PurchasePricePerShare: uncertain.NewFixed(avgPurchaseAtStart),
 
 		InvestmentRounds: investmentRounds,
 		ShareChanges:     shareChangesMap,
 		Options:          options,
+		ProjectedRounds:  projectedRounds,
+		Exits:            exits,
+		Preferences:      liquidationPreferences(rounds, startDate),
 	}
 
+	growthModel.VestOptions(startDate)
+
 	eventDates := make(map[date.Date]struct{})
 	for _, round := range rounds {
 		if round.Date < startDate {
@@ -330,6 +525,7 @@ func buildStartupShareForecastState(
 			ssa.TaxRate,
 			postMoneyShares,
 			ssa.ValuationDiscountFactor,
+			liquidationPreferences(rounds, eventDate),
 		)
 		snapshots = append(snapshots, finance2.BalanceSnapshot{
 			Date:    eventDate,
@@ -355,6 +551,9 @@ type groupingEventHandler struct {
 	ucfg             *uncertain.Config
 	accsById         map[string]pdb.Account
 	accountTypesById map[string]pdb.AccountType
+	ownership        Ownership
+	persons          Persons
+	personID         string
 	groupBy          GroupBy
 	q1               float64
 	q2               float64
@@ -403,21 +602,42 @@ func (h *groupingEventHandler) setup(entities []finance2.Entity, endDate date.Da
 				dates: make(map[date.Date]uncertain.Value),
 			}
 		}
+	case GroupByOwner:
+		groupedEntities[""] = GroupedEntities{
+			name:  "Shared",
+			color: "",
+			dates: make(map[date.Date]uncertain.Value),
+		}
+		for _, p := range h.persons {
+			groupedEntities[p.ID] = GroupedEntities{
+				name:  p.Name,
+				color: "",
+				dates: make(map[date.Date]uncertain.Value),
+			}
+		}
 	}
 
 	for _, e := range entities {
-		key := h.getKey(e.ID)
-
-		ent := groupedEntities[key]
-
-		for _, s := range e.Snapshots {
-			amount := ent.dates[s.Date]
-			if amount.Zero() {
-				amount = s.Balance
-			} else {
-				amount = amount.Add(h.ucfg, s.Balance)
+		for _, k := range h.getKeys(e.ID) {
+			ent, ok := groupedEntities[k.key]
+			if !ok {
+				continue
+			}
+			// Balances before the forecast are converted at the current rate.
+			share := k.share
+			if e.FX != nil {
+				share *= e.FX.InitialRate
+			}
+			for _, s := range e.Snapshots {
+				balance := scaleByShare(h.ucfg, s.Balance, share)
+				amount := ent.dates[s.Date]
+				if amount.Zero() {
+					amount = balance
+				} else {
+					amount = amount.Add(h.ucfg, balance)
+				}
+				ent.dates[s.Date] = amount
 			}
-			ent.dates[s.Date] = amount
 		}
 	}
 
@@ -461,14 +681,35 @@ func (h *groupingEventHandler) setup(entities []finance2.Entity, endDate date.Da
 	})
 }
 
-func (h *groupingEventHandler) getKey(id string) string {
+type groupKey struct {
+	key   string
+	share float64
+}
+
+// getKeys returns the groups an account's balance is added to, with the share
+// of the balance each group gets. Accounts the selected person does not own
+// are left out.
+func (h *groupingEventHandler) getKeys(id string) []groupKey {
+	if h.groupBy == GroupByOwner {
+		keys := make([]groupKey, 0, 2)
+		for personID, share := range h.ownership.Owners(id) {
+			if h.personID == "" || personID == h.personID {
+				keys = append(keys, groupKey{key: personID, share: share})
+			}
+		}
+		return keys
+	}
+	share := h.ownership.Share(id, h.personID)
+	if share == 0 {
+		return nil
+	}
 	switch h.groupBy {
 	case GroupByType:
-		return h.accountTypesById[ui.OrDefault(h.accsById[id].TypeID)].ID
+		return []groupKey{{key: h.accountTypesById[ui.OrDefault(h.accsById[id].TypeID)].ID, share: share}}
 	case GroupByNone:
-		return id
+		return []groupKey{{key: id, share: share}}
 	case GroupByTotal:
-		return "total"
+		return []groupKey{{key: "total", share: share}}
 	default:
 		panic("invalid group by")
 	}
@@ -482,12 +723,14 @@ func (h *groupingEventHandler) snapshot(id string, day date.Date, balance uncert
 		h.currentDate = day
 	}
 
-	key := h.getKey(id)
-	acc, ok := h.currentAccs[key]
-	if !ok {
-		h.currentAccs[key] = balance
-	} else {
-		h.currentAccs[key] = acc.Add(h.ucfg, balance)
+	for _, k := range h.getKeys(id) {
+		owned := scaleByShare(h.ucfg, balance, k.share)
+		acc, ok := h.currentAccs[k.key]
+		if !ok {
+			h.currentAccs[k.key] = owned
+		} else {
+			h.currentAccs[k.key] = acc.Add(h.ucfg, owned)
+		}
 	}
 	return nil
 }
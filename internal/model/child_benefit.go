@@ -0,0 +1,103 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+// childBenefitRecurrence is the day Försäkringskassan pays barnbidrag.
+const childBenefitRecurrence = date.Cron("*-*-20")
+
+// GenerateChildBenefitTransferTemplates returns read-only templates paying the
+// household's barnbidrag and flerbarnstillägg into accountID. There is one
+// template per period with the same number of eligible children. Children
+// without a birth date are left out.
+func GenerateChildBenefitTransferTemplates(children []Person, accountID string) []TransferTemplate {
+	if accountID == "" {
+		return nil
+	}
+	type period struct{ start, end date.Date }
+	periods := make([]period, 0, len(children))
+	var changes []date.Date
+	for _, c := range children {
+		if !c.IsChild || c.BirthDate.IsZero() {
+			continue
+		}
+		start, end := swe.BarnbidragPeriod(c.BirthDate)
+		periods = append(periods, period{start, end})
+		changes = append(changes, start, end)
+	}
+	slices.Sort(changes)
+	changes = slices.Compact(changes)
+
+	source := TransferTemplateSource{
+		Type:     "child_benefit",
+		EntityID: accountID,
+		Label:    "Barnbidrag",
+		EditURL:  "/settings?tab=household",
+	}
+	var templates []TransferTemplate
+	for i := 0; i+1 < len(changes); i++ {
+		start, end := changes[i], changes[i+1]
+		n := 0
+		for _, p := range periods {
+			if p.start <= start && end <= p.end {
+				n++
+			}
+		}
+		if n == 0 {
+			continue
+		}
+		templates = append(templates, TransferTemplate{
+			ID:          fmt.Sprintf("child_benefit:%d", i),
+			Name:        fmt.Sprintf("Barnbidrag (%d children)", n),
+			ToAccountID: accountID,
+			AmountType:  "fixed",
+			AmountFixed: uncertain.NewFixed(swe.ChildBenefitMonthly(n)),
+			Recurrence:  childBenefitRecurrence,
+			StartDate:   start,
+			EndDate:     ptr(end),
+			Enabled:     true,
+			Source:      source,
+		})
+	}
+	return templates
+}
+
+// ChildBenefitOn returns the household's monthly barnbidrag on day.
+func ChildBenefitOn(persons []Person, day date.Date) float64 {
+	n := 0
+	for _, p := range persons {
+		if !p.IsChild || p.BirthDate.IsZero() {
+			continue
+		}
+		if start, end := swe.BarnbidragPeriod(p.BirthDate); start <= day && day < end {
+			n++
+		}
+	}
+	return swe.ChildBenefitMonthly(n)
+}
+
+func (s *Service) generateChildBenefitTransferTemplates(ctx context.Context) ([]TransferTemplate, error) {
+	accountID, err := s.GetChildBenefitAccount(ctx)
+	if err != nil || accountID == "" {
+		return nil, err
+	}
+	accounts, err := s.ListAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+	if !slices.ContainsFunc(accounts, func(a Account) bool { return a.ID == accountID }) {
+		return nil, nil
+	}
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return GenerateChildBenefitTransferTemplates(persons, accountID), nil
+}
@@ -0,0 +1,637 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/goslu/sid"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/ui"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+// companyCashFlowRecurrence is when the monthly revenue and expenses of a
+// company are booked, and companyTaxRecurrence when its preliminary corporate
+// tax is paid.
+const (
+	companyCashFlowRecurrence = date.Cron("*-*-01")
+	companyTaxRecurrence      = date.Cron("*-*-12")
+)
+
+// Company is an aktiebolag owned by a member of the household, a fåmansbolag
+// whose dividends are taxed under the 3:12 rules. The monthly Revenue and
+// Expenses flow through AccountID, which also pays the owner's salary
+// (SalaryID) with arbetsgivaravgifter and the corporate tax. The profit left
+// after tax is paid out yearly on DividendDate into DividendAccountID, up to
+// the owner's gränsbelopp.
+type Company struct {
+	ID                 string
+	Name               string
+	OwnerID            string
+	OwnershipShare     float64
+	AccountID          string
+	DividendAccountID  string
+	SalaryID           string
+	Revenue            uncertain.Value
+	Expenses           uncertain.Value
+	GransbeloppRule    swe.GransbeloppRule
+	AcquisitionCost    float64
+	CapitalRate        float64
+	SavedDividendSpace float64
+	DividendDate       date.Cron
+	StartDate          date.Date
+}
+
+// CompanyPlanYear compares the current salary and dividend of a company's
+// owner with the split giving them the most for a year.
+type CompanyPlanYear struct {
+	Year    int
+	Current swe.OwnerSplit
+	Optimal swe.OwnerSplit
+}
+
+func (c Company) source() TransferTemplateSource {
+	return TransferTemplateSource{
+		Type:     "company",
+		EntityID: c.ID,
+		Label:    c.Name,
+		EditURL:  "/companies/" + c.ID + "/edit",
+	}
+}
+
+func (c Company) share() float64 {
+	if c.OwnershipShare <= 0 {
+		return 1
+	}
+	return c.OwnershipShare
+}
+
+func (c Company) GetRevenueString() string {
+	if c.ID == "" {
+		return ""
+	}
+	return c.Revenue.SimpleEncode()
+}
+
+func (c Company) GetExpensesString() string {
+	if c.ID == "" {
+		return ""
+	}
+	return c.Expenses.SimpleEncode()
+}
+
+func (c Company) GetOwnershipShareString() string {
+	return strconv.FormatFloat(c.share()*100, 'f', -1, 64)
+}
+
+func (c Company) GetCapitalRateString() string {
+	return strconv.FormatFloat(c.CapitalRate*100, 'f', -1, 64)
+}
+
+func (c Company) GetAcquisitionCostString() string {
+	return strconv.FormatFloat(c.AcquisitionCost, 'f', -1, 64)
+}
+
+func (c Company) GetSavedDividendSpaceString() string {
+	return strconv.FormatFloat(c.SavedDividendSpace, 'f', -1, 64)
+}
+
+func (c Company) GetStartDateString() string {
+	if c.ID == "" {
+		return ""
+	}
+	return c.StartDate.String()
+}
+
+func decodeCompanyAmount(encoded string) (uncertain.Value, error) {
+	if encoded == "" {
+		return uncertain.NewFixed(0), nil
+	}
+	return uncertain.Decode(encoded)
+}
+
+func companyFromDB(c pdb.Company) (Company, error) {
+	revenue, err := decodeCompanyAmount(c.Revenue)
+	if err != nil {
+		return Company{}, fmt.Errorf("decoding company revenue: %w", err)
+	}
+	expenses, err := decodeCompanyAmount(c.Expenses)
+	if err != nil {
+		return Company{}, fmt.Errorf("decoding company expenses: %w", err)
+	}
+	return Company{
+		ID:                 c.ID,
+		Name:               c.Name,
+		OwnerID:            ui.OrDefault(c.OwnerID),
+		OwnershipShare:     c.OwnershipShare,
+		AccountID:          c.AccountID,
+		DividendAccountID:  ui.OrDefault(c.DividendAccountID),
+		SalaryID:           ui.OrDefault(c.SalaryID),
+		Revenue:            revenue,
+		Expenses:           expenses,
+		GransbeloppRule:    swe.GransbeloppRule(c.GransbeloppRule),
+		AcquisitionCost:    c.AcquisitionCost,
+		CapitalRate:        c.CapitalRate,
+		SavedDividendSpace: c.SavedDividendSpace,
+		DividendDate:       date.Cron(c.DividendDate),
+		StartDate:          date.Date(c.StartDate),
+	}, nil
+}
+
+func (s *Service) UpsertCompany(ctx context.Context, inp Company) (Company, error) {
+	if inp.Name == "" {
+		return Company{}, fmt.Errorf("company name is required")
+	}
+	if inp.AccountID == "" {
+		return Company{}, fmt.Errorf("a company account is required")
+	}
+	if inp.OwnershipShare <= 0 || inp.OwnershipShare > 1 {
+		return Company{}, fmt.Errorf("invalid ownership share: %g", inp.OwnershipShare)
+	}
+	if inp.GransbeloppRule != swe.GransbeloppSimplified && inp.GransbeloppRule != swe.GransbeloppMain {
+		return Company{}, fmt.Errorf("invalid gränsbelopp rule: %q", inp.GransbeloppRule)
+	}
+	if inp.DividendDate == "" {
+		inp.DividendDate = "*-06-30"
+	}
+	if inp.ID == "" {
+		inp.ID = sid.MustNewString(32)
+	}
+	revenue, err := inp.Revenue.Encode()
+	if err != nil {
+		return Company{}, fmt.Errorf("encoding company revenue: %w", err)
+	}
+	expenses, err := inp.Expenses.Encode()
+	if err != nil {
+		return Company{}, fmt.Errorf("encoding company expenses: %w", err)
+	}
+	now := time.Now().Unix()
+	c, err := s.q.UpsertCompany(ctx, pdb.UpsertCompanyParams{
+		ID:                 inp.ID,
+		Name:               inp.Name,
+		OwnerID:            ui.WithDefaultNull(inp.OwnerID),
+		OwnershipShare:     inp.OwnershipShare,
+		AccountID:          inp.AccountID,
+		DividendAccountID:  ui.WithDefaultNull(inp.DividendAccountID),
+		SalaryID:           ui.WithDefaultNull(inp.SalaryID),
+		Revenue:            revenue,
+		Expenses:           expenses,
+		GransbeloppRule:    string(inp.GransbeloppRule),
+		AcquisitionCost:    inp.AcquisitionCost,
+		CapitalRate:        inp.CapitalRate,
+		SavedDividendSpace: inp.SavedDividendSpace,
+		DividendDate:       string(inp.DividendDate),
+		StartDate:          int64(inp.StartDate),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	})
+	if err != nil {
+		return Company{}, fmt.Errorf("upserting company: %w", err)
+	}
+	s.invalidateForecast()
+	return companyFromDB(c)
+}
+
+func (s *Service) GetCompany(ctx context.Context, id string) (Company, error) {
+	c, err := s.q.GetCompany(ctx, id)
+	if err != nil {
+		return Company{}, fmt.Errorf("getting company: %w", err)
+	}
+	return companyFromDB(c)
+}
+
+func (s *Service) ListCompanies(ctx context.Context) ([]Company, error) {
+	rows, err := s.q.ListCompanies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing companies: %w", err)
+	}
+	companies := make([]Company, len(rows))
+	for i, r := range rows {
+		if companies[i], err = companyFromDB(r); err != nil {
+			return nil, err
+		}
+	}
+	return companies, nil
+}
+
+func (s *Service) DeleteCompany(ctx context.Context, id string) error {
+	if err := s.q.DeleteCompany(ctx, id); err != nil {
+		return fmt.Errorf("deleting company: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// companySalary returns the owner's salary paid by the company with its
+// amounts sorted by start date, or nil when the company pays no salary.
+func (s *Service) companySalary(ctx context.Context, c Company) (*Salary, error) {
+	if c.SalaryID == "" {
+		return nil, nil
+	}
+	sal, err := s.GetSalary(ctx, c.SalaryID)
+	if err != nil {
+		return nil, fmt.Errorf("getting company salary: %w", err)
+	}
+	if !sal.Enabled {
+		return nil, nil
+	}
+	sort.Slice(sal.Amounts, func(i, j int) bool { return sal.Amounts[i].StartDate < sal.Amounts[j].StartDate })
+	return &sal, nil
+}
+
+// companyMonth is the mean monthly result of a company while the owner's
+// salary is unchanged. Corporate tax is paid on the result of the year, so a
+// month is only taxable as part of it.
+type companyMonth struct {
+	gross   float64
+	taxable float64
+}
+
+func (c Company) monthAt(sal *Salary, d date.Date) companyMonth {
+	var gross float64
+	if sal != nil {
+		if amt := activeSalaryAmountAt(sal.Amounts, d); amt != nil {
+			gross = amt.Mean()
+		}
+	}
+	return companyMonth{
+		gross:   gross,
+		taxable: c.Revenue.Mean() - c.Expenses.Mean() - gross*(1+swe.EmployerContributionRate),
+	}
+}
+
+// companyTaxYear is a calendar year of a company's corporate tax, with the
+// months of revenue and expenses, the owner's salary payments and the
+// preliminary tax payments in it.
+type companyTaxYear struct {
+	year     int
+	start    date.Date
+	end      *date.Date
+	months   int
+	payments int
+	salaries []uncertain.Value
+}
+
+// taxYears splits a company into calendar years until the owner's salary no
+// longer changes, after which the last year repeats.
+func (c Company) taxYears(sal *Salary) []companyTaxYear {
+	last := c.StartDate.Year()
+	if sal != nil {
+		for _, amt := range sal.Amounts {
+			last = max(last, amt.StartDate.Year())
+		}
+	}
+	last++
+	var years []companyTaxYear
+	for year := c.StartDate.Year(); year <= last; year++ {
+		y := companyTaxYear{year: year, start: max(januaryFirst(year), c.StartDate)}
+		if year < last {
+			end := januaryFirst(year + 1)
+			y.end = &end
+		}
+		for d := y.start; d < januaryFirst(year+1); d = d.Add(date.Day) {
+			if companyCashFlowRecurrence.Matches(d) {
+				y.months++
+			}
+			if companyTaxRecurrence.Matches(d) {
+				y.payments++
+			}
+			if sal != nil && sal.Recurrence.Matches(d) {
+				if amt := activeSalaryAmountAt(sal.Amounts, d); amt != nil {
+					y.salaries = append(y.salaries, *amt)
+				}
+			}
+		}
+		years = append(years, y)
+	}
+	return years
+}
+
+// tax returns a sample of the corporate tax on the result of the year, where
+// losses in some months offset the profit of others.
+func (y companyTaxYear) tax(cfg *uncertain.Config, revenue, expenses uncertain.Value) float64 {
+	taxable := float64(y.months) * (revenue.Sample(cfg) - expenses.Sample(cfg))
+	for _, gross := range y.salaries {
+		taxable -= gross.Sample(cfg) * (1 + swe.EmployerContributionRate)
+	}
+	return math.Max(0, taxable) * swe.CorporateTaxRate
+}
+
+func (s *Service) generateCompanyTransferTemplates(ctx context.Context) ([]TransferTemplate, error) {
+	companies, err := s.ListCompanies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(companies) == 0 {
+		return nil, nil
+	}
+	ibbs, err := s.ListSweYearlyParams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing swe yearly params: %w", err)
+	}
+	var templates []TransferTemplate
+	for _, c := range companies {
+		sal, err := s.companySalary(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, c.cashFlowTemplates(sal)...)
+		templates = append(templates, c.dividendTemplates(sal, ibbs)...)
+	}
+	return templates, nil
+}
+
+// cashFlowTemplates books the monthly revenue and expenses and the salary
+// cost between the changes in salary, and the preliminary corporate tax on
+// the result of each year spread evenly over its payments. The net salary
+// itself is paid by the salary's own templates.
+func (c Company) cashFlowTemplates(sal *Salary) []TransferTemplate {
+	source := c.source()
+	templates := []TransferTemplate{{
+		ID:          "company-revenue:" + c.ID,
+		Name:        c.Name + " (revenue)",
+		ToAccountID: c.AccountID,
+		AmountType:  "fixed",
+		AmountFixed: c.Revenue,
+		Recurrence:  companyCashFlowRecurrence,
+		StartDate:   c.StartDate,
+		Enabled:     true,
+		Source:      source,
+	}}
+	if c.Expenses.Mean() != 0 {
+		templates = append(templates, TransferTemplate{
+			ID:            "company-expenses:" + c.ID,
+			Name:          c.Name + " (expenses)",
+			FromAccountID: c.AccountID,
+			AmountType:    "fixed",
+			AmountFixed:   c.Expenses,
+			Recurrence:    companyCashFlowRecurrence,
+			StartDate:     c.StartDate,
+			Enabled:       true,
+			Source:        source,
+		})
+	}
+
+	dates := []date.Date{c.StartDate}
+	if sal != nil {
+		for _, amt := range sal.Amounts {
+			if amt.StartDate > c.StartDate {
+				dates = append(dates, amt.StartDate)
+			}
+		}
+	}
+	for i, d := range dates {
+		var endDate *date.Date
+		if i+1 < len(dates) {
+			ed := dates[i+1]
+			endDate = &ed
+		}
+		gross := uncertain.NewFixed(0)
+		if sal != nil {
+			if amt := activeSalaryAmountAt(sal.Amounts, d); amt != nil {
+				gross = *amt
+			}
+		}
+		if sal != nil && gross.Mean() != 0 {
+			templates = append(templates, TransferTemplate{
+				ID:            fmt.Sprintf("company-salary-cost:%s:%s", c.ID, d),
+				Name:          c.Name + " (lön och arbetsgivaravgifter)",
+				FromAccountID: c.AccountID,
+				AmountType:    "fixed",
+				AmountFixed: uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+					return gross.Sample(cfg) * (1 + swe.EmployerContributionRate)
+				}),
+				Recurrence: sal.Recurrence,
+				StartDate:  d,
+				EndDate:    endDate,
+				Enabled:    true,
+				Source:     source,
+			})
+		}
+	}
+	revenue, expenses := c.Revenue, c.Expenses
+	for _, y := range c.taxYears(sal) {
+		if y.payments == 0 || y.months == 0 {
+			continue
+		}
+		templates = append(templates, TransferTemplate{
+			ID:            fmt.Sprintf("company-tax:%s:%d", c.ID, y.year),
+			Name:          c.Name + " (bolagsskatt)",
+			FromAccountID: c.AccountID,
+			AmountType:    "fixed",
+			AmountFixed: uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+				return y.tax(cfg, revenue, expenses) / float64(y.payments)
+			}),
+			Recurrence: companyTaxRecurrence,
+			StartDate:  y.start,
+			EndDate:    y.end,
+			Enabled:    true,
+			Source:     source,
+		})
+	}
+	return templates
+}
+
+// dividendTemplates pays the owner's dividend each year from the profit
+// retained after corporate tax, up to their gränsbelopp, which is based on
+// the IBB and salaries of the previous year. Unused gränsbelopp is saved for
+// later years, uppräknat each year by statslåneräntan plus 3 percentage
+// points. The qualified dividend tax is withdrawn with the dividend, and
+// the other owners are paid in proportion to their shares. Years after the
+// last change in salary or IBB pay the same, so the last year recurs yearly.
+func (c Company) dividendTemplates(sal *Salary, ibbs []SweYearlyParams) []TransferTemplate {
+	if c.DividendAccountID == "" {
+		return nil
+	}
+	lastChange := c.StartDate.Year()
+	if sal != nil {
+		for _, amt := range sal.Amounts {
+			lastChange = max(lastChange, amt.StartDate.Year())
+		}
+	}
+	for _, p := range ibbs {
+		lastChange = max(lastChange, p.ValidFrom.Year())
+	}
+
+	source := c.source()
+	var templates []TransferTemplate
+	retained, saved := 0.0, c.SavedDividendSpace
+	last := lastChange + 2
+	for year := c.StartDate.Year() + 1; year <= last; year++ {
+		prevStart, prevEnd := januaryFirst(year-1), januaryFirst(year)
+		var taxable, ownerSalary float64
+		for d := max(prevStart, c.StartDate); d < prevEnd; d = d.Add(date.Day) {
+			if companyCashFlowRecurrence.Matches(d) {
+				taxable += c.monthAt(sal, d).taxable
+			}
+			if sal != nil && sal.Recurrence.Matches(d) {
+				ownerSalary += c.monthAt(sal, d).gross
+			}
+		}
+		retained += taxable - math.Max(0, taxable)*swe.CorporateTaxRate
+		if year > c.StartDate.Year()+1 {
+			saved = swe.UpliftSavedSpace(saved, activeStatslanerantaAt(ibbs, januaryFirst(year)))
+		}
+		gransbelopp := swe.CalculateGransbelopp(swe.GransbeloppInput{
+			Rule:            c.GransbeloppRule,
+			IBB:             activeIBBAt(ibbs, prevStart),
+			OwnershipShare:  c.share(),
+			AcquisitionCost: c.AcquisitionCost,
+			CapitalRate:     c.CapitalRate,
+			TotalSalaries:   ownerSalary,
+			OwnerSalary:     ownerSalary,
+			Saved:           saved,
+		})
+		dividend := math.Min(math.Max(0, retained*c.share()), gransbelopp)
+		saved = gransbelopp - dividend
+		retained -= dividend / c.share()
+		if dividend < 1 {
+			continue
+		}
+
+		payDate, ok := firstMatchInYear(c.DividendDate, year)
+		if !ok {
+			continue
+		}
+		recurrence := date.Cron(payDate.String())
+		if year == last {
+			recurrence = c.DividendDate
+		}
+		tt := func(kind, name, toAccountID string, amount float64) TransferTemplate {
+			return TransferTemplate{
+				ID:            fmt.Sprintf("company-%s:%s:%d", kind, c.ID, year),
+				Name:          c.Name + " (" + name + ")",
+				FromAccountID: c.AccountID,
+				ToAccountID:   toAccountID,
+				AmountType:    "fixed",
+				AmountFixed:   uncertain.NewFixed(amount),
+				Recurrence:    recurrence,
+				StartDate:     payDate,
+				Enabled:       true,
+				Source:        source,
+			}
+		}
+		templates = append(templates,
+			tt("dividend", "utdelning", c.DividendAccountID, dividend*(1-swe.QualifiedDividendTaxRate)),
+			tt("dividend-tax", "skatt på utdelning", "", dividend*swe.QualifiedDividendTaxRate),
+		)
+		if c.share() < 1 {
+			templates = append(templates, tt("dividend-others", "utdelning till övriga ägare", "", dividend/c.share()-dividend))
+		}
+	}
+	return templates
+}
+
+func firstMatchInYear(cron date.Cron, year int) (date.Date, bool) {
+	for d := januaryFirst(year); d < januaryFirst(year+1); d = d.Add(date.Day) {
+		if cron.Matches(d) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// PlanCompany compares the owner's current salary and dividend with the
+// split that gives them the most, for this year and the following four. Each
+// year is planned on its own from the expected yearly profit, as if the
+// salary had been the same the year before.
+func (s *Service) PlanCompany(ctx context.Context, c Company) ([]CompanyPlanYear, error) {
+	sal, err := s.companySalary(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	ibbs, err := s.ListSweYearlyParams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing swe yearly params: %w", err)
+	}
+	profit := 12 * (c.Revenue.Mean() - c.Expenses.Mean())
+	thisYear := date.Today().Year()
+	plan := make([]CompanyPlanYear, 0, 5)
+	for year := thisYear; year < thisYear+5; year++ {
+		start := januaryFirst(year)
+		ibb := activeIBBAt(ibbs, januaryFirst(year-1))
+		gransbelopp := func(salary float64) float64 {
+			return swe.CalculateGransbelopp(swe.GransbeloppInput{
+				Rule:            c.GransbeloppRule,
+				IBB:             ibb,
+				OwnershipShare:  c.share(),
+				AcquisitionCost: c.AcquisitionCost,
+				CapitalRate:     c.CapitalRate,
+				TotalSalaries:   salary,
+				OwnerSalary:     salary,
+			})
+		}
+		monthlyTax := swe.NewFormulaTaxFunc(incomeTaxParamsAt(ibbs, start, swe.AverageMunicipalTaxRate))
+		var current float64
+		if sal != nil {
+			if monthlyTax, err = s.salaryTaxFunc(ctx, *sal, start, ibbs); err != nil {
+				return nil, err
+			}
+			if amt := activeSalaryAmountAt(sal.Amounts, start); amt != nil {
+				current = 12 * amt.Mean()
+			}
+		}
+		incomeTax := func(annual float64) float64 {
+			tax, err := monthlyTax(annual / 12)
+			if err != nil {
+				return 0
+			}
+			return 12 * tax
+		}
+		plan = append(plan, CompanyPlanYear{
+			Year:    year,
+			Current: swe.CalculateOwnerSplit(profit, current, gransbelopp(current), incomeTax),
+			Optimal: swe.PlanOwnerSplit(profit, 12000, gransbelopp, incomeTax),
+		})
+	}
+	return plan, nil
+}
+
+type CompanyEditView struct {
+	Company  Company
+	Accounts []Account
+	Salaries []Salary
+	Persons  []Person
+	Plan     []CompanyPlanYear
+}
+
+func (v CompanyEditView) IsEdit() bool {
+	return v.Company.ID != ""
+}
+
+// GetCompanyEditPageData returns the data for editing a company, or for a
+// new one when id is empty.
+func (s *Service) GetCompanyEditPageData(ctx context.Context, id string) (*CompanyEditView, error) {
+	v := &CompanyEditView{Company: Company{
+		OwnershipShare:  1,
+		GransbeloppRule: swe.GransbeloppSimplified,
+		AcquisitionCost: 25000,
+		CapitalRate:     swe.DefaultCapitalRate,
+		DividendDate:    "*-06-30",
+	}}
+	var err error
+	if id != "" {
+		if v.Company, err = s.GetCompany(ctx, id); err != nil {
+			return nil, err
+		}
+		if v.Plan, err = s.PlanCompany(ctx, v.Company); err != nil {
+			return nil, fmt.Errorf("planning company: %w", err)
+		}
+	}
+	if v.Accounts, err = s.ListAccounts(ctx); err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+	if v.Salaries, err = s.GetSalariesPageData(ctx); err != nil {
+		return nil, fmt.Errorf("listing salaries: %w", err)
+	}
+	if v.Persons, err = s.ListPersons(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
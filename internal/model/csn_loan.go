@@ -0,0 +1,244 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/ui"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+// DefaultCSNRecurrence is the default day of the month CSN is paid.
+const DefaultCSNRecurrence = date.Cron("*-*-27")
+
+// CSNLoan repays a CSN annuity loan account from FromAccountID. The yearly
+// amount is redetermined every January from the remaining debt, the CSN
+// interest rate in the yearly params and the years left until FinalYear, or
+// until the borrower turns 64 when no final year is set. The birth date
+// defaults to that of the account's owner. With Reduction the yearly amount is
+// capped at 5% of the gross income of SalaryID (nedsättning).
+type CSNLoan struct {
+	AccountID     string
+	FromAccountID string
+	SalaryID      string
+	BirthDate     date.Date
+	FinalYear     int64
+	Recurrence    date.Cron
+	Reduction     bool
+}
+
+func (l CSNLoan) GetBirthDateString() string {
+	if l.BirthDate.IsZero() {
+		return ""
+	}
+	return l.BirthDate.String()
+}
+
+func (l CSNLoan) GetFinalYearString() string {
+	if l.FinalYear == 0 {
+		return ""
+	}
+	return strconv.FormatInt(l.FinalYear, 10)
+}
+
+// RepaymentYears returns the years left to repay in year, including year.
+func (l CSNLoan) RepaymentYears(year int) int {
+	finalYear := int(l.FinalYear)
+	if finalYear == 0 && !l.BirthDate.IsZero() {
+		finalYear = swe.CSNFinalRepaymentYear(l.BirthDate.Year())
+	}
+	if finalYear == 0 {
+		finalYear = year + swe.CSNMaxRepaymentYears - 1
+	}
+	return swe.CSNRepaymentYears(year, finalYear)
+}
+
+// AnnualAmount returns the yearly payment for a debt in year, after any
+// nedsättning for the given gross yearly income.
+func (l CSNLoan) AnnualAmount(year int, debt, rate, grossIncome float64) float64 {
+	annual := swe.CSNAnnualAmount(debt, rate, l.RepaymentYears(year))
+	if l.Reduction && l.SalaryID != "" {
+		annual = swe.CSNReducedAnnualAmount(annual, grossIncome)
+	}
+	return annual
+}
+
+// CSNLoanEstimate is the CSN payment given today's balance.
+type CSNLoanEstimate struct {
+	Rate    float64
+	Years   int
+	Annual  float64
+	Monthly float64
+}
+
+func (e CSNLoanEstimate) GetAnnualString() string {
+	return ui.FormatWithThousands(e.Annual)
+}
+
+func (e CSNLoanEstimate) GetMonthlyString() string {
+	return ui.FormatWithThousands(e.Monthly)
+}
+
+func csnLoanFromDB(l pdb.CsnLoan) CSNLoan {
+	return CSNLoan{
+		AccountID:     l.AccountID,
+		FromAccountID: ui.OrDefault(l.FromAccountID),
+		SalaryID:      ui.OrDefault(l.SalaryID),
+		BirthDate:     date.Date(l.BirthDate),
+		FinalYear:     l.FinalYear,
+		Recurrence:    date.Cron(l.Recurrence),
+		Reduction:     l.Reduction != 0,
+	}
+}
+
+func (s *Service) UpsertCSNLoan(ctx context.Context, inp CSNLoan) (CSNLoan, error) {
+	if inp.FinalYear < 0 {
+		return CSNLoan{}, fmt.Errorf("invalid final repayment year: %d", inp.FinalYear)
+	}
+	if inp.Recurrence == "" {
+		inp.Recurrence = DefaultCSNRecurrence
+	}
+	// The CSN annuity is the amortization of the loan, so it cannot follow
+	// an amortization requirement as well.
+	if _, err := s.q.GetAmortizationRequirement(ctx, inp.AccountID); err == nil {
+		return CSNLoan{}, fmt.Errorf("account %s has an amortization requirement, remove it before adding a csn loan", inp.AccountID)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return CSNLoan{}, fmt.Errorf("getting amortization requirement: %w", err)
+	}
+	var reduction int64
+	if inp.Reduction {
+		reduction = 1
+	}
+	now := time.Now().Unix()
+	l, err := s.q.UpsertCsnLoan(ctx, pdb.UpsertCsnLoanParams{
+		AccountID:     inp.AccountID,
+		FromAccountID: ui.WithDefaultNull(inp.FromAccountID),
+		SalaryID:      ui.WithDefaultNull(inp.SalaryID),
+		BirthDate:     int64(inp.BirthDate),
+		FinalYear:     inp.FinalYear,
+		Recurrence:    string(inp.Recurrence),
+		Reduction:     reduction,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return CSNLoan{}, fmt.Errorf("upserting csn loan: %w", err)
+	}
+	s.invalidateForecast()
+	return csnLoanFromDB(l), nil
+}
+
+func (s *Service) GetCSNLoan(ctx context.Context, accountID string) (CSNLoan, error) {
+	l, err := s.q.GetCsnLoan(ctx, accountID)
+	if err != nil {
+		return CSNLoan{}, err
+	}
+	return csnLoanFromDB(l), nil
+}
+
+func (s *Service) ListCSNLoans(ctx context.Context) ([]CSNLoan, error) {
+	rows, err := s.q.ListCsnLoans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing csn loans: %w", err)
+	}
+	birthDates, err := s.accountOwnerBirthDates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loans := make([]CSNLoan, len(rows))
+	for i, r := range rows {
+		loans[i] = csnLoanFromDB(r)
+		if d, ok := birthDates[r.AccountID]; ok && loans[i].BirthDate.IsZero() {
+			loans[i].BirthDate = d
+		}
+	}
+	return loans, nil
+}
+
+func (s *Service) DeleteCSNLoan(ctx context.Context, accountID string) error {
+	if err := s.q.DeleteCsnLoan(ctx, accountID); err != nil {
+		return fmt.Errorf("deleting csn loan: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// EstimateCSNLoan returns this year's CSN payment for a debt.
+func (s *Service) EstimateCSNLoan(ctx context.Context, loan CSNLoan, debt float64) (CSNLoanEstimate, error) {
+	params, err := s.ListSweYearlyParams(ctx)
+	if err != nil {
+		return CSNLoanEstimate{}, err
+	}
+	incomeAt, err := s.csnIncomeFunc(ctx, loan)
+	if err != nil {
+		return CSNLoanEstimate{}, err
+	}
+	today := date.Today()
+	rate := activeCsnRantaAt(params, today)
+	annual := loan.AnnualAmount(today.Year(), debt, rate, incomeAt(today))
+	return CSNLoanEstimate{
+		Rate:    rate,
+		Years:   loan.RepaymentYears(today.Year()),
+		Annual:  annual,
+		Monthly: annual / 12,
+	}, nil
+}
+
+func (s *Service) csnIncomeFunc(ctx context.Context, loan CSNLoan) (func(date.Date) float64, error) {
+	if loan.SalaryID == "" {
+		return func(date.Date) float64 { return 0 }, nil
+	}
+	incomeAt, err := s.grossIncomeFunc(ctx, loan.SalaryID)
+	if err != nil {
+		return nil, fmt.Errorf("getting csn income: %w", err)
+	}
+	return incomeAt, nil
+}
+
+// csnInterestGrowth accrues the CSN interest rate from the yearly params,
+// each rate applying until the next one is valid. It returns nil when no
+// yearly params are configured.
+func csnInterestGrowth(params []SweYearlyParams) finance.GrowthModel {
+	if len(params) == 0 {
+		return nil
+	}
+	growths := make([]finance.GrowthModel, len(params))
+	for i, p := range params {
+		g := &finance.FixedGrowth{
+			TimeFrameGrowth: finance.TimeFrameGrowth{StartDate: p.ValidFrom},
+			AnnualRate:      uncertain.NewFixed(p.CsnRanta),
+		}
+		if i+1 < len(params) {
+			end := params[i+1].ValidFrom.Add(-date.Day)
+			g.EndDate = &end
+		}
+		growths[i] = g
+	}
+	return finance.NewGrowthCombined(growths...)
+}
+
+// csnLoanToFinance builds the yearly CSN repayment, redetermined every
+// January until the end of the prediction.
+func csnLoanToFinance(loan CSNLoan, params []SweYearlyParams, incomeAt func(date.Date) float64, from, until date.Date) *finance.AmortizationModel {
+	var januaries []date.Date
+	for year := from.Year() + 1; year <= until.Year(); year++ {
+		januaries = append(januaries, date.FromTime(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	}
+	return &finance.AmortizationModel{
+		Frequency:    loan.Recurrence,
+		SourceID:     loan.FromAccountID,
+		Revaluations: januaries,
+		YearlyAmount: func(day date.Date, balance func(id string) float64) float64 {
+			debt := max(0, -balance(loan.AccountID))
+			return loan.AnnualAmount(day.Year(), debt, activeCsnRantaAt(params, day), incomeAt(day))
+		},
+	}
+}
@@ -48,12 +48,16 @@ type DashboardView struct {
 	AccountChartData     []AccountTypeChartEntry
 	SnapshotHistoryChart SnapshotHistoryChartData
 	HasSpecialDates      bool
+	// PersonID is the household member the dashboard is filtered to, showing
+	// their share of each account.
+	PersonID string
+	Persons  []Person
 }
 
-func (s *Service) GetDashboardData(ctx context.Context) (*DashboardView, error) {
+func (s *Service) GetDashboardData(ctx context.Context, personID string) (*DashboardView, error) {
 	today := date.Today()
 
-	budget, err := s.GetBudgetData(ctx)
+	budget, err := s.GetBudgetData(ctx, personID)
 	if err != nil {
 		return nil, fmt.Errorf("computing budget view: %w", err)
 	}
@@ -62,6 +66,20 @@ func (s *Service) GetDashboardData(ctx context.Context) (*DashboardView, error)
 	if err != nil {
 		return nil, fmt.Errorf("listing accounts: %w", err)
 	}
+	ownership, err := s.listOwnership(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts = ownedAccounts(accounts, ownership, personID)
+	fx, err := s.fxRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts = inDefaultCurrency(accounts, fx)
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	accountTypes, err := s.ListAccountTypes(ctx)
 	if err != nil {
@@ -84,7 +102,7 @@ func (s *Service) GetDashboardData(ctx context.Context) (*DashboardView, error)
 	groups := groupAccountsByType(accounts, accountTypes)
 	chartData := buildAccountChartData(groups)
 
-	snapshotHistory, err := s.buildSnapshotHistoryChart(ctx, accountTypes)
+	snapshotHistory, err := s.buildSnapshotHistoryChart(ctx, accountTypes, ownership, personID)
 	if err != nil {
 		return nil, fmt.Errorf("building snapshot history chart: %w", err)
 	}
@@ -103,15 +121,60 @@ func (s *Service) GetDashboardData(ctx context.Context) (*DashboardView, error)
 		AccountChartData:     chartData,
 		SnapshotHistoryChart: snapshotHistory,
 		HasSpecialDates:      len(specialDates) > 0,
+		PersonID:             personID,
+		Persons:              persons,
 	}, nil
 }
 
-func (s *Service) buildSnapshotHistoryChart(ctx context.Context, accountTypes []AccountType) (SnapshotHistoryChartData, error) {
+// ownedAccounts returns the accounts personID owns a share of, with their
+// latest balance scaled to that share. An empty personID keeps all accounts.
+func ownedAccounts(accounts []AccountDetailed, ownership Ownership, personID string) []AccountDetailed {
+	if personID == "" {
+		return accounts
+	}
+	owned := make([]AccountDetailed, 0, len(accounts))
+	for _, acc := range accounts {
+		share := ownership.Share(acc.ID, personID)
+		if share == 0 {
+			continue
+		}
+		if acc.LastSnapshot != nil && share != 1 {
+			snap := *acc.LastSnapshot
+			snap.Balance = uncertain.NewFixed(snap.Balance.Mean() * share)
+			acc.LastSnapshot = &snap
+		}
+		owned = append(owned, acc)
+	}
+	return owned
+}
+
+// inDefaultCurrency converts the latest balances of accounts in other
+// currencies at the current rate, so they can be summed.
+func inDefaultCurrency(accounts []AccountDetailed, fx *fxRates) []AccountDetailed {
+	converted := make([]AccountDetailed, len(accounts))
+	for i, acc := range accounts {
+		if acc.LastSnapshot != nil && fx.process(acc.Currency) != nil {
+			snap := *acc.LastSnapshot
+			snap.Balance = uncertain.NewFixed(fx.spot(acc.Currency, snap.Balance.Mean()))
+			acc.LastSnapshot = &snap
+		}
+		converted[i] = acc
+	}
+	return converted
+}
+
+// buildSnapshotHistoryChart sums the snapshot history per account type,
+// counting personID's share of each account, or all of it when empty.
+func (s *Service) buildSnapshotHistoryChart(ctx context.Context, accountTypes []AccountType, ownership Ownership, personID string) (SnapshotHistoryChartData, error) {
 	q := s.q
 	rows, err := q.ListSnapshotHistoryWithType(ctx)
 	if err != nil {
 		return SnapshotHistoryChartData{}, err
 	}
+	fx, err := s.fxRates(ctx)
+	if err != nil {
+		return SnapshotHistoryChartData{}, err
+	}
 	type TypeKey struct {
 		Date   int64
 		TypeID string
@@ -126,7 +189,7 @@ func (s *Service) buildSnapshotHistoryChart(ctx context.Context, accountTypes []
 			return SnapshotHistoryChartData{}, fmt.Errorf("decoding balance: %w", err)
 		}
 		key := typeKey(r.Date, r.TypeID)
-		sumByDateAndType[key] += v.Mean()
+		sumByDateAndType[key] += fx.spot(r.Currency, v.Mean()) * ownership.Share(r.AccountID, personID)
 		dateSet[r.Date] = struct{}{}
 		accountsWithSnapshots[r.AccountID] = struct{}{}
 	}
@@ -146,8 +209,10 @@ func (s *Service) buildSnapshotHistoryChart(ctx context.Context, accountTypes []
 	if len(startupRows) > 0 {
 		type startupAccount struct {
 			TypeID       string
+			Share        float64
 			Rounds       []pdb.ListStartupShareSnapshotHistoryRow
 			ShareChanges []ShareChange
+			Investments  []InvestmentRound
 		}
 		startupAccounts := make(map[string]*startupAccount)
 		for _, r := range startupRows {
@@ -156,10 +221,19 @@ func (s *Service) buildSnapshotHistoryChart(ctx context.Context, accountTypes []
 			}
 			sa, ok := startupAccounts[r.AccountID]
 			if !ok {
-				sa = &startupAccount{TypeID: r.TypeID}
+				sa = &startupAccount{TypeID: r.TypeID, Share: ownership.Share(r.AccountID, personID)}
 				startupAccounts[r.AccountID] = sa
 			}
 			sa.Rounds = append(sa.Rounds, r)
+			sa.Investments = append(sa.Investments, InvestmentRound{
+				Date:                  date.Date(r.Date),
+				Valuation:             r.Valuation,
+				PreMoneyShares:        r.PreMoneyShares,
+				Investment:            r.Investment,
+				LiquidationPreference: r.LiquidationPreference,
+				Participating:         r.Participating,
+				Seniority:             r.Seniority,
+			})
 		}
 		for accountID, sa := range startupAccounts {
 			changes, err := s.ListShareChanges(ctx, accountID)
@@ -190,8 +264,9 @@ func (s *Service) buildSnapshotHistoryChart(ctx context.Context, accountTypes []
 					best.TaxRate,
 					postMoneyShares,
 					best.ValuationDiscountFactor,
+					liquidationPreferences(sa.Investments, date.Date(chartDate)),
 				)
-				sumByDateAndType[typeKey(chartDate, sa.TypeID)] += balance.Mean()
+				sumByDateAndType[typeKey(chartDate, sa.TypeID)] += balance.Mean() * sa.Share
 			}
 		}
 	}
@@ -0,0 +1,568 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/goslu/sid"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
+	"github.com/SimonSchneider/pefigo/pkg/ui"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+// EquityGrantKind is how stock in a listed employer is granted.
+type EquityGrantKind string
+
+const (
+	// EquityGrantRSU is restricted stock units, vesting into shares that are
+	// taxed as salary at the market price on vest.
+	EquityGrantRSU EquityGrantKind = "rsu"
+	// EquityGrantESPP is an employee stock purchase plan, buying shares with
+	// salary deductions at a discount that is taxed as a salary benefit.
+	EquityGrantESPP EquityGrantKind = "espp"
+)
+
+// EquityGrantKinds lists the selectable kinds of equity grants.
+var EquityGrantKinds = []EquityGrantKind{
+	EquityGrantRSU,
+	EquityGrantESPP,
+}
+
+func EquityGrantKindLabel(kind EquityGrantKind) string {
+	switch kind {
+	case EquityGrantESPP:
+		return "ESPP"
+	default:
+		return "RSU"
+	}
+}
+
+// EquityGrant is stock in a listed employer granted to a member of the
+// household and held in HoldingAccountID, whose growth models are those of
+// the employer stock from SharePrice on StartDate.
+//
+// RSUs vest Shares from StartDate over VestingMonths, in steps of
+// VestingIntervalMonths after a cliff of CliffMonths. Their value on vest is
+// taxed as salary on top of the owner's salaries, and the shares covering the
+// tax are sold, leaving the rest in the holding account.
+//
+// An ESPP buys shares for the Contribution withheld from the salary paid to
+// SalaryAccountID on each Recurrence, at a Discount to the market price. The
+// discount is a salary benefit, whose tax is also paid from SalaryAccountID.
+type EquityGrant struct {
+	ID                    string
+	Name                  string
+	OwnerID               string
+	Kind                  EquityGrantKind
+	HoldingAccountID      string
+	SalaryAccountID       string
+	SharePrice            float64
+	Shares                float64
+	CliffMonths           int64
+	VestingMonths         int64
+	VestingIntervalMonths int64
+	Contribution          uncertain.Value
+	Discount              float64
+	Recurrence            date.Cron
+	MunicipalTaxRate      float64
+	StartDate             date.Date
+	Enabled               bool
+}
+
+// EquityGrantYear is the expected outcome of an equity grant for a calendar
+// year: the shares vested, their market Value, the part of it taxed as salary
+// and the Tax on it.
+type EquityGrantYear struct {
+	Year    int
+	Shares  float64
+	Value   float64
+	Benefit float64
+	Tax     float64
+}
+
+func (g EquityGrant) IsESPP() bool {
+	return g.Kind == EquityGrantESPP
+}
+
+func (g EquityGrant) source() TransferTemplateSource {
+	return TransferTemplateSource{
+		Type:     "equity-grant",
+		EntityID: g.ID,
+		Label:    g.Name,
+		EditURL:  "/equity-grants/" + g.ID + "/edit",
+	}
+}
+
+func (g EquityGrant) GetSharePriceString() string {
+	if g.ID == "" {
+		return ""
+	}
+	return strconv.FormatFloat(g.SharePrice, 'f', -1, 64)
+}
+
+func (g EquityGrant) GetSharesString() string {
+	if g.ID == "" {
+		return ""
+	}
+	return strconv.FormatFloat(g.Shares, 'f', -1, 64)
+}
+
+func (g EquityGrant) GetCliffMonthsString() string {
+	return strconv.FormatInt(g.CliffMonths, 10)
+}
+
+func (g EquityGrant) GetVestingMonthsString() string {
+	return strconv.FormatInt(g.VestingMonths, 10)
+}
+
+func (g EquityGrant) GetContributionString() string {
+	if g.ID == "" {
+		return ""
+	}
+	return g.Contribution.SimpleEncode()
+}
+
+func (g EquityGrant) GetDiscountString() string {
+	return strconv.FormatFloat(g.Discount*100, 'f', -1, 64)
+}
+
+func (g EquityGrant) GetMunicipalTaxRateString() string {
+	if g.MunicipalTaxRate == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(g.MunicipalTaxRate*100, 'f', -1, 64)
+}
+
+func (g EquityGrant) GetStartDateString() string {
+	if g.ID == "" {
+		return ""
+	}
+	return g.StartDate.String()
+}
+
+func equityGrantFromDB(g pdb.EquityGrant) (EquityGrant, error) {
+	contribution, err := uncertain.Decode(g.Contribution)
+	if err != nil {
+		return EquityGrant{}, fmt.Errorf("decoding equity grant contribution: %w", err)
+	}
+	return EquityGrant{
+		ID:                    g.ID,
+		Name:                  g.Name,
+		OwnerID:               ui.OrDefault(g.OwnerID),
+		Kind:                  EquityGrantKind(g.Kind),
+		HoldingAccountID:      g.HoldingAccountID,
+		SalaryAccountID:       ui.OrDefault(g.SalaryAccountID),
+		SharePrice:            g.SharePrice,
+		Shares:                g.Shares,
+		CliffMonths:           g.CliffMonths,
+		VestingMonths:         g.VestingMonths,
+		VestingIntervalMonths: g.VestingIntervalMonths,
+		Contribution:          contribution,
+		Discount:              g.Discount,
+		Recurrence:            date.Cron(g.Recurrence),
+		MunicipalTaxRate:      g.MunicipalTaxRate,
+		StartDate:             date.Date(g.StartDate),
+		Enabled:               g.Enabled,
+	}, nil
+}
+
+func (s *Service) UpsertEquityGrant(ctx context.Context, inp EquityGrant) (EquityGrant, error) {
+	if inp.Name == "" {
+		return EquityGrant{}, fmt.Errorf("equity grant name is required")
+	}
+	if inp.HoldingAccountID == "" {
+		return EquityGrant{}, fmt.Errorf("a holding account is required")
+	}
+	switch inp.Kind {
+	case "":
+		inp.Kind = EquityGrantRSU
+	case EquityGrantRSU, EquityGrantESPP:
+	default:
+		return EquityGrant{}, fmt.Errorf("invalid equity grant kind: %q", inp.Kind)
+	}
+	if inp.Kind == EquityGrantRSU && (inp.SharePrice <= 0 || inp.Shares <= 0) {
+		return EquityGrant{}, fmt.Errorf("an RSU grant requires a share price and shares")
+	}
+	if inp.Kind == EquityGrantESPP && inp.SalaryAccountID == "" {
+		return EquityGrant{}, fmt.Errorf("an ESPP requires a salary account")
+	}
+	if inp.Discount < 0 || inp.Discount >= 1 {
+		return EquityGrant{}, fmt.Errorf("discount must be between 0 and 100%%")
+	}
+	if inp.CliffMonths < 0 || inp.VestingMonths < 0 || inp.CliffMonths > inp.VestingMonths && inp.VestingMonths > 0 {
+		return EquityGrant{}, fmt.Errorf("invalid vesting of %d months with a %d month cliff", inp.VestingMonths, inp.CliffMonths)
+	}
+	if inp.VestingIntervalMonths <= 0 {
+		inp.VestingIntervalMonths = 3
+	}
+	if inp.Recurrence == "" {
+		inp.Recurrence = "*-*-25"
+	}
+	if !inp.Contribution.Valid() {
+		inp.Contribution = uncertain.NewFixed(0)
+	}
+	if inp.ID == "" {
+		inp.ID = sid.MustNewString(32)
+	}
+	contribution, err := inp.Contribution.Encode()
+	if err != nil {
+		return EquityGrant{}, fmt.Errorf("encoding equity grant contribution: %w", err)
+	}
+	now := time.Now().Unix()
+	g, err := s.q.UpsertEquityGrant(ctx, pdb.UpsertEquityGrantParams{
+		ID:                    inp.ID,
+		Name:                  inp.Name,
+		OwnerID:               ui.WithDefaultNull(inp.OwnerID),
+		Kind:                  string(inp.Kind),
+		HoldingAccountID:      inp.HoldingAccountID,
+		SalaryAccountID:       ui.WithDefaultNull(inp.SalaryAccountID),
+		SharePrice:            inp.SharePrice,
+		Shares:                inp.Shares,
+		CliffMonths:           inp.CliffMonths,
+		VestingMonths:         inp.VestingMonths,
+		VestingIntervalMonths: inp.VestingIntervalMonths,
+		Contribution:          contribution,
+		Discount:              inp.Discount,
+		Recurrence:            string(inp.Recurrence),
+		MunicipalTaxRate:      inp.MunicipalTaxRate,
+		StartDate:             int64(inp.StartDate),
+		Enabled:               inp.Enabled,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	})
+	if err != nil {
+		return EquityGrant{}, fmt.Errorf("upserting equity grant: %w", err)
+	}
+	s.invalidateForecast()
+	return equityGrantFromDB(g)
+}
+
+func (s *Service) GetEquityGrant(ctx context.Context, id string) (EquityGrant, error) {
+	g, err := s.q.GetEquityGrant(ctx, id)
+	if err != nil {
+		return EquityGrant{}, fmt.Errorf("getting equity grant: %w", err)
+	}
+	return equityGrantFromDB(g)
+}
+
+func (s *Service) ListEquityGrants(ctx context.Context) ([]EquityGrant, error) {
+	rows, err := s.q.ListEquityGrants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing equity grants: %w", err)
+	}
+	grants := make([]EquityGrant, len(rows))
+	for i, r := range rows {
+		if grants[i], err = equityGrantFromDB(r); err != nil {
+			return nil, err
+		}
+	}
+	return grants, nil
+}
+
+func (s *Service) DeleteEquityGrant(ctx context.Context, id string) error {
+	if err := s.q.DeleteEquityGrant(ctx, id); err != nil {
+		return fmt.Errorf("deleting equity grant: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// sharePrice returns the price of a share on day, grown from SharePrice on
+// StartDate by the fixed and log-normal growth models of the holding account.
+func (g EquityGrant) sharePrice(gms []GrowthModel, day date.Date) uncertain.Value {
+	return uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+		logReturn := 0.0
+		for _, gm := range gms {
+			from, to := max(g.StartDate, gm.StartDate), day
+			if gm.EndDate != nil {
+				to = min(to, *gm.EndDate)
+			}
+			if to <= from {
+				continue
+			}
+			years := float64(to.Sub(from)) / float64(date.Year)
+			switch gm.Type {
+			case "fixed":
+				logReturn += years * math.Log1p(gm.AnnualRate.Sample(cfg))
+			case "lognormal":
+				logReturn += years * gm.AnnualRate.Sample(cfg)
+				if gm.AnnualVolatility.Valid() {
+					logReturn += math.Sqrt(years) * gm.AnnualVolatility.Sample(cfg) * cfg.RNG.NormFloat64()
+				}
+			}
+		}
+		return g.SharePrice * math.Exp(logReturn)
+	})
+}
+
+// equityGrantVest is the part of an RSU grant vesting on a day.
+type equityGrantVest struct {
+	day    date.Date
+	shares float64
+}
+
+// vests returns the vesting days of an RSU grant and the shares vesting on
+// each.
+func (g EquityGrant) vests() []equityGrantVest {
+	schedule := finance.StartupGrowthOption{
+		VestingStart:          g.StartDate,
+		CliffMonths:           int(g.CliffMonths),
+		VestingMonths:         int(g.VestingMonths),
+		VestingIntervalMonths: int(g.VestingIntervalMonths),
+	}
+	var vests []equityGrantVest
+	vested := 0.0
+	for month := 0; month <= int(g.VestingMonths); month++ {
+		d := addMonths(g.StartDate, month)
+		fraction := schedule.VestedFraction(d)
+		if fraction > vested {
+			vests = append(vests, equityGrantVest{day: d, shares: g.Shares * (fraction - vested)})
+			vested = fraction
+		}
+	}
+	return vests
+}
+
+// equityGrantTaxYear is a calendar year of an equity grant with the expected
+// outcome of the year, and the average tax rate of the benefit on top of the
+// owner's salaries. The last year of an ESPP has no end and repeats.
+type equityGrantTaxYear struct {
+	EquityGrantYear
+	start   date.Date
+	end     *date.Date
+	taxRate float64
+}
+
+// equityGrantTaxYears splits an equity grant into the calendar years of its
+// vests, or for an ESPP until the owner's salaries have reached a steady
+// state.
+func (s *Service) equityGrantTaxYears(ctx context.Context, g EquityGrant, owner ownerSalaryTax, ibbs []SweYearlyParams, gms []GrowthModel) ([]equityGrantTaxYear, error) {
+	byYear := make(map[int]*equityGrantTaxYear)
+	var years []*equityGrantTaxYear
+	yearOf := func(year int) *equityGrantTaxYear {
+		if y, ok := byYear[year]; ok {
+			return y
+		}
+		y := &equityGrantTaxYear{EquityGrantYear: EquityGrantYear{Year: year}, start: max(januaryFirst(year), g.StartDate)}
+		end := januaryFirst(year + 1)
+		y.end = &end
+		byYear[year] = y
+		years = append(years, y)
+		return y
+	}
+	switch g.Kind {
+	case EquityGrantESPP:
+		last := g.StartDate.Year()
+		for year := range owner.income {
+			last = max(last, year)
+		}
+		for _, p := range ibbs {
+			last = max(last, p.ValidFrom.Year())
+		}
+		market := g.Contribution.Mean() / (1 - g.Discount)
+		for year := g.StartDate.Year(); year <= last+1; year++ {
+			y := yearOf(year)
+			for d := y.start; d < *y.end; d = d.Add(date.Day) {
+				if g.Recurrence.Matches(d) {
+					y.Value += market
+					y.Benefit += market * g.Discount
+				}
+			}
+		}
+		years[len(years)-1].end = nil
+	default:
+		for _, v := range g.vests() {
+			y := yearOf(v.day.Year())
+			value := v.shares * g.sharePrice(gms, v.day).Mean()
+			y.Shares += v.shares
+			y.Value += value
+			y.Benefit += value
+		}
+	}
+
+	res := make([]equityGrantTaxYear, len(years))
+	for i, y := range years {
+		incomeTax, err := s.incomeTaxOnSalary(ctx, owner, ibbs, y.Year, g.MunicipalTaxRate)
+		if err != nil {
+			return nil, err
+		}
+		if y.Benefit > 0 {
+			y.Tax = incomeTax(y.Benefit)
+			y.taxRate = y.Tax / y.Benefit
+		}
+		res[i] = *y
+	}
+	return res, nil
+}
+
+func (s *Service) generateEquityGrantTransferTemplates(ctx context.Context) ([]TransferTemplate, error) {
+	grants, err := s.ListEquityGrants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(grants) == 0 {
+		return nil, nil
+	}
+	owners, ibbs, err := s.ownerSalaryTaxes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("computing owner salary income: %w", err)
+	}
+	var templates []TransferTemplate
+	for _, g := range grants {
+		gms, err := s.ListAccountGrowthModels(ctx, g.HoldingAccountID)
+		if err != nil {
+			return nil, err
+		}
+		years, err := s.equityGrantTaxYears(ctx, g, owners[g.OwnerID], ibbs, gms)
+		if err != nil {
+			return nil, fmt.Errorf("computing taxes for %s: %w", g.Name, err)
+		}
+		templates = append(templates, g.transferTemplates(years, gms)...)
+	}
+	return templates, nil
+}
+
+// transferTemplates deposits each RSU vest net of its tax in the holding
+// account, or moves the ESPP contributions from the salary account to the
+// holding account with the discount on top, and pays the tax on the discount
+// of each year from the salary account.
+func (g EquityGrant) transferTemplates(years []equityGrantTaxYear, gms []GrowthModel) []TransferTemplate {
+	source := g.source()
+	var templates []TransferTemplate
+	if g.Kind == EquityGrantRSU {
+		taxRates := make(map[int]float64, len(years))
+		for _, y := range years {
+			taxRates[y.Year] = y.taxRate
+		}
+		for i, v := range g.vests() {
+			price, net := g.sharePrice(gms, v.day), v.shares*(1-taxRates[v.day.Year()])
+			templates = append(templates, TransferTemplate{
+				ID:          fmt.Sprintf("equity-grant-vest:%s:%d", g.ID, i),
+				Name:        g.Name + " (vest)",
+				ToAccountID: g.HoldingAccountID,
+				AmountType:  "fixed",
+				AmountFixed: uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+					return net * price.Sample(cfg)
+				}),
+				Recurrence: date.Cron(v.day.String()),
+				StartDate:  v.day,
+				Enabled:    g.Enabled,
+				Source:     source,
+			})
+		}
+		return templates
+	}
+
+	contribution, discount := g.Contribution, g.Discount/(1-g.Discount)
+	templates = append(templates, TransferTemplate{
+		ID:            "equity-grant-contribution:" + g.ID,
+		Name:          g.Name,
+		FromAccountID: g.SalaryAccountID,
+		ToAccountID:   g.HoldingAccountID,
+		AmountType:    "fixed",
+		AmountFixed:   contribution,
+		Recurrence:    g.Recurrence,
+		StartDate:     g.StartDate,
+		Enabled:       g.Enabled,
+		Source:        source,
+	}, TransferTemplate{
+		ID:          "equity-grant-discount:" + g.ID,
+		Name:        g.Name + " (discount)",
+		ToAccountID: g.HoldingAccountID,
+		AmountType:  "fixed",
+		AmountFixed: uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+			return contribution.Sample(cfg) * discount
+		}),
+		Recurrence: g.Recurrence,
+		StartDate:  g.StartDate,
+		Enabled:    g.Enabled,
+		Source:     source,
+	})
+	for _, y := range years {
+		if y.taxRate == 0 {
+			continue
+		}
+		taxRate := y.taxRate
+		templates = append(templates, TransferTemplate{
+			ID:            fmt.Sprintf("equity-grant-tax:%s:%d", g.ID, y.Year),
+			Name:          g.Name + " (tax)",
+			FromAccountID: g.SalaryAccountID,
+			AmountType:    "fixed",
+			AmountFixed: uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+				return contribution.Sample(cfg) * discount * taxRate
+			}),
+			Recurrence: g.Recurrence,
+			StartDate:  y.start,
+			EndDate:    y.end,
+			Enabled:    g.Enabled,
+			Source:     source,
+		})
+	}
+	return templates
+}
+
+// PlanEquityGrant returns the expected outcome of each year of an equity
+// grant.
+func (s *Service) PlanEquityGrant(ctx context.Context, g EquityGrant) ([]EquityGrantYear, error) {
+	owners, ibbs, err := s.ownerSalaryTaxes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("computing owner salary income: %w", err)
+	}
+	gms, err := s.ListAccountGrowthModels(ctx, g.HoldingAccountID)
+	if err != nil {
+		return nil, err
+	}
+	years, err := s.equityGrantTaxYears(ctx, g, owners[g.OwnerID], ibbs, gms)
+	if err != nil {
+		return nil, err
+	}
+	plan := make([]EquityGrantYear, len(years))
+	for i, y := range years {
+		plan[i] = y.EquityGrantYear
+	}
+	return plan, nil
+}
+
+type EquityGrantEditView struct {
+	EquityGrant EquityGrant
+	Accounts    []Account
+	Persons     []Person
+	Plan        []EquityGrantYear
+}
+
+func (v EquityGrantEditView) IsEdit() bool {
+	return v.EquityGrant.ID != ""
+}
+
+// GetEquityGrantEditPageData returns the data for editing an equity grant, or
+// for a new one when id is empty.
+func (s *Service) GetEquityGrantEditPageData(ctx context.Context, id string) (*EquityGrantEditView, error) {
+	v := &EquityGrantEditView{EquityGrant: EquityGrant{
+		Kind:                  EquityGrantRSU,
+		VestingMonths:         48,
+		VestingIntervalMonths: 3,
+		Recurrence:            "*-*-25",
+		Enabled:               true,
+	}}
+	var err error
+	if id != "" {
+		if v.EquityGrant, err = s.GetEquityGrant(ctx, id); err != nil {
+			return nil, err
+		}
+		if v.Plan, err = s.PlanEquityGrant(ctx, v.EquityGrant); err != nil {
+			return nil, fmt.Errorf("planning equity grant: %w", err)
+		}
+	}
+	if v.Accounts, err = s.ListAccounts(ctx); err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+	if v.Persons, err = s.ListPersons(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -108,7 +108,11 @@ func (s *Service) GetForecastCacheForDashboard(ctx context.Context) (*ForecastDa
 	}
 
 	// Build entities from historic snapshot data
-	history, err := s.buildSnapshotHistoryChart(ctx, accountTypes)
+	ownership, err := s.listOwnership(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.buildSnapshotHistoryChart(ctx, accountTypes, ownership, "")
 	if err != nil {
 		return nil, fmt.Errorf("building snapshot history: %w", err)
 	}
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/currency"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
+)
+
+// FXRateModel is the expected path of a currency against the default
+// currency. Accounts and transfer templates in the currency are converted at
+// one shared simulated rate, so a forecast carries the exchange rate risk.
+type FXRateModel struct {
+	Currency         string
+	InitialRate      float64 // Price of one unit in the default currency, 0 for the current spot rate
+	AnnualDrift      float64
+	AnnualVolatility float64
+}
+
+func (m FXRateModel) GetInitialRateString() string {
+	if m.InitialRate == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%g", m.InitialRate)
+}
+
+func (m FXRateModel) GetAnnualDriftString() string {
+	return formatOptionalPercent(m.Currency, m.AnnualDrift)
+}
+
+func (m FXRateModel) GetAnnualVolatilityString() string {
+	return formatOptionalPercent(m.Currency, m.AnnualVolatility)
+}
+
+func fxRateModelFromDB(m pdb.FxRateModel) FXRateModel {
+	return FXRateModel{
+		Currency:         m.Currency,
+		InitialRate:      m.InitialRate,
+		AnnualDrift:      m.AnnualDrift,
+		AnnualVolatility: m.AnnualVolatility,
+	}
+}
+
+func (s *Service) UpsertFXRateModel(ctx context.Context, inp FXRateModel) (FXRateModel, error) {
+	if _, ok := currency.Get(inp.Currency); !ok {
+		return FXRateModel{}, fmt.Errorf("unsupported currency: %s", inp.Currency)
+	}
+	if inp.InitialRate < 0 || inp.AnnualVolatility < 0 {
+		return FXRateModel{}, fmt.Errorf("initial rate and volatility must not be negative")
+	}
+	now := time.Now().Unix()
+	m, err := s.q.UpsertFXRateModel(ctx, pdb.UpsertFXRateModelParams{
+		Currency:         inp.Currency,
+		InitialRate:      inp.InitialRate,
+		AnnualDrift:      inp.AnnualDrift,
+		AnnualVolatility: inp.AnnualVolatility,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	})
+	if err != nil {
+		return FXRateModel{}, fmt.Errorf("upserting fx rate model: %w", err)
+	}
+	s.invalidateForecast()
+	return fxRateModelFromDB(m), nil
+}
+
+func (s *Service) ListFXRateModels(ctx context.Context) ([]FXRateModel, error) {
+	rows, err := s.q.ListFXRateModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing fx rate models: %w", err)
+	}
+	models := make([]FXRateModel, len(rows))
+	for i, r := range rows {
+		models[i] = fxRateModelFromDB(r)
+	}
+	return models, nil
+}
+
+func (s *Service) DeleteFXRateModel(ctx context.Context, currency string) error {
+	if err := s.q.DeleteFXRateModel(ctx, currency); err != nil {
+		return fmt.Errorf("deleting fx rate model: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// fxRates holds one rate process per foreign currency for a prediction run,
+// so everything in a currency follows the same path.
+type fxRates struct {
+	processes map[string]*finance.FXRateProcess
+}
+
+// fxRates resolves the rate processes of the currencies accounts and
+// transfer templates are kept in. The default currency has no process, and
+// currencies without a model or initial rate start at the current spot rate,
+// falling back to 1 like bills do.
+func (s *Service) fxRates(ctx context.Context) (*fxRates, error) {
+	defaultCurrency, err := s.GetDefaultCurrency(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting default currency: %w", err)
+	}
+	models, err := s.ListFXRateModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byCurrency := KeyBy(models, func(m FXRateModel) string { return m.Currency })
+	currencies, err := s.q.ListAccountCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing account currencies: %w", err)
+	}
+	templateCurrencies, err := s.q.ListTransferTemplateCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing transfer template currencies: %w", err)
+	}
+	r := &fxRates{processes: make(map[string]*finance.FXRateProcess)}
+	for _, cur := range append(currencies, templateCurrencies...) {
+		if cur == "" || cur == defaultCurrency || r.processes[cur] != nil {
+			continue
+		}
+		m := byCurrency[cur]
+		if m.InitialRate == 0 {
+			m.InitialRate = 1
+			if s.currencyClient != nil {
+				if rate, err := s.currencyClient.GetRate(ctx, cur, defaultCurrency); err == nil {
+					m.InitialRate = rate
+				}
+			}
+		}
+		r.processes[cur] = &finance.FXRateProcess{
+			Currency:         cur,
+			InitialRate:      m.InitialRate,
+			AnnualDrift:      m.AnnualDrift,
+			AnnualVolatility: m.AnnualVolatility,
+		}
+	}
+	return r, nil
+}
+
+// process returns the rate process of a currency, nil for the default.
+func (r *fxRates) process(cur string) *finance.FXRateProcess {
+	return r.processes[cur]
+}
+
+// spot converts an amount in a currency to the default currency at the
+// initial rate, for balances that are not simulated.
+func (r *fxRates) spot(cur string, amount float64) float64 {
+	if p := r.processes[cur]; p != nil {
+		return amount * p.InitialRate
+	}
+	return amount
+}
+
+// transferCurrency is the currency of a fixed transfer amount. Generated
+// templates are computed in the default currency, user templates default to
+// the currency of the source account, or the destination without a source.
+func transferCurrency(t TransferTemplate, accsByID map[string]pdb.Account) string {
+	if t.Source.IsGenerated() {
+		return ""
+	}
+	if t.Currency != "" {
+		return t.Currency
+	}
+	if acc, ok := accsByID[t.FromAccountID]; ok {
+		return acc.Currency
+	}
+	return accsByID[t.ToAccountID].Currency
+}
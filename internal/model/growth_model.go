@@ -3,12 +3,14 @@ package model
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/SimonSchneider/goslu/date"
 	"github.com/SimonSchneider/goslu/sid"
 	"github.com/SimonSchneider/pefigo/internal/pdb"
 	"github.com/SimonSchneider/pefigo/pkg/finance"
+	"github.com/SimonSchneider/pefigo/pkg/ui"
 	"github.com/SimonSchneider/pefigo/pkg/uncertain"
 )
 
@@ -20,6 +22,10 @@ type GrowthModel struct {
 	AnnualVolatility uncertain.Value
 	StartDate        date.Date
 	EndDate          *date.Date
+	// RateModelID and BindingYears only apply to variable growth models, where
+	// AnnualRate is the margin on top of the interest rate model.
+	RateModelID  string
+	BindingYears int64
 }
 
 func (gm GrowthModel) GetEndDateString() string {
@@ -29,6 +35,13 @@ func (gm GrowthModel) GetEndDateString() string {
 	return gm.EndDate.String()
 }
 
+func (gm GrowthModel) GetBindingYearsString() string {
+	if gm.BindingYears == 0 {
+		return ""
+	}
+	return strconv.FormatInt(gm.BindingYears, 10)
+}
+
 type AccountGrowthModelInput struct {
 	ID               string
 	AccountID        string
@@ -37,11 +50,16 @@ type AccountGrowthModelInput struct {
 	AnnualVolatility uncertain.Value
 	StartDate        date.Date
 	EndDate          *date.Date
+	RateModelID      string
+	BindingYears     int64
 }
 
 type GrowthModels []GrowthModel
 
-func (gms GrowthModels) ToFinance() finance.GrowthModel {
+// ToFinance converts the growth models, resolving variable growth models
+// against the shared rate processes. A variable growth model whose interest
+// rate model is missing grows at its margin only.
+func (gms GrowthModels) ToFinance(rates map[string]*finance.InterestRateProcess) finance.GrowthModel {
 	fgms := make([]finance.GrowthModel, 0, len(gms))
 	for _, gm := range gms {
 		switch gm.Type {
@@ -62,6 +80,26 @@ func (gms GrowthModels) ToFinance() finance.GrowthModel {
 				AnnualRate:       gm.AnnualRate,
 				AnnualVolatility: gm.AnnualVolatility,
 			})
+		case "variable":
+			timeFrame := finance.TimeFrameGrowth{
+				StartDate: gm.StartDate,
+				EndDate:   gm.EndDate,
+			}
+			process, ok := rates[gm.RateModelID]
+			if !ok {
+				fgms = append(fgms, &finance.FixedGrowth{TimeFrameGrowth: timeFrame, AnnualRate: gm.AnnualRate})
+				continue
+			}
+			var lockedUntil date.Date
+			if gm.BindingYears > 0 {
+				lockedUntil = addYears(gm.StartDate, int(gm.BindingYears))
+			}
+			fgms = append(fgms, &finance.VariableRateGrowth{
+				TimeFrameGrowth: timeFrame,
+				Rates:           process,
+				Margin:          gm.AnnualRate,
+				LockedUntil:     lockedUntil,
+			})
 		}
 	}
 	return finance.NewGrowthCombined(fgms...)
@@ -88,6 +126,8 @@ func growthModelFromDB(g pdb.GrowthModel) (GrowthModel, error) {
 		AnnualVolatility: annualVolatility,
 		StartDate:        date.Date(g.StartDate),
 		EndDate:          endDate,
+		RateModelID:      ui.OrDefault(g.RateModelID),
+		BindingYears:     g.BindingYears,
 	}, nil
 }
 
@@ -96,6 +136,12 @@ func (s *Service) UpsertAccountGrowthModel(ctx context.Context, inp AccountGrowt
 	if inp.EndDate != nil {
 		endDate = ptr(int64(*inp.EndDate))
 	}
+	if inp.Type == "variable" && inp.RateModelID == "" {
+		return GrowthModel{}, fmt.Errorf("variable growth model requires an interest rate model")
+	}
+	if inp.BindingYears < 0 {
+		return GrowthModel{}, fmt.Errorf("invalid binding period: %d", inp.BindingYears)
+	}
 	if inp.ID == "" {
 		inp.ID = sid.MustNewString(32)
 	}
@@ -115,6 +161,8 @@ func (s *Service) UpsertAccountGrowthModel(ctx context.Context, inp AccountGrowt
 		AnnualVolatility: annualVolatility,
 		StartDate:        int64(inp.StartDate),
 		EndDate:          endDate,
+		RateModelID:      ui.WithDefaultNull(inp.RateModelID),
+		BindingYears:     inp.BindingYears,
 		CreatedAt:        time.Now().UnixMilli(),
 		UpdatedAt:        time.Now().UnixMilli(),
 	})
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/ui"
+)
+
+// InterestDeduction enables ränteavdrag for a loan account. Interest paid
+// through the account's cash flow is accumulated per calendar year and the
+// refund is paid into ToAccountID on SettlementDate the following year.
+type InterestDeduction struct {
+	AccountID      string
+	ToAccountID    string
+	SettlementDate date.Cron
+	Borrowers      int64
+}
+
+func (d InterestDeduction) GetBorrowersString() string {
+	return strconv.FormatInt(max(1, d.Borrowers), 10)
+}
+
+func interestDeductionFromDB(d pdb.InterestDeduction) InterestDeduction {
+	return InterestDeduction{
+		AccountID:      d.AccountID,
+		ToAccountID:    ui.OrDefault(d.ToAccountID),
+		SettlementDate: date.Cron(d.SettlementDate),
+		Borrowers:      d.Borrowers,
+	}
+}
+
+func (s *Service) UpsertInterestDeduction(ctx context.Context, inp InterestDeduction) (InterestDeduction, error) {
+	if inp.Borrowers < 1 {
+		return InterestDeduction{}, fmt.Errorf("invalid number of borrowers: %d", inp.Borrowers)
+	}
+	if inp.SettlementDate == "" {
+		inp.SettlementDate = swe.DefaultInterestSettlementDate
+	}
+	now := time.Now().Unix()
+	d, err := s.q.UpsertInterestDeduction(ctx, pdb.UpsertInterestDeductionParams{
+		AccountID:      inp.AccountID,
+		ToAccountID:    ui.WithDefaultNull(inp.ToAccountID),
+		SettlementDate: string(inp.SettlementDate),
+		Borrowers:      inp.Borrowers,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if err != nil {
+		return InterestDeduction{}, fmt.Errorf("upserting interest deduction: %w", err)
+	}
+	s.invalidateForecast()
+	return interestDeductionFromDB(d), nil
+}
+
+func (s *Service) GetInterestDeduction(ctx context.Context, accountID string) (InterestDeduction, error) {
+	d, err := s.q.GetInterestDeduction(ctx, accountID)
+	if err != nil {
+		return InterestDeduction{}, err
+	}
+	return interestDeductionFromDB(d), nil
+}
+
+func (s *Service) ListInterestDeductions(ctx context.Context) ([]InterestDeduction, error) {
+	rows, err := s.q.ListInterestDeductions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing interest deductions: %w", err)
+	}
+	deductions := make([]InterestDeduction, len(rows))
+	for i, r := range rows {
+		deductions[i] = interestDeductionFromDB(r)
+	}
+	return deductions, nil
+}
+
+func (s *Service) DeleteInterestDeduction(ctx context.Context, accountID string) error {
+	if err := s.q.DeleteInterestDeduction(ctx, accountID); err != nil {
+		return fmt.Errorf("deleting interest deduction: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// interestDeductionsToFinance groups the loan deductions into one tax
+// settlement per refund account, settlement date and number of borrowers, so
+// the 100 000 kr threshold applies to the combined interest of those loans.
+// It returns the settlement per loan account and per refund account.
+func interestDeductionsToFinance(deductions []InterestDeduction) (byLoan map[string]*swe.InterestDeduction, byRefundAccount map[string][]*swe.InterestDeduction) {
+	type groupKey struct {
+		toAccountID    string
+		settlementDate date.Cron
+		borrowers      int64
+	}
+	groups := make(map[groupKey]*swe.InterestDeduction)
+	byLoan = make(map[string]*swe.InterestDeduction)
+	byRefundAccount = make(map[string][]*swe.InterestDeduction)
+	for _, d := range deductions {
+		if d.ToAccountID == "" {
+			continue
+		}
+		key := groupKey{toAccountID: d.ToAccountID, settlementDate: d.SettlementDate, borrowers: d.Borrowers}
+		g, ok := groups[key]
+		if !ok {
+			g = &swe.InterestDeduction{SettlementDate: d.SettlementDate, Borrowers: int(d.Borrowers)}
+			groups[key] = g
+			byRefundAccount[d.ToAccountID] = append(byRefundAccount[d.ToAccountID], g)
+		}
+		byLoan[d.AccountID] = g
+	}
+	return byLoan, byRefundAccount
+}
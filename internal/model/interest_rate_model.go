@@ -0,0 +1,140 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/sid"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
+)
+
+// InterestRateModel is a mean-reverting interest rate, such as the Riksbank
+// policy rate, that variable-rate growth models on loans follow. All loans
+// referencing the same model share one simulated rate path.
+type InterestRateModel struct {
+	ID             string
+	Name           string
+	Type           finance.InterestRateModelType
+	InitialRate    float64
+	LongTermRate   float64
+	ReversionSpeed float64
+	Volatility     float64
+}
+
+func (m InterestRateModel) GetInitialRateString() string {
+	return formatOptionalPercent(m.ID, m.InitialRate)
+}
+
+func (m InterestRateModel) GetLongTermRateString() string {
+	return formatOptionalPercent(m.ID, m.LongTermRate)
+}
+
+func (m InterestRateModel) GetReversionSpeedString() string {
+	if m.ID == "" {
+		return ""
+	}
+	return strconv.FormatFloat(m.ReversionSpeed, 'f', -1, 64)
+}
+
+func (m InterestRateModel) GetVolatilityString() string {
+	return formatOptionalPercent(m.ID, m.Volatility)
+}
+
+func formatOptionalPercent(id string, v float64) string {
+	if id == "" {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", v*100)
+}
+
+func (m InterestRateModel) ToFinance() *finance.InterestRateProcess {
+	return &finance.InterestRateProcess{
+		Type:           m.Type,
+		InitialRate:    m.InitialRate,
+		LongTermRate:   m.LongTermRate,
+		ReversionSpeed: m.ReversionSpeed,
+		Volatility:     m.Volatility,
+	}
+}
+
+func interestRateModelFromDB(m pdb.InterestRateModel) InterestRateModel {
+	return InterestRateModel{
+		ID:             m.ID,
+		Name:           m.Name,
+		Type:           finance.InterestRateModelType(m.ModelType),
+		InitialRate:    m.InitialRate,
+		LongTermRate:   m.LongTermRate,
+		ReversionSpeed: m.ReversionSpeed,
+		Volatility:     m.Volatility,
+	}
+}
+
+func (s *Service) UpsertInterestRateModel(ctx context.Context, inp InterestRateModel) (InterestRateModel, error) {
+	if inp.Type != finance.InterestRateVasicek && inp.Type != finance.InterestRateCIR {
+		return InterestRateModel{}, fmt.Errorf("invalid interest rate model type: %s", inp.Type)
+	}
+	if inp.ReversionSpeed < 0 || inp.Volatility < 0 {
+		return InterestRateModel{}, fmt.Errorf("reversion speed and volatility must not be negative")
+	}
+	if inp.ID == "" {
+		inp.ID = sid.MustNewString(32)
+	}
+	now := time.Now().Unix()
+	m, err := s.q.UpsertInterestRateModel(ctx, pdb.UpsertInterestRateModelParams{
+		ID:             inp.ID,
+		Name:           inp.Name,
+		ModelType:      string(inp.Type),
+		InitialRate:    inp.InitialRate,
+		LongTermRate:   inp.LongTermRate,
+		ReversionSpeed: inp.ReversionSpeed,
+		Volatility:     inp.Volatility,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if err != nil {
+		return InterestRateModel{}, fmt.Errorf("upserting interest rate model: %w", err)
+	}
+	s.invalidateForecast()
+	return interestRateModelFromDB(m), nil
+}
+
+func (s *Service) GetInterestRateModel(ctx context.Context, id string) (InterestRateModel, error) {
+	m, err := s.q.GetInterestRateModel(ctx, id)
+	if err != nil {
+		return InterestRateModel{}, fmt.Errorf("getting interest rate model: %w", err)
+	}
+	return interestRateModelFromDB(m), nil
+}
+
+func (s *Service) ListInterestRateModels(ctx context.Context) ([]InterestRateModel, error) {
+	rows, err := s.q.ListInterestRateModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing interest rate models: %w", err)
+	}
+	models := make([]InterestRateModel, len(rows))
+	for i, r := range rows {
+		models[i] = interestRateModelFromDB(r)
+	}
+	return models, nil
+}
+
+func (s *Service) DeleteInterestRateModel(ctx context.Context, id string) error {
+	if err := s.q.DeleteInterestRateModel(ctx, id); err != nil {
+		return fmt.Errorf("deleting interest rate model: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// interestRateProcesses creates one rate process per model for a prediction
+// run, so every loan referencing a model follows the same path.
+func interestRateProcesses(models []InterestRateModel) map[string]*finance.InterestRateProcess {
+	processes := make(map[string]*finance.InterestRateProcess, len(models))
+	for _, m := range models {
+		processes[m.ID] = m.ToFinance()
+	}
+	return processes
+}
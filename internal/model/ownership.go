@@ -0,0 +1,159 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+// AccountOwner is a person's share of a jointly owned account, such as 50% of
+// an apartment. Shares of an account add up to at most 1; the rest is shared
+// by the household. Without any shares the account belongs entirely to its
+// OwnerID, if set.
+type AccountOwner struct {
+	AccountID string
+	PersonID  string
+	Share     float64
+}
+
+func (o AccountOwner) GetShareString() string {
+	if o.PersonID == "" {
+		return ""
+	}
+	return strconv.FormatFloat(o.Share*100, 'f', -1, 64)
+}
+
+func accountOwnerFromDB(o pdb.AccountOwner) AccountOwner {
+	return AccountOwner{
+		AccountID: o.AccountID,
+		PersonID:  o.PersonID,
+		Share:     o.Share,
+	}
+}
+
+func (s *Service) UpsertAccountOwner(ctx context.Context, inp AccountOwner) (AccountOwner, error) {
+	if inp.PersonID == "" {
+		return AccountOwner{}, fmt.Errorf("an owner is required")
+	}
+	if inp.Share <= 0 || inp.Share > 1 {
+		return AccountOwner{}, fmt.Errorf("invalid ownership share: %g", inp.Share)
+	}
+	owners, err := s.ListAccountOwners(ctx, inp.AccountID)
+	if err != nil {
+		return AccountOwner{}, err
+	}
+	total := inp.Share
+	for _, o := range owners {
+		if o.PersonID != inp.PersonID {
+			total += o.Share
+		}
+	}
+	if total > 1+1e-9 {
+		return AccountOwner{}, fmt.Errorf("ownership shares add up to %.0f%%", total*100)
+	}
+	o, err := s.q.UpsertAccountOwner(ctx, pdb.UpsertAccountOwnerParams{
+		AccountID: inp.AccountID,
+		PersonID:  inp.PersonID,
+		Share:     inp.Share,
+	})
+	if err != nil {
+		return AccountOwner{}, fmt.Errorf("upserting account owner: %w", err)
+	}
+	s.invalidateForecast()
+	return accountOwnerFromDB(o), nil
+}
+
+func (s *Service) ListAccountOwners(ctx context.Context, accountID string) ([]AccountOwner, error) {
+	rows, err := s.q.ListAccountOwnersByAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("listing account owners: %w", err)
+	}
+	owners := make([]AccountOwner, len(rows))
+	for i, r := range rows {
+		owners[i] = accountOwnerFromDB(r)
+	}
+	return owners, nil
+}
+
+func (s *Service) DeleteAccountOwner(ctx context.Context, accountID, personID string) error {
+	if err := s.q.DeleteAccountOwner(ctx, pdb.DeleteAccountOwnerParams{AccountID: accountID, PersonID: personID}); err != nil {
+		return fmt.Errorf("deleting account owner: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// Ownership is the share each person owns of each account, keyed by account
+// ID and then person ID. The household's shared remainder is keyed by "".
+type Ownership map[string]map[string]float64
+
+// Owners returns the shares of an account. Unknown accounts are shared.
+func (o Ownership) Owners(accountID string) map[string]float64 {
+	if owners, ok := o[accountID]; ok {
+		return owners
+	}
+	return map[string]float64{"": 1}
+}
+
+// Share returns the share of an account owned by personID, where an empty
+// personID stands for the whole household.
+func (o Ownership) Share(accountID, personID string) float64 {
+	if personID == "" {
+		return 1
+	}
+	return o.Owners(accountID)[personID]
+}
+
+func newOwnership(accounts []Account, owners []AccountOwner) Ownership {
+	sharesByAccount := make(map[string][]AccountOwner)
+	for _, ao := range owners {
+		sharesByAccount[ao.AccountID] = append(sharesByAccount[ao.AccountID], ao)
+	}
+	o := make(Ownership, len(accounts))
+	for _, acc := range accounts {
+		shares := make(map[string]float64)
+		if split, ok := sharesByAccount[acc.ID]; ok {
+			remainder := 1.0
+			for _, ao := range split {
+				shares[ao.PersonID] += ao.Share
+				remainder -= ao.Share
+			}
+			if remainder > 1e-9 {
+				shares[""] = remainder
+			}
+		} else if acc.OwnerID != "" {
+			shares[acc.OwnerID] = 1
+		} else {
+			shares[""] = 1
+		}
+		o[acc.ID] = shares
+	}
+	return o
+}
+
+func (s *Service) listOwnership(ctx context.Context) (Ownership, error) {
+	accounts, err := s.ListAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+	rows, err := s.q.ListAccountOwners(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing account owners: %w", err)
+	}
+	owners := make([]AccountOwner, len(rows))
+	for i, r := range rows {
+		owners[i] = accountOwnerFromDB(r)
+	}
+	return newOwnership(accounts, owners), nil
+}
+
+// scaleByShare returns the part of a balance owned at share.
+func scaleByShare(ucfg *uncertain.Config, v uncertain.Value, share float64) uncertain.Value {
+	if share == 1 {
+		return v
+	}
+	return v.ApplyFixed(ucfg, share, func(a, b float64) float64 { return a * b })
+}
@@ -0,0 +1,283 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/ui"
+)
+
+// PensionPayoutRecurrence is the day of the month pension payouts are made.
+const PensionPayoutRecurrence = date.Cron("*-*-25")
+
+// PensionPayoutPeriods lists the selectable payout periods in years, 0 meaning lifetime.
+var PensionPayoutPeriods = []int64{5, 10, 20, 0}
+
+// PensionPayout describes the payout phase of a pension account. The payout
+// starts either on StartDate or, when both BirthDate and StartAge are set, on
+// the day the holder reaches StartAge. Each monthly gross payment is the
+// remaining balance divided by the remaining number of payments, taxed as
+// pension income and paid into ToAccountID. A lifetime payout never ends, and
+// the remaining payments are the holder's remaining life expectancy. Without a BirthDate the birth date
+// of the account's owner is used in the forecast.
+type PensionPayout struct {
+	AccountID    string
+	ToAccountID  string
+	StartDate    date.Date
+	BirthDate    *date.Date
+	StartAge     *int64
+	PeriodYears  int64 // 0 means lifetime
+	Kommun       string
+	Forsamling   string
+	ChurchMember bool
+}
+
+func addYears(d date.Date, years int) date.Date {
+	return date.FromTime(d.ToStdTime().AddDate(years, 0, 0))
+}
+
+func (p PensionPayout) IsLifetime() bool {
+	return p.PeriodYears == 0
+}
+
+// PayoutStartDate returns the date of the first payout.
+func (p PensionPayout) PayoutStartDate() date.Date {
+	if p.BirthDate != nil && p.StartAge != nil {
+		return addYears(*p.BirthDate, int(*p.StartAge))
+	}
+	return p.StartDate
+}
+
+// PayoutEndDate returns the exclusive end of the payout period, or the zero
+// date for a lifetime payout, which is paid as long as the forecast runs.
+func (p PensionPayout) PayoutEndDate() date.Date {
+	if p.IsLifetime() {
+		return 0
+	}
+	return addYears(p.PayoutStartDate(), int(p.PeriodYears))
+}
+
+// PayoutMonths returns the number of monthly payments the balance is spread
+// over when the payout starts. A lifetime payout is spread over the holder's
+// remaining life expectancy.
+func (p PensionPayout) PayoutMonths() int {
+	start := p.PayoutStartDate()
+	if p.IsLifetime() {
+		return int(math.Round(p.lifetimePayouts(start)))
+	}
+	end := p.PayoutEndDate()
+	return (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+}
+
+// lifetimePayouts returns the monthly payments left of the holder's remaining
+// life expectancy on day. Without a birth date the holder is assumed to be
+// swe.DefaultPensionStartAge at the start of the payout.
+func (p PensionPayout) lifetimePayouts(day date.Date) float64 {
+	birth := addYears(p.PayoutStartDate(), -swe.DefaultPensionStartAge)
+	if p.BirthDate != nil {
+		birth = *p.BirthDate
+	}
+	age := float64(day.Sub(birth)) / 365.25
+	return 12 * swe.RemainingLifeExpectancy(age)
+}
+
+// TaxColumn returns the tax table column used for payouts in the given year.
+func (p PensionPayout) TaxColumn(year int) int {
+	if p.BirthDate == nil {
+		return swe.TaxColumnPensionOver66
+	}
+	return swe.PensionTaxColumn(p.BirthDate.Year(), year)
+}
+
+func (p PensionPayout) GetStartDateString() string {
+	if p.StartDate.IsZero() {
+		return ""
+	}
+	return p.StartDate.String()
+}
+
+func (p PensionPayout) GetBirthDateString() string {
+	if p.BirthDate == nil {
+		return ""
+	}
+	return p.BirthDate.String()
+}
+
+func (p PensionPayout) GetStartAgeString() string {
+	if p.StartAge == nil {
+		return ""
+	}
+	return strconv.FormatInt(*p.StartAge, 10)
+}
+
+func PensionPayoutPeriodLabel(years int64) string {
+	if years == 0 {
+		return "Lifetime"
+	}
+	return fmt.Sprintf("%d years", years)
+}
+
+func pensionPayoutFromDB(p pdb.PensionPayout) PensionPayout {
+	var birthDate *date.Date
+	if p.BirthDate != nil {
+		d := date.Date(*p.BirthDate)
+		birthDate = &d
+	}
+	return PensionPayout{
+		AccountID:    p.AccountID,
+		ToAccountID:  ui.OrDefault(p.ToAccountID),
+		StartDate:    date.Date(p.StartDate),
+		BirthDate:    birthDate,
+		StartAge:     p.StartAge,
+		PeriodYears:  p.PeriodYears,
+		Kommun:       p.Kommun,
+		Forsamling:   p.Forsamling,
+		ChurchMember: p.ChurchMember,
+	}
+}
+
+func (s *Service) UpsertPensionPayout(ctx context.Context, inp PensionPayout) (PensionPayout, error) {
+	if inp.PeriodYears < 0 {
+		return PensionPayout{}, fmt.Errorf("invalid payout period: %d", inp.PeriodYears)
+	}
+	var birthDate *int64
+	if inp.BirthDate != nil {
+		birthDate = ptr(int64(*inp.BirthDate))
+	}
+	now := time.Now().Unix()
+	p, err := s.q.UpsertPensionPayout(ctx, pdb.UpsertPensionPayoutParams{
+		AccountID:    inp.AccountID,
+		ToAccountID:  ui.WithDefaultNull(inp.ToAccountID),
+		StartDate:    int64(inp.StartDate),
+		BirthDate:    birthDate,
+		StartAge:     inp.StartAge,
+		PeriodYears:  inp.PeriodYears,
+		Kommun:       inp.Kommun,
+		Forsamling:   inp.Forsamling,
+		ChurchMember: inp.ChurchMember,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		return PensionPayout{}, fmt.Errorf("upserting pension payout: %w", err)
+	}
+	s.invalidateForecast()
+	return pensionPayoutFromDB(p), nil
+}
+
+func (s *Service) GetPensionPayout(ctx context.Context, accountID string) (PensionPayout, error) {
+	p, err := s.q.GetPensionPayout(ctx, accountID)
+	if err != nil {
+		return PensionPayout{}, err
+	}
+	return pensionPayoutFromDB(p), nil
+}
+
+func (s *Service) ListPensionPayouts(ctx context.Context) ([]PensionPayout, error) {
+	rows, err := s.q.ListPensionPayouts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing pension payouts: %w", err)
+	}
+	birthDates, err := s.accountOwnerBirthDates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payouts := make([]PensionPayout, len(rows))
+	for i, r := range rows {
+		payouts[i] = pensionPayoutFromDB(r)
+		if d, ok := birthDates[r.AccountID]; ok && payouts[i].BirthDate == nil {
+			payouts[i].BirthDate = &d
+		}
+	}
+	return payouts, nil
+}
+
+func (s *Service) DeletePensionPayout(ctx context.Context, accountID string) error {
+	if err := s.q.DeletePensionPayout(ctx, accountID); err != nil {
+		return fmt.Errorf("deleting pension payout: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// pensionPayoutToFinance builds the finance payout model for a pension
+// payout. Payouts are taxed with the tax table for the holder's kommun and
+// församling; without a kommun the gross amount is paid out. When a table
+// lookup fails the payout is taxed with the formula for non-work income at
+// the average municipal tax rate.
+func (s *Service) pensionPayoutToFinance(ctx context.Context, p PensionPayout) (*finance.PayoutModel, error) {
+	pm := &finance.PayoutModel{
+		StartDate:     p.PayoutStartDate(),
+		EndDate:       p.PayoutEndDate(),
+		Frequency:     PensionPayoutRecurrence,
+		DestinationID: p.ToAccountID,
+	}
+	if p.IsLifetime() {
+		pm.RemainingPayouts = p.lifetimePayouts
+	}
+	if p.Kommun == "" || p.Forsamling == "" {
+		return pm, nil
+	}
+
+	ibbs, err := s.ListSweYearlyParams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing swe yearly params: %w", err)
+	}
+	formulaNet := func(day date.Date, gross float64) float64 {
+		p := incomeTaxParamsAt(ibbs, day, swe.AverageMunicipalTaxRate)
+		p.NonWorkIncome = true
+		return gross - swe.CalculateAnnualIncomeTax(gross*12, p).TotalTax/12
+	}
+
+	// Tax tables stop at the current year, so only a handful of distinct
+	// calculators are needed for the whole payout period. The column stops
+	// changing once the holder has turned 66.
+	type calcKey struct{ year, column int }
+	calculators := make(map[calcKey]func(float64) (*swe.NetSalaryResult, error))
+	currentYear := time.Now().Year()
+	keyAt := func(year int) calcKey {
+		return calcKey{year: min(year, currentYear), column: p.TaxColumn(year)}
+	}
+	lastYear := pm.EndDate.Year()
+	if p.IsLifetime() {
+		lastYear = max(pm.StartDate.Year(), currentYear)
+		if p.BirthDate != nil {
+			lastYear = max(lastYear, p.BirthDate.Year()+67)
+		}
+	}
+	for year := pm.StartDate.Year(); year <= lastYear; year++ {
+		key := keyAt(year)
+		if _, ok := calculators[key]; !ok {
+			calc, err := s.sweClient.NetSalaryCalculator(ctx, swe.GrossSalaryInput{
+				Kommun:       p.Kommun,
+				Forsamling:   p.Forsamling,
+				Year:         strconv.Itoa(key.year),
+				ChurchMember: p.ChurchMember,
+				Column:       key.column,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("creating pension tax calculator: %w", err)
+			}
+			calculators[key] = calc
+		}
+	}
+	pm.Net = func(day date.Date, gross float64) float64 {
+		calc, ok := calculators[keyAt(day.Year())]
+		if !ok {
+			return formulaNet(day, gross)
+		}
+		res, err := calc(gross)
+		if err != nil {
+			return formulaNet(day, gross)
+		}
+		return res.NetMonthly
+	}
+	return pm, nil
+}
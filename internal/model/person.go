@@ -0,0 +1,209 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/goslu/sid"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/ui"
+)
+
+// Person is a member of the household. Accounts and salaries can be owned by
+// a person, and dates can be expressed relative to their age. Children give
+// the household barnbidrag. When TaxAccountID is set, the person's yearly
+// slutskattebesked is simulated and settled in that account.
+type Person struct {
+	ID           string
+	Name         string
+	BirthDate    date.Date
+	IsChild      bool
+	TaxAccountID string
+}
+
+// DateAtAge returns the date the person turns age.
+func (p Person) DateAtAge(age int64) date.Date {
+	return addYears(p.BirthDate, int(age))
+}
+
+// AgeOn returns the person's age in whole years on day.
+func (p Person) AgeOn(day date.Date) int {
+	age := day.Year() - p.BirthDate.Year()
+	if day < addYears(p.BirthDate, age) {
+		age--
+	}
+	return age
+}
+
+func (p Person) GetBirthDateString() string {
+	if p.BirthDate.IsZero() {
+		return ""
+	}
+	return p.BirthDate.String()
+}
+
+// PersonAge is a date relative to a person, such as the day they turn 65.
+type PersonAge struct {
+	PersonID string
+	Age      int64
+	// PersonName is set when the date is resolved against the household.
+	PersonName string
+}
+
+func (pa PersonAge) String() string {
+	return pa.PersonName + " turns " + strconv.FormatInt(pa.Age, 10)
+}
+
+func (pa *PersonAge) GetPersonID() string {
+	if pa == nil {
+		return ""
+	}
+	return pa.PersonID
+}
+
+func (pa *PersonAge) GetAgeString() string {
+	if pa == nil {
+		return ""
+	}
+	return strconv.FormatInt(pa.Age, 10)
+}
+
+func personAgeFromDB(personID *string, age int64) *PersonAge {
+	if personID == nil {
+		return nil
+	}
+	return &PersonAge{PersonID: *personID, Age: age}
+}
+
+// personAgeToDB returns the columns for a relative date, which are empty when
+// the date is absolute.
+func personAgeToDB(pa *PersonAge) (*string, int64) {
+	if pa == nil || pa.PersonID == "" {
+		return nil, 0
+	}
+	return &pa.PersonID, pa.Age
+}
+
+// Persons is the household, keyed by person ID.
+type Persons map[string]Person
+
+// resolve returns the date for a relative date, or fallback when it is
+// absolute or the person is missing. The person's name is recorded on pa.
+func (ps Persons) resolve(pa *PersonAge, fallback date.Date) date.Date {
+	if pa == nil {
+		return fallback
+	}
+	p, ok := ps[pa.PersonID]
+	if !ok || p.BirthDate.IsZero() {
+		return fallback
+	}
+	pa.PersonName = p.Name
+	return p.DateAtAge(pa.Age)
+}
+
+func personFromDB(p pdb.User) Person {
+	var birthDate date.Date
+	if p.BirthDate != nil {
+		birthDate = date.Date(*p.BirthDate)
+	}
+	return Person{
+		ID:           p.ID,
+		Name:         p.Name,
+		BirthDate:    birthDate,
+		IsChild:      p.IsChild,
+		TaxAccountID: ui.OrDefault(p.TaxAccountID),
+	}
+}
+
+func (s *Service) UpsertPerson(ctx context.Context, inp Person) (Person, error) {
+	if inp.Name == "" {
+		return Person{}, fmt.Errorf("person name is required")
+	}
+	if inp.ID == "" {
+		inp.ID = sid.MustNewString(15)
+	}
+	var birthDate *int64
+	if !inp.BirthDate.IsZero() {
+		birthDate = ptr(int64(inp.BirthDate))
+	}
+	now := time.Now().Unix()
+	p, err := s.q.UpsertPerson(ctx, pdb.UpsertPersonParams{
+		ID:           inp.ID,
+		Name:         inp.Name,
+		BirthDate:    birthDate,
+		IsChild:      inp.IsChild,
+		TaxAccountID: ui.WithDefaultNull(inp.TaxAccountID),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		return Person{}, fmt.Errorf("upserting person: %w", err)
+	}
+	s.invalidateForecast()
+	return personFromDB(p), nil
+}
+
+func (s *Service) GetPerson(ctx context.Context, id string) (Person, error) {
+	p, err := s.q.GetPerson(ctx, id)
+	if err != nil {
+		return Person{}, fmt.Errorf("getting person: %w", err)
+	}
+	return personFromDB(p), nil
+}
+
+func (s *Service) ListPersons(ctx context.Context) ([]Person, error) {
+	rows, err := s.q.ListPersons(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing persons: %w", err)
+	}
+	persons := make([]Person, len(rows))
+	for i, r := range rows {
+		persons[i] = personFromDB(r)
+	}
+	return persons, nil
+}
+
+func (s *Service) listPersonsByID(ctx context.Context) (Persons, error) {
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return KeyBy(persons, func(p Person) string { return p.ID }), nil
+}
+
+// DeletePerson removes a person from the household. Owned accounts and
+// salaries are kept without an owner, and dates relative to the person keep
+// the date they last resolved to.
+func (s *Service) DeletePerson(ctx context.Context, id string) error {
+	if err := s.q.ClearAccountOwner(ctx, &id); err != nil {
+		return fmt.Errorf("clearing account owner: %w", err)
+	}
+	if err := s.q.DeletePerson(ctx, id); err != nil {
+		return fmt.Errorf("deleting person: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// accountOwnerBirthDates returns the birth date of each owned account's
+// owner, keyed by account ID.
+func (s *Service) accountOwnerBirthDates(ctx context.Context) (map[string]date.Date, error) {
+	accounts, err := s.ListAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+	persons, err := s.listPersonsByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	birthDates := make(map[string]date.Date)
+	for _, acc := range accounts {
+		if p, ok := persons[acc.OwnerID]; ok && !p.BirthDate.IsZero() {
+			birthDates[acc.ID] = p.BirthDate
+		}
+	}
+	return birthDates, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,30 +29,61 @@ type NetSalarySegment struct {
 }
 
 type Salary struct {
-	ID                    string
-	Name                  string
-	ToAccountID           string
-	PensionAccountID      string
-	Priority              int64
-	Recurrence            date.Cron
-	BudgetCategoryID      *string
-	Enabled               bool
-	Kommun                string
-	Forsamling            string
-	ChurchMember          bool
-	IsGross               bool
+	ID               string
+	Name             string
+	ToAccountID      string
+	PensionAccountID string
+	OwnerID          string
+	Priority         int64
+	Recurrence       date.Cron
+	BudgetCategoryID *string
+	Enabled          bool
+	Kommun           string
+	Forsamling       string
+	ChurchMember     bool
+	IsGross          bool
+	TaxMethod        string
+	// MunicipalTaxRate is the total local tax rate used by the formula tax
+	// method, and as a fallback when no tax tables are available.
+	MunicipalTaxRate float64
+	// ParentalPayRate, ParentalPayAboveCeilingRate and ParentalPayMaxDays are
+	// the föräldralön rules of the employer's collective agreement. The top-up
+	// is paid for ParentalPayMaxDays calendar days from the start of each full
+	// parental leave, or the whole leave when 0.
+	ParentalPayRate             float64
+	ParentalPayAboveCeilingRate float64
+	ParentalPayMaxDays          int64
+	// SalaryExchangeAmount is the monthly gross salary exchanged into pension
+	// (löneväxling), with SalaryExchangeUplift added by the employer.
+	SalaryExchangeAmount float64
+	SalaryExchangeUplift float64
+	// SecondaryEmployer marks a salary from a secondary employer, taxed at
+	// SecondaryTaxRate (the jämkning rate), or the flat 30% when 0. The
+	// difference to the final tax is settled the following year.
+	SecondaryEmployer     bool
+	SecondaryTaxRate      float64
 	Amounts               []SalaryAmount
 	Adjustments           []SalaryAdjustment
 	PartialParentalLeaves []PartialParentalLeave
 	FullParentalLeaves    []FullParentalLeave
+	Unemployments         []Unemployment
+	SickLeaves            []SickLeave
+	Bonuses               []SalaryBonus
 	// NetSegments is populated by the service layer when IsGross is true.
 	// Segments are split at the union of salary-amount, adjustment, and PBB change dates.
 	NetSegments []NetSalarySegment
 	// PensionSegments is populated by the service layer when IsGross is true.
 	// Segments are split at the union of salary-amount and IBB change dates.
 	PensionSegments []PensionSegment
+	// BonusSegments is populated by the service layer when IsGross is true.
+	BonusSegments []BonusSegment
 }
 
+const (
+	TaxMethodTable   = "table"
+	TaxMethodFormula = "formula"
+)
+
 type SalaryAmount struct {
 	ID        string
 	SalaryID  string
@@ -145,6 +177,43 @@ func (s Salary) GenerateTransferTemplates() []TransferTemplate {
 		}
 	}
 
+	if s.IsGross && len(s.NetSegments) > 0 {
+		for _, seg := range s.BonusSegments {
+			templates = append(templates, TransferTemplate{
+				ID:               fmt.Sprintf("salary-bonus:%s:%s", seg.Bonus.ID, seg.StartDate),
+				Name:             seg.Bonus.label(s.Name),
+				FromAccountID:    "",
+				ToAccountID:      s.ToAccountID,
+				AmountType:       "fixed",
+				AmountFixed:      seg.Net,
+				Priority:         s.Priority,
+				Recurrence:       seg.Bonus.recurrence(),
+				StartDate:        seg.StartDate,
+				EndDate:          seg.EndDate,
+				Enabled:          s.Enabled,
+				BudgetCategoryID: s.BudgetCategoryID,
+				Source:           source,
+			})
+		}
+	} else {
+		for _, b := range s.Bonuses {
+			templates = append(templates, TransferTemplate{
+				ID:               "salary-bonus:" + b.ID,
+				Name:             b.label(s.Name),
+				FromAccountID:    "",
+				ToAccountID:      s.ToAccountID,
+				AmountType:       "fixed",
+				AmountFixed:      b.expectedAmount(),
+				Priority:         s.Priority,
+				Recurrence:       b.recurrence(),
+				StartDate:        b.PayDate,
+				Enabled:          s.Enabled,
+				BudgetCategoryID: s.BudgetCategoryID,
+				Source:           source,
+			})
+		}
+	}
+
 	// Pension TTs: split at both salary-amount and IBB change boundaries
 	if s.IsGross && s.PensionAccountID != "" {
 		for i, seg := range s.PensionSegments {
@@ -164,6 +233,23 @@ func (s Salary) GenerateTransferTemplates() []TransferTemplate {
 				Source:           source,
 			})
 		}
+		for _, seg := range s.BonusSegments {
+			templates = append(templates, TransferTemplate{
+				ID:               fmt.Sprintf("salary-bonus-pension:%s:%s", seg.Bonus.ID, seg.StartDate),
+				Name:             seg.Bonus.label(s.Name) + " (pension)",
+				FromAccountID:    "",
+				ToAccountID:      s.PensionAccountID,
+				AmountType:       "fixed",
+				AmountFixed:      seg.Pension,
+				Priority:         s.Priority,
+				Recurrence:       seg.Bonus.recurrence(),
+				StartDate:        seg.StartDate,
+				EndDate:          seg.EndDate,
+				Enabled:          s.Enabled,
+				BudgetCategoryID: s.BudgetCategoryID,
+				Source:           source,
+			})
+		}
 	}
 
 	return templates
@@ -171,18 +257,28 @@ func (s Salary) GenerateTransferTemplates() []TransferTemplate {
 
 func salaryFromDB(s pdb.Salary) Salary {
 	return Salary{
-		ID:               s.ID,
-		Name:             s.Name,
-		ToAccountID:      ui.OrDefault(s.ToAccountID),
-		PensionAccountID: ui.OrDefault(s.PensionAccountID),
-		Priority:         s.Priority,
-		Recurrence:       date.Cron(s.Recurrence),
-		BudgetCategoryID: s.BudgetCategoryID,
-		Enabled:          s.Enabled,
-		Kommun:           s.Kommun,
-		Forsamling:       s.Forsamling,
-		ChurchMember:     s.ChurchMember,
-		IsGross:          s.IsGross,
+		ID:                          s.ID,
+		Name:                        s.Name,
+		ToAccountID:                 ui.OrDefault(s.ToAccountID),
+		PensionAccountID:            ui.OrDefault(s.PensionAccountID),
+		OwnerID:                     ui.OrDefault(s.OwnerID),
+		Priority:                    s.Priority,
+		Recurrence:                  date.Cron(s.Recurrence),
+		BudgetCategoryID:            s.BudgetCategoryID,
+		Enabled:                     s.Enabled,
+		Kommun:                      s.Kommun,
+		Forsamling:                  s.Forsamling,
+		ChurchMember:                s.ChurchMember,
+		IsGross:                     s.IsGross,
+		TaxMethod:                   s.TaxMethod,
+		MunicipalTaxRate:            s.MunicipalTaxRate,
+		ParentalPayRate:             s.ParentalPayRate,
+		ParentalPayAboveCeilingRate: s.ParentalPayAboveCeilingRate,
+		ParentalPayMaxDays:          s.ParentalPayMaxDays,
+		SalaryExchangeAmount:        s.SalaryExchangeAmount,
+		SalaryExchangeUplift:        s.SalaryExchangeUplift,
+		SecondaryEmployer:           s.SecondaryEmployer,
+		SecondaryTaxRate:            s.SecondaryTaxRate,
 	}
 }
 
@@ -205,20 +301,30 @@ func (s *Service) UpsertSalary(ctx context.Context, inp Salary) (Salary, error)
 	}
 	now := time.Now().Unix()
 	sal, err := s.q.UpsertSalary(ctx, pdb.UpsertSalaryParams{
-		ID:               inp.ID,
-		Name:             inp.Name,
-		ToAccountID:      ui.WithDefaultNull(inp.ToAccountID),
-		PensionAccountID: ui.WithDefaultNull(inp.PensionAccountID),
-		Priority:         inp.Priority,
-		Recurrence:       string(inp.Recurrence),
-		BudgetCategoryID: inp.BudgetCategoryID,
-		Enabled:          inp.Enabled,
-		Kommun:           inp.Kommun,
-		Forsamling:       inp.Forsamling,
-		ChurchMember:     inp.ChurchMember,
-		IsGross:          inp.IsGross,
-		CreatedAt:        now,
-		UpdatedAt:        now,
+		ID:                          inp.ID,
+		Name:                        inp.Name,
+		ToAccountID:                 ui.WithDefaultNull(inp.ToAccountID),
+		PensionAccountID:            ui.WithDefaultNull(inp.PensionAccountID),
+		OwnerID:                     ui.WithDefaultNull(inp.OwnerID),
+		Priority:                    inp.Priority,
+		Recurrence:                  string(inp.Recurrence),
+		BudgetCategoryID:            inp.BudgetCategoryID,
+		Enabled:                     inp.Enabled,
+		Kommun:                      inp.Kommun,
+		Forsamling:                  inp.Forsamling,
+		ChurchMember:                inp.ChurchMember,
+		IsGross:                     inp.IsGross,
+		TaxMethod:                   inp.TaxMethod,
+		MunicipalTaxRate:            inp.MunicipalTaxRate,
+		ParentalPayRate:             inp.ParentalPayRate,
+		ParentalPayAboveCeilingRate: inp.ParentalPayAboveCeilingRate,
+		ParentalPayMaxDays:          inp.ParentalPayMaxDays,
+		SalaryExchangeAmount:        inp.SalaryExchangeAmount,
+		SalaryExchangeUplift:        inp.SalaryExchangeUplift,
+		SecondaryEmployer:           inp.SecondaryEmployer,
+		SecondaryTaxRate:            inp.SecondaryTaxRate,
+		CreatedAt:                   now,
+		UpdatedAt:                   now,
 	})
 	if err != nil {
 		return Salary{}, fmt.Errorf("upserting salary: %w", err)
@@ -264,6 +370,21 @@ func (s *Service) GetSalary(ctx context.Context, id string) (Salary, error) {
 		return Salary{}, fmt.Errorf("listing full parental leaves: %w", err)
 	}
 	result.FullParentalLeaves = fullPLs
+	unemployments, err := s.ListUnemployments(ctx, id)
+	if err != nil {
+		return Salary{}, err
+	}
+	result.Unemployments = unemployments
+	sickLeaves, err := s.ListSickLeaves(ctx, id)
+	if err != nil {
+		return Salary{}, err
+	}
+	result.SickLeaves = sickLeaves
+	bonuses, err := s.ListSalaryBonuses(ctx, id)
+	if err != nil {
+		return Salary{}, err
+	}
+	result.Bonuses = bonuses
 	return result, nil
 }
 
@@ -512,6 +633,40 @@ func activePartialParentalLeaveAt(leaves []PartialParentalLeave, d date.Date) *P
 	return nil
 }
 
+// addMonths returns d moved n calendar months.
+func addMonths(d date.Date, n int) date.Date {
+	return date.FromTime(d.ToStdTime().AddDate(0, n, 0))
+}
+
+// monthsSince returns the month (0-based) of a period starting at start that
+// d falls in.
+func monthsSince(start, d date.Date) int {
+	month := 0
+	for addMonths(start, month+1) <= d {
+		month++
+	}
+	return month
+}
+
+// monthlyChangeDates returns the start of each month of the period from start
+// to end, and end, for benefits that change every month of a period.
+func monthlyChangeDates(start, end date.Date) []date.Date {
+	var dates []date.Date
+	for m := 0; addMonths(start, m) < end; m++ {
+		dates = append(dates, addMonths(start, m))
+	}
+	return append(dates, end)
+}
+
+// parentalPayEnd returns the date föräldralön stops during the leave: maxDays
+// after its start, or its end when maxDays is 0 or outlasts the leave.
+func (fpl FullParentalLeave) parentalPayEnd(maxDays int64) date.Date {
+	if end := fpl.StartDate.Add(date.Duration(maxDays)); maxDays > 0 && end < fpl.EndDate {
+		return end
+	}
+	return fpl.EndDate
+}
+
 func activeFullParentalLeaveAt(leaves []FullParentalLeave, d date.Date) *FullParentalLeave {
 	for i := range leaves {
 		if leaves[i].StartDate <= d && d < leaves[i].EndDate {
@@ -553,6 +708,66 @@ func (sa SalaryAmount) GetAmountString() string {
 	return sa.Amount.SimpleEncode()
 }
 
+func (s Salary) GetMunicipalTaxRateString() string {
+	if s.MunicipalTaxRate == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", s.MunicipalTaxRate*100)
+}
+
+func (s Salary) GetParentalPayRateString() string {
+	if s.ParentalPayRate == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(s.ParentalPayRate*100, 'f', -1, 64)
+}
+
+func (s Salary) GetParentalPayAboveCeilingRateString() string {
+	if s.ParentalPayAboveCeilingRate == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(s.ParentalPayAboveCeilingRate*100, 'f', -1, 64)
+}
+
+func (s Salary) GetParentalPayMaxDaysString() string {
+	if s.ParentalPayMaxDays == 0 {
+		return ""
+	}
+	return strconv.FormatInt(s.ParentalPayMaxDays, 10)
+}
+
+func (s Salary) parentalPay() swe.ParentalPay {
+	return swe.ParentalPay{
+		Rate:             s.ParentalPayRate,
+		AboveCeilingRate: s.ParentalPayAboveCeilingRate,
+	}
+}
+
+func (s Salary) GetSalaryExchangeUpliftString() string {
+	if s.ID == "" {
+		return ""
+	}
+	return strconv.FormatFloat(s.SalaryExchangeUplift*100, 'f', -1, 64)
+}
+
+func (s Salary) GetSecondaryTaxRateString() string {
+	if s.SecondaryTaxRate == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(s.SecondaryTaxRate*100, 'f', -1, 64)
+}
+
+func (s Salary) secondaryTaxRate() float64 {
+	if s.SecondaryTaxRate > 0 {
+		return s.SecondaryTaxRate
+	}
+	return swe.SecondaryIncomeTaxRate
+}
+
+func (s Salary) salaryExchange() swe.SalaryExchange {
+	return swe.SalaryExchange{Amount: s.SalaryExchangeAmount, Uplift: s.SalaryExchangeUplift}
+}
+
 func (s Salary) CurrentAmount() float64 {
 	today := date.Today()
 	var current *SalaryAmount
@@ -574,6 +789,7 @@ type SalaryEditView struct {
 	Accounts   []Account
 	Categories []TransferTemplateCategory
 	Breakdowns []NetSalarySegmentBreakdown
+	Persons    []Person
 }
 
 func (v SalaryEditView) IsEdit() bool {
@@ -612,9 +828,14 @@ func (s *Service) GetSalaryNewPageData(ctx context.Context) (*SalaryEditView, er
 	if err != nil {
 		return nil, fmt.Errorf("listing categories: %w", err)
 	}
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
 	return &SalaryEditView{
 		Accounts:   accs,
 		Categories: categories,
+		Persons:    persons,
 	}, nil
 }
 
@@ -631,34 +852,58 @@ func (s *Service) GetSalaryEditPageData(ctx context.Context, id string) (*Salary
 	if err != nil {
 		return nil, fmt.Errorf("listing categories: %w", err)
 	}
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
 	return &SalaryEditView{
 		Salary:     sal,
 		Accounts:   accs,
 		Categories: categories,
+		Persons:    persons,
 	}, nil
 }
 
 func (s *Service) generateSalaryTransferTemplates(ctx context.Context) ([]TransferTemplate, error) {
+	salaries, ibbs, err := s.listComputedSalaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var templates []TransferTemplate
+	for _, salary := range salaries {
+		templates = append(templates, salary.GenerateTransferTemplates()...)
+	}
+	reconciliation, err := s.generateSalaryTaxReconciliationTemplates(ctx, salaries, ibbs)
+	if err != nil {
+		return nil, fmt.Errorf("reconciling salary tax: %w", err)
+	}
+	return append(templates, reconciliation...), nil
+}
+
+// listComputedSalaries returns all salaries with their amounts, leaves and
+// bonuses, and the net, pension and bonus segments of gross salaries, along
+// with the yearly params they were computed with.
+func (s *Service) listComputedSalaries(ctx context.Context) ([]Salary, []SweYearlyParams, error) {
 	salaries, err := s.q.ListSalaries(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("listing salaries: %w", err)
+		return nil, nil, fmt.Errorf("listing salaries: %w", err)
 	}
 	allAmounts, err := s.q.ListAllSalaryAmounts(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("listing salary amounts: %w", err)
+		return nil, nil, fmt.Errorf("listing salary amounts: %w", err)
 	}
 	amountsBySalary := make(map[string][]SalaryAmount)
 	for _, a := range allAmounts {
 		parsed, err := salaryAmountFromDB(a)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		amountsBySalary[a.SalaryID] = append(amountsBySalary[a.SalaryID], parsed)
 	}
 
 	allAdjustments, err := s.q.ListAllSalaryAdjustments(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("listing salary adjustments: %w", err)
+		return nil, nil, fmt.Errorf("listing salary adjustments: %w", err)
 	}
 	adjustmentsBySalary := make(map[string][]SalaryAdjustment)
 	for _, a := range allAdjustments {
@@ -667,7 +912,7 @@ func (s *Service) generateSalaryTransferTemplates(ctx context.Context) ([]Transf
 
 	allPartialPLs, err := s.q.ListAllPartialParentalLeaves(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("listing partial parental leaves: %w", err)
+		return nil, nil, fmt.Errorf("listing partial parental leaves: %w", err)
 	}
 	partialPLsBySalary := make(map[string][]PartialParentalLeave)
 	for _, a := range allPartialPLs {
@@ -676,38 +921,77 @@ func (s *Service) generateSalaryTransferTemplates(ctx context.Context) ([]Transf
 
 	allFullPLs, err := s.q.ListAllFullParentalLeaves(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("listing full parental leaves: %w", err)
+		return nil, nil, fmt.Errorf("listing full parental leaves: %w", err)
 	}
 	fullPLsBySalary := make(map[string][]FullParentalLeave)
 	for _, a := range allFullPLs {
 		fullPLsBySalary[a.SalaryID] = append(fullPLsBySalary[a.SalaryID], fullParentalLeaveFromDB(a))
 	}
 
+	allUnemployments, err := s.q.ListAllUnemployments(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing unemployments: %w", err)
+	}
+	unemploymentsBySalary := make(map[string][]Unemployment)
+	for _, u := range allUnemployments {
+		unemploymentsBySalary[u.SalaryID] = append(unemploymentsBySalary[u.SalaryID], unemploymentFromDB(u))
+	}
+
+	allSickLeaves, err := s.q.ListAllSickLeaves(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing sick leaves: %w", err)
+	}
+	sickLeavesBySalary := make(map[string][]SickLeave)
+	for _, sl := range allSickLeaves {
+		sickLeavesBySalary[sl.SalaryID] = append(sickLeavesBySalary[sl.SalaryID], sickLeaveFromDB(sl))
+	}
+
+	allBonuses, err := s.q.ListAllSalaryBonuses(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing salary bonuses: %w", err)
+	}
+	bonusesBySalary := make(map[string][]SalaryBonus)
+	for _, b := range allBonuses {
+		parsed, err := salaryBonusFromDB(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		bonusesBySalary[b.SalaryID] = append(bonusesBySalary[b.SalaryID], parsed)
+	}
+
 	ibbs, err := s.ListSweYearlyParams(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("listing swe yearly params: %w", err)
+		return nil, nil, fmt.Errorf("listing swe yearly params: %w", err)
 	}
 
-	var templates []TransferTemplate
+	computed := make([]Salary, 0, len(salaries))
 	for _, sal := range salaries {
 		salary := salaryFromDB(sal)
 		salary.Amounts = amountsBySalary[salary.ID]
 		salary.Adjustments = adjustmentsBySalary[salary.ID]
 		salary.PartialParentalLeaves = partialPLsBySalary[salary.ID]
 		salary.FullParentalLeaves = fullPLsBySalary[salary.ID]
+		salary.Unemployments = unemploymentsBySalary[salary.ID]
+		salary.SickLeaves = sickLeavesBySalary[salary.ID]
+		salary.Bonuses = bonusesBySalary[salary.ID]
 
-		if salary.IsGross && salary.Kommun != "" && salary.Forsamling != "" {
+		if salary.IsGross && salary.HasTaxSetup() {
 			netSegs, err := s.computeNetSegments(ctx, salary, ibbs)
 			if err != nil {
-				return nil, fmt.Errorf("computing net segments: %w", err)
+				return nil, nil, fmt.Errorf("computing net segments: %w", err)
 			}
 			salary.NetSegments = netSegs
 			salary.PensionSegments = s.computePensionSegments(ctx, salary, ibbs)
+			bonusSegs, err := s.computeBonusSegments(ctx, salary, ibbs)
+			if err != nil {
+				return nil, nil, fmt.Errorf("computing bonus segments: %w", err)
+			}
+			salary.BonusSegments = bonusSegs
 		}
 
-		templates = append(templates, salary.GenerateTransferTemplates()...)
+		computed = append(computed, salary)
 	}
-	return templates, nil
+	return computed, ibbs, nil
 }
 
 // computeNetSegments builds net salary segments split at the union of
@@ -749,6 +1033,23 @@ func (s *Service) computeNetSegments(ctx context.Context, sal Salary, ibbs []Swe
 		if fpl.EndDate >= sorted[0].StartDate {
 			dateSet[fpl.EndDate] = struct{}{}
 		}
+		if payEnd := fpl.parentalPayEnd(sal.ParentalPayMaxDays); payEnd >= sorted[0].StartDate {
+			dateSet[payEnd] = struct{}{}
+		}
+	}
+	for _, u := range sal.Unemployments {
+		for _, d := range monthlyChangeDates(u.StartDate, u.EndDate) {
+			if d >= sorted[0].StartDate {
+				dateSet[d] = struct{}{}
+			}
+		}
+	}
+	for _, sl := range sal.SickLeaves {
+		for _, d := range monthlyChangeDates(sl.StartDate, sl.EndDate) {
+			if d >= sorted[0].StartDate {
+				dateSet[d] = struct{}{}
+			}
+		}
 	}
 	for _, ibb := range ibbs {
 		if ibb.ValidFrom >= sorted[0].StartDate {
@@ -769,25 +1070,19 @@ func (s *Service) computeNetSegments(ctx context.Context, sal Salary, ibbs []Swe
 			continue
 		}
 
-		year := strings.SplitN(d.String(), "-", 2)[0]
-		calculator, err := s.sweClient.NetSalaryCalculator(ctx, swe.GrossSalaryInput{
-			Kommun:       sal.Kommun,
-			Forsamling:   sal.Forsamling,
-			Year:         year,
-			ChurchMember: sal.ChurchMember,
-			Column:       1,
-		})
+		taxFunc, err := s.salaryTaxFunc(ctx, sal, d, ibbs)
 		if err != nil {
-			return nil, fmt.Errorf("creating net salary calculator: %w", err)
+			return nil, err
 		}
 
 		adj := activeSalaryAdjustmentAt(sal.Adjustments, d)
 		pbb := activePBBAt(ibbs, d)
 		ppl := activePartialParentalLeaveAt(sal.PartialParentalLeaves, d)
 		fpl := activeFullParentalLeaveAt(sal.FullParentalLeaves, d)
+		unemployment := activeUnemploymentAt(sal.Unemployments, d)
+		sickLeave := activeSickLeaveAt(sal.SickLeaves, d)
 
 		gross := *grossAmount
-		calc := calculator
 		adjParams := swe.SalaryAdjustmentParams{
 			YearlyVacationDays:   adj.VacationDaysPerYear,
 			SickDaysPerOccasion:  adj.SickDaysPerOccasion,
@@ -805,20 +1100,72 @@ func (s *Service) computeNetSegments(ctx context.Context, sal Salary, ibbs []Swe
 		}
 
 		var net uncertain.Value
-		if fplActive != nil {
+		if unemployment != nil {
+			benefitTax, err := s.benefitTaxFunc(ctx, sal, d, ibbs)
+			if err != nil {
+				return nil, err
+			}
+			month := monthsSince(unemployment.StartDate, d)
+			member, ins := unemployment.AKassaMember, unemployment.incomeInsurance()
+			net = uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+				akassa, topUp := swe.UnemploymentMonthlyBenefit(gross.Sample(cfg), month, member, ins)
+				benefit := akassa + topUp
+				tax, err := benefitTax(benefit)
+				if err != nil {
+					return benefit
+				}
+				return benefit - tax
+			})
+		} else if sickLeave != nil {
+			benefitTax, err := s.benefitTaxFunc(ctx, sal, d, ibbs)
+			if err != nil {
+				return nil, err
+			}
+			firstDay, days := sickLeave.monthDays(d)
+			params := sickLeave.params(pbbVal)
+			net = uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+				income := swe.CalculateSickLeaveIncome(gross.Sample(cfg), firstDay, days, params)
+				taxable := income.Taxable()
+				tax, err := swe.TaxSickLeaveIncome(income, taxFunc, benefitTax)
+				if err != nil {
+					return taxable
+				}
+				return taxable - tax
+			})
+		} else if fplActive != nil {
+			var parentalPay swe.ParentalPay
+			if d < fplActive.parentalPayEnd(sal.ParentalPayMaxDays) {
+				parentalPay = sal.parentalPay()
+			}
 			net = uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
-				return swe.CalculateFullParentalLeaveCompensation(gross.Sample(cfg), fplActive.SjukDaysPerWeek, pbbVal)
+				sampled := gross.Sample(cfg)
+				comp := swe.CalculateFullParentalLeaveCompensation(sampled, fplActive.SjukDaysPerWeek, pbbVal)
+				pay := swe.CalculateParentalPay(sampled, fplActive.SjukDaysPerWeek, pbbVal, parentalPay)
+				if pay == 0 {
+					return comp
+				}
+				base, err := taxFunc(comp)
+				if err != nil {
+					return comp + pay
+				}
+				total, err := taxFunc(comp + pay)
+				if err != nil {
+					return comp + pay
+				}
+				return comp + pay - (total - base)
 			})
 		} else {
+			exchange := sal.salaryExchange()
 			net = uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
 				sampled := gross.Sample(cfg)
+				sampled -= exchange.Exchanged(sampled)
 				adjusted := swe.AdjustGrossSalary(sampled, adjParams)
 				adjusted -= swe.CalculatePartialParentalLeaveDeduction(sampled, pplSjuk, pplLagsta, pplSkipped, pbbVal)
-				res, err := calc(adjusted)
+				tax, err := taxFunc(adjusted)
 				if err != nil {
 					return adjusted
 				}
-				return res.NetMonthly
+				return adjusted - tax
 			})
 		}
 
@@ -837,6 +1184,76 @@ func (s *Service) computeNetSegments(ctx context.Context, sal Salary, ibbs []Swe
 	return segments, nil
 }
 
+// HasTaxSetup reports whether the salary has enough information to compute
+// income tax: a kommun and församling for the tax tables, or a municipal tax
+// rate for the formula-based calculation. Secondary employers always withhold
+// a flat rate.
+func (s Salary) HasTaxSetup() bool {
+	if s.SecondaryEmployer {
+		return true
+	}
+	if s.TaxMethod == TaxMethodFormula {
+		return s.MunicipalTaxRate > 0
+	}
+	return s.Kommun != "" && s.Forsamling != ""
+}
+
+// salaryTaxFunc returns the monthly tax function for a gross salary at the
+// given date. The table method uses the Skatteverket tax tables and falls
+// back to the formula when the tables cannot be fetched and a municipal tax
+// rate is known. The formula method works offline and for future years.
+// Secondary employers withhold a flat rate.
+func (s *Service) salaryTaxFunc(ctx context.Context, sal Salary, d date.Date, params []SweYearlyParams) (func(float64) (float64, error), error) {
+	return s.incomeTaxFunc(ctx, sal, d, params, false)
+}
+
+// benefitTaxFunc returns the monthly tax function for benefits that replace
+// the salary, such as a-kassa and sjukpenning. They are not work income, so
+// they get no jobbskatteavdrag and use column 3 of the tax tables like
+// pension before 66.
+func (s *Service) benefitTaxFunc(ctx context.Context, sal Salary, d date.Date, params []SweYearlyParams) (func(float64) (float64, error), error) {
+	return s.incomeTaxFunc(ctx, sal, d, params, true)
+}
+
+func (s *Service) incomeTaxFunc(ctx context.Context, sal Salary, d date.Date, params []SweYearlyParams, nonWork bool) (func(float64) (float64, error), error) {
+	if sal.SecondaryEmployer {
+		return swe.NewFlatTaxFunc(sal.secondaryTaxRate()), nil
+	}
+	formula := func() func(float64) (float64, error) {
+		p := incomeTaxParamsAt(params, d, sal.MunicipalTaxRate)
+		p.NonWorkIncome = nonWork
+		return swe.NewFormulaTaxFunc(p)
+	}
+	if sal.TaxMethod == TaxMethodFormula {
+		return formula(), nil
+	}
+	column := 1
+	if nonWork {
+		column = swe.TaxColumnPensionUnder66
+	}
+	year := strings.SplitN(d.String(), "-", 2)[0]
+	calculator, err := s.sweClient.NetSalaryCalculator(ctx, swe.GrossSalaryInput{
+		Kommun:       sal.Kommun,
+		Forsamling:   sal.Forsamling,
+		Year:         year,
+		ChurchMember: sal.ChurchMember,
+		Column:       column,
+	})
+	if err != nil {
+		if sal.MunicipalTaxRate > 0 {
+			return formula(), nil
+		}
+		return nil, fmt.Errorf("creating net salary calculator: %w", err)
+	}
+	return func(gross float64) (float64, error) {
+		res, err := calculator(gross)
+		if err != nil {
+			return 0, err
+		}
+		return res.Tax, nil
+	}, nil
+}
+
 type NetSalarySegmentBreakdown struct {
 	StartDate date.Date
 	EndDate   *date.Date
@@ -850,7 +1267,7 @@ func (s *Service) ComputeSalaryBreakdowns(ctx context.Context, salaryID string)
 	if err != nil {
 		return nil, fmt.Errorf("getting salary: %w", err)
 	}
-	if !sal.IsGross || sal.Kommun == "" || sal.Forsamling == "" || len(sal.Amounts) == 0 {
+	if !sal.IsGross || !sal.HasTaxSetup() || len(sal.Amounts) == 0 {
 		return nil, nil
 	}
 
@@ -893,6 +1310,23 @@ func (s *Service) computeBreakdownSegments(ctx context.Context, sal Salary, ibbs
 		if fpl.EndDate >= sorted[0].StartDate {
 			dateSet[fpl.EndDate] = struct{}{}
 		}
+		if payEnd := fpl.parentalPayEnd(sal.ParentalPayMaxDays); payEnd >= sorted[0].StartDate {
+			dateSet[payEnd] = struct{}{}
+		}
+	}
+	for _, u := range sal.Unemployments {
+		for _, d := range monthlyChangeDates(u.StartDate, u.EndDate) {
+			if d >= sorted[0].StartDate {
+				dateSet[d] = struct{}{}
+			}
+		}
+	}
+	for _, sl := range sal.SickLeaves {
+		for _, d := range monthlyChangeDates(sl.StartDate, sl.EndDate) {
+			if d >= sorted[0].StartDate {
+				dateSet[d] = struct{}{}
+			}
+		}
 	}
 	for _, ibb := range ibbs {
 		if ibb.ValidFrom >= sorted[0].StartDate {
@@ -918,8 +1352,32 @@ func (s *Service) computeBreakdownSegments(ctx context.Context, sal Salary, ibbs
 		pbb := activePBBAt(ibbs, d)
 
 		var bd swe.SalaryBreakdown
-		if fpl != nil {
+		if unemployment := activeUnemploymentAt(sal.Unemployments, d); unemployment != nil {
+			taxFunc, err := s.benefitTaxFunc(ctx, sal, d, ibbs)
+			if err != nil {
+				return nil, err
+			}
+			bd = swe.CalculateUnemploymentBreakdown(grossMean, monthsSince(unemployment.StartDate, d), unemployment.AKassaMember, unemployment.incomeInsurance(), taxFunc)
+		} else if sickLeave := activeSickLeaveAt(sal.SickLeaves, d); sickLeave != nil {
+			taxFunc, err := s.salaryTaxFunc(ctx, sal, d, ibbs)
+			if err != nil {
+				return nil, err
+			}
+			benefitTax, err := s.benefitTaxFunc(ctx, sal, d, ibbs)
+			if err != nil {
+				return nil, err
+			}
+			firstDay, days := sickLeave.monthDays(d)
+			bd = swe.CalculateSickLeaveBreakdown(grossMean, firstDay, days, sickLeave.params(pbb), taxFunc, benefitTax)
+		} else if fpl != nil {
 			bd = swe.CalculateFullParentalLeaveBreakdown(grossMean, fpl.SjukDaysPerWeek, pbb)
+			if d < fpl.parentalPayEnd(sal.ParentalPayMaxDays) {
+				taxFunc, err := s.salaryTaxFunc(ctx, sal, d, ibbs)
+				if err != nil {
+					return nil, err
+				}
+				bd = swe.AddParentalPay(bd, swe.CalculateParentalPay(grossMean, fpl.SjukDaysPerWeek, pbb, sal.parentalPay()), taxFunc)
+			}
 		} else {
 			adj := activeSalaryAdjustmentAt(sal.Adjustments, d)
 			adjParams := swe.SalaryAdjustmentParams{
@@ -937,25 +1395,17 @@ func (s *Service) computeBreakdownSegments(ctx context.Context, sal Salary, ibbs
 				pplSkipped = ppl.SkippedWorkDaysPerYear
 			}
 
-			year := strings.SplitN(d.String(), "-", 2)[0]
-			calculator, err := s.sweClient.NetSalaryCalculator(ctx, swe.GrossSalaryInput{
-				Kommun:       sal.Kommun,
-				Forsamling:   sal.Forsamling,
-				Year:         year,
-				ChurchMember: sal.ChurchMember,
-				Column:       1,
-			})
+			taxFunc, err := s.salaryTaxFunc(ctx, sal, d, ibbs)
 			if err != nil {
-				return nil, fmt.Errorf("creating net salary calculator: %w", err)
+				return nil, err
 			}
-			taxFunc := func(adjustedGross float64) (float64, error) {
-				res, err := calculator(adjustedGross)
-				if err != nil {
-					return 0, err
-				}
-				return res.Tax, nil
+			exchanged := sal.salaryExchange().Exchanged(grossMean)
+			bd = swe.CalculateSalaryBreakdown(grossMean-exchanged, adjParams, pplSjuk, pplLagsta, pplSkipped, pbb, taxFunc)
+			if exchanged > 0 {
+				bd.GrossMonthly = grossMean
+				bd.SalaryExchange = exchanged
+				bd.BelowSalaryExchangeThreshold = swe.BelowSalaryExchangeThreshold(grossMean-exchanged, activeIBBAt(ibbs, d))
 			}
-			bd = swe.CalculateSalaryBreakdown(grossMean, adjParams, pplSjuk, pplLagsta, pplSkipped, pbb, taxFunc)
 		}
 
 		var endDate *date.Date
@@ -976,6 +1426,8 @@ func (s *Service) computeBreakdownSegments(ctx context.Context, sal Salary, ibbs
 // computePensionSegments builds pension segments split at the union of
 // salary-amount and IBB change-point dates. Each segment's Pension is a
 // mapped uncertain.Value that derives pension from the gross amount.
+// ITP1 is based on the salary before löneväxling, with the exchanged salary
+// and its uplift paid on top.
 func (s *Service) computePensionSegments(_ context.Context, sal Salary, ibbs []SweYearlyParams) []PensionSegment {
 	if len(sal.Amounts) == 0 {
 		return nil
@@ -1016,8 +1468,10 @@ func (s *Service) computePensionSegments(_ context.Context, sal Salary, ibbs []S
 
 		gross := *grossAmount
 		ibbVal := ibb
+		exchange := sal.salaryExchange()
 		pension := uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
-			return swe.CalculateITP1Pension(gross.Sample(cfg), ibbVal)
+			sampled := gross.Sample(cfg)
+			return swe.CalculateITP1Pension(sampled, ibbVal) + exchange.Premium(sampled)
 		})
 
 		var endDate *date.Date
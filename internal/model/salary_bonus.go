@@ -0,0 +1,236 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/goslu/sid"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+// SalaryBonus is a gross bonus paid on top of a salary, once on PayDate or,
+// when Yearly, on the same day every year from PayDate. The bonus is paid
+// with Probability, and taxed as an engångsbelopp rather than with the
+// monthly tax table.
+type SalaryBonus struct {
+	ID          string
+	SalaryID    string
+	Name        string
+	Amount      uncertain.Value
+	Probability float64
+	PayDate     date.Date
+	Yearly      bool
+}
+
+// BonusSegment is a bonus payment with its net amount and pension
+// contribution, split at the salary-amount and IBB change dates for yearly
+// bonuses.
+type BonusSegment struct {
+	Bonus     SalaryBonus
+	StartDate date.Date
+	EndDate   *date.Date
+	Net       uncertain.Value
+	Pension   uncertain.Value
+}
+
+func (b SalaryBonus) recurrence() date.Cron {
+	if b.Yearly {
+		return date.Cron(fmt.Sprintf("*-%02d-%02d", b.PayDate.Month(), b.PayDate.Day()))
+	}
+	return date.Cron(b.PayDate.String())
+}
+
+func (b SalaryBonus) label(salaryName string) string {
+	if b.Name == "" {
+		return salaryName + " (bonus)"
+	}
+	return salaryName + " (" + b.Name + ")"
+}
+
+// sample draws the gross bonus, which is 0 when the bonus isn't paid.
+func (b SalaryBonus) sample(cfg *uncertain.Config) float64 {
+	if b.Probability < 1 && cfg.RNG.Float64() >= b.Probability {
+		return 0
+	}
+	return b.Amount.Sample(cfg)
+}
+
+// expectedAmount is the gross bonus weighted by its probability.
+func (b SalaryBonus) expectedAmount() uncertain.Value {
+	if b.Probability >= 1 {
+		return b.Amount
+	}
+	return uncertain.NewMapped(b.sample)
+}
+
+func (b SalaryBonus) GetPayDateString() string {
+	if b.ID == "" {
+		return ""
+	}
+	return b.PayDate.String()
+}
+
+func (b SalaryBonus) GetAmountString() string {
+	if b.ID == "" {
+		return ""
+	}
+	return b.Amount.SimpleEncode()
+}
+
+func (b SalaryBonus) GetProbabilityString() string {
+	return strconv.FormatFloat(b.Probability*100, 'f', -1, 64)
+}
+
+func salaryBonusFromDB(b pdb.SalaryBonus) (SalaryBonus, error) {
+	var amount uncertain.Value
+	if err := amount.Decode(b.Amount); err != nil {
+		return SalaryBonus{}, fmt.Errorf("decoding bonus amount: %w", err)
+	}
+	return SalaryBonus{
+		ID:          b.ID,
+		SalaryID:    b.SalaryID,
+		Name:        b.Name,
+		Amount:      amount,
+		Probability: b.Probability,
+		PayDate:     date.Date(b.PayDate),
+		Yearly:      b.Yearly,
+	}, nil
+}
+
+func (s *Service) UpsertSalaryBonus(ctx context.Context, inp SalaryBonus) (SalaryBonus, error) {
+	if inp.Probability <= 0 || inp.Probability > 1 {
+		return SalaryBonus{}, fmt.Errorf("invalid bonus probability: %g", inp.Probability)
+	}
+	if inp.ID == "" {
+		inp.ID = sid.MustNewString(32)
+	}
+	encoded, err := inp.Amount.Encode()
+	if err != nil {
+		return SalaryBonus{}, fmt.Errorf("encoding bonus amount: %w", err)
+	}
+	now := time.Now().Unix()
+	b, err := s.q.UpsertSalaryBonus(ctx, pdb.UpsertSalaryBonusParams{
+		ID:          inp.ID,
+		SalaryID:    inp.SalaryID,
+		Name:        inp.Name,
+		Amount:      encoded,
+		Probability: inp.Probability,
+		PayDate:     int64(inp.PayDate),
+		Yearly:      inp.Yearly,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return SalaryBonus{}, fmt.Errorf("upserting salary bonus: %w", err)
+	}
+	s.invalidateForecast()
+	return salaryBonusFromDB(b)
+}
+
+func (s *Service) ListSalaryBonuses(ctx context.Context, salaryID string) ([]SalaryBonus, error) {
+	rows, err := s.q.ListSalaryBonuses(ctx, salaryID)
+	if err != nil {
+		return nil, fmt.Errorf("listing salary bonuses: %w", err)
+	}
+	result := make([]SalaryBonus, len(rows))
+	for i, r := range rows {
+		if result[i], err = salaryBonusFromDB(r); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) DeleteSalaryBonus(ctx context.Context, id string) error {
+	if err := s.q.DeleteSalaryBonus(ctx, id); err != nil {
+		return fmt.Errorf("deleting salary bonus: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// computeBonusSegments derives the net bonus and the ITP1 pension on it from
+// the gross salary active when it is paid. A yearly bonus is split at the
+// salary-amount and IBB change dates after its first payment.
+func (s *Service) computeBonusSegments(ctx context.Context, sal Salary, ibbs []SweYearlyParams) ([]BonusSegment, error) {
+	sorted := make([]SalaryAmount, len(sal.Amounts))
+	copy(sorted, sal.Amounts)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartDate < sorted[j].StartDate
+	})
+
+	var segments []BonusSegment
+	for _, bonus := range sal.Bonuses {
+		dateSet := map[date.Date]struct{}{bonus.PayDate: {}}
+		if bonus.Yearly {
+			for _, amt := range sorted {
+				if amt.StartDate > bonus.PayDate {
+					dateSet[amt.StartDate] = struct{}{}
+				}
+			}
+			for _, ibb := range ibbs {
+				if ibb.ValidFrom > bonus.PayDate {
+					dateSet[ibb.ValidFrom] = struct{}{}
+				}
+			}
+		}
+		dates := make([]date.Date, 0, len(dateSet))
+		for d := range dateSet {
+			dates = append(dates, d)
+		}
+		sort.Slice(dates, func(i, j int) bool { return dates[i] < dates[j] })
+
+		for i, d := range dates {
+			gross := uncertain.NewFixed(0)
+			if active := activeSalaryAmountAt(sorted, d); active != nil {
+				gross = *active
+			}
+			taxFunc, err := s.salaryTaxFunc(ctx, sal, d, ibbs)
+			if err != nil {
+				return nil, err
+			}
+			ibb := activeIBBAt(ibbs, d)
+			exchange := sal.salaryExchange()
+
+			net := uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+				b := bonus.sample(cfg)
+				if b == 0 {
+					return 0
+				}
+				monthly := gross.Sample(cfg)
+				tax, err := swe.CalculateBonusTax(monthly-exchange.Exchanged(monthly), b, taxFunc)
+				if err != nil {
+					return b
+				}
+				return b - tax
+			})
+			pension := uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+				b := bonus.sample(cfg)
+				if b == 0 || ibb == 0 {
+					return 0
+				}
+				return swe.CalculateBonusITP1Pension(gross.Sample(cfg), b, ibb)
+			})
+
+			var endDate *date.Date
+			if i+1 < len(dates) {
+				ed := dates[i+1]
+				endDate = &ed
+			}
+			segments = append(segments, BonusSegment{
+				Bonus:     bonus,
+				StartDate: d,
+				EndDate:   endDate,
+				Net:       net,
+				Pension:   pension,
+			})
+		}
+	}
+	return segments, nil
+}
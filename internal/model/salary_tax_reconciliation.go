@@ -0,0 +1,270 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+// salaryTaxYear is the taxable salary income of a person in a calendar year,
+// the preliminary tax withheld on it and the final tax on the total. Income
+// includes BenefitIncome, the a-kassa and sjukpenning that replaced salary.
+type salaryTaxYear struct {
+	Income        float64
+	BenefitIncome float64
+	Withheld      float64
+	FinalTax      float64
+}
+
+// salaryTaxGroups groups the gross salaries by the person owning them, and
+// keeps salaries without an owner on their own.
+func salaryTaxGroups(salaries []Salary) [][]Salary {
+	var keys []string
+	groups := make(map[string][]Salary)
+	for _, sal := range salaries {
+		if !sal.IsGross || !sal.Enabled || !sal.HasTaxSetup() {
+			continue
+		}
+		key := "person:" + sal.OwnerID
+		if sal.OwnerID == "" {
+			key = "salary:" + sal.ID
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], sal)
+	}
+	result := make([][]Salary, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, groups[key])
+	}
+	return result
+}
+
+// generateSalaryTaxReconciliationTemplates settles the preliminary tax of
+// people with a secondary employer against their final tax. The final tax on
+// the combined salary income of a year is estimated with the primary
+// employer's tax table, and the difference to the tax withheld is paid as a
+// skatteåterbäring or restskatt the following year. Years after the last
+// change in any of the salaries settle the same amount, so the last year
+// recurs yearly. People with a tax account are settled by their simulated
+// slutskattebesked instead.
+func (s *Service) generateSalaryTaxReconciliationTemplates(ctx context.Context, salaries []Salary, ibbs []SweYearlyParams) ([]TransferTemplate, error) {
+	persons, err := s.listPersonsByID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing persons: %w", err)
+	}
+	var templates []TransferTemplate
+	for _, group := range salaryTaxGroups(salaries) {
+		if persons[group[0].OwnerID].TaxAccountID != "" || !slices.ContainsFunc(group, func(sal Salary) bool { return sal.SecondaryEmployer }) {
+			continue
+		}
+		primary := primarySalary(group)
+
+		years, err := s.finalSalaryTaxYears(ctx, group, ibbs)
+		if err != nil {
+			return nil, err
+		}
+		if len(years) == 0 {
+			continue
+		}
+		yearNums := make([]int, 0, len(years))
+		for y := range years {
+			yearNums = append(yearNums, y)
+		}
+		sort.Ints(yearNums)
+
+		source := TransferTemplateSource{
+			Type:     "salary",
+			EntityID: primary.ID,
+			Label:    primary.Name,
+			EditURL:  "/salaries/" + primary.ID + "/edit",
+		}
+		for i, year := range yearNums {
+			y := years[year]
+			diff := y.Withheld - y.FinalTax
+			if math.Abs(diff) < 1 {
+				continue
+			}
+			refund := diff > 0
+			settlement := swe.FinalTaxSettlementDate(year, refund)
+			tt := TransferTemplate{
+				ID:               fmt.Sprintf("salary-tax:%s:%d", primary.ID, year),
+				AmountType:       "fixed",
+				AmountFixed:      uncertain.NewFixed(math.Abs(diff)),
+				Priority:         primary.Priority,
+				Recurrence:       date.Cron(settlement.String()),
+				StartDate:        settlement,
+				Enabled:          primary.Enabled,
+				BudgetCategoryID: primary.BudgetCategoryID,
+				Source:           source,
+			}
+			if i == len(yearNums)-1 {
+				tt.Recurrence = date.Cron(fmt.Sprintf("*-%02d-%02d", settlement.Month(), settlement.Day()))
+			}
+			if refund {
+				tt.Name = primary.Name + " (skatteåterbäring)"
+				tt.ToAccountID = primary.ToAccountID
+			} else {
+				tt.Name = primary.Name + " (restskatt)"
+				tt.FromAccountID = primary.ToAccountID
+			}
+			templates = append(templates, tt)
+		}
+	}
+	return templates, nil
+}
+
+// primarySalary returns the salary from the main employer of a person, which
+// is the first one not from a secondary employer.
+func primarySalary(group []Salary) Salary {
+	for _, sal := range group {
+		if !sal.SecondaryEmployer {
+			return sal
+		}
+	}
+	return group[0]
+}
+
+// finalSalaryTaxYears returns the salary income and tax withheld per year of
+// one person, with the final tax on the income estimated with the tax table
+// of the primary employer.
+func (s *Service) finalSalaryTaxYears(ctx context.Context, salaries []Salary, ibbs []SweYearlyParams) (map[int]salaryTaxYear, error) {
+	years, err := s.salaryTaxYears(ctx, salaries, ibbs)
+	if err != nil {
+		return nil, err
+	}
+	// The final tax follows the ordinary rules even when all income is from
+	// secondary employers, if the salary has a tax setup of its own.
+	primary := primarySalary(salaries)
+	if ordinary := primary; primary.SecondaryEmployer {
+		ordinary.SecondaryEmployer = false
+		if ordinary.HasTaxSetup() {
+			primary = ordinary
+		}
+	}
+	for year, y := range years {
+		taxFunc, err := s.salaryTaxFunc(ctx, primary, januaryFirst(year), ibbs)
+		if err != nil {
+			return nil, err
+		}
+		work := y.Income - y.BenefitIncome
+		monthlyTax, err := taxFunc(work / 12)
+		if err != nil {
+			return nil, fmt.Errorf("computing final tax for %d: %w", year, err)
+		}
+		// Benefits get no jobbskatteavdrag, so they are taxed on top of the
+		// work income without it.
+		if y.BenefitIncome != 0 {
+			benefitTax, err := s.benefitTaxFunc(ctx, primary, januaryFirst(year), ibbs)
+			if err != nil {
+				return nil, err
+			}
+			base, err := benefitTax(work / 12)
+			if err != nil {
+				return nil, fmt.Errorf("computing final tax for %d: %w", year, err)
+			}
+			total, err := benefitTax(y.Income / 12)
+			if err != nil {
+				return nil, fmt.Errorf("computing final tax for %d: %w", year, err)
+			}
+			monthlyTax += total - base
+		}
+		y.FinalTax = 12 * monthlyTax
+		years[year] = y
+	}
+	return years, nil
+}
+
+// salaryTaxYears sums the taxable income and the tax withheld on the salaries
+// and bonuses of one person per calendar year, from the first salary until
+// the year after the last change.
+func (s *Service) salaryTaxYears(ctx context.Context, salaries []Salary, ibbs []SweYearlyParams) (map[int]salaryTaxYear, error) {
+	type breakdowns struct {
+		salary Salary
+		segs   []NetSalarySegmentBreakdown
+	}
+	type bonusPayment struct {
+		seg      BonusSegment
+		gross    float64
+		withheld float64
+	}
+	var all []breakdowns
+	var bonuses []bonusPayment
+	first, last := 0, 0
+	track := func(d date.Date) {
+		if first == 0 || d.Year() < first {
+			first = d.Year()
+		}
+		last = max(last, d.Year())
+	}
+	for _, sal := range salaries {
+		if len(sal.Amounts) == 0 {
+			continue
+		}
+		segs, err := s.computeBreakdownSegments(ctx, sal, ibbs)
+		if err != nil {
+			return nil, fmt.Errorf("computing breakdown segments: %w", err)
+		}
+		for _, seg := range segs {
+			track(seg.StartDate)
+		}
+		all = append(all, breakdowns{salary: sal, segs: segs})
+		for _, seg := range sal.BonusSegments {
+			track(seg.StartDate)
+			gross := seg.Bonus.expectedAmount().Mean()
+			bonuses = append(bonuses, bonusPayment{seg: seg, gross: gross, withheld: gross - seg.Net.Mean()})
+		}
+	}
+	if first == 0 {
+		return nil, nil
+	}
+
+	years := make(map[int]salaryTaxYear)
+	for year := first; year <= last+1; year++ {
+		var y salaryTaxYear
+		start, end := januaryFirst(year), januaryFirst(year+1)
+		for d := start; d < end; d = d.Add(date.Day) {
+			for _, b := range all {
+				if !b.salary.Recurrence.Matches(d) {
+					continue
+				}
+				if bd := activeBreakdownAt(b.segs, d); bd != nil {
+					y.Income += bd.TaxableIncome()
+					y.BenefitIncome += bd.BenefitIncome()
+					y.Withheld += bd.Tax
+				}
+			}
+			for _, b := range bonuses {
+				if b.seg.StartDate <= d && (b.seg.EndDate == nil || d < *b.seg.EndDate) && b.seg.Bonus.recurrence().Matches(d) {
+					y.Income += b.gross
+					y.Withheld += b.withheld
+				}
+			}
+		}
+		if y.Income != 0 || y.Withheld != 0 {
+			years[year] = y
+		}
+	}
+	return years, nil
+}
+
+func activeBreakdownAt(segs []NetSalarySegmentBreakdown, d date.Date) *swe.SalaryBreakdown {
+	for i := range segs {
+		if segs[i].StartDate <= d && (segs[i].EndDate == nil || d < *segs[i].EndDate) {
+			return &segs[i].Breakdown
+		}
+	}
+	return nil
+}
+
+func januaryFirst(year int) date.Date {
+	return date.FromTime(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC))
+}
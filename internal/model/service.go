@@ -78,6 +78,19 @@ func ParseConfig(args []string, getEnv func(string) string) (cfg Config, err err
 	return cfg, err
 }
 
+// TaxDataConfig configures the import-tax-data and export-tax-data commands.
+// File is the export to import; Dataset selects what to export to stdout.
+type TaxDataConfig struct {
+	DbURL   string
+	File    string
+	Dataset string
+}
+
+func ParseTaxDataConfig(args []string, getEnv func(string) string) (cfg TaxDataConfig, err error) {
+	err = config.ParseInto(&cfg, flag.NewFlagSet("", flag.ExitOnError), args, getEnv)
+	return cfg, err
+}
+
 func GetMigratedDB(ctx context.Context, dir fs.FS, path string, conn string) (*sql.DB, error) {
 	db, err := sql.Open("sqlite3", conn)
 	if err != nil {
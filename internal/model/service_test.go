@@ -2,14 +2,21 @@ package model_test
 
 import (
 	"fmt"
+	"maps"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/SimonSchneider/goslu/date"
 	"github.com/SimonSchneider/pefigo"
 	"github.com/SimonSchneider/pefigo/internal/model"
 	"github.com/SimonSchneider/pefigo/pkg/currency"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
 	"github.com/SimonSchneider/pefigo/pkg/swe"
 	"github.com/SimonSchneider/pefigo/pkg/uncertain"
 
@@ -375,7 +382,7 @@ func TestGetDashboardData(t *testing.T) {
 		Balance: newFixedValue(5000),
 	})
 
-	dashboard, err := svc.GetDashboardData(ctx)
+	dashboard, err := svc.GetDashboardData(ctx, "")
 	if err != nil {
 		t.Fatalf("get dashboard data: %v", err)
 	}
@@ -416,7 +423,7 @@ func TestGetBudgetData(t *testing.T) {
 		BudgetCategoryID: &cat.ID,
 	})
 
-	budget, err := svc.GetBudgetData(ctx)
+	budget, err := svc.GetBudgetData(ctx, "")
 	if err != nil {
 		t.Fatalf("get budget data: %v", err)
 	}
@@ -1567,9 +1574,9 @@ func seedTaxCache(t *testing.T, svc *model.Service, kommun, forsamling, year str
 	}
 
 	taxTableJSON := `[` +
-		`{"inkomst fr.o.m.":"0","inkomst t.o.m.":"24999","kolumn 1":"0","tabellnr":"31","år":"` + year + `","antal dgr":"30B"},` +
-		`{"inkomst fr.o.m.":"25000","inkomst t.o.m.":"49999","kolumn 1":"7500","tabellnr":"31","år":"` + year + `","antal dgr":"30B"},` +
-		`{"inkomst fr.o.m.":"50000","inkomst t.o.m.":"99999","kolumn 1":"15000","tabellnr":"31","år":"` + year + `","antal dgr":"30B"}` +
+		`{"inkomst fr.o.m.":"0","inkomst t.o.m.":"24999","kolumn 1":"0","kolumn 3":"2000","tabellnr":"31","år":"` + year + `","antal dgr":"30B"},` +
+		`{"inkomst fr.o.m.":"25000","inkomst t.o.m.":"49999","kolumn 1":"7500","kolumn 3":"9000","tabellnr":"31","år":"` + year + `","antal dgr":"30B"},` +
+		`{"inkomst fr.o.m.":"50000","inkomst t.o.m.":"99999","kolumn 1":"15000","kolumn 3":"17000","tabellnr":"31","år":"` + year + `","antal dgr":"30B"}` +
 		`]`
 	if err := cache.Set(ctx, "tax_table:31:"+year, taxTableJSON); err != nil {
 		t.Fatalf("seeding tax table cache: %v", err)
@@ -2312,7 +2319,7 @@ func TestGetBudgetData_IncludesSalaryTemplates(t *testing.T) {
 		StartDate: mustParseDate("2020-01-01"),
 	})
 
-	budget, err := svc.GetBudgetData(ctx)
+	budget, err := svc.GetBudgetData(ctx, "")
 	if err != nil {
 		t.Fatalf("GetBudgetData: %v", err)
 	}
@@ -3705,3 +3712,3108 @@ func TestForecastSettings(t *testing.T) {
 		t.Fatalf("expected samples 5000, got %d", samples)
 	}
 }
+
+// ---- Pension Payout ----
+
+func TestPensionPayoutCRUD(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	pension, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Pension"})
+	if err != nil {
+		t.Fatalf("create pension account: %v", err)
+	}
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+
+	p, err := svc.UpsertPensionPayout(ctx, model.PensionPayout{
+		AccountID:   pension.ID,
+		ToAccountID: checking.ID,
+		StartDate:   mustParseDate("2050-01-01"),
+		PeriodYears: 10,
+		Kommun:      "STOCKHOLM",
+		Forsamling:  "TEST",
+	})
+	if err != nil {
+		t.Fatalf("upsert pension payout: %v", err)
+	}
+	if p.ToAccountID != checking.ID || p.PeriodYears != 10 || p.StartDate != mustParseDate("2050-01-01") {
+		t.Fatalf("unexpected pension payout: %+v", p)
+	}
+
+	birth := mustParseDate("1985-06-15")
+	age := int64(67)
+	p.BirthDate = &birth
+	p.StartAge = &age
+	p.PeriodYears = 0
+	if _, err := svc.UpsertPensionPayout(ctx, p); err != nil {
+		t.Fatalf("update pension payout: %v", err)
+	}
+	got, err := svc.GetPensionPayout(ctx, pension.ID)
+	if err != nil {
+		t.Fatalf("get pension payout: %v", err)
+	}
+	if got.BirthDate == nil || *got.BirthDate != birth || got.StartAge == nil || *got.StartAge != 67 || !got.IsLifetime() {
+		t.Fatalf("unexpected updated pension payout: %+v", got)
+	}
+
+	list, err := svc.ListPensionPayouts(ctx)
+	if err != nil {
+		t.Fatalf("list pension payouts: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 pension payout, got %d", len(list))
+	}
+
+	if err := svc.DeletePensionPayout(ctx, pension.ID); err != nil {
+		t.Fatalf("delete pension payout: %v", err)
+	}
+	list, err = svc.ListPensionPayouts(ctx)
+	if err != nil {
+		t.Fatalf("list after delete: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("expected 0 pension payouts after delete, got %d", len(list))
+	}
+}
+
+func TestPensionPayoutDates(t *testing.T) {
+	birth := mustParseDate("1985-06-15")
+	age := int64(65)
+	tests := []struct {
+		name       string
+		payout     model.PensionPayout
+		wantStart  string
+		wantEnd    string
+		wantMonths int
+	}{
+		{
+			name:       "fixed period from start date",
+			payout:     model.PensionPayout{StartDate: mustParseDate("2050-01-01"), PeriodYears: 10},
+			wantStart:  "2050-01-01",
+			wantEnd:    "2060-01-01",
+			wantMonths: 120,
+		},
+		{
+			name:       "start age overrides start date",
+			payout:     model.PensionPayout{StartDate: mustParseDate("2040-01-01"), BirthDate: &birth, StartAge: &age, PeriodYears: 5},
+			wantStart:  "2050-06-15",
+			wantEnd:    "2055-06-15",
+			wantMonths: 60,
+		},
+		{
+			name:       "lifetime with birth date",
+			payout:     model.PensionPayout{BirthDate: &birth, StartAge: &age},
+			wantStart:  "2050-06-15",
+			wantMonths: 247,
+		},
+		{
+			name:       "lifetime without birth date",
+			payout:     model.PensionPayout{StartDate: mustParseDate("2050-01-01")},
+			wantStart:  "2050-01-01",
+			wantMonths: 247,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.payout.PayoutStartDate(); got != mustParseDate(tt.wantStart) {
+				t.Errorf("PayoutStartDate() = %s, want %s", got, tt.wantStart)
+			}
+			// Lifetime payouts have no end.
+			if got := tt.payout.PayoutEndDate(); (tt.wantEnd == "" && !got.IsZero()) || (tt.wantEnd != "" && got != mustParseDate(tt.wantEnd)) {
+				t.Errorf("PayoutEndDate() = %s, want %q", got, tt.wantEnd)
+			}
+			if got := tt.payout.PayoutMonths(); got != tt.wantMonths {
+				t.Errorf("PayoutMonths() = %d, want %d", got, tt.wantMonths)
+			}
+		})
+	}
+}
+
+type latestSnapshotHandler struct {
+	balances map[string]model.PredictionBalanceSnapshot
+}
+
+func (h *latestSnapshotHandler) Setup(model.PredictionSetupEvent) error { return nil }
+
+func (h *latestSnapshotHandler) Snapshot(snap model.PredictionBalanceSnapshot) error {
+	if prev, ok := h.balances[snap.ID]; !ok || prev.Day < snap.Day {
+		h.balances[snap.ID] = snap
+	}
+	return nil
+}
+
+func (h *latestSnapshotHandler) Close() error { return nil }
+
+func TestRunPrediction_PensionPayoutPaysNetIntoAccount(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	cache := model.NewSQLiteCache(svc.DB())
+	year := strconv.Itoa(time.Now().Year())
+	if err := cache.Set(ctx, "tax_rate:STOCKHOLM:TEST:"+year, `[{"kommun":"STOCKHOLM","församling":"TEST","summa, exkl. kyrkoavgift":"31","summa, inkl. kyrkoavgift":"32","år":"`+year+`"}]`); err != nil {
+		t.Fatalf("seeding tax rate cache: %v", err)
+	}
+	if err := cache.Set(ctx, "tax_table:31:"+year, `[{"inkomst fr.o.m.":"0","inkomst t.o.m.":"99999","kolumn 1":"0","kolumn 2":"3000","tabellnr":"31","år":"`+year+`","antal dgr":"30B"}]`); err != nil {
+		t.Fatalf("seeding tax table cache: %v", err)
+	}
+
+	today := date.Today()
+	pension, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Pension"})
+	if err != nil {
+		t.Fatalf("create pension account: %v", err)
+	}
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	for id, bal := range map[string]float64{pension.ID: 600_000, checking.ID: 0} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: today, Balance: newFixedValue(bal)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	if _, err := svc.UpsertPensionPayout(ctx, model.PensionPayout{
+		AccountID:   pension.ID,
+		ToAccountID: checking.ID,
+		StartDate:   today,
+		PeriodYears: 5,
+		Kommun:      "STOCKHOLM",
+		Forsamling:  "TEST",
+	}); err != nil {
+		t.Fatalf("upsert pension payout: %v", err)
+	}
+
+	h := &latestSnapshotHandler{balances: make(map[string]model.PredictionBalanceSnapshot)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	pensionBal := h.balances[pension.ID].Balance
+	checkingBal := h.balances[checking.ID].Balance
+	paidGross := 600_000 - pensionBal
+	if paidGross <= 0 {
+		t.Fatalf("expected payouts from pension account, balance is %f", pensionBal)
+	}
+	// Each 10 000 gross payment is taxed 3 000 using the pension column.
+	if !approxEqual(checkingBal, paidGross*0.7, 1) {
+		t.Errorf("checking balance = %f, want %f (70%% of %f paid out)", checkingBal, paidGross*0.7, paidGross)
+	}
+}
+
+// ---- Formula Income Tax ----
+
+func TestNetSegments_FormulaTaxMethodWorksOffline(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	sal, err := svc.UpsertSalary(ctx, model.Salary{
+		Name:             "Offline Salary",
+		IsGross:          true,
+		Enabled:          true,
+		Recurrence:       "*-*-25",
+		TaxMethod:        model.TaxMethodFormula,
+		MunicipalTaxRate: 0.32,
+	})
+	if err != nil {
+		t.Fatalf("creating salary: %v", err)
+	}
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(40000),
+		StartDate: mustParseDate("2030-01-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:              80600,
+		Prisbasbelopp:       58800,
+		StateTaxThreshold:   625800,
+		PublicServiceFeeMax: 1249,
+		ValidFrom:           mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating swe yearly params: %v", err)
+	}
+
+	netTTs := salaryTTsFromAll(t, svc, sal.ID)
+	if len(netTTs) != 1 {
+		t.Fatalf("expected 1 net TT, got %d", len(netTTs))
+	}
+	want := 40000 - 97892.968/12
+	if got := netTTs[0].AmountFixed.Mean(); !approxEqual(got, want, 0.01) {
+		t.Errorf("net = %v, want %v", got, want)
+	}
+
+	bds, err := svc.ComputeSalaryBreakdowns(ctx, sal.ID)
+	if err != nil {
+		t.Fatalf("computing breakdowns: %v", err)
+	}
+	if len(bds) != 1 || !approxEqual(bds[0].Breakdown.Tax, 97892.968/12, 0.01) {
+		t.Errorf("unexpected breakdowns: %+v", bds)
+	}
+}
+
+func TestNetSegments_FormulaTaxWithoutYearlyParamsUsesDefaultPBB(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	sal, err := svc.UpsertSalary(ctx, model.Salary{
+		Name:             "Offline Salary",
+		IsGross:          true,
+		Enabled:          true,
+		Recurrence:       "*-*-25",
+		TaxMethod:        model.TaxMethodFormula,
+		MunicipalTaxRate: 0.32,
+	})
+	if err != nil {
+		t.Fatalf("creating salary: %v", err)
+	}
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(40000),
+		StartDate: mustParseDate("2030-01-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+
+	// Without yearly params the 2025 prisbasbelopp keeps the grundavdrag and
+	// jobbskatteavdrag.
+	netTTs := salaryTTsFromAll(t, svc, sal.ID)
+	if len(netTTs) != 1 {
+		t.Fatalf("expected 1 net TT, got %d", len(netTTs))
+	}
+	want := 40000 - 97892.968/12
+	if got := netTTs[0].AmountFixed.Mean(); !approxEqual(got, want, 0.01) {
+		t.Errorf("net = %v, want %v", got, want)
+	}
+}
+
+func TestNetSegments_TableTaxMethodFallsBackToFormula(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	sal, err := svc.UpsertSalary(ctx, model.Salary{
+		Name:             "Fallback Salary",
+		Kommun:           "STOCKHOLM",
+		Forsamling:       "TEST",
+		IsGross:          true,
+		Enabled:          true,
+		Recurrence:       "*-*-25",
+		TaxMethod:        model.TaxMethodTable,
+		MunicipalTaxRate: 0.32,
+	})
+	if err != nil {
+		t.Fatalf("creating salary: %v", err)
+	}
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(40000),
+		StartDate: mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        80600,
+		Prisbasbelopp: 58800,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating swe yearly params: %v", err)
+	}
+
+	// No tax tables are cached and the swe client cannot reach the API.
+	netTTs := salaryTTsFromAll(t, svc, sal.ID)
+	if len(netTTs) != 1 {
+		t.Fatalf("expected 1 net TT, got %d", len(netTTs))
+	}
+	want := 40000 - 97892.968/12
+	if got := netTTs[0].AmountFixed.Mean(); !approxEqual(got, want, 0.01) {
+		t.Errorf("net = %v, want %v", got, want)
+	}
+}
+
+// ---- Interest Deduction ----
+
+func TestInterestDeductionCRUD(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	loan, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Mortgage"})
+	if err != nil {
+		t.Fatalf("create loan account: %v", err)
+	}
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+
+	if _, err := svc.UpsertInterestDeduction(ctx, model.InterestDeduction{AccountID: loan.ID, ToAccountID: checking.ID}); err == nil {
+		t.Fatal("expected error for zero borrowers")
+	}
+	d, err := svc.UpsertInterestDeduction(ctx, model.InterestDeduction{AccountID: loan.ID, ToAccountID: checking.ID, Borrowers: 2})
+	if err != nil {
+		t.Fatalf("upsert interest deduction: %v", err)
+	}
+	if d.SettlementDate != swe.DefaultInterestSettlementDate || d.Borrowers != 2 || d.ToAccountID != checking.ID {
+		t.Fatalf("unexpected interest deduction: %+v", d)
+	}
+
+	d.SettlementDate = "*-06-01"
+	if _, err := svc.UpsertInterestDeduction(ctx, d); err != nil {
+		t.Fatalf("update interest deduction: %v", err)
+	}
+	got, err := svc.GetInterestDeduction(ctx, loan.ID)
+	if err != nil {
+		t.Fatalf("get interest deduction: %v", err)
+	}
+	if got.SettlementDate != "*-06-01" {
+		t.Errorf("settlement date = %q, want *-06-01", got.SettlementDate)
+	}
+
+	if err := svc.DeleteAccount(ctx, checking.ID); err != nil {
+		t.Fatalf("delete checking account: %v", err)
+	}
+	got, err = svc.GetInterestDeduction(ctx, loan.ID)
+	if err != nil {
+		t.Fatalf("get interest deduction after deleting refund account: %v", err)
+	}
+	if got.ToAccountID != "" {
+		t.Errorf("expected refund account to be cleared, got %q", got.ToAccountID)
+	}
+
+	if err := svc.DeleteInterestDeduction(ctx, loan.ID); err != nil {
+		t.Fatalf("delete interest deduction: %v", err)
+	}
+	list, err := svc.ListInterestDeductions(ctx)
+	if err != nil {
+		t.Fatalf("list interest deductions: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("expected no interest deductions, got %d", len(list))
+	}
+}
+
+type snapshotsByDayHandler struct {
+	balances map[string]map[date.Date]float64
+}
+
+func (h *snapshotsByDayHandler) Setup(model.PredictionSetupEvent) error { return nil }
+
+func (h *snapshotsByDayHandler) Snapshot(snap model.PredictionBalanceSnapshot) error {
+	if h.balances[snap.ID] == nil {
+		h.balances[snap.ID] = make(map[date.Date]float64)
+	}
+	h.balances[snap.ID][date.FromTime(time.UnixMilli(snap.Day).UTC())] = snap.Balance
+	return nil
+}
+
+func (h *snapshotsByDayHandler) Close() error { return nil }
+
+func TestRunPrediction_InterestDeductionRefundsFollowingYear(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	thisYear := time.Now().Year()
+	lastYearStart := mustParseDate(fmt.Sprintf("%d-01-01", thisYear-1))
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	savings, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Savings"})
+	if err != nil {
+		t.Fatalf("create savings account: %v", err)
+	}
+	loan, err := svc.UpsertAccount(ctx, model.AccountInput{
+		Name:                  "Mortgage",
+		CashFlowFrequency:     "*-*-01",
+		CashFlowDestinationID: checking.ID,
+	})
+	if err != nil {
+		t.Fatalf("create loan account: %v", err)
+	}
+	if _, err := svc.UpsertAccountGrowthModel(ctx, model.AccountGrowthModelInput{
+		AccountID:        loan.ID,
+		Type:             "fixed",
+		AnnualRate:       newFixedValue(0.03),
+		AnnualVolatility: newFixedValue(0),
+		StartDate:        lastYearStart,
+	}); err != nil {
+		t.Fatalf("create growth model: %v", err)
+	}
+	for id, bal := range map[string]float64{loan.ID: -1_000_000, checking.ID: 0, savings.ID: 0} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: lastYearStart, Balance: newFixedValue(bal)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	if _, err := svc.UpsertInterestDeduction(ctx, model.InterestDeduction{
+		AccountID:      loan.ID,
+		ToAccountID:    savings.ID,
+		SettlementDate: "*-04-07",
+		Borrowers:      1,
+	}); err != nil {
+		t.Fatalf("upsert interest deduction: %v", err)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	if bal := h.balances[savings.ID][mustParseDate(fmt.Sprintf("%d-04-01", thisYear))]; bal != 0 {
+		t.Errorf("savings balance before settlement = %f, want 0", bal)
+	}
+	// Interest paid Feb 1 - Dec 1 last year covers Jan - Nov, roughly 11/12 of 30 000.
+	want := 30_000.0 * 11 / 12 * swe.InterestDeductionRate
+	got := h.balances[savings.ID][mustParseDate(fmt.Sprintf("%d-05-01", thisYear))]
+	if !approxEqual(got, want, want*0.03) {
+		t.Errorf("savings balance after settlement = %f, want about %f", got, want)
+	}
+}
+
+func TestAmortizationRequirementCRUD(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	loan, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Mortgage"})
+	if err != nil {
+		t.Fatalf("create loan account: %v", err)
+	}
+	house, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "House"})
+	if err != nil {
+		t.Fatalf("create house account: %v", err)
+	}
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+
+	if _, err := svc.UpsertAmortizationRequirement(ctx, model.AmortizationRequirement{AccountID: loan.ID, RevaluationYears: -1}); err == nil {
+		t.Fatal("expected error for negative revaluation interval")
+	}
+	a, err := svc.UpsertAmortizationRequirement(ctx, model.AmortizationRequirement{
+		AccountID:         loan.ID,
+		PropertyAccountID: house.ID,
+		FromAccountID:     checking.ID,
+		ValuationDate:     mustParseDate("2024-03-15"),
+		RevaluationYears:  5,
+	})
+	if err != nil {
+		t.Fatalf("upsert amortization requirement: %v", err)
+	}
+	if a.Recurrence != model.DefaultAmortizationRecurrence || a.PropertyAccountID != house.ID || a.FromAccountID != checking.ID {
+		t.Fatalf("unexpected amortization requirement: %+v", a)
+	}
+	revaluations := a.Revaluations(mustParseDate("2035-01-01"))
+	if len(revaluations) != 2 || revaluations[0] != mustParseDate("2029-03-15") || revaluations[1] != mustParseDate("2034-03-15") {
+		t.Errorf("revaluations = %v, want 2029-03-15 and 2034-03-15", revaluations)
+	}
+
+	for id, bal := range map[string]float64{loan.ID: -1_600_000, house.ID: 2_000_000} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: mustParseDate("2024-03-15"), Balance: newFixedValue(bal)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	est, err := svc.EstimateAmortization(ctx, a)
+	if err != nil {
+		t.Fatalf("estimate amortization: %v", err)
+	}
+	if est.Rate != 0.02 || !approxEqual(est.Monthly, 1_600_000*0.02/12, 0.01) {
+		t.Errorf("estimate = %+v, want 2%% of 1 600 000", est)
+	}
+
+	if err := svc.DeleteAccount(ctx, house.ID); err != nil {
+		t.Fatalf("delete house account: %v", err)
+	}
+	got, err := svc.GetAmortizationRequirement(ctx, loan.ID)
+	if err != nil {
+		t.Fatalf("get amortization requirement after deleting property: %v", err)
+	}
+	if got.PropertyAccountID != "" {
+		t.Errorf("expected property account to be cleared, got %q", got.PropertyAccountID)
+	}
+
+	if err := svc.DeleteAmortizationRequirement(ctx, loan.ID); err != nil {
+		t.Fatalf("delete amortization requirement: %v", err)
+	}
+	list, err := svc.ListAmortizationRequirements(ctx)
+	if err != nil {
+		t.Fatalf("list amortization requirements: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("expected no amortization requirements, got %d", len(list))
+	}
+}
+
+func TestRunPrediction_AmortizationLoweredOnRevaluation(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	today := date.Today()
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	house, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "House"})
+	if err != nil {
+		t.Fatalf("create house account: %v", err)
+	}
+	loan, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Mortgage"})
+	if err != nil {
+		t.Fatalf("create loan account: %v", err)
+	}
+	if _, err := svc.UpsertAccountGrowthModel(ctx, model.AccountGrowthModelInput{
+		AccountID:        house.ID,
+		Type:             "fixed",
+		AnnualRate:       newFixedValue(0.10),
+		AnnualVolatility: newFixedValue(0),
+		StartDate:        today,
+	}); err != nil {
+		t.Fatalf("create growth model: %v", err)
+	}
+	for id, bal := range map[string]float64{loan.ID: -720_000, house.ID: 1_000_000, checking.ID: 100_000} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: today, Balance: newFixedValue(bal)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	// Valued almost five years ago, so the property is revalued in about half a year.
+	valuation := date.FromTime(today.ToStdTime().AddDate(-5, 6, 0))
+	if _, err := svc.UpsertAmortizationRequirement(ctx, model.AmortizationRequirement{
+		AccountID:         loan.ID,
+		PropertyAccountID: house.ID,
+		FromAccountID:     checking.ID,
+		Recurrence:        "*-*-25",
+		ValuationDate:     valuation,
+		RevaluationYears:  5,
+	}); err != nil {
+		t.Fatalf("upsert amortization requirement: %v", err)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	days := slices.Sorted(maps.Keys(h.balances[loan.ID]))
+	if len(days) < 12 {
+		t.Fatalf("expected a year of monthly snapshots, got %d", len(days))
+	}
+	payment := func(i int) float64 {
+		return h.balances[loan.ID][days[i+1]] - h.balances[loan.ID][days[i]]
+	}
+	// 72% loan-to-value requires 2% yearly.
+	if got, want := payment(1), 720_000*0.02/12; !approxEqual(got, want, 1) {
+		t.Errorf("monthly amortization before revaluation = %f, want %f", got, want)
+	}
+	// After the property has grown ~5% it is revalued below 70%, requiring 1%.
+	if got, want := payment(len(days)-2), 715_000*0.01/12; !approxEqual(got, want, want*0.02) {
+		t.Errorf("monthly amortization after revaluation = %f, want about %f", got, want)
+	}
+	if got := h.balances[checking.ID][days[2]] - h.balances[checking.ID][days[1]]; !approxEqual(got, -payment(1), 1) {
+		t.Errorf("checking change = %f, want %f", got, -payment(1))
+	}
+}
+
+func TestInterestRateModelCRUD(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	if _, err := svc.UpsertInterestRateModel(ctx, model.InterestRateModel{Name: "Bad", Type: "hull-white"}); err == nil {
+		t.Fatal("expected error for unknown model type")
+	}
+	rm, err := svc.UpsertInterestRateModel(ctx, model.InterestRateModel{
+		Name:           "Riksbank",
+		Type:           finance.InterestRateVasicek,
+		InitialRate:    0.0225,
+		LongTermRate:   0.025,
+		ReversionSpeed: 0.3,
+		Volatility:     0.01,
+	})
+	if err != nil {
+		t.Fatalf("upsert interest rate model: %v", err)
+	}
+	if rm.ID == "" || rm.GetInitialRateString() != "2.25" {
+		t.Fatalf("unexpected interest rate model: %+v", rm)
+	}
+
+	loan, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Mortgage"})
+	if err != nil {
+		t.Fatalf("create loan account: %v", err)
+	}
+	if _, err := svc.UpsertAccountGrowthModel(ctx, model.AccountGrowthModelInput{
+		AccountID:        loan.ID,
+		Type:             "variable",
+		AnnualRate:       newFixedValue(0.01),
+		AnnualVolatility: newFixedValue(0),
+	}); err == nil {
+		t.Fatal("expected error for variable growth model without rate model")
+	}
+	gm, err := svc.UpsertAccountGrowthModel(ctx, model.AccountGrowthModelInput{
+		AccountID:        loan.ID,
+		Type:             "variable",
+		AnnualRate:       newFixedValue(0.01),
+		AnnualVolatility: newFixedValue(0),
+		RateModelID:      rm.ID,
+		BindingYears:     3,
+	})
+	if err != nil {
+		t.Fatalf("upsert variable growth model: %v", err)
+	}
+	if gm.RateModelID != rm.ID || gm.BindingYears != 3 {
+		t.Fatalf("unexpected growth model: %+v", gm)
+	}
+
+	if err := svc.DeleteInterestRateModel(ctx, rm.ID); err != nil {
+		t.Fatalf("delete interest rate model: %v", err)
+	}
+	got, err := svc.GetGrowthModel(ctx, gm.ID)
+	if err != nil {
+		t.Fatalf("get growth model: %v", err)
+	}
+	if got.RateModelID != "" {
+		t.Errorf("expected rate model to be cleared, got %q", got.RateModelID)
+	}
+	list, err := svc.ListInterestRateModels(ctx)
+	if err != nil {
+		t.Fatalf("list interest rate models: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("expected no interest rate models, got %d", len(list))
+	}
+}
+
+func TestRunPrediction_VariableRateLoansShareRatePath(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	today := date.Today()
+	rm, err := svc.UpsertInterestRateModel(ctx, model.InterestRateModel{
+		Name:           "Riksbank",
+		Type:           finance.InterestRateVasicek,
+		InitialRate:    0.01,
+		LongTermRate:   0.05,
+		ReversionSpeed: 2,
+	})
+	if err != nil {
+		t.Fatalf("upsert interest rate model: %v", err)
+	}
+	loans := make(map[int64]string)
+	for _, binding := range []int64{0, 2} {
+		loan, err := svc.UpsertAccount(ctx, model.AccountInput{Name: fmt.Sprintf("Mortgage %d", binding)})
+		if err != nil {
+			t.Fatalf("create loan account: %v", err)
+		}
+		if _, err := svc.UpsertAccountGrowthModel(ctx, model.AccountGrowthModelInput{
+			AccountID:        loan.ID,
+			Type:             "variable",
+			AnnualRate:       newFixedValue(0.01),
+			AnnualVolatility: newFixedValue(0),
+			StartDate:        today,
+			RateModelID:      rm.ID,
+			BindingYears:     binding,
+		}); err != nil {
+			t.Fatalf("create growth model: %v", err)
+		}
+		if _, err := svc.UpsertAccountSnapshot(ctx, loan.ID, model.AccountSnapshotInput{Date: today, Balance: newFixedValue(-100_000)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+		loans[binding] = loan.ID
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	last := func(id string) float64 {
+		days := slices.Sorted(maps.Keys(h.balances[id]))
+		return h.balances[id][days[len(days)-1]]
+	}
+	// The bound loan keeps the 2% it started at, the floating one follows the rising rate.
+	bound, floating := last(loans[2]), last(loans[0])
+	if bound < -100_000*1.021 || bound > -100_000*1.015 {
+		t.Errorf("bound loan balance = %f, want about %f", bound, -100_000*1.02)
+	}
+	if floating > -100_000*1.035 {
+		t.Errorf("floating loan balance = %f, want the rising rate to cost more than 3.5%%", floating)
+	}
+}
+
+func TestCSNLoanCRUD(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	loan, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "CSN"})
+	if err != nil {
+		t.Fatalf("create loan account: %v", err)
+	}
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	sal, err := svc.UpsertSalary(ctx, model.Salary{Name: "Acme Corp", ToAccountID: checking.ID, Enabled: true})
+	if err != nil {
+		t.Fatalf("create salary: %v", err)
+	}
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(20_000),
+		StartDate: mustParseDate("2000-01-01"),
+	}); err != nil {
+		t.Fatalf("create salary amount: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{CsnRanta: 0.01, ValidFrom: mustParseDate("2000-01-01")}); err != nil {
+		t.Fatalf("create yearly params: %v", err)
+	}
+
+	if _, err := svc.UpsertCSNLoan(ctx, model.CSNLoan{AccountID: loan.ID, FinalYear: -1}); err == nil {
+		t.Fatal("expected error for negative final year")
+	}
+	finalYear := int64(date.Today().Year() + 9)
+	l, err := svc.UpsertCSNLoan(ctx, model.CSNLoan{
+		AccountID:     loan.ID,
+		FromAccountID: checking.ID,
+		SalaryID:      sal.ID,
+		FinalYear:     finalYear,
+	})
+	if err != nil {
+		t.Fatalf("upsert csn loan: %v", err)
+	}
+	if l.Recurrence != model.DefaultCSNRecurrence || l.FromAccountID != checking.ID || l.SalaryID != sal.ID || l.Reduction {
+		t.Fatalf("unexpected csn loan: %+v", l)
+	}
+
+	est, err := svc.EstimateCSNLoan(ctx, l, 250_000)
+	if err != nil {
+		t.Fatalf("estimate csn loan: %v", err)
+	}
+	if want := swe.CSNAnnualAmount(250_000, 0.01, 10); est.Years != 10 || est.Rate != 0.01 || !approxEqual(est.Annual, want, 0.01) {
+		t.Errorf("estimate = %+v, want 10 years and %f yearly", est, want)
+	}
+
+	l.Reduction = true
+	if l, err = svc.UpsertCSNLoan(ctx, l); err != nil {
+		t.Fatalf("upsert csn loan with reduction: %v", err)
+	}
+	est, err = svc.EstimateCSNLoan(ctx, l, 250_000)
+	if err != nil {
+		t.Fatalf("estimate reduced csn loan: %v", err)
+	}
+	if want := 20_000 * 12 * swe.CSNReductionRate; !approxEqual(est.Annual, want, 0.01) {
+		t.Errorf("reduced yearly amount = %f, want %f", est.Annual, want)
+	}
+
+	if err := svc.DeleteCSNLoan(ctx, loan.ID); err != nil {
+		t.Fatalf("delete csn loan: %v", err)
+	}
+	list, err := svc.ListCSNLoans(ctx)
+	if err != nil {
+		t.Fatalf("list csn loans: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("expected no csn loans, got %d", len(list))
+	}
+}
+
+func TestCSNLoanAndAmortizationRequirementAreExclusive(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	mortgage, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Mortgage"})
+	if err != nil {
+		t.Fatalf("create mortgage account: %v", err)
+	}
+	csn, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "CSN"})
+	if err != nil {
+		t.Fatalf("create csn account: %v", err)
+	}
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+
+	if _, err := svc.UpsertAmortizationRequirement(ctx, model.AmortizationRequirement{AccountID: mortgage.ID, FromAccountID: checking.ID}); err != nil {
+		t.Fatalf("upsert amortization requirement: %v", err)
+	}
+	if _, err := svc.UpsertCSNLoan(ctx, model.CSNLoan{AccountID: mortgage.ID, FromAccountID: checking.ID}); err == nil {
+		t.Error("expected error for a csn loan on an account with an amortization requirement")
+	}
+
+	if _, err := svc.UpsertCSNLoan(ctx, model.CSNLoan{AccountID: csn.ID, FromAccountID: checking.ID}); err != nil {
+		t.Fatalf("upsert csn loan: %v", err)
+	}
+	if _, err := svc.UpsertAmortizationRequirement(ctx, model.AmortizationRequirement{AccountID: csn.ID, FromAccountID: checking.ID}); err == nil {
+		t.Error("expected error for an amortization requirement on a csn loan")
+	}
+}
+
+func TestRunPrediction_CSNLoanRepaidMonthly(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	today := date.Today()
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	loan, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "CSN"})
+	if err != nil {
+		t.Fatalf("create loan account: %v", err)
+	}
+	for id, bal := range map[string]float64{loan.ID: -100_000, checking.ID: 100_000} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: today, Balance: newFixedValue(bal)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{ValidFrom: mustParseDate("2000-01-01")}); err != nil {
+		t.Fatalf("create yearly params: %v", err)
+	}
+	finalYear := today.Year() + 9
+	if _, err := svc.UpsertCSNLoan(ctx, model.CSNLoan{
+		AccountID:     loan.ID,
+		FromAccountID: checking.ID,
+		FinalYear:     int64(finalYear),
+	}); err != nil {
+		t.Fatalf("upsert csn loan: %v", err)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	days := slices.Sorted(maps.Keys(h.balances[loan.ID]))
+	if len(days) < 12 {
+		t.Fatalf("expected a year of monthly snapshots, got %d", len(days))
+	}
+	payment := h.balances[loan.ID][days[2]] - h.balances[loan.ID][days[1]]
+	// The yearly amount grows 2% when redetermined in January, so allow for
+	// the month falling in either year.
+	want := swe.CSNAnnualAmount(100_000, 0, 10) / 12
+	if !approxEqual(payment, want, want*0.03) {
+		t.Errorf("monthly csn payment = %f, want about %f", payment, want)
+	}
+	if got := h.balances[checking.ID][days[2]] - h.balances[checking.ID][days[1]]; !approxEqual(got, -payment, 1) {
+		t.Errorf("checking change = %f, want %f", got, -payment)
+	}
+}
+
+func TestPersonCRUDAndRelativeDates(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	if _, err := svc.UpsertPerson(ctx, model.Person{}); err == nil {
+		t.Fatal("expected error for person without name")
+	}
+	alice, err := svc.UpsertPerson(ctx, model.Person{Name: "Alice", BirthDate: mustParseDate("1990-05-10")})
+	if err != nil {
+		t.Fatalf("upsert person: %v", err)
+	}
+	if got := alice.AgeOn(mustParseDate("2025-05-09")); got != 34 {
+		t.Errorf("age the day before birthday = %d, want 34", got)
+	}
+	if got := alice.AgeOn(mustParseDate("2025-05-10")); got != 35 {
+		t.Errorf("age on birthday = %d, want 35", got)
+	}
+
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking", OwnerID: alice.ID})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if checking.OwnerID != alice.ID {
+		t.Fatalf("account owner = %q, want %q", checking.OwnerID, alice.ID)
+	}
+	sal, err := svc.UpsertSalary(ctx, model.Salary{Name: "Acme Corp", ToAccountID: checking.ID, OwnerID: alice.ID, Enabled: true})
+	if err != nil {
+		t.Fatalf("create salary: %v", err)
+	}
+	if sal.OwnerID != alice.ID {
+		t.Fatalf("salary owner = %q, want %q", sal.OwnerID, alice.ID)
+	}
+
+	sd, err := svc.UpsertSpecialDate(ctx, model.SpecialDateInput{
+		Name:      "Retirement",
+		PersonAge: &model.PersonAge{PersonID: alice.ID, Age: 65},
+	})
+	if err != nil {
+		t.Fatalf("upsert special date: %v", err)
+	}
+	if sd.Date != mustParseDate("2055-05-10") || sd.PersonAge.String() != "Alice turns 65" {
+		t.Fatalf("special date = %v (%s), want 2055-05-10 (Alice turns 65)", sd.Date, sd.PersonAge)
+	}
+	tt, err := svc.UpsertTransferTemplate(ctx, model.TransferTemplate{
+		Name:          "Savings",
+		FromAccountID: checking.ID,
+		AmountType:    "fixed",
+		AmountFixed:   newFixedValue(1000),
+		Recurrence:    "*-*-25",
+		StartDate:     mustParseDate("2025-01-01"),
+		EndAtAge:      &model.PersonAge{PersonID: alice.ID, Age: 60},
+		Enabled:       true,
+	})
+	if err != nil {
+		t.Fatalf("upsert transfer template: %v", err)
+	}
+	if tt.EndDate == nil || *tt.EndDate != mustParseDate("2050-05-10") {
+		t.Fatalf("transfer template end = %v, want 2050-05-10", tt.EndDate)
+	}
+
+	// Relative dates follow a changed birth date.
+	alice.BirthDate = mustParseDate("1991-01-01")
+	if _, err := svc.UpsertPerson(ctx, alice); err != nil {
+		t.Fatalf("update person: %v", err)
+	}
+	dates, err := svc.ListSpecialDates(ctx)
+	if err != nil {
+		t.Fatalf("list special dates: %v", err)
+	}
+	if len(dates) != 1 || dates[0].Date != mustParseDate("2056-01-01") {
+		t.Errorf("special dates = %+v, want retirement on 2056-01-01", dates)
+	}
+	templates, err := svc.ListTransferTemplates(ctx)
+	if err != nil {
+		t.Fatalf("list transfer templates: %v", err)
+	}
+	if len(templates) != 1 || templates[0].EndDate == nil || *templates[0].EndDate != mustParseDate("2051-01-01") {
+		t.Errorf("transfer template end = %v, want 2051-01-01", templates[0].EndDate)
+	}
+
+	// Deleting the person keeps owned accounts and the last resolved dates.
+	if err := svc.DeletePerson(ctx, alice.ID); err != nil {
+		t.Fatalf("delete person: %v", err)
+	}
+	acc, err := svc.GetAccount(ctx, checking.ID)
+	if err != nil {
+		t.Fatalf("get account after deleting owner: %v", err)
+	}
+	if acc.OwnerID != "" {
+		t.Errorf("expected account owner to be cleared, got %q", acc.OwnerID)
+	}
+	got, err := svc.GetSpecialDate(ctx, sd.ID)
+	if err != nil {
+		t.Fatalf("get special date: %v", err)
+	}
+	if got.PersonAge != nil || got.Date != mustParseDate("2055-05-10") {
+		t.Errorf("special date after deleting person = %+v, want fixed 2055-05-10", got)
+	}
+}
+
+func TestListPensionPayouts_DefaultsToOwnerBirthDate(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	bob, err := svc.UpsertPerson(ctx, model.Person{Name: "Bob", BirthDate: mustParseDate("1970-03-01")})
+	if err != nil {
+		t.Fatalf("upsert person: %v", err)
+	}
+	pension, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Pension", OwnerID: bob.ID})
+	if err != nil {
+		t.Fatalf("create pension account: %v", err)
+	}
+	startAge := int64(65)
+	if _, err := svc.UpsertPensionPayout(ctx, model.PensionPayout{AccountID: pension.ID, StartAge: &startAge, PeriodYears: 10}); err != nil {
+		t.Fatalf("upsert pension payout: %v", err)
+	}
+	payouts, err := svc.ListPensionPayouts(ctx)
+	if err != nil {
+		t.Fatalf("list pension payouts: %v", err)
+	}
+	if len(payouts) != 1 || payouts[0].PayoutStartDate() != mustParseDate("2035-03-01") {
+		t.Errorf("pension payouts = %+v, want payout from 2035-03-01", payouts)
+	}
+}
+
+func TestAccountOwnerCRUD(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	alice, err := svc.UpsertPerson(ctx, model.Person{Name: "Alice"})
+	if err != nil {
+		t.Fatalf("upsert person: %v", err)
+	}
+	bob, err := svc.UpsertPerson(ctx, model.Person{Name: "Bob"})
+	if err != nil {
+		t.Fatalf("upsert person: %v", err)
+	}
+	apartment, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Apartment"})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if _, err := svc.UpsertAccountOwner(ctx, model.AccountOwner{AccountID: apartment.ID, PersonID: alice.ID, Share: 0.6}); err != nil {
+		t.Fatalf("upsert account owner: %v", err)
+	}
+	if _, err := svc.UpsertAccountOwner(ctx, model.AccountOwner{AccountID: apartment.ID, PersonID: bob.ID, Share: 0.5}); err == nil {
+		t.Error("expected shares above 100% to be rejected")
+	}
+	if _, err := svc.UpsertAccountOwner(ctx, model.AccountOwner{AccountID: apartment.ID, PersonID: alice.ID, Share: 0.5}); err != nil {
+		t.Fatalf("update account owner: %v", err)
+	}
+	if _, err := svc.UpsertAccountOwner(ctx, model.AccountOwner{AccountID: apartment.ID, PersonID: bob.ID, Share: 0.5}); err != nil {
+		t.Fatalf("upsert account owner: %v", err)
+	}
+	owners, err := svc.ListAccountOwners(ctx, apartment.ID)
+	if err != nil {
+		t.Fatalf("list account owners: %v", err)
+	}
+	if len(owners) != 2 {
+		t.Fatalf("expected 2 owners, got %d", len(owners))
+	}
+	if err := svc.DeleteAccountOwner(ctx, apartment.ID, bob.ID); err != nil {
+		t.Fatalf("delete account owner: %v", err)
+	}
+	if err := svc.DeletePerson(ctx, alice.ID); err != nil {
+		t.Fatalf("delete person: %v", err)
+	}
+	owners, err = svc.ListAccountOwners(ctx, apartment.ID)
+	if err != nil {
+		t.Fatalf("list account owners: %v", err)
+	}
+	if len(owners) != 0 {
+		t.Errorf("expected owners to be removed with their person, got %+v", owners)
+	}
+}
+
+func TestOwnership_SplitsBalancesPerPerson(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	alice, err := svc.UpsertPerson(ctx, model.Person{Name: "Alice"})
+	if err != nil {
+		t.Fatalf("upsert person: %v", err)
+	}
+	bob, err := svc.UpsertPerson(ctx, model.Person{Name: "Bob"})
+	if err != nil {
+		t.Fatalf("upsert person: %v", err)
+	}
+	apartment, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Apartment"})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	isk, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Alice ISK", OwnerID: alice.ID})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	today := date.Today()
+	for id, bal := range map[string]float64{apartment.ID: 4_000_000, isk.ID: 100_000} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: today, Balance: newFixedValue(bal)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	for _, p := range []model.Person{alice, bob} {
+		if _, err := svc.UpsertAccountOwner(ctx, model.AccountOwner{AccountID: apartment.ID, PersonID: p.ID, Share: 0.5}); err != nil {
+			t.Fatalf("upsert account owner: %v", err)
+		}
+	}
+
+	dashboard, err := svc.GetDashboardData(ctx, bob.ID)
+	if err != nil {
+		t.Fatalf("get dashboard data: %v", err)
+	}
+	if dashboard.TotalBalance != 2_000_000 {
+		t.Errorf("Bob's net worth = %f, want 2000000", dashboard.TotalBalance)
+	}
+	dashboard, err = svc.GetDashboardData(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("get dashboard data: %v", err)
+	}
+	if dashboard.TotalBalance != 2_100_000 {
+		t.Errorf("Alice's net worth = %f, want 2100000", dashboard.TotalBalance)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Month,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByOwner,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+	for personID, want := range map[string]float64{alice.ID: 2_100_000, bob.ID: 2_000_000} {
+		if len(h.balances[personID]) == 0 {
+			t.Fatalf("no snapshots for person %s", personID)
+		}
+		for day, got := range h.balances[personID] {
+			if !approxEqual(got, want, 1) {
+				t.Errorf("balance of %s on %s = %f, want %f", personID, day, got, want)
+			}
+		}
+	}
+	if len(h.balances[""]) != 0 {
+		t.Errorf("expected no shared balance, got %v", h.balances[""])
+	}
+}
+
+func TestChildBenefitTransferTemplates(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	for _, p := range []model.Person{
+		{Name: "Parent", BirthDate: mustParseDate("1985-01-01")},
+		{Name: "Older", BirthDate: mustParseDate("2020-02-10"), IsChild: true},
+		{Name: "Younger", BirthDate: mustParseDate("2023-08-05"), IsChild: true},
+	} {
+		if _, err := svc.UpsertPerson(ctx, p); err != nil {
+			t.Fatalf("upsert person: %v", err)
+		}
+	}
+
+	hasChildBenefit := func() bool {
+		all, err := svc.ListAllTransferTemplates(ctx)
+		if err != nil {
+			t.Fatalf("list all transfer templates: %v", err)
+		}
+		return slices.ContainsFunc(all, func(tt model.TransferTemplate) bool { return tt.Source.Type == "child_benefit" })
+	}
+	if hasChildBenefit() {
+		t.Fatal("expected no child benefit without an account")
+	}
+	if err := svc.SetChildBenefitAccount(ctx, checking.ID); err != nil {
+		t.Fatalf("set child benefit account: %v", err)
+	}
+	if !hasChildBenefit() {
+		t.Fatal("expected child benefit templates")
+	}
+
+	persons, err := svc.ListPersons(ctx)
+	if err != nil {
+		t.Fatalf("list persons: %v", err)
+	}
+	templates := model.GenerateChildBenefitTransferTemplates(persons, checking.ID)
+	want := []struct {
+		start, end string
+		amount     float64
+	}{
+		{"2020-03-01", "2023-09-01", 1250},
+		{"2023-09-01", "2036-04-01", 2650},
+		{"2036-04-01", "2039-10-01", 1250},
+	}
+	if len(templates) != len(want) {
+		t.Fatalf("expected %d templates, got %d", len(want), len(templates))
+	}
+	for i, w := range want {
+		tt := templates[i]
+		if tt.StartDate.String() != w.start || tt.EndDate == nil || tt.EndDate.String() != w.end {
+			t.Errorf("template %d period = %s - %v, want %s - %s", i, tt.StartDate, tt.EndDate, w.start, w.end)
+		}
+		if got := tt.AmountFixed.Mean(); got != w.amount {
+			t.Errorf("template %d amount = %f, want %f", i, got, w.amount)
+		}
+		if tt.ToAccountID != checking.ID || tt.FromAccountID != "" {
+			t.Errorf("template %d accounts = %q -> %q, want income into checking", i, tt.FromAccountID, tt.ToAccountID)
+		}
+	}
+	if got := model.ChildBenefitOn(persons, mustParseDate("2030-01-01")); got != 2650 {
+		t.Errorf("ChildBenefitOn() = %f, want 2650", got)
+	}
+}
+
+func TestNetSegments_UnemploymentReplacesNetSalary(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2025")
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2026")
+
+	sal := createGrossSalary(t, svc, "Test Salary", "STOCKHOLM", "TEST")
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(50000),
+		StartDate: mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+	if _, err := svc.UpsertUnemployment(ctx, model.Unemployment{
+		SalaryID:  sal.ID,
+		StartDate: mustParseDate("2026-01-01"),
+		EndDate:   mustParseDate("2025-12-01"),
+	}); err == nil {
+		t.Error("expected unemployment ending before it starts to be rejected")
+	}
+	u, err := svc.UpsertUnemployment(ctx, model.Unemployment{
+		SalaryID:               sal.ID,
+		StartDate:              mustParseDate("2025-06-01"),
+		EndDate:                mustParseDate("2026-03-01"),
+		AKassaMember:           true,
+		IncomeInsuranceCeiling: 100000,
+		IncomeInsuranceDays:    120,
+	})
+	if err != nil {
+		t.Fatalf("creating unemployment: %v", err)
+	}
+	list, err := svc.ListUnemployments(ctx, sal.ID)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("listing unemployments = %v, %v, want 1", list, err)
+	}
+
+	netTTs := salaryTTsFromAll(t, svc, sal.ID)
+	// One segment before, one per month of the 9 month period, and one after.
+	if len(netTTs) != 11 {
+		t.Fatalf("expected 11 net TTs, got %d", len(netTTs))
+	}
+	normalNet := netTTs[0].AmountFixed.Mean()
+	firstMonth := netTTs[1].AmountFixed.Mean()
+	insured := netTTs[2].AmountFixed.Mean()
+	uninsured := netTTs[9].AmountFixed.Mean()
+	if !(firstMonth < insured && insured < normalNet) {
+		t.Errorf("expected karens month (%v) < insured month (%v) < normal net (%v)", firstMonth, insured, normalNet)
+	}
+	if uninsured >= insured {
+		t.Errorf("expected benefit after income insurance (%v) < insured (%v)", uninsured, insured)
+	}
+	if after := netTTs[10].AmountFixed.Mean(); !approxEqual(after, normalNet, 1.0) {
+		t.Errorf("expected net after unemployment (%v) ~= normal net (%v)", after, normalNet)
+	}
+
+	breakdowns, err := svc.ComputeSalaryBreakdowns(ctx, sal.ID)
+	if err != nil {
+		t.Fatalf("computing breakdowns: %v", err)
+	}
+	if !breakdowns[1].Breakdown.IsUnemployment || breakdowns[1].Breakdown.Tax <= 0 {
+		t.Errorf("expected taxed unemployment breakdown, got %+v", breakdowns[1].Breakdown)
+	}
+
+	if err := svc.DeleteUnemployment(ctx, u.ID); err != nil {
+		t.Fatalf("deleting unemployment: %v", err)
+	}
+	if netTTs := salaryTTsFromAll(t, svc, sal.ID); len(netTTs) != 1 {
+		t.Errorf("expected 1 net TT after delete, got %d", len(netTTs))
+	}
+}
+
+func TestNetSegments_UnemploymentIsTaxedAsNonWorkIncome(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	sal, err := svc.UpsertSalary(ctx, model.Salary{
+		Name:             "Formula Salary",
+		IsGross:          true,
+		Enabled:          true,
+		Recurrence:       "*-*-25",
+		TaxMethod:        model.TaxMethodFormula,
+		MunicipalTaxRate: 0.32,
+	})
+	if err != nil {
+		t.Fatalf("creating salary: %v", err)
+	}
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(40000),
+		StartDate: mustParseDate("2030-01-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+	if _, err := svc.UpsertUnemployment(ctx, model.Unemployment{
+		SalaryID:     sal.ID,
+		StartDate:    mustParseDate("2030-06-01"),
+		EndDate:      mustParseDate("2030-09-01"),
+		AKassaMember: true,
+	}); err != nil {
+		t.Fatalf("creating unemployment: %v", err)
+	}
+
+	bds, err := svc.ComputeSalaryBreakdowns(ctx, sal.ID)
+	if err != nil {
+		t.Fatalf("computing breakdowns: %v", err)
+	}
+	bd := bds[2].Breakdown
+	if !bd.IsUnemployment || bd.AKassa <= 0 {
+		t.Fatalf("expected a-kassa breakdown, got %+v", bd)
+	}
+	workTax, _ := swe.NewFormulaTaxFunc(swe.IncomeTaxParams{
+		Prisbasbelopp:       swe.DefaultPrisbasbelopp,
+		MunicipalTaxRate:    0.32,
+		StateTaxThreshold:   swe.DefaultStateTaxThreshold,
+		StateTaxRate:        swe.DefaultStateTaxRate,
+		PublicServiceFeeMax: swe.DefaultPublicServiceFeeMax,
+	})(bd.AdjustedGross)
+	if bd.Tax <= workTax {
+		t.Errorf("a-kassa tax = %v, want more than the salary tax %v without jobbskatteavdrag", bd.Tax, workTax)
+	}
+
+	netTTs := salaryTTsFromAll(t, svc, sal.ID)
+	if got, want := netTTs[2].AmountFixed.Mean(), bd.NetMonthly; !approxEqual(got, want, 0.01) {
+		t.Errorf("unemployment net = %v, want breakdown net %v", got, want)
+	}
+}
+
+func TestNetSegments_SickLeaveReplacesNetSalary(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2025")
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2026")
+
+	sal := createGrossSalary(t, svc, "Test Salary", "STOCKHOLM", "TEST")
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(40000),
+		StartDate: mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        76200,
+		Prisbasbelopp: 57300,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating swe yearly params: %v", err)
+	}
+	if _, err := svc.UpsertSickLeave(ctx, model.SickLeave{
+		SalaryID:  sal.ID,
+		StartDate: mustParseDate("2025-03-01"),
+		EndDate:   mustParseDate("2025-06-01"),
+	}); err == nil {
+		t.Error("expected sick leave without extent to be rejected")
+	}
+	sl, err := svc.UpsertSickLeave(ctx, model.SickLeave{
+		SalaryID:          sal.ID,
+		StartDate:         mustParseDate("2025-03-01"),
+		EndDate:           mustParseDate("2025-06-01"),
+		Extent:            1,
+		EmployerTopUpRate: 0.1,
+	})
+	if err != nil {
+		t.Fatalf("creating sick leave: %v", err)
+	}
+	list, err := svc.ListSickLeaves(ctx, sal.ID)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("listing sick leaves = %v, %v, want 1", list, err)
+	}
+
+	netTTs := salaryTTsFromAll(t, svc, sal.ID)
+	// Before, one per month of the 3 month leave, and after.
+	if len(netTTs) != 5 {
+		t.Fatalf("expected 5 net TTs, got %d", len(netTTs))
+	}
+	normalNet := netTTs[0].AmountFixed.Mean()
+	firstMonth := netTTs[1].AmountFixed.Mean()
+	secondMonth := netTTs[2].AmountFixed.Mean()
+	// The first month has a karensdag, later months sjukpenning with a top-up.
+	if !(firstMonth < secondMonth && secondMonth < normalNet) {
+		t.Errorf("expected sjuklön month (%v) < sjukpenning month (%v) < normal net (%v)", firstMonth, secondMonth, normalNet)
+	}
+	if after := netTTs[4].AmountFixed.Mean(); !approxEqual(after, normalNet, 1.0) {
+		t.Errorf("expected net after sick leave (%v) ~= normal net (%v)", after, normalNet)
+	}
+
+	breakdowns, err := svc.ComputeSalaryBreakdowns(ctx, sal.ID)
+	if err != nil {
+		t.Fatalf("computing breakdowns: %v", err)
+	}
+	if bd := breakdowns[2].Breakdown; !bd.IsSickLeave || bd.FKSjukCompensation <= 0 || bd.EmployerTopUp <= 0 {
+		t.Errorf("expected sick leave breakdown with sjukpenning and top-up, got %+v", bd)
+	}
+
+	if err := svc.DeleteSickLeave(ctx, sl.ID); err != nil {
+		t.Fatalf("deleting sick leave: %v", err)
+	}
+	if netTTs := salaryTTsFromAll(t, svc, sal.ID); len(netTTs) != 1 {
+		t.Errorf("expected 1 net TT after delete, got %d", len(netTTs))
+	}
+}
+
+func TestComputeSalaryBreakdowns_FullParentalLeaveWithParentalPay(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2025")
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2026")
+
+	sal := createGrossSalary(t, svc, "Test Salary", "STOCKHOLM", "TEST")
+	sal.ParentalPayRate = 0.10
+	sal.ParentalPayAboveCeilingRate = 0.90
+	sal.ParentalPayMaxDays = 90
+	if _, err := svc.UpsertSalary(ctx, sal); err != nil {
+		t.Fatalf("updating salary: %v", err)
+	}
+
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(70000),
+		StartDate: mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        76200,
+		Prisbasbelopp: 57300,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating swe yearly params: %v", err)
+	}
+	if _, err := svc.UpsertFullParentalLeave(ctx, model.FullParentalLeave{
+		SalaryID:        sal.ID,
+		StartDate:       mustParseDate("2025-06-01"),
+		EndDate:         mustParseDate("2026-01-01"),
+		SjukDaysPerWeek: 7,
+	}); err != nil {
+		t.Fatalf("creating full parental leave: %v", err)
+	}
+
+	breakdowns, err := svc.ComputeSalaryBreakdowns(ctx, sal.ID)
+	if err != nil {
+		t.Fatalf("ComputeSalaryBreakdowns: %v", err)
+	}
+	if len(breakdowns) != 4 {
+		t.Fatalf("expected 4 breakdown segments, got %d", len(breakdowns))
+	}
+	if got := breakdowns[2].StartDate; got != mustParseDate("2025-08-30") {
+		t.Errorf("föräldralön should stop after 90 days, segment 2 starts %s", got)
+	}
+	withPay, withoutPay := breakdowns[1].Breakdown, breakdowns[2].Breakdown
+	if !withPay.IsFullParentalLeave || !withoutPay.IsFullParentalLeave {
+		t.Fatal("segments 1 and 2 should be full parental leave")
+	}
+	// 10% up to the SGI ceiling and 90% above it.
+	wantPay := (0.10*10*57300 + 0.90*(70000*12-10*57300)) / 12
+	if !approxEqual(withPay.ParentalPay, wantPay, 0.01) {
+		t.Errorf("ParentalPay = %v, want %v", withPay.ParentalPay, wantPay)
+	}
+	if withoutPay.ParentalPay != 0 {
+		t.Errorf("ParentalPay after max days = %v, want 0", withoutPay.ParentalPay)
+	}
+	if withPay.NetMonthly <= withoutPay.NetMonthly {
+		t.Errorf("net with föräldralön (%v) should exceed net without (%v)", withPay.NetMonthly, withoutPay.NetMonthly)
+	}
+
+	netTTs := salaryTTsFromAll(t, svc, sal.ID)
+	if len(netTTs) != 4 {
+		t.Fatalf("expected 4 net salary templates, got %d", len(netTTs))
+	}
+	if got := netTTs[1].AmountFixed.Mean(); !approxEqual(got, withPay.NetMonthly, 1) {
+		t.Errorf("net template with föräldralön = %v, want breakdown net %v", got, withPay.NetMonthly)
+	}
+	if got := netTTs[2].AmountFixed.Mean(); !approxEqual(got, withoutPay.NetMonthly, 1) {
+		t.Errorf("net template after föräldralön = %v, want breakdown net %v", got, withoutPay.NetMonthly)
+	}
+}
+
+func TestSalaryBonus_TaxedAsEngangsbeloppWithPension(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2025")
+
+	at, err := svc.UpsertAccountType(ctx, model.AccountTypeInput{Name: "Pension"})
+	if err != nil {
+		t.Fatalf("create account type: %v", err)
+	}
+	pensionAcc, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "ITP1", TypeID: at.ID})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	sal := createGrossSalary(t, svc, "Test Salary", "STOCKHOLM", "TEST")
+	sal.PensionAccountID = pensionAcc.ID
+	if _, err := svc.UpsertSalary(ctx, sal); err != nil {
+		t.Fatalf("updating salary: %v", err)
+	}
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(40000),
+		StartDate: mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        76200,
+		Prisbasbelopp: 57300,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating swe yearly params: %v", err)
+	}
+
+	oneOff, err := svc.UpsertSalaryBonus(ctx, model.SalaryBonus{
+		SalaryID:    sal.ID,
+		Name:        "Signing bonus",
+		Amount:      newFixedValue(120000),
+		Probability: 1,
+		PayDate:     mustParseDate("2025-03-25"),
+	})
+	if err != nil {
+		t.Fatalf("creating bonus: %v", err)
+	}
+	yearly, err := svc.UpsertSalaryBonus(ctx, model.SalaryBonus{
+		SalaryID:    sal.ID,
+		Amount:      newFixedValue(120000),
+		Probability: 0.5,
+		PayDate:     mustParseDate("2025-12-20"),
+		Yearly:      true,
+	})
+	if err != nil {
+		t.Fatalf("creating bonus: %v", err)
+	}
+	if _, err := svc.UpsertSalaryBonus(ctx, model.SalaryBonus{SalaryID: sal.ID, Amount: newFixedValue(1), Probability: 1.5}); err == nil {
+		t.Error("expected error for probability above 100%")
+	}
+
+	byID := make(map[string]model.TransferTemplate)
+	for _, tt := range salaryTTsFromAll(t, svc, sal.ID) {
+		byID[tt.ID] = tt
+	}
+	net, ok := byID["salary-bonus:"+oneOff.ID+":2025-03-25"]
+	if !ok {
+		t.Fatalf("missing one-off bonus template, got %v", byID)
+	}
+	if net.Recurrence != "2025-03-25" || net.Name != "Test Salary (Signing bonus)" {
+		t.Errorf("one-off bonus template = %q %q", net.Recurrence, net.Name)
+	}
+	// 40 000 kr/month is taxed 7 500 kr; spreading the bonus over the year
+	// lifts it to 50 000 kr/month, taxed 15 000 kr.
+	if got := net.AmountFixed.Mean(); !approxEqual(got, 120000-12*7500, 0.01) {
+		t.Errorf("net bonus = %v, want %v", got, 120000-12*7500)
+	}
+	pension, ok := byID["salary-bonus-pension:"+oneOff.ID+":2025-03-25"]
+	if !ok {
+		t.Fatal("missing bonus pension template")
+	}
+	if pension.ToAccountID != pensionAcc.ID {
+		t.Errorf("bonus pension paid to %q, want %q", pension.ToAccountID, pensionAcc.ID)
+	}
+	cutoff := 76200 * 7.5 / 12
+	wantPension := 12 * (cutoff*0.045 + (50000-cutoff)*0.30 - 40000*0.045)
+	if got := pension.AmountFixed.Mean(); !approxEqual(got, wantPension, 0.01) {
+		t.Errorf("bonus pension = %v, want %v", got, wantPension)
+	}
+
+	yearlyTT, ok := byID["salary-bonus:"+yearly.ID+":2025-12-20"]
+	if !ok {
+		t.Fatal("missing yearly bonus template")
+	}
+	if yearlyTT.Recurrence != "*-12-20" || yearlyTT.Name != "Test Salary (bonus)" {
+		t.Errorf("yearly bonus template = %q %q", yearlyTT.Recurrence, yearlyTT.Name)
+	}
+	// Paid with 50% probability, so the expected net is about half.
+	if got := yearlyTT.AmountFixed.Mean(); got < 0.3*30000 || got > 0.7*30000 {
+		t.Errorf("expected yearly bonus net = %v, want about %v", got, 0.5*30000)
+	}
+}
+
+func TestSalaryExchange_ReducesNetAndPaysPension(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2025")
+
+	at, err := svc.UpsertAccountType(ctx, model.AccountTypeInput{Name: "Pension"})
+	if err != nil {
+		t.Fatalf("create account type: %v", err)
+	}
+	pensionAcc, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "ITP1", TypeID: at.ID})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	sal := createGrossSalary(t, svc, "Test Salary", "STOCKHOLM", "TEST")
+	sal.PensionAccountID = pensionAcc.ID
+	sal.SalaryExchangeAmount = 5000
+	sal.SalaryExchangeUplift = 0.058
+	if _, err := svc.UpsertSalary(ctx, sal); err != nil {
+		t.Fatalf("updating salary: %v", err)
+	}
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(60000),
+		StartDate: mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(55000),
+		StartDate: mustParseDate("2025-07-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        76200,
+		Prisbasbelopp: 57300,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating swe yearly params: %v", err)
+	}
+
+	breakdowns, err := svc.ComputeSalaryBreakdowns(ctx, sal.ID)
+	if err != nil {
+		t.Fatalf("ComputeSalaryBreakdowns: %v", err)
+	}
+	if len(breakdowns) != 2 {
+		t.Fatalf("expected 2 breakdown segments, got %d", len(breakdowns))
+	}
+	first := breakdowns[0].Breakdown
+	if first.GrossMonthly != 60000 || first.SalaryExchange != 5000 || first.AdjustedGross != 55000 {
+		t.Errorf("breakdown gross=%v exchange=%v adjusted=%v, want 60000, 5000, 55000",
+			first.GrossMonthly, first.SalaryExchange, first.AdjustedGross)
+	}
+	if first.BelowSalaryExchangeThreshold {
+		t.Error("55 000 kr/month is above 8.07 IBB, expected no warning")
+	}
+	// 50 000 kr/month left is below 8.07 IBB (51 245 kr/month).
+	if !breakdowns[1].Breakdown.BelowSalaryExchangeThreshold {
+		t.Error("expected warning when the remaining salary drops below 8.07 IBB")
+	}
+
+	var netTTs, pensionTTs []model.TransferTemplate
+	for _, tt := range salaryTTsFromAll(t, svc, sal.ID) {
+		if tt.ToAccountID == pensionAcc.ID {
+			pensionTTs = append(pensionTTs, tt)
+		} else {
+			netTTs = append(netTTs, tt)
+		}
+	}
+	if len(netTTs) != 2 || len(pensionTTs) != 2 {
+		t.Fatalf("expected 2 net and 2 pension templates, got %d and %d", len(netTTs), len(pensionTTs))
+	}
+	if got := netTTs[0].AmountFixed.Mean(); !approxEqual(got, first.NetMonthly, 0.01) {
+		t.Errorf("net template = %v, want breakdown net %v", got, first.NetMonthly)
+	}
+	// ITP1 stays on the salary before the exchange, and the exchange is paid
+	// with the employer uplift on top.
+	wantPension := swe.CalculateITP1Pension(60000, 76200) + 5000*1.058
+	if got := pensionTTs[0].AmountFixed.Mean(); !approxEqual(got, wantPension, 0.01) {
+		t.Errorf("pension = %v, want %v", got, wantPension)
+	}
+}
+
+func TestSecondaryEmployer_FlatTaxAndYearlyReconciliation(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	person, err := svc.UpsertPerson(ctx, model.Person{Name: "Alex"})
+	if err != nil {
+		t.Fatalf("creating person: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        80600,
+		Prisbasbelopp: 58800,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating swe yearly params: %v", err)
+	}
+	newSalary := func(name string, amount float64, secondary bool) model.Salary {
+		sal, err := svc.UpsertSalary(ctx, model.Salary{
+			Name:              name,
+			OwnerID:           person.ID,
+			IsGross:           true,
+			Enabled:           true,
+			Recurrence:        "*-*-25",
+			TaxMethod:         model.TaxMethodFormula,
+			MunicipalTaxRate:  0.32,
+			SecondaryEmployer: secondary,
+		})
+		if err != nil {
+			t.Fatalf("creating salary: %v", err)
+		}
+		if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+			SalaryID:  sal.ID,
+			Amount:    newFixedValue(amount),
+			StartDate: mustParseDate("2025-01-01"),
+		}); err != nil {
+			t.Fatalf("creating salary amount: %v", err)
+		}
+		return sal
+	}
+	primary := newSalary("Main job", 40000, false)
+	secondary := newSalary("Side job", 10000, true)
+
+	breakdowns, err := svc.ComputeSalaryBreakdowns(ctx, secondary.ID)
+	if err != nil {
+		t.Fatalf("ComputeSalaryBreakdowns: %v", err)
+	}
+	if len(breakdowns) != 1 || breakdowns[0].Breakdown.Tax != 3000 {
+		t.Fatalf("secondary salary should be taxed a flat 30%%, got %+v", breakdowns)
+	}
+
+	params := swe.IncomeTaxParams{
+		Prisbasbelopp:       58800,
+		MunicipalTaxRate:    0.32,
+		StateTaxThreshold:   swe.DefaultStateTaxThreshold,
+		StateTaxRate:        swe.DefaultStateTaxRate,
+		PublicServiceFeeMax: swe.DefaultPublicServiceFeeMax,
+	}
+	withheld := swe.CalculateAnnualIncomeTax(40000*12, params).TotalTax + 12*3000
+	final := swe.CalculateAnnualIncomeTax(50000*12, params).TotalTax
+	restskatt := final - withheld
+	if restskatt <= 0 {
+		t.Fatalf("expected restskatt with 30%% on the side job, got %v", restskatt)
+	}
+
+	var settlements []model.TransferTemplate
+	for _, tt := range salaryTTsFromAll(t, svc, primary.ID) {
+		if strings.HasPrefix(tt.ID, "salary-tax:") {
+			settlements = append(settlements, tt)
+		}
+	}
+	if len(settlements) != 2 {
+		t.Fatalf("expected settlements for 2025 and recurring from 2026, got %d", len(settlements))
+	}
+	slices.SortFunc(settlements, func(a, b model.TransferTemplate) int { return int(a.StartDate - b.StartDate) })
+	first := settlements[0]
+	if first.StartDate != mustParseDate("2026-11-12") || first.Recurrence != "2026-11-12" {
+		t.Errorf("2025 restskatt due %s (%s), want 2026-11-12", first.StartDate, first.Recurrence)
+	}
+	if first.FromAccountID != primary.ToAccountID || first.ToAccountID != "" {
+		t.Errorf("restskatt should be paid from the salary account")
+	}
+	if got := first.AmountFixed.Mean(); !approxEqual(got, restskatt, 1) {
+		t.Errorf("restskatt = %v, want %v", got, restskatt)
+	}
+	if settlements[1].Recurrence != "*-11-12" || settlements[1].StartDate != mustParseDate("2027-11-12") {
+		t.Errorf("last settlement should recur yearly from 2027-11-12, got %s from %s", settlements[1].Recurrence, settlements[1].StartDate)
+	}
+}
+
+func TestSecondaryEmployer_ReconciliationTaxesBenefitsAsNonWorkIncome(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	person, err := svc.UpsertPerson(ctx, model.Person{Name: "Alex"})
+	if err != nil {
+		t.Fatalf("creating person: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        80600,
+		Prisbasbelopp: 58800,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating swe yearly params: %v", err)
+	}
+	newSalary := func(name string, amount float64, secondary bool) model.Salary {
+		sal, err := svc.UpsertSalary(ctx, model.Salary{
+			Name:              name,
+			OwnerID:           person.ID,
+			IsGross:           true,
+			Enabled:           true,
+			Recurrence:        "*-*-25",
+			TaxMethod:         model.TaxMethodFormula,
+			MunicipalTaxRate:  0.32,
+			SecondaryEmployer: secondary,
+		})
+		if err != nil {
+			t.Fatalf("creating salary: %v", err)
+		}
+		if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+			SalaryID:  sal.ID,
+			Amount:    newFixedValue(amount),
+			StartDate: mustParseDate("2025-01-01"),
+		}); err != nil {
+			t.Fatalf("creating salary amount: %v", err)
+		}
+		return sal
+	}
+	primary := newSalary("Main job", 40000, false)
+	newSalary("Side job", 10000, true)
+	if _, err := svc.UpsertUnemployment(ctx, model.Unemployment{
+		SalaryID:     primary.ID,
+		StartDate:    mustParseDate("2025-01-01"),
+		EndDate:      mustParseDate("2026-01-01"),
+		AKassaMember: true,
+	}); err != nil {
+		t.Fatalf("creating unemployment: %v", err)
+	}
+
+	breakdowns, err := svc.ComputeSalaryBreakdowns(ctx, primary.ID)
+	if err != nil {
+		t.Fatalf("ComputeSalaryBreakdowns: %v", err)
+	}
+	benefits, withheld := 0.0, 12*3000.0
+	for _, bd := range breakdowns {
+		if bd.StartDate.Year() == 2025 {
+			benefits += bd.Breakdown.BenefitIncome()
+			withheld += bd.Breakdown.Tax
+		}
+	}
+	if benefits <= 0 {
+		t.Fatalf("expected a-kassa in 2025, got %+v", breakdowns)
+	}
+
+	params := swe.IncomeTaxParams{
+		Prisbasbelopp:       58800,
+		MunicipalTaxRate:    0.32,
+		StateTaxThreshold:   swe.DefaultStateTaxThreshold,
+		StateTaxRate:        swe.DefaultStateTaxRate,
+		PublicServiceFeeMax: swe.DefaultPublicServiceFeeMax,
+	}
+	nonWork := params
+	nonWork.NonWorkIncome = true
+	work := 10000.0 * 12
+	final := swe.CalculateAnnualIncomeTax(work, params).TotalTax +
+		swe.CalculateAnnualIncomeTax(work+benefits, nonWork).TotalTax -
+		swe.CalculateAnnualIncomeTax(work, nonWork).TotalTax
+
+	for _, tt := range salaryTTsFromAll(t, svc, primary.ID) {
+		if tt.ID != "salary-tax:"+primary.ID+":2025" {
+			continue
+		}
+		if got := tt.AmountFixed.Mean(); !approxEqual(got, math.Abs(withheld-final), 1) {
+			t.Errorf("2025 settlement = %v, want %v", got, math.Abs(withheld-final))
+		}
+		return
+	}
+	t.Fatal("expected a settlement for 2025")
+}
+
+func TestRunPrediction_TaxReturnSettlesFinalTax(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	thisYear := time.Now().Year()
+	lastYearStart := mustParseDate(fmt.Sprintf("%d-01-01", thisYear-1))
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	taxAccount, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Tax account"})
+	if err != nil {
+		t.Fatalf("create tax account: %v", err)
+	}
+	person, err := svc.UpsertPerson(ctx, model.Person{Name: "Alex", TaxAccountID: taxAccount.ID})
+	if err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+	if person.TaxAccountID != taxAccount.ID {
+		t.Fatalf("tax account = %q, want %q", person.TaxAccountID, taxAccount.ID)
+	}
+	loan, err := svc.UpsertAccount(ctx, model.AccountInput{
+		Name:                  "Mortgage",
+		OwnerID:               person.ID,
+		CashFlowFrequency:     "*-*-01",
+		CashFlowDestinationID: checking.ID,
+	})
+	if err != nil {
+		t.Fatalf("create loan account: %v", err)
+	}
+	if _, err := svc.UpsertAccountGrowthModel(ctx, model.AccountGrowthModelInput{
+		AccountID:        loan.ID,
+		Type:             "fixed",
+		AnnualRate:       newFixedValue(0.03),
+		AnnualVolatility: newFixedValue(0),
+		StartDate:        lastYearStart,
+	}); err != nil {
+		t.Fatalf("create growth model: %v", err)
+	}
+	for id, bal := range map[string]float64{loan.ID: -1_000_000, checking.ID: 0, taxAccount.ID: 0} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: lastYearStart, Balance: newFixedValue(bal)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	// A salary from a secondary employer only, whose 30% withholding exceeds
+	// the final tax on the low income.
+	sal, err := svc.UpsertSalary(ctx, model.Salary{
+		Name:              "Side job",
+		OwnerID:           person.ID,
+		ToAccountID:       checking.ID,
+		IsGross:           true,
+		Enabled:           true,
+		Recurrence:        "*-*-25",
+		TaxMethod:         model.TaxMethodFormula,
+		MunicipalTaxRate:  0.32,
+		SecondaryEmployer: true,
+	})
+	if err != nil {
+		t.Fatalf("create salary: %v", err)
+	}
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{SalaryID: sal.ID, Amount: newFixedValue(10000), StartDate: lastYearStart}); err != nil {
+		t.Fatalf("create salary amount: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{Amount: 80600, Prisbasbelopp: 58800, ValidFrom: lastYearStart}); err != nil {
+		t.Fatalf("create swe yearly params: %v", err)
+	}
+
+	for _, tt := range salaryTTsFromAll(t, svc, sal.ID) {
+		if strings.HasPrefix(tt.ID, "salary-tax:") {
+			t.Errorf("salary tax settled by template %s alongside the tax return", tt.ID)
+		}
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	if bal := h.balances[taxAccount.ID][mustParseDate(fmt.Sprintf("%d-04-01", thisYear))]; bal != 0 {
+		t.Errorf("tax account balance before settlement = %f, want 0", bal)
+	}
+	params := swe.IncomeTaxParams{
+		Prisbasbelopp:       58800,
+		MunicipalTaxRate:    0.32,
+		StateTaxThreshold:   swe.DefaultStateTaxThreshold,
+		StateTaxRate:        swe.DefaultStateTaxRate,
+		PublicServiceFeeMax: swe.DefaultPublicServiceFeeMax,
+	}
+	salaryRefund := 12*3000 - swe.CalculateAnnualIncomeTax(12*10000, params).TotalTax
+	// Interest paid Feb 1 - Dec 1 last year covers Jan - Nov, roughly 11/12 of 30 000.
+	want := salaryRefund + 30_000.0*11/12*swe.InterestDeductionRate
+	got := h.balances[taxAccount.ID][mustParseDate(fmt.Sprintf("%d-05-01", thisYear))]
+	if !approxEqual(got, want, want*0.03) {
+		t.Errorf("tax account balance after settlement = %f, want about %f", got, want)
+	}
+}
+
+func TestRunPrediction_TaxReturnTakesDeductibleInterest(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	thisYear := time.Now().Year()
+	lastYearStart := mustParseDate(fmt.Sprintf("%d-01-01", thisYear-1))
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	savings, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Savings"})
+	if err != nil {
+		t.Fatalf("create savings account: %v", err)
+	}
+	taxAccount, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Tax account"})
+	if err != nil {
+		t.Fatalf("create tax account: %v", err)
+	}
+	person, err := svc.UpsertPerson(ctx, model.Person{Name: "Alex", TaxAccountID: taxAccount.ID})
+	if err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+	loan, err := svc.UpsertAccount(ctx, model.AccountInput{
+		Name:                  "Mortgage",
+		OwnerID:               person.ID,
+		CashFlowFrequency:     "*-*-01",
+		CashFlowDestinationID: checking.ID,
+	})
+	if err != nil {
+		t.Fatalf("create loan account: %v", err)
+	}
+	if _, err := svc.UpsertAccountGrowthModel(ctx, model.AccountGrowthModelInput{
+		AccountID:        loan.ID,
+		Type:             "fixed",
+		AnnualRate:       newFixedValue(0.03),
+		AnnualVolatility: newFixedValue(0),
+		StartDate:        lastYearStart,
+	}); err != nil {
+		t.Fatalf("create growth model: %v", err)
+	}
+	for id, bal := range map[string]float64{loan.ID: -1_000_000, checking.ID: 0, savings.ID: 0, taxAccount.ID: 0} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: lastYearStart, Balance: newFixedValue(bal)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	if _, err := svc.UpsertInterestDeduction(ctx, model.InterestDeduction{
+		AccountID:      loan.ID,
+		ToAccountID:    savings.ID,
+		SettlementDate: "*-04-07",
+		Borrowers:      1,
+	}); err != nil {
+		t.Fatalf("upsert interest deduction: %v", err)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	// The owner declares the interest in the tax return, so the separate
+	// deduction refunds nothing.
+	after := mustParseDate(fmt.Sprintf("%d-05-01", thisYear))
+	if bal := h.balances[savings.ID][after]; bal != 0 {
+		t.Errorf("savings balance after settlement = %f, want 0", bal)
+	}
+	// Interest paid Feb 1 - Dec 1 last year covers Jan - Nov, roughly 11/12 of 30 000.
+	want := 30_000.0 * 11 / 12 * swe.InterestDeductionRate
+	if got := h.balances[taxAccount.ID][after]; !approxEqual(got, want, want*0.03) {
+		t.Errorf("tax account balance after settlement = %f, want about %f", got, want)
+	}
+}
+
+func TestRunPrediction_TaxReturnTaxesStartupExitGain(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	now := time.Now().UTC()
+	monthStart := func(months int) date.Date {
+		return date.FromTime(time.Date(now.Year(), now.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC))
+	}
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	taxAccount, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Tax account"})
+	if err != nil {
+		t.Fatalf("create tax account: %v", err)
+	}
+	for _, id := range []string{checking.ID, taxAccount.ID} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: monthStart(-1), Balance: newFixedValue(0)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	person, err := svc.UpsertPerson(ctx, model.Person{Name: "Alex", TaxAccountID: taxAccount.ID})
+	if err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+	startup, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Startup", OwnerID: person.ID})
+	if err != nil {
+		t.Fatalf("create startup account: %v", err)
+	}
+	if _, err := svc.UpsertStartupShareAccount(ctx, model.StartupShareAccountInput{AccountID: startup.ID, TaxRate: 0.25, ValuationDiscountFactor: 1}); err != nil {
+		t.Fatalf("create startup share account: %v", err)
+	}
+	if _, err := svc.UpsertInvestmentRound(ctx, model.InvestmentRoundInput{AccountID: startup.ID, Date: monthStart(-12), Valuation: 1_000_000, PreMoneyShares: 1000}); err != nil {
+		t.Fatalf("create investment round: %v", err)
+	}
+	if _, err := svc.UpsertShareChange(ctx, model.ShareChangeInput{AccountID: startup.ID, Date: monthStart(-12), DeltaShares: 100, TotalPrice: 10_000}); err != nil {
+		t.Fatalf("create share change: %v", err)
+	}
+	exitDay := monthStart(2)
+	if _, err := svc.UpsertStartupExit(ctx, model.StartupExitInput{
+		AccountID:       startup.ID,
+		Date:            exitDay,
+		Kind:            finance.StartupExitAcquisition,
+		Multiple:        newFixedValue(2),
+		PayoutAccountID: checking.ID,
+	}); err != nil {
+		t.Fatalf("create startup exit: %v", err)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         3 * date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	// The 100 shares sell at 2 000 each and are paid out untaxed, with the
+	// gain over the 10 000 paid taxed as capital in the tax return.
+	gross := 2.0 * 1000 * 100
+	if got := h.balances[checking.ID][monthStart(3)]; !approxEqual(got, gross, 1) {
+		t.Errorf("checking balance after exit = %f, want %f", got, gross)
+	}
+	restskatt := (gross - 10_000) * swe.CapitalIncomeTaxRate
+	settled := mustParseDate(fmt.Sprintf("%d-12-01", exitDay.Year()+1))
+	if got := h.balances[taxAccount.ID][settled]; !approxEqual(got, -restskatt, 1) {
+		t.Errorf("tax account balance after settlement = %f, want %f", got, -restskatt)
+	}
+}
+
+func TestCompany_SalaryCostTaxAndDividendTemplates(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2025")
+
+	companyAcc, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Company"})
+	if err != nil {
+		t.Fatalf("create company account: %v", err)
+	}
+	privateAcc, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Private"})
+	if err != nil {
+		t.Fatalf("create private account: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        80600,
+		Prisbasbelopp: 58800,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating ibb: %v", err)
+	}
+	sal := createGrossSalary(t, svc, "Owner Salary", "STOCKHOLM", "TEST")
+	if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+		SalaryID:  sal.ID,
+		Amount:    newFixedValue(40000),
+		StartDate: mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating salary amount: %v", err)
+	}
+	c, err := svc.UpsertCompany(ctx, model.Company{
+		Name:              "Konsult AB",
+		OwnershipShare:    1,
+		AccountID:         companyAcc.ID,
+		DividendAccountID: privateAcc.ID,
+		SalaryID:          sal.ID,
+		Revenue:           newFixedValue(100000),
+		Expenses:          newFixedValue(10000),
+		GransbeloppRule:   swe.GransbeloppSimplified,
+		StartDate:         mustParseDate("2025-01-01"),
+	})
+	if err != nil {
+		t.Fatalf("creating company: %v", err)
+	}
+
+	all, err := svc.ListAllTransferTemplates(ctx)
+	if err != nil {
+		t.Fatalf("listing transfer templates: %v", err)
+	}
+	byID := make(map[string]model.TransferTemplate)
+	for _, tt := range all {
+		if tt.Source.Type == "company" {
+			byID[tt.ID] = tt
+		}
+	}
+
+	salaryCost, ok := byID["company-salary-cost:"+c.ID+":2025-01-01"]
+	if !ok {
+		t.Fatalf("missing salary cost template, got %v", slices.Collect(maps.Keys(byID)))
+	}
+	if want := 40000 * (1 + swe.EmployerContributionRate); !approxEqual(salaryCost.AmountFixed.Mean(), want, 0.01) {
+		t.Errorf("salary cost = %v, want %v", salaryCost.AmountFixed.Mean(), want)
+	}
+	if salaryCost.FromAccountID != companyAcc.ID || salaryCost.Recurrence != sal.Recurrence {
+		t.Errorf("salary cost from %q on %q, want %q on %q", salaryCost.FromAccountID, salaryCost.Recurrence, companyAcc.ID, sal.Recurrence)
+	}
+
+	taxable := 90000 - 40000*(1+swe.EmployerContributionRate)
+	tax, ok := byID["company-tax:"+c.ID+":2025"]
+	if !ok {
+		t.Fatal("missing corporate tax template")
+	}
+	if want := taxable * swe.CorporateTaxRate; !approxEqual(tax.AmountFixed.Mean(), want, 0.01) {
+		t.Errorf("corporate tax = %v, want %v", tax.AmountFixed.Mean(), want)
+	}
+
+	// A yearly profit of about 357k after tax is capped at 2.75 IBB.
+	gransbelopp := swe.SimplifiedRuleIBB * 80600
+	for _, year := range []string{"2026", "2027"} {
+		dividend, ok := byID["company-dividend:"+c.ID+":"+year]
+		if !ok {
+			t.Fatalf("missing dividend template for %s", year)
+		}
+		if want := gransbelopp * (1 - swe.QualifiedDividendTaxRate); !approxEqual(dividend.AmountFixed.Mean(), want, 0.01) {
+			t.Errorf("%s dividend = %v, want %v", year, dividend.AmountFixed.Mean(), want)
+		}
+		if dividend.ToAccountID != privateAcc.ID || dividend.StartDate != mustParseDate(year+"-06-30") {
+			t.Errorf("%s dividend to %q on %v", year, dividend.ToAccountID, dividend.StartDate)
+		}
+		divTax := byID["company-dividend-tax:"+c.ID+":"+year]
+		if want := gransbelopp * swe.QualifiedDividendTaxRate; !approxEqual(divTax.AmountFixed.Mean(), want, 0.01) {
+			t.Errorf("%s dividend tax = %v, want %v", year, divTax.AmountFixed.Mean(), want)
+		}
+	}
+	if got := byID["company-dividend:"+c.ID+":2027"].Recurrence; got != "*-06-30" {
+		t.Errorf("last dividend recurrence = %q, want *-06-30", got)
+	}
+	if _, ok := byID["company-dividend-others:"+c.ID+":2026"]; ok {
+		t.Error("sole owner should not pay other owners")
+	}
+}
+
+func TestCompany_SavedDividendSpaceUpraknas(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2025")
+
+	companyAcc, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Company"})
+	if err != nil {
+		t.Fatalf("create company account: %v", err)
+	}
+	privateAcc, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Private"})
+	if err != nil {
+		t.Fatalf("create private account: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:         80600,
+		Prisbasbelopp:  58800,
+		Statslaneranta: 0.02,
+		ValidFrom:      mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating ibb: %v", err)
+	}
+	// The salary takes all of the revenue for two years, after which the
+	// company makes a profit.
+	sal := createGrossSalary(t, svc, "Owner Salary", "STOCKHOLM", "TEST")
+	for _, amt := range []struct {
+		start  string
+		amount float64
+	}{{"2025-01-01", 100000 / (1 + swe.EmployerContributionRate)}, {"2027-01-01", 0}} {
+		if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+			SalaryID:  sal.ID,
+			Amount:    newFixedValue(amt.amount),
+			StartDate: mustParseDate(amt.start),
+		}); err != nil {
+			t.Fatalf("creating salary amount: %v", err)
+		}
+	}
+	c, err := svc.UpsertCompany(ctx, model.Company{
+		Name:              "Konsult AB",
+		OwnershipShare:    1,
+		AccountID:         companyAcc.ID,
+		DividendAccountID: privateAcc.ID,
+		SalaryID:          sal.ID,
+		Revenue:           newFixedValue(100000),
+		Expenses:          newFixedValue(0),
+		GransbeloppRule:   swe.GransbeloppSimplified,
+		StartDate:         mustParseDate("2025-01-01"),
+	})
+	if err != nil {
+		t.Fatalf("creating company: %v", err)
+	}
+
+	all, err := svc.ListAllTransferTemplates(ctx)
+	if err != nil {
+		t.Fatalf("listing transfer templates: %v", err)
+	}
+	byID := make(map[string]model.TransferTemplate)
+	for _, tt := range all {
+		byID[tt.ID] = tt
+	}
+	for _, year := range []string{"2026", "2027"} {
+		if _, ok := byID["company-dividend:"+c.ID+":"+year]; ok {
+			t.Errorf("unexpected dividend in %s without a profit", year)
+		}
+	}
+	// The gränsbelopp of 2026 and 2027 is saved, and grows by the
+	// statslåneränta plus 3 percentage points each year it is carried.
+	gransbelopp := swe.SimplifiedRuleIBB * 80600
+	saved := (gransbelopp*1.05 + gransbelopp) * 1.05
+	dividend, ok := byID["company-dividend:"+c.ID+":2028"]
+	if !ok {
+		t.Fatalf("missing 2028 dividend template")
+	}
+	if want := (gransbelopp + saved) * (1 - swe.QualifiedDividendTaxRate); !approxEqual(dividend.AmountFixed.Mean(), want, 1) {
+		t.Errorf("2028 dividend = %v, want %v", dividend.AmountFixed.Mean(), want)
+	}
+}
+
+func TestCompany_CorporateTaxOnYearlyResult(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+	seedTaxCache(t, svc, "STOCKHOLM", "TEST", "2025")
+
+	companyAcc, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Company"})
+	if err != nil {
+		t.Fatalf("create company account: %v", err)
+	}
+	sal := createGrossSalary(t, svc, "Owner Salary", "STOCKHOLM", "TEST")
+	for _, amt := range []struct {
+		start  string
+		amount float64
+	}{{"2025-01-01", 120000}, {"2025-07-01", 0}} {
+		if _, err := svc.UpsertSalaryAmount(ctx, model.SalaryAmount{
+			SalaryID:  sal.ID,
+			Amount:    newFixedValue(amt.amount),
+			StartDate: mustParseDate(amt.start),
+		}); err != nil {
+			t.Fatalf("creating salary amount: %v", err)
+		}
+	}
+	c, err := svc.UpsertCompany(ctx, model.Company{
+		Name:            "Konsult AB",
+		OwnershipShare:  1,
+		AccountID:       companyAcc.ID,
+		SalaryID:        sal.ID,
+		Revenue:         newFixedValue(100000),
+		Expenses:        newFixedValue(0),
+		GransbeloppRule: swe.GransbeloppSimplified,
+		StartDate:       mustParseDate("2025-01-01"),
+	})
+	if err != nil {
+		t.Fatalf("creating company: %v", err)
+	}
+
+	all, err := svc.ListAllTransferTemplates(ctx)
+	if err != nil {
+		t.Fatalf("listing transfer templates: %v", err)
+	}
+	byID := make(map[string]model.TransferTemplate)
+	for _, tt := range all {
+		byID[tt.ID] = tt
+	}
+	// The loss of the first half year offsets the profit of the second.
+	taxable := 12*100000 - 6*120000*(1+swe.EmployerContributionRate)
+	tax, ok := byID["company-tax:"+c.ID+":2025"]
+	if !ok {
+		t.Fatalf("missing 2025 corporate tax template")
+	}
+	if want := taxable * swe.CorporateTaxRate / 12; !approxEqual(tax.AmountFixed.Mean(), want, 0.01) {
+		t.Errorf("2025 corporate tax payment = %v, want %v", tax.AmountFixed.Mean(), want)
+	}
+	if tax.EndDate == nil || *tax.EndDate != mustParseDate("2026-01-01") {
+		t.Errorf("2025 corporate tax should end at the end of the year, got %v", tax.EndDate)
+	}
+	last, ok := byID["company-tax:"+c.ID+":2026"]
+	if !ok {
+		t.Fatalf("missing 2026 corporate tax template")
+	}
+	if want := 100000 * swe.CorporateTaxRate; !approxEqual(last.AmountFixed.Mean(), want, 0.01) || last.EndDate != nil {
+		t.Errorf("2026 corporate tax payment = %v until %v, want %v recurring", last.AmountFixed.Mean(), last.EndDate, want)
+	}
+}
+
+func TestSoleProprietorship_FSkattWithPeriodiseringsfondReversal(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	acc, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Business"})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	owner, err := svc.UpsertPerson(ctx, model.Person{Name: "Alice", BirthDate: mustParseDate("1990-05-10")})
+	if err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        80600,
+		Prisbasbelopp: 58800,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating ibb: %v", err)
+	}
+	sp, err := svc.UpsertSoleProprietorship(ctx, model.SoleProprietorship{
+		Name:                    "Webbdesign",
+		OwnerID:                 owner.ID,
+		ToAccountID:             acc.ID,
+		Profit:                  newFixedValue(20000),
+		PeriodiseringsfondShare: 0.25,
+		MunicipalTaxRate:        0.32,
+		StartDate:               mustParseDate("2025-01-01"),
+		Enabled:                 true,
+	})
+	if err != nil {
+		t.Fatalf("creating sole proprietorship: %v", err)
+	}
+
+	all, err := svc.ListAllTransferTemplates(ctx)
+	if err != nil {
+		t.Fatalf("listing transfer templates: %v", err)
+	}
+	byID := make(map[string]model.TransferTemplate)
+	for _, tt := range all {
+		if tt.Source.Type == "sole-proprietorship" {
+			byID[tt.ID] = tt
+		}
+	}
+	if profit := byID["sole-proprietorship-profit:"+sp.ID]; profit.ToAccountID != acc.ID || profit.AmountFixed.Mean() != 20000 {
+		t.Errorf("profit template to %q of %v, want %q of 20000", profit.ToAccountID, profit.AmountFixed.Mean(), acc.ID)
+	}
+
+	incomeTax := func(annual float64) float64 {
+		return swe.CalculateAnnualIncomeTax(annual, swe.IncomeTaxParams{
+			Prisbasbelopp:       58800,
+			MunicipalTaxRate:    0.32,
+			StateTaxThreshold:   swe.DefaultStateTaxThreshold,
+			StateTaxRate:        swe.DefaultStateTaxRate,
+			PublicServiceFeeMax: swe.DefaultPublicServiceFeeMax,
+		}).TotalTax
+	}
+	first := swe.CalculateSoleProprietor(swe.SoleProprietorInput{Profit: 240000, PeriodiseringsfondShare: 0.25}, incomeTax)
+	tax2025, ok := byID[fmt.Sprintf("sole-proprietorship-tax:%s:2025", sp.ID)]
+	if !ok {
+		t.Fatalf("missing 2025 F-skatt template, got %v", slices.Collect(maps.Keys(byID)))
+	}
+	if !approxEqual(tax2025.AmountFixed.Mean(), first.Tax()/12, 1) {
+		t.Errorf("2025 F-skatt = %v, want %v", tax2025.AmountFixed.Mean(), first.Tax()/12)
+	}
+	if tax2025.FromAccountID != acc.ID || tax2025.EndDate == nil || *tax2025.EndDate != mustParseDate("2026-01-01") {
+		t.Errorf("2025 F-skatt from %q ending %v", tax2025.FromAccountID, tax2025.EndDate)
+	}
+
+	// The 2025 allocation is reversed six years later, raising the tax.
+	reversed := swe.CalculateSoleProprietor(swe.SoleProprietorInput{
+		Profit: 240000, PeriodiseringsfondShare: 0.25, Reversal: first.Periodiseringsfond,
+	}, incomeTax)
+	tax2031, ok := byID[fmt.Sprintf("sole-proprietorship-tax:%s:2031", sp.ID)]
+	if !ok {
+		t.Fatal("missing 2031 F-skatt template")
+	}
+	if !approxEqual(tax2031.AmountFixed.Mean(), reversed.Tax()/12, 1) {
+		t.Errorf("2031 F-skatt = %v, want %v", tax2031.AmountFixed.Mean(), reversed.Tax()/12)
+	}
+	if tax2031.EndDate != nil {
+		t.Errorf("last F-skatt template should repeat, ends %v", *tax2031.EndDate)
+	}
+	if reversed.Tax() <= first.Tax() {
+		t.Errorf("reversal should raise the tax: %v <= %v", reversed.Tax(), first.Tax())
+	}
+}
+
+func TestRunPrediction_StartupProjectedRoundAndExit(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	now := time.Now().UTC()
+	monthStart := func(months int) date.Date {
+		return date.FromTime(time.Date(now.Year(), now.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC))
+	}
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	if _, err := svc.UpsertAccountSnapshot(ctx, checking.ID, model.AccountSnapshotInput{Date: monthStart(-1), Balance: newFixedValue(0)}); err != nil {
+		t.Fatalf("create checking snapshot: %v", err)
+	}
+	startup, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Startup"})
+	if err != nil {
+		t.Fatalf("create startup account: %v", err)
+	}
+	if _, err := svc.UpsertStartupShareAccount(ctx, model.StartupShareAccountInput{AccountID: startup.ID, TaxRate: 0.3, ValuationDiscountFactor: 1}); err != nil {
+		t.Fatalf("create startup share account: %v", err)
+	}
+	if _, err := svc.UpsertInvestmentRound(ctx, model.InvestmentRoundInput{AccountID: startup.ID, Date: monthStart(-12), Valuation: 1_000_000, PreMoneyShares: 1000}); err != nil {
+		t.Fatalf("create investment round: %v", err)
+	}
+	if _, err := svc.UpsertShareChange(ctx, model.ShareChangeInput{AccountID: startup.ID, Date: monthStart(-12), DeltaShares: 100, TotalPrice: 10_000}); err != nil {
+		t.Fatalf("create share change: %v", err)
+	}
+	if _, err := svc.UpsertProjectedInvestmentRound(ctx, model.ProjectedInvestmentRoundInput{
+		AccountID:         startup.ID,
+		Date:              monthStart(2),
+		Probability:       1,
+		PreMoneyValuation: newFixedValue(2_000_000),
+		Investment:        newFixedValue(0),
+	}); err != nil {
+		t.Fatalf("create projected round: %v", err)
+	}
+	if _, err := svc.UpsertProjectedInvestmentRound(ctx, model.ProjectedInvestmentRoundInput{
+		AccountID:         startup.ID,
+		Date:              monthStart(3),
+		Probability:       1.5,
+		PreMoneyValuation: newFixedValue(2_000_000),
+	}); err == nil {
+		t.Error("expected an error for a probability above 1")
+	}
+	if _, err := svc.UpsertStartupExit(ctx, model.StartupExitInput{
+		AccountID:       startup.ID,
+		Date:            monthStart(4),
+		Kind:            finance.StartupExitAcquisition,
+		Multiple:        newFixedValue(2),
+		PayoutAccountID: checking.ID,
+	}); err != nil {
+		t.Fatalf("create startup exit: %v", err)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	// The round doubles the price per share to 2 000 and the exit sells the
+	// 100 shares at twice that, taxing the gain over the 10 000 paid.
+	gross := 2.0 * 2000 * 100
+	want := gross - (gross-10_000)*0.3
+	if got := h.balances[checking.ID][monthStart(6)]; !approxEqual(got, want, 1) {
+		t.Errorf("checking balance after exit = %f, want %f", got, want)
+	}
+	if got := h.balances[startup.ID][monthStart(6)]; got != 0 {
+		t.Errorf("startup balance after exit = %f, want 0", got)
+	}
+}
+
+func TestRunPrediction_StartupOptionVestingCashedOutAtExit(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	now := time.Now().UTC()
+	monthStart := func(months int) date.Date {
+		return date.FromTime(time.Date(now.Year(), now.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC))
+	}
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	if _, err := svc.UpsertAccountSnapshot(ctx, checking.ID, model.AccountSnapshotInput{Date: monthStart(-1), Balance: newFixedValue(0)}); err != nil {
+		t.Fatalf("create checking snapshot: %v", err)
+	}
+	startup, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Startup"})
+	if err != nil {
+		t.Fatalf("create startup account: %v", err)
+	}
+	if _, err := svc.UpsertStartupShareAccount(ctx, model.StartupShareAccountInput{AccountID: startup.ID, TaxRate: 0.3, ValuationDiscountFactor: 1}); err != nil {
+		t.Fatalf("create startup share account: %v", err)
+	}
+	if _, err := svc.UpsertInvestmentRound(ctx, model.InvestmentRoundInput{AccountID: startup.ID, Date: monthStart(-12), Valuation: 1_000_000, PreMoneyShares: 1000}); err != nil {
+		t.Fatalf("create investment round: %v", err)
+	}
+	if _, err := svc.UpsertStartupShareOption(ctx, model.StartupShareOptionInput{
+		AccountID:           startup.ID,
+		SourceAccountID:     checking.ID,
+		Shares:              120,
+		StrikePricePerShare: 100,
+		GrantDate:           monthStart(-12),
+		EndDate:             monthStart(60),
+		ExercisePolicy:      "sometimes",
+	}); err == nil {
+		t.Error("expected an error for an unknown exercise policy")
+	}
+	opt, err := svc.UpsertStartupShareOption(ctx, model.StartupShareOptionInput{
+		AccountID:           startup.ID,
+		SourceAccountID:     checking.ID,
+		Shares:              120,
+		StrikePricePerShare: 100,
+		GrantDate:           monthStart(-12),
+		EndDate:             monthStart(60),
+		CliffMonths:         12,
+		VestingMonths:       48,
+		ExercisePolicy:      finance.StartupOptionExerciseAtExit,
+	})
+	if err != nil {
+		t.Fatalf("create startup share option: %v", err)
+	}
+	if opt.VestingIntervalMonths != 1 {
+		t.Errorf("vesting interval = %d, want monthly by default", opt.VestingIntervalMonths)
+	}
+	if got := opt.VestedShares(monthStart(-1)); got != 0 {
+		t.Errorf("vested before the cliff = %v, want 0", got)
+	}
+	if _, err := svc.UpsertStartupExit(ctx, model.StartupExitInput{
+		AccountID:       startup.ID,
+		Date:            monthStart(6),
+		Kind:            finance.StartupExitAcquisition,
+		Multiple:        newFixedValue(1),
+		PayoutAccountID: checking.ID,
+	}); err != nil {
+		t.Fatalf("create startup exit: %v", err)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	// Only the vested options count, at a spread of 900 over the strike and
+	// after the 30% tax.
+	if got, want := h.balances[startup.ID][monthStart(3)], 15.0/48*120*900*0.7; !approxEqual(got, want, 1) {
+		t.Errorf("startup balance with 15 months vested = %f, want %f", got, want)
+	}
+	if got, want := h.balances[checking.ID][monthStart(7)], 18.0/48*120*900*0.7; !approxEqual(got, want, 1) {
+		t.Errorf("checking balance after exit = %f, want %f", got, want)
+	}
+}
+
+func TestRunPrediction_StartupExitPaysPreferencesFirst(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	now := time.Now().UTC()
+	monthStart := func(months int) date.Date {
+		return date.FromTime(time.Date(now.Year(), now.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC))
+	}
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	if _, err := svc.UpsertAccountSnapshot(ctx, checking.ID, model.AccountSnapshotInput{Date: monthStart(-1), Balance: newFixedValue(0)}); err != nil {
+		t.Fatalf("create checking snapshot: %v", err)
+	}
+	startup, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Startup"})
+	if err != nil {
+		t.Fatalf("create startup account: %v", err)
+	}
+	if _, err := svc.UpsertStartupShareAccount(ctx, model.StartupShareAccountInput{AccountID: startup.ID, TaxRate: 0.3, ValuationDiscountFactor: 1}); err != nil {
+		t.Fatalf("create startup share account: %v", err)
+	}
+	// 40M raised at a 10M pre-money valuation with a 1x non-participating
+	// preference, issuing 400 shares on top of the 100 common.
+	round, err := svc.UpsertInvestmentRound(ctx, model.InvestmentRoundInput{
+		AccountID:             startup.ID,
+		Date:                  monthStart(-12),
+		Valuation:             10_000_000,
+		PreMoneyShares:        100,
+		Investment:            40_000_000,
+		LiquidationPreference: 1,
+	})
+	if err != nil {
+		t.Fatalf("create investment round: %v", err)
+	}
+	if got := round.IssuedShares(); !approxEqual(got, 400, 1e-9) {
+		t.Errorf("issued shares = %v, want 400", got)
+	}
+	if _, err := svc.UpsertShareChange(ctx, model.ShareChangeInput{AccountID: startup.ID, Date: monthStart(-12), DeltaShares: 10}); err != nil {
+		t.Fatalf("create share change: %v", err)
+	}
+	if _, err := svc.UpsertStartupExit(ctx, model.StartupExitInput{
+		AccountID:       startup.ID,
+		Date:            monthStart(4),
+		Kind:            finance.StartupExitAcquisition,
+		Multiple:        newFixedValue(0.9),
+		PayoutAccountID: checking.ID,
+	}); err != nil {
+		t.Fatalf("create startup exit: %v", err)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	// The 45M exit pays the 40M preference first, leaving 5M for the 100
+	// common shares instead of 90 000 per share pro rata.
+	want := 10 * 50_000 * 0.7
+	if got := h.balances[checking.ID][monthStart(6)]; !approxEqual(got, want, 1) {
+		t.Errorf("checking balance after exit = %f, want %f", got, want)
+	}
+}
+
+func TestRunPrediction_StartupOptionTaxTreatment(t *testing.T) {
+	for _, tt := range []struct {
+		treatment finance.StartupGrantTax
+		want      float64
+	}{
+		// Qualified options only pay capital gains tax on the spread.
+		{treatment: finance.StartupGrantQualified, want: 10 * 9_000 * 0.7},
+		// Regular employee options are taxed as salary when exercised.
+		{treatment: finance.StartupGrantEmployee, want: 10 * 9_000 * 0.5},
+	} {
+		t.Run(string(tt.treatment), func(t *testing.T) {
+			svc := newTestService(t)
+			ctx := t.Context()
+
+			now := time.Now().UTC()
+			monthStart := func(months int) date.Date {
+				return date.FromTime(time.Date(now.Year(), now.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC))
+			}
+			checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+			if err != nil {
+				t.Fatalf("create checking account: %v", err)
+			}
+			if _, err := svc.UpsertAccountSnapshot(ctx, checking.ID, model.AccountSnapshotInput{Date: monthStart(-1), Balance: newFixedValue(0)}); err != nil {
+				t.Fatalf("create checking snapshot: %v", err)
+			}
+			startup, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Startup"})
+			if err != nil {
+				t.Fatalf("create startup account: %v", err)
+			}
+			if _, err := svc.UpsertStartupShareAccount(ctx, model.StartupShareAccountInput{AccountID: startup.ID, TaxRate: 0.3, ValuationDiscountFactor: 1, MarginalIncomeTaxRate: 0.5}); err != nil {
+				t.Fatalf("create startup share account: %v", err)
+			}
+			if _, err := svc.UpsertInvestmentRound(ctx, model.InvestmentRoundInput{
+				AccountID:      startup.ID,
+				Date:           monthStart(-12),
+				Valuation:      10_000_000,
+				PreMoneyShares: 1000,
+			}); err != nil {
+				t.Fatalf("create investment round: %v", err)
+			}
+			opt, err := svc.UpsertStartupShareOption(ctx, model.StartupShareOptionInput{
+				AccountID:           startup.ID,
+				SourceAccountID:     checking.ID,
+				Shares:              10,
+				StrikePricePerShare: 1_000,
+				GrantDate:           monthStart(-12),
+				EndDate:             monthStart(36),
+				ExercisePolicy:      finance.StartupOptionExerciseAtExit,
+				TaxTreatment:        tt.treatment,
+			})
+			if err != nil {
+				t.Fatalf("create option: %v", err)
+			}
+			if opt.TaxTreatment != tt.treatment {
+				t.Errorf("tax treatment = %q, want %q", opt.TaxTreatment, tt.treatment)
+			}
+			if _, err := svc.UpsertStartupExit(ctx, model.StartupExitInput{
+				AccountID:       startup.ID,
+				Date:            monthStart(4),
+				Kind:            finance.StartupExitAcquisition,
+				Multiple:        newFixedValue(1),
+				PayoutAccountID: checking.ID,
+			}); err != nil {
+				t.Fatalf("create startup exit: %v", err)
+			}
+
+			h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+			if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+				Duration:         date.Year,
+				Samples:          1,
+				Quantile:         0.8,
+				SnapshotInterval: "*-*-01",
+				GroupBy:          model.GroupByNone,
+			}); err != nil {
+				t.Fatalf("run prediction: %v", err)
+			}
+			if got := h.balances[checking.ID][monthStart(6)]; !approxEqual(got, tt.want, 1) {
+				t.Errorf("checking balance after exit = %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertStartupShareOption_DefaultsTaxTreatment(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	startup, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Startup"})
+	if err != nil {
+		t.Fatalf("create startup account: %v", err)
+	}
+	opt, err := svc.UpsertStartupShareOption(ctx, model.StartupShareOptionInput{AccountID: startup.ID, SourceAccountID: startup.ID, Shares: 1})
+	if err != nil {
+		t.Fatalf("create option: %v", err)
+	}
+	if opt.TaxTreatment != finance.StartupGrantQualified {
+		t.Errorf("tax treatment = %q, want %q", opt.TaxTreatment, finance.StartupGrantQualified)
+	}
+	if _, err := svc.UpsertStartupShareOption(ctx, model.StartupShareOptionInput{AccountID: startup.ID, SourceAccountID: startup.ID, Shares: 1, TaxTreatment: "bogus"}); err == nil {
+		t.Error("expected an error for an unknown tax treatment")
+	}
+}
+
+func TestEquityGrant_RSUVestsNetOfTax(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	holding, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Employer Stock"})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if _, err := svc.UpsertAccountGrowthModel(ctx, model.AccountGrowthModelInput{
+		AccountID:        holding.ID,
+		Type:             "fixed",
+		AnnualRate:       newFixedValue(0.1),
+		AnnualVolatility: newFixedValue(0),
+		StartDate:        mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("create growth model: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        80600,
+		Prisbasbelopp: 58800,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating ibb: %v", err)
+	}
+	g, err := svc.UpsertEquityGrant(ctx, model.EquityGrant{
+		Name:                  "RSU",
+		Kind:                  model.EquityGrantRSU,
+		HoldingAccountID:      holding.ID,
+		SharePrice:            100,
+		Shares:                400,
+		CliffMonths:           12,
+		VestingMonths:         24,
+		VestingIntervalMonths: 12,
+		MunicipalTaxRate:      0.32,
+		StartDate:             mustParseDate("2025-01-01"),
+		Enabled:               true,
+	})
+	if err != nil {
+		t.Fatalf("creating equity grant: %v", err)
+	}
+
+	all, err := svc.ListAllTransferTemplates(ctx)
+	if err != nil {
+		t.Fatalf("listing transfer templates: %v", err)
+	}
+	byID := make(map[string]model.TransferTemplate)
+	for _, tt := range all {
+		if tt.Source.Type == "equity-grant" {
+			byID[tt.ID] = tt
+		}
+	}
+	if len(byID) != 2 {
+		t.Fatalf("got templates %v, want one per yearly vest", slices.Collect(maps.Keys(byID)))
+	}
+
+	incomeTax := func(annual float64) float64 {
+		return swe.CalculateAnnualIncomeTax(annual, swe.IncomeTaxParams{
+			Prisbasbelopp:       58800,
+			MunicipalTaxRate:    0.32,
+			StateTaxThreshold:   swe.DefaultStateTaxThreshold,
+			StateTaxRate:        swe.DefaultStateTaxRate,
+			PublicServiceFeeMax: swe.DefaultPublicServiceFeeMax,
+		}).TotalTax
+	}
+	// Half the grant vests after each year, at the stock price grown 10% a
+	// year, with the tax covered by selling shares.
+	for i, tc := range []struct {
+		day   date.Date
+		value float64
+	}{
+		{day: mustParseDate("2026-01-01"), value: 200 * 110},
+		{day: mustParseDate("2027-01-01"), value: 200 * 121},
+	} {
+		vest, ok := byID[fmt.Sprintf("equity-grant-vest:%s:%d", g.ID, i)]
+		if !ok {
+			t.Fatalf("missing vest %d", i)
+		}
+		if vest.ToAccountID != holding.ID || vest.StartDate != tc.day || !vest.Recurrence.Matches(tc.day) {
+			t.Errorf("vest %d to %q on %v, want %q on %v", i, vest.ToAccountID, vest.StartDate, holding.ID, tc.day)
+		}
+		want := tc.value - incomeTax(tc.value)
+		if got := vest.AmountFixed.Mean(); !approxEqual(got, want, 1) {
+			t.Errorf("vest %d net = %v, want %v", i, got, want)
+		}
+	}
+
+	plan, err := svc.PlanEquityGrant(ctx, g)
+	if err != nil {
+		t.Fatalf("planning equity grant: %v", err)
+	}
+	if len(plan) != 2 || plan[0].Year != 2026 || plan[0].Shares != 200 || !approxEqual(plan[0].Tax, incomeTax(22000), 1) {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+}
+
+func TestEquityGrant_ESPPBuysAtDiscount(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	holding, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Employer Stock"})
+	if err != nil {
+		t.Fatalf("create holding account: %v", err)
+	}
+	if _, err := svc.UpsertSweYearlyParams(ctx, model.SweYearlyParams{
+		Amount:        80600,
+		Prisbasbelopp: 58800,
+		ValidFrom:     mustParseDate("2025-01-01"),
+	}); err != nil {
+		t.Fatalf("creating ibb: %v", err)
+	}
+	if _, err := svc.UpsertEquityGrant(ctx, model.EquityGrant{
+		Name:             "ESPP",
+		Kind:             model.EquityGrantESPP,
+		HoldingAccountID: holding.ID,
+		Discount:         0.15,
+	}); err == nil {
+		t.Error("expected an ESPP without a salary account to be rejected")
+	}
+	g, err := svc.UpsertEquityGrant(ctx, model.EquityGrant{
+		Name:             "ESPP",
+		Kind:             model.EquityGrantESPP,
+		HoldingAccountID: holding.ID,
+		SalaryAccountID:  checking.ID,
+		Contribution:     newFixedValue(17000),
+		Discount:         0.15,
+		MunicipalTaxRate: 0.32,
+		StartDate:        mustParseDate("2025-01-01"),
+		Enabled:          true,
+	})
+	if err != nil {
+		t.Fatalf("creating equity grant: %v", err)
+	}
+
+	all, err := svc.ListAllTransferTemplates(ctx)
+	if err != nil {
+		t.Fatalf("listing transfer templates: %v", err)
+	}
+	byID := make(map[string]model.TransferTemplate)
+	for _, tt := range all {
+		if tt.Source.Type == "equity-grant" {
+			byID[tt.ID] = tt
+		}
+	}
+	contribution := byID["equity-grant-contribution:"+g.ID]
+	if contribution.FromAccountID != checking.ID || contribution.ToAccountID != holding.ID || contribution.AmountFixed.Mean() != 17000 {
+		t.Errorf("contribution from %q to %q of %v", contribution.FromAccountID, contribution.ToAccountID, contribution.AmountFixed.Mean())
+	}
+	// 17 000 buys shares worth 20 000, a benefit of 3 000 a month.
+	discount := byID["equity-grant-discount:"+g.ID]
+	if discount.ToAccountID != holding.ID || !approxEqual(discount.AmountFixed.Mean(), 3000, 1e-6) {
+		t.Errorf("discount to %q of %v, want 3000", discount.ToAccountID, discount.AmountFixed.Mean())
+	}
+	tax, ok := byID[fmt.Sprintf("equity-grant-tax:%s:2025", g.ID)]
+	if !ok {
+		t.Fatalf("missing 2025 tax template, got %v", slices.Collect(maps.Keys(byID)))
+	}
+	annualTax := swe.CalculateAnnualIncomeTax(36000, swe.IncomeTaxParams{
+		Prisbasbelopp:       58800,
+		MunicipalTaxRate:    0.32,
+		StateTaxThreshold:   swe.DefaultStateTaxThreshold,
+		StateTaxRate:        swe.DefaultStateTaxRate,
+		PublicServiceFeeMax: swe.DefaultPublicServiceFeeMax,
+	}).TotalTax
+	if annualTax <= 0 {
+		t.Fatalf("expected tax on the yearly benefit, got %v", annualTax)
+	}
+	if !approxEqual(tax.AmountFixed.Mean(), annualTax/12, 1) || tax.FromAccountID != checking.ID {
+		t.Errorf("2025 tax from %q of %v, want %v", tax.FromAccountID, tax.AmountFixed.Mean(), annualTax/12)
+	}
+	last, ok := byID[fmt.Sprintf("equity-grant-tax:%s:2026", g.ID)]
+	if !ok || last.EndDate != nil {
+		t.Errorf("the last tax template should repeat, got %v", slices.Collect(maps.Keys(byID)))
+	}
+}
+
+func TestRunPrediction_ConvertsForeignCurrencyAccounts(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	lastYearStart := mustParseDate(fmt.Sprintf("%d-01-01", time.Now().Year()-1))
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	brokerage, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Brokerage", Currency: "USD"})
+	if err != nil {
+		t.Fatalf("create brokerage account: %v", err)
+	}
+	if brokerage.Currency != "USD" {
+		t.Fatalf("brokerage currency = %q, want USD", brokerage.Currency)
+	}
+	if _, err := svc.UpsertFXRateModel(ctx, model.FXRateModel{Currency: "USD", InitialRate: 10}); err != nil {
+		t.Fatalf("upsert fx rate model: %v", err)
+	}
+	for id, bal := range map[string]float64{checking.ID: 0, brokerage.ID: 1000} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: lastYearStart, Balance: newFixedValue(bal)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	tt, err := svc.UpsertTransferTemplate(ctx, model.TransferTemplate{
+		Name:          "Monthly USD deposit",
+		FromAccountID: checking.ID,
+		ToAccountID:   brokerage.ID,
+		AmountType:    "fixed",
+		AmountFixed:   newFixedValue(100),
+		Currency:      "USD",
+		Recurrence:    "*-*-25",
+		StartDate:     lastYearStart,
+		Enabled:       true,
+	})
+	if err != nil {
+		t.Fatalf("create transfer template: %v", err)
+	}
+	if tt.Currency != "USD" {
+		t.Fatalf("transfer currency = %q, want USD", tt.Currency)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	// The 1000 USD snapshot is worth 10000, and each 100 USD deposit costs
+	// 1000 of the default currency and adds 1000 to the converted balance.
+	lowest := 0.0
+	for day, bal := range h.balances[brokerage.ID] {
+		checkingBal := h.balances[checking.ID][day]
+		if got := bal + checkingBal; !approxEqual(got, 10_000, 1e-6) {
+			t.Errorf("total on %s = %f, want 10000", day, got)
+		}
+		if !approxEqual(math.Mod(checkingBal, 1000), 0, 1e-6) {
+			t.Errorf("checking on %s = %f, want whole deposits of 1000", day, checkingBal)
+		}
+		lowest = min(lowest, checkingBal)
+	}
+	if lowest > -9000 {
+		t.Errorf("lowest checking balance = %f, want the forecast year of deposits", lowest)
+	}
+}
+
+func TestRunPrediction_AmortizationValuesForeignPropertyInDefaultCurrency(t *testing.T) {
+	svc := newTestService(t)
+	ctx := t.Context()
+
+	today := date.Today()
+	checking, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Checking"})
+	if err != nil {
+		t.Fatalf("create checking account: %v", err)
+	}
+	house, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Summer house", Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("create house account: %v", err)
+	}
+	loan, err := svc.UpsertAccount(ctx, model.AccountInput{Name: "Mortgage"})
+	if err != nil {
+		t.Fatalf("create loan account: %v", err)
+	}
+	if _, err := svc.UpsertFXRateModel(ctx, model.FXRateModel{Currency: "EUR", InitialRate: 10}); err != nil {
+		t.Fatalf("upsert fx rate model: %v", err)
+	}
+	for id, bal := range map[string]float64{loan.ID: -600_000, house.ID: 100_000, checking.ID: 100_000} {
+		if _, err := svc.UpsertAccountSnapshot(ctx, id, model.AccountSnapshotInput{Date: today, Balance: newFixedValue(bal)}); err != nil {
+			t.Fatalf("create snapshot: %v", err)
+		}
+	}
+	req, err := svc.UpsertAmortizationRequirement(ctx, model.AmortizationRequirement{
+		AccountID:         loan.ID,
+		PropertyAccountID: house.ID,
+		FromAccountID:     checking.ID,
+		Recurrence:        "*-*-25",
+		ValuationDate:     today,
+	})
+	if err != nil {
+		t.Fatalf("upsert amortization requirement: %v", err)
+	}
+
+	// The house is worth 1 000 000 in the default currency, a loan-to-value
+	// of 60% that requires 1% yearly.
+	estimate, err := svc.EstimateAmortization(ctx, req)
+	if err != nil {
+		t.Fatalf("estimate amortization: %v", err)
+	}
+	if estimate.Rate != 0.01 {
+		t.Errorf("estimated rate = %f, want 0.01", estimate.Rate)
+	}
+
+	h := &snapshotsByDayHandler{balances: make(map[string]map[date.Date]float64)}
+	if err := svc.RunPrediction(ctx, h, model.PredictionParams{
+		Duration:         date.Year,
+		Samples:          1,
+		Quantile:         0.8,
+		SnapshotInterval: "*-*-01",
+		GroupBy:          model.GroupByNone,
+	}); err != nil {
+		t.Fatalf("run prediction: %v", err)
+	}
+
+	days := slices.Sorted(maps.Keys(h.balances[loan.ID]))
+	if len(days) < 3 {
+		t.Fatalf("expected monthly snapshots, got %d", len(days))
+	}
+	if got, want := h.balances[loan.ID][days[2]]-h.balances[loan.ID][days[1]], 600_000*0.01/12; !approxEqual(got, want, 1) {
+		t.Errorf("monthly amortization = %f, want %f", got, want)
+	}
+}
@@ -16,6 +16,7 @@ const (
 	settingForecastConfidence       = "forecast_confidence"
 	settingForecastSamples          = "forecast_samples"
 	settingForecastSnapshotInterval = "forecast_snapshot_interval"
+	settingChildBenefitAccount      = "child_benefit_account"
 )
 
 func (s *Service) GetDefaultCurrency(ctx context.Context) (string, error) {
@@ -122,3 +123,27 @@ func (s *Service) SetForecastSnapshotInterval(ctx context.Context, interval stri
 	s.invalidateForecast()
 	return nil
 }
+
+// GetChildBenefitAccount returns the account barnbidrag is paid into, or ""
+// when the household does not receive it.
+func (s *Service) GetChildBenefitAccount(ctx context.Context) (string, error) {
+	val, err := s.q.GetSetting(ctx, settingChildBenefitAccount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("getting child benefit account: %w", err)
+	}
+	return val, nil
+}
+
+func (s *Service) SetChildBenefitAccount(ctx context.Context, accountID string) error {
+	if err := s.q.UpsertSetting(ctx, pdb.UpsertSettingParams{
+		Key:   settingChildBenefitAccount,
+		Value: accountID,
+	}); err != nil {
+		return fmt.Errorf("setting child benefit account: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/goslu/sid"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+// SickLeave is a long-term sick leave where sjuklön and then sjukpenning
+// replace the salary for the Extent of full time on leave.
+type SickLeave struct {
+	ID                string
+	SalaryID          string
+	StartDate         date.Date
+	EndDate           date.Date
+	Extent            float64
+	EmployerTopUpRate float64
+	EmployerTopUpDays int64
+}
+
+func (sl SickLeave) params(prisbasbelopp float64) swe.SickLeaveParams {
+	return swe.SickLeaveParams{
+		Extent:            sl.Extent,
+		EmployerTopUpRate: sl.EmployerTopUpRate,
+		EmployerTopUpDays: int(sl.EmployerTopUpDays),
+		Prisbasbelopp:     prisbasbelopp,
+	}
+}
+
+// monthDays returns the day of sickness (1-based) the month of the leave d
+// falls in starts on, and the number of days in that month.
+func (sl SickLeave) monthDays(d date.Date) (firstDay, days int) {
+	month := monthsSince(sl.StartDate, d)
+	start := addMonths(sl.StartDate, month)
+	return int(start.Sub(sl.StartDate)) + 1, int(addMonths(sl.StartDate, month+1).Sub(start))
+}
+
+func (sl SickLeave) GetStartDateString() string {
+	if sl.ID == "" {
+		return ""
+	}
+	return sl.StartDate.String()
+}
+
+func (sl SickLeave) GetEndDateString() string {
+	if sl.ID == "" {
+		return ""
+	}
+	return sl.EndDate.String()
+}
+
+func (sl SickLeave) GetExtentString() string {
+	return strconv.FormatFloat(sl.Extent*100, 'f', -1, 64)
+}
+
+func (sl SickLeave) GetEmployerTopUpRateString() string {
+	if sl.EmployerTopUpRate == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(sl.EmployerTopUpRate*100, 'f', -1, 64)
+}
+
+func (sl SickLeave) GetEmployerTopUpDaysString() string {
+	if sl.EmployerTopUpDays == 0 {
+		return ""
+	}
+	return strconv.FormatInt(sl.EmployerTopUpDays, 10)
+}
+
+func sickLeaveFromDB(sl pdb.SickLeave) SickLeave {
+	return SickLeave{
+		ID:                sl.ID,
+		SalaryID:          sl.SalaryID,
+		StartDate:         date.Date(sl.StartDate),
+		EndDate:           date.Date(sl.EndDate),
+		Extent:            sl.Extent,
+		EmployerTopUpRate: sl.EmployerTopUpRate,
+		EmployerTopUpDays: sl.EmployerTopUpDays,
+	}
+}
+
+func (s *Service) UpsertSickLeave(ctx context.Context, inp SickLeave) (SickLeave, error) {
+	if inp.EndDate <= inp.StartDate {
+		return SickLeave{}, fmt.Errorf("sick leave must end after it starts")
+	}
+	if inp.Extent <= 0 || inp.Extent > 1 {
+		return SickLeave{}, fmt.Errorf("invalid sick leave extent: %g", inp.Extent)
+	}
+	if inp.ID == "" {
+		inp.ID = sid.MustNewString(32)
+	}
+	now := time.Now().Unix()
+	sl, err := s.q.UpsertSickLeave(ctx, pdb.UpsertSickLeaveParams{
+		ID:                inp.ID,
+		SalaryID:          inp.SalaryID,
+		StartDate:         int64(inp.StartDate),
+		EndDate:           int64(inp.EndDate),
+		Extent:            inp.Extent,
+		EmployerTopUpRate: inp.EmployerTopUpRate,
+		EmployerTopUpDays: inp.EmployerTopUpDays,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	})
+	if err != nil {
+		return SickLeave{}, fmt.Errorf("upserting sick leave: %w", err)
+	}
+	s.invalidateForecast()
+	return sickLeaveFromDB(sl), nil
+}
+
+func (s *Service) ListSickLeaves(ctx context.Context, salaryID string) ([]SickLeave, error) {
+	rows, err := s.q.ListSickLeaves(ctx, salaryID)
+	if err != nil {
+		return nil, fmt.Errorf("listing sick leaves: %w", err)
+	}
+	result := make([]SickLeave, len(rows))
+	for i, r := range rows {
+		result[i] = sickLeaveFromDB(r)
+	}
+	return result, nil
+}
+
+func (s *Service) DeleteSickLeave(ctx context.Context, id string) error {
+	if err := s.q.DeleteSickLeave(ctx, id); err != nil {
+		return fmt.Errorf("deleting sick leave: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+func activeSickLeaveAt(leaves []SickLeave, d date.Date) *SickLeave {
+	for i := range leaves {
+		if leaves[i].StartDate <= d && d < leaves[i].EndDate {
+			return &leaves[i]
+		}
+	}
+	return nil
+}
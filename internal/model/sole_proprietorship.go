@@ -0,0 +1,447 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/goslu/sid"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/ui"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+// fSkattRecurrence is when a sole proprietor pays the monthly F-skatt.
+const fSkattRecurrence = date.Cron("*-*-12")
+
+// SoleProprietorship is an enskild firma run by a member of the household.
+// Its monthly Profit is paid to ToAccountID, which also pays the F-skatt: the
+// egenavgifter and the income tax on the surplus after the allocations to a
+// periodiseringsfond and expansionsfond. The income tax is taxed on top of
+// the owner's salaries, with their tax setup unless a MunicipalTaxRate is
+// given.
+type SoleProprietorship struct {
+	ID                      string
+	Name                    string
+	OwnerID                 string
+	ToAccountID             string
+	Profit                  uncertain.Value
+	Recurrence              date.Cron
+	PeriodiseringsfondShare float64
+	Expansionsfond          float64
+	MunicipalTaxRate        float64
+	StartDate               date.Date
+	Enabled                 bool
+}
+
+// SoleProprietorshipYear is the expected result of a sole proprietorship for
+// a calendar year.
+type SoleProprietorshipYear struct {
+	Year   int
+	Result swe.SoleProprietorResult
+}
+
+func (sp SoleProprietorship) source() TransferTemplateSource {
+	return TransferTemplateSource{
+		Type:     "sole-proprietorship",
+		EntityID: sp.ID,
+		Label:    sp.Name,
+		EditURL:  "/sole-proprietorships/" + sp.ID + "/edit",
+	}
+}
+
+func (sp SoleProprietorship) GetProfitString() string {
+	if sp.ID == "" {
+		return ""
+	}
+	return sp.Profit.SimpleEncode()
+}
+
+func (sp SoleProprietorship) GetPeriodiseringsfondShareString() string {
+	return strconv.FormatFloat(sp.PeriodiseringsfondShare*100, 'f', -1, 64)
+}
+
+func (sp SoleProprietorship) GetExpansionsfondString() string {
+	return strconv.FormatFloat(sp.Expansionsfond, 'f', -1, 64)
+}
+
+func (sp SoleProprietorship) GetMunicipalTaxRateString() string {
+	if sp.MunicipalTaxRate == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(sp.MunicipalTaxRate*100, 'f', -1, 64)
+}
+
+func (sp SoleProprietorship) GetStartDateString() string {
+	if sp.ID == "" {
+		return ""
+	}
+	return sp.StartDate.String()
+}
+
+func soleProprietorshipFromDB(sp pdb.SoleProprietorship) (SoleProprietorship, error) {
+	profit, err := uncertain.Decode(sp.Profit)
+	if err != nil {
+		return SoleProprietorship{}, fmt.Errorf("decoding sole proprietorship profit: %w", err)
+	}
+	return SoleProprietorship{
+		ID:                      sp.ID,
+		Name:                    sp.Name,
+		OwnerID:                 ui.OrDefault(sp.OwnerID),
+		ToAccountID:             sp.ToAccountID,
+		Profit:                  profit,
+		Recurrence:              date.Cron(sp.Recurrence),
+		PeriodiseringsfondShare: sp.PeriodiseringsfondShare,
+		Expansionsfond:          sp.Expansionsfond,
+		MunicipalTaxRate:        sp.MunicipalTaxRate,
+		StartDate:               date.Date(sp.StartDate),
+		Enabled:                 sp.Enabled,
+	}, nil
+}
+
+func (s *Service) UpsertSoleProprietorship(ctx context.Context, inp SoleProprietorship) (SoleProprietorship, error) {
+	if inp.Name == "" {
+		return SoleProprietorship{}, fmt.Errorf("sole proprietorship name is required")
+	}
+	if inp.ToAccountID == "" {
+		return SoleProprietorship{}, fmt.Errorf("a sole proprietorship account is required")
+	}
+	if inp.PeriodiseringsfondShare < 0 || inp.PeriodiseringsfondShare > swe.PeriodiseringsfondMaxShare {
+		return SoleProprietorship{}, fmt.Errorf("periodiseringsfond share must be between 0 and %g%%", swe.PeriodiseringsfondMaxShare*100)
+	}
+	if inp.Expansionsfond < 0 {
+		return SoleProprietorship{}, fmt.Errorf("invalid expansionsfond allocation: %g", inp.Expansionsfond)
+	}
+	if inp.Recurrence == "" {
+		inp.Recurrence = "*-*-25"
+	}
+	if inp.ID == "" {
+		inp.ID = sid.MustNewString(32)
+	}
+	profit, err := inp.Profit.Encode()
+	if err != nil {
+		return SoleProprietorship{}, fmt.Errorf("encoding sole proprietorship profit: %w", err)
+	}
+	now := time.Now().Unix()
+	sp, err := s.q.UpsertSoleProprietorship(ctx, pdb.UpsertSoleProprietorshipParams{
+		ID:                      inp.ID,
+		Name:                    inp.Name,
+		OwnerID:                 ui.WithDefaultNull(inp.OwnerID),
+		ToAccountID:             inp.ToAccountID,
+		Profit:                  profit,
+		Recurrence:              string(inp.Recurrence),
+		PeriodiseringsfondShare: inp.PeriodiseringsfondShare,
+		Expansionsfond:          inp.Expansionsfond,
+		MunicipalTaxRate:        inp.MunicipalTaxRate,
+		StartDate:               int64(inp.StartDate),
+		Enabled:                 inp.Enabled,
+		CreatedAt:               now,
+		UpdatedAt:               now,
+	})
+	if err != nil {
+		return SoleProprietorship{}, fmt.Errorf("upserting sole proprietorship: %w", err)
+	}
+	s.invalidateForecast()
+	return soleProprietorshipFromDB(sp)
+}
+
+func (s *Service) GetSoleProprietorship(ctx context.Context, id string) (SoleProprietorship, error) {
+	sp, err := s.q.GetSoleProprietorship(ctx, id)
+	if err != nil {
+		return SoleProprietorship{}, fmt.Errorf("getting sole proprietorship: %w", err)
+	}
+	return soleProprietorshipFromDB(sp)
+}
+
+func (s *Service) ListSoleProprietorships(ctx context.Context) ([]SoleProprietorship, error) {
+	rows, err := s.q.ListSoleProprietorships(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing sole proprietorships: %w", err)
+	}
+	sps := make([]SoleProprietorship, len(rows))
+	for i, r := range rows {
+		if sps[i], err = soleProprietorshipFromDB(r); err != nil {
+			return nil, err
+		}
+	}
+	return sps, nil
+}
+
+func (s *Service) DeleteSoleProprietorship(ctx context.Context, id string) error {
+	if err := s.q.DeleteSoleProprietorship(ctx, id); err != nil {
+		return fmt.Errorf("deleting sole proprietorship: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+// soleProprietorshipTaxYear is a calendar year of a sole proprietorship:
+// when it is active, how many profit months and F-skatt payments fall in it,
+// the periodiseringsfond reversed and the income tax on top of the owner's
+// salaries.
+type soleProprietorshipTaxYear struct {
+	year      int
+	start     date.Date
+	end       *date.Date
+	months    int
+	payments  int
+	reversal  float64
+	incomeTax func(annual float64) float64
+}
+
+func (y soleProprietorshipTaxYear) input(sp SoleProprietorship, monthlyProfit float64) swe.SoleProprietorInput {
+	return swe.SoleProprietorInput{
+		Profit:                  monthlyProfit * float64(y.months),
+		PeriodiseringsfondShare: sp.PeriodiseringsfondShare,
+		Expansionsfond:          sp.Expansionsfond,
+		Reversal:                y.reversal,
+	}
+}
+
+// ownerSalaryTax is the yearly salary income of a person and their primary
+// salary, whose tax setup their business income is taxed with.
+type ownerSalaryTax struct {
+	income  map[int]float64
+	primary *Salary
+}
+
+// ownerSalaryTaxes returns the salary income and primary salary of each
+// person owning a gross salary.
+func (s *Service) ownerSalaryTaxes(ctx context.Context) (map[string]ownerSalaryTax, []SweYearlyParams, error) {
+	salaries, ibbs, err := s.listComputedSalaries(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	owners := make(map[string]ownerSalaryTax)
+	for _, group := range salaryTaxGroups(salaries) {
+		if group[0].OwnerID == "" {
+			continue
+		}
+		years, err := s.salaryTaxYears(ctx, group, ibbs)
+		if err != nil {
+			return nil, nil, err
+		}
+		o := ownerSalaryTax{income: make(map[int]float64, len(years))}
+		for year, y := range years {
+			o.income[year] = y.Income
+		}
+		if primary := primarySalary(group); !primary.SecondaryEmployer {
+			o.primary = &primary
+		}
+		owners[group[0].OwnerID] = o
+	}
+	return owners, ibbs, nil
+}
+
+// salaryIncomeIn returns the owner's salary income in year, or in the latest
+// year before it with income, as the salaries repeat after their last change.
+func (o ownerSalaryTax) salaryIncomeIn(year int) float64 {
+	last := 0
+	for y := range o.income {
+		if y <= year {
+			last = max(last, y)
+		}
+	}
+	return o.income[last]
+}
+
+// incomeTaxOnSalary returns the income tax of year on an annual income taxed
+// on top of the owner's salaries, with the tax setup of their primary salary
+// unless a municipalTaxRate is given.
+func (s *Service) incomeTaxOnSalary(ctx context.Context, owner ownerSalaryTax, ibbs []SweYearlyParams, year int, municipalTaxRate float64) (func(annual float64) float64, error) {
+	start := januaryFirst(year)
+	var monthlyTax func(float64) (float64, error)
+	switch {
+	case municipalTaxRate > 0:
+		monthlyTax = swe.NewFormulaTaxFunc(incomeTaxParamsAt(ibbs, start, municipalTaxRate))
+	case owner.primary != nil:
+		var err error
+		if monthlyTax, err = s.salaryTaxFunc(ctx, *owner.primary, start, ibbs); err != nil {
+			return nil, err
+		}
+	default:
+		monthlyTax = swe.NewFormulaTaxFunc(incomeTaxParamsAt(ibbs, start, swe.AverageMunicipalTaxRate))
+	}
+	yearlyTax := func(annual float64) float64 {
+		tax, err := monthlyTax(annual / 12)
+		if err != nil {
+			return 0
+		}
+		return 12 * tax
+	}
+	salary := owner.salaryIncomeIn(year)
+	base := yearlyTax(salary)
+	return func(annual float64) float64 {
+		return yearlyTax(salary+annual) - base
+	}, nil
+}
+
+// soleProprietorshipTaxYears splits a sole proprietorship into calendar years until the
+// periodiseringsfonder and the owner's salaries have reached a steady state,
+// after which the last year repeats.
+func (s *Service) soleProprietorshipTaxYears(ctx context.Context, sp SoleProprietorship, owner ownerSalaryTax, ibbs []SweYearlyParams) ([]soleProprietorshipTaxYear, error) {
+	lastChange := sp.StartDate.Year()
+	for year := range owner.income {
+		lastChange = max(lastChange, year)
+	}
+	for _, p := range ibbs {
+		lastChange = max(lastChange, p.ValidFrom.Year())
+	}
+	last := lastChange + 1
+	if sp.PeriodiseringsfondShare > 0 {
+		last = lastChange + swe.PeriodiseringsfondYears
+	}
+
+	allocations := make(map[int]float64)
+	var years []soleProprietorshipTaxYear
+	for year := sp.StartDate.Year(); year <= last; year++ {
+		y := soleProprietorshipTaxYear{
+			year:     year,
+			start:    max(januaryFirst(year), sp.StartDate),
+			reversal: allocations[year-swe.PeriodiseringsfondYears],
+		}
+		if year < last {
+			end := januaryFirst(year + 1)
+			y.end = &end
+		}
+		for d := y.start; d < januaryFirst(year+1); d = d.Add(date.Day) {
+			if sp.Recurrence.Matches(d) {
+				y.months++
+			}
+			if fSkattRecurrence.Matches(d) {
+				y.payments++
+			}
+		}
+
+		var err error
+		if y.incomeTax, err = s.incomeTaxOnSalary(ctx, owner, ibbs, year, sp.MunicipalTaxRate); err != nil {
+			return nil, err
+		}
+
+		allocations[year] = swe.CalculateSoleProprietor(y.input(sp, sp.Profit.Mean()), y.incomeTax).Periodiseringsfond
+		years = append(years, y)
+	}
+	return years, nil
+}
+
+func (s *Service) generateSoleProprietorshipTransferTemplates(ctx context.Context) ([]TransferTemplate, error) {
+	sps, err := s.ListSoleProprietorships(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(sps) == 0 {
+		return nil, nil
+	}
+	owners, ibbs, err := s.ownerSalaryTaxes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("computing owner salary income: %w", err)
+	}
+	var templates []TransferTemplate
+	for _, sp := range sps {
+		years, err := s.soleProprietorshipTaxYears(ctx, sp, owners[sp.OwnerID], ibbs)
+		if err != nil {
+			return nil, fmt.Errorf("computing taxes for %s: %w", sp.Name, err)
+		}
+		templates = append(templates, sp.transferTemplates(years)...)
+	}
+	return templates, nil
+}
+
+// transferTemplates pays the monthly profit to the owner's account and the
+// F-skatt of each year from it, spread evenly over the payments of the year.
+func (sp SoleProprietorship) transferTemplates(years []soleProprietorshipTaxYear) []TransferTemplate {
+	source := sp.source()
+	templates := []TransferTemplate{{
+		ID:          "sole-proprietorship-profit:" + sp.ID,
+		Name:        sp.Name,
+		ToAccountID: sp.ToAccountID,
+		AmountType:  "fixed",
+		AmountFixed: sp.Profit,
+		Recurrence:  sp.Recurrence,
+		StartDate:   sp.StartDate,
+		Enabled:     sp.Enabled,
+		Source:      source,
+	}}
+	profit := sp.Profit
+	for _, y := range years {
+		if y.payments == 0 || y.months == 0 {
+			continue
+		}
+		templates = append(templates, TransferTemplate{
+			ID:            fmt.Sprintf("sole-proprietorship-tax:%s:%d", sp.ID, y.year),
+			Name:          sp.Name + " (F-skatt)",
+			FromAccountID: sp.ToAccountID,
+			AmountType:    "fixed",
+			AmountFixed: uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+				r := swe.CalculateSoleProprietor(y.input(sp, profit.Sample(cfg)), y.incomeTax)
+				return r.Tax() / float64(y.payments)
+			}),
+			Recurrence: fSkattRecurrence,
+			StartDate:  y.start,
+			EndDate:    y.end,
+			Enabled:    sp.Enabled,
+			Source:     source,
+		})
+	}
+	return templates
+}
+
+// PlanSoleProprietorship returns the expected result of each year of a sole
+// proprietorship until it repeats.
+func (s *Service) PlanSoleProprietorship(ctx context.Context, sp SoleProprietorship) ([]SoleProprietorshipYear, error) {
+	owners, ibbs, err := s.ownerSalaryTaxes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("computing owner salary income: %w", err)
+	}
+	years, err := s.soleProprietorshipTaxYears(ctx, sp, owners[sp.OwnerID], ibbs)
+	if err != nil {
+		return nil, err
+	}
+	plan := make([]SoleProprietorshipYear, 0, len(years))
+	for _, y := range years {
+		plan = append(plan, SoleProprietorshipYear{
+			Year:   y.year,
+			Result: swe.CalculateSoleProprietor(y.input(sp, sp.Profit.Mean()), y.incomeTax),
+		})
+	}
+	return plan, nil
+}
+
+type SoleProprietorshipEditView struct {
+	SoleProprietorship SoleProprietorship
+	Accounts           []Account
+	Persons            []Person
+	Plan               []SoleProprietorshipYear
+}
+
+func (v SoleProprietorshipEditView) IsEdit() bool {
+	return v.SoleProprietorship.ID != ""
+}
+
+// GetSoleProprietorshipEditPageData returns the data for editing a sole
+// proprietorship, or for a new one when id is empty.
+func (s *Service) GetSoleProprietorshipEditPageData(ctx context.Context, id string) (*SoleProprietorshipEditView, error) {
+	v := &SoleProprietorshipEditView{SoleProprietorship: SoleProprietorship{
+		Recurrence: "*-*-25",
+		Enabled:    true,
+	}}
+	var err error
+	if id != "" {
+		if v.SoleProprietorship, err = s.GetSoleProprietorship(ctx, id); err != nil {
+			return nil, err
+		}
+		if v.Plan, err = s.PlanSoleProprietorship(ctx, v.SoleProprietorship); err != nil {
+			return nil, fmt.Errorf("planning sole proprietorship: %w", err)
+		}
+	}
+	if v.Accounts, err = s.ListAccounts(ctx); err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+	if v.Persons, err = s.ListPersons(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
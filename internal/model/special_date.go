@@ -1,8 +1,10 @@
 package model
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/SimonSchneider/goslu/date"
 	"github.com/SimonSchneider/goslu/sid"
@@ -10,18 +12,22 @@ import (
 	"github.com/SimonSchneider/pefigo/pkg/ui"
 )
 
+// SpecialDate marks a date in the charts. When PersonAge is set the date is
+// the day the person reaches that age, and Date is resolved from it.
 type SpecialDate struct {
-	ID    string
-	Name  string
-	Date  date.Date
-	Color string
+	ID        string
+	Name      string
+	Date      date.Date
+	Color     string
+	PersonAge *PersonAge
 }
 
 type SpecialDateInput struct {
-	ID    string
-	Name  string
-	Date  date.Date
-	Color string
+	ID        string
+	Name      string
+	Date      date.Date
+	Color     string
+	PersonAge *PersonAge
 }
 
 func specialDateFromDB(sd pdb.SpecialDate) SpecialDate {
@@ -30,19 +36,30 @@ func specialDateFromDB(sd pdb.SpecialDate) SpecialDate {
 		panic(fmt.Errorf("parsing date: %w", err))
 	}
 	return SpecialDate{
-		ID:    sd.ID,
-		Name:  sd.Name,
-		Date:  day,
-		Color: ui.OrDefault(sd.Color),
+		ID:        sd.ID,
+		Name:      sd.Name,
+		Date:      day,
+		Color:     ui.OrDefault(sd.Color),
+		PersonAge: personAgeFromDB(sd.PersonID, sd.PersonAge),
 	}
 }
 
+func (sd *SpecialDate) resolve(persons Persons) {
+	sd.Date = persons.resolve(sd.PersonAge, sd.Date)
+}
+
 func (s *Service) GetSpecialDate(ctx context.Context, id string) (SpecialDate, error) {
-	sd, err := s.q.GetSpecialDate(ctx, id)
+	row, err := s.q.GetSpecialDate(ctx, id)
 	if err != nil {
 		return SpecialDate{}, fmt.Errorf("failed to get special date: %w", err)
 	}
-	return specialDateFromDB(sd), nil
+	persons, err := s.listPersonsByID(ctx)
+	if err != nil {
+		return SpecialDate{}, err
+	}
+	sd := specialDateFromDB(row)
+	sd.resolve(persons)
+	return sd, nil
 }
 
 func (s *Service) UpsertSpecialDate(ctx context.Context, inp SpecialDateInput) (SpecialDate, error) {
@@ -50,17 +67,28 @@ func (s *Service) UpsertSpecialDate(ctx context.Context, inp SpecialDateInput) (
 	if id == "" {
 		id = sid.MustNewString(15)
 	}
-	sd, err := s.q.UpsertSpecialDate(ctx, pdb.UpsertSpecialDateParams{
-		ID:    id,
-		Name:  inp.Name,
-		Date:  inp.Date.String(),
-		Color: ui.WithDefaultNull(inp.Color),
+	persons, err := s.listPersonsByID(ctx)
+	if err != nil {
+		return SpecialDate{}, err
+	}
+	// The resolved date is stored as well, so it is kept if the person is removed.
+	day := persons.resolve(inp.PersonAge, inp.Date)
+	personID, personAge := personAgeToDB(inp.PersonAge)
+	row, err := s.q.UpsertSpecialDate(ctx, pdb.UpsertSpecialDateParams{
+		ID:        id,
+		Name:      inp.Name,
+		Date:      day.String(),
+		Color:     ui.WithDefaultNull(inp.Color),
+		PersonID:  personID,
+		PersonAge: personAge,
 	})
 	if err != nil {
 		return SpecialDate{}, fmt.Errorf("failed to upsert special date: %w", err)
 	}
 	s.invalidateForecast()
-	return specialDateFromDB(sd), nil
+	sd := specialDateFromDB(row)
+	sd.resolve(persons)
+	return sd, nil
 }
 
 func (s *Service) DeleteSpecialDate(ctx context.Context, id string) error {
@@ -77,9 +105,16 @@ func (s *Service) ListSpecialDates(ctx context.Context) ([]SpecialDate, error) {
 	if err != nil {
 		return nil, err
 	}
+	persons, err := s.listPersonsByID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	result := make([]SpecialDate, len(sds))
 	for i := range sds {
 		result[i] = specialDateFromDB(sds[i])
+		result[i].resolve(persons)
 	}
+	// Relative dates may have moved with a changed birth date.
+	slices.SortStableFunc(result, func(a, b SpecialDate) int { return cmp.Compare(a.Date, b.Date) })
 	return result, nil
 }
@@ -3,30 +3,44 @@ package model
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/SimonSchneider/goslu/date"
 	"github.com/SimonSchneider/goslu/sid"
 	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
 	"github.com/SimonSchneider/pefigo/pkg/uncertain"
 )
 
+// StartupShareAccount holds the capital gains TaxRate on sold shares, and the
+// MarginalIncomeTaxRate on the benefit of exercising employee options. The
+// benefit is salary, but it is taxed at this flat rate as an approximation of
+// the owner's marginal tax rather than through the tax tables.
 type StartupShareAccount struct {
 	AccountID               string
 	TaxRate                 float64
 	ValuationDiscountFactor float64
+	MarginalIncomeTaxRate   float64
 }
 
+// InvestmentRound is a funding round. A LiquidationPreference above zero
+// gives the investors that multiple of their investment back at an exit
+// before the common shares, in order of Seniority.
 type InvestmentRound struct {
-	ID             string
-	AccountID      string
-	Date           date.Date
-	Valuation      float64
-	PreMoneyShares float64
-	Investment     float64
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	ID                    string
+	AccountID             string
+	Date                  date.Date
+	Valuation             float64
+	PreMoneyShares        float64
+	Investment            float64
+	LiquidationPreference float64
+	Participating         bool
+	Seniority             int64
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
 }
 
 func (ir InvestmentRound) GetDateString() string {
@@ -57,6 +71,48 @@ func (ir InvestmentRound) GetInvestmentString() string {
 	return fmt.Sprintf("%.2f", ir.Investment)
 }
 
+func (ir InvestmentRound) GetLiquidationPreferenceString() string {
+	if ir.ID == "" {
+		return ""
+	}
+	return strconv.FormatFloat(ir.LiquidationPreference, 'f', -1, 64)
+}
+
+func (ir InvestmentRound) GetSeniorityString() string {
+	if ir.ID == "" {
+		return ""
+	}
+	return strconv.FormatInt(ir.Seniority, 10)
+}
+
+func (ir InvestmentRound) preference() finance.LiquidationPreference {
+	return finance.LiquidationPreference{
+		Shares:        ir.IssuedShares(),
+		Invested:      ir.Investment,
+		Multiple:      ir.LiquidationPreference,
+		Participating: ir.Participating,
+		Seniority:     int(ir.Seniority),
+	}
+}
+
+// liquidationPreferences returns the liquidation preferences of the rounds
+// up to d.
+func liquidationPreferences(rounds []InvestmentRound, d date.Date) []finance.LiquidationPreference {
+	var prefs []finance.LiquidationPreference
+	for _, round := range rounds {
+		if round.Date <= d && round.LiquidationPreference > 0 {
+			prefs = append(prefs, round.preference())
+		}
+	}
+	return prefs
+}
+
+// IssuedShares returns the shares issued to the investors of the round.
+func (ir InvestmentRound) IssuedShares() float64 {
+	_, postShares := PostMoneyValuationAndShares(ir.Valuation, ir.PreMoneyShares, ir.Investment)
+	return postShares - ir.PreMoneyShares
+}
+
 type ShareChange struct {
 	ID          string
 	AccountID   string
@@ -88,16 +144,61 @@ func (sc ShareChange) GetTotalPriceString() string {
 	return fmt.Sprintf("%.2f", sc.TotalPrice)
 }
 
+// StartupOptionExercisePolicies lists the selectable exercise policies.
+var StartupOptionExercisePolicies = []finance.StartupOptionExercisePolicy{
+	finance.StartupOptionExerciseInTheMoney,
+	finance.StartupOptionExerciseAtVesting,
+	finance.StartupOptionExerciseAtExit,
+}
+
+func StartupOptionExercisePolicyLabel(policy finance.StartupOptionExercisePolicy) string {
+	switch policy {
+	case finance.StartupOptionExerciseAtVesting:
+		return "At vesting"
+	case finance.StartupOptionExerciseAtExit:
+		return "At exit"
+	default:
+		return "At expiry, unless underwater"
+	}
+}
+
+// StartupGrantTaxes lists the selectable tax treatments of option grants.
+var StartupGrantTaxes = []finance.StartupGrantTax{
+	finance.StartupGrantQualified,
+	finance.StartupGrantEmployee,
+	finance.StartupGrantPurchase,
+}
+
+func StartupGrantTaxLabel(treatment finance.StartupGrantTax) string {
+	switch treatment {
+	case finance.StartupGrantEmployee:
+		return "Employee option"
+	case finance.StartupGrantPurchase:
+		return "Purchased"
+	default:
+		return "Qualified (KPO)"
+	}
+}
+
+// StartupShareOption is an option grant vesting from GrantDate, and expiring
+// at EndDate. A grant without vesting months is vested when granted. The
+// TaxTreatment decides whether exercising is taxed as salary.
 type StartupShareOption struct {
-	ID                  string
-	AccountID           string
-	SourceAccountID     string
-	Shares              float64
-	StrikePricePerShare float64
-	GrantDate           date.Date
-	EndDate             date.Date
-	CreatedAt           time.Time
-	UpdatedAt           time.Time
+	ID                    string
+	AccountID             string
+	SourceAccountID       string
+	Shares                float64
+	StrikePricePerShare   float64
+	GrantDate             date.Date
+	EndDate               date.Date
+	CliffMonths           int64
+	VestingMonths         int64
+	VestingIntervalMonths int64
+	AccelerateOnExit      bool
+	ExercisePolicy        finance.StartupOptionExercisePolicy
+	TaxTreatment          finance.StartupGrantTax
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
 }
 
 func (opt StartupShareOption) GetSharesString() string {
@@ -128,19 +229,58 @@ func (opt StartupShareOption) GetEndDateString() string {
 	return opt.EndDate.String()
 }
 
+func (opt StartupShareOption) GetCliffMonthsString() string {
+	if opt.ID == "" {
+		return ""
+	}
+	return strconv.FormatInt(opt.CliffMonths, 10)
+}
+
+func (opt StartupShareOption) GetVestingMonthsString() string {
+	if opt.ID == "" {
+		return ""
+	}
+	return strconv.FormatInt(opt.VestingMonths, 10)
+}
+
+// VestedShares returns the options vested on day, without acceleration.
+func (opt StartupShareOption) VestedShares(day date.Date) float64 {
+	return opt.Shares * opt.toFinance().VestedFraction(day)
+}
+
+func (opt StartupShareOption) toFinance() finance.StartupGrowthOption {
+	return finance.StartupGrowthOption{
+		StrikePricePerShare:   uncertain.NewFixed(opt.StrikePricePerShare),
+		NumShares:             uncertain.NewFixed(opt.Shares),
+		SourceAccountID:       opt.SourceAccountID,
+		VestingStart:          opt.GrantDate,
+		Expiry:                opt.EndDate,
+		CliffMonths:           int(opt.CliffMonths),
+		VestingMonths:         int(opt.VestingMonths),
+		VestingIntervalMonths: int(opt.VestingIntervalMonths),
+		AccelerateOnExit:      opt.AccelerateOnExit,
+		ExercisePolicy:        opt.ExercisePolicy,
+		TaxTreatment:          opt.TaxTreatment,
+	}
+}
+
 type StartupShareAccountInput struct {
 	AccountID               string
 	TaxRate                 float64
 	ValuationDiscountFactor float64
+	MarginalIncomeTaxRate   float64
 }
 
 type InvestmentRoundInput struct {
-	ID             string
-	AccountID      string
-	Date           date.Date
-	Valuation      float64
-	PreMoneyShares float64
-	Investment     float64
+	ID                    string
+	AccountID             string
+	Date                  date.Date
+	Valuation             float64
+	PreMoneyShares        float64
+	Investment            float64
+	LiquidationPreference float64
+	Participating         bool
+	Seniority             int64
 }
 
 type ShareChangeInput struct {
@@ -152,19 +292,26 @@ type ShareChangeInput struct {
 }
 
 type StartupShareOptionInput struct {
-	ID                  string
-	AccountID           string
-	SourceAccountID     string
-	Shares              float64
-	StrikePricePerShare float64
-	GrantDate           date.Date
-	EndDate             date.Date
+	ID                    string
+	AccountID             string
+	SourceAccountID       string
+	Shares                float64
+	StrikePricePerShare   float64
+	GrantDate             date.Date
+	EndDate               date.Date
+	CliffMonths           int64
+	VestingMonths         int64
+	VestingIntervalMonths int64
+	AccelerateOnExit      bool
+	ExercisePolicy        finance.StartupOptionExercisePolicy
+	TaxTreatment          finance.StartupGrantTax
 }
 
 type DerivedStartupShareSummary struct {
 	SharesOwned              float64
 	TotalShares              float64
 	AvgPurchasePricePerShare float64
+	VestedOptions            float64
 }
 
 func startupShareAccountFromDB(s pdb.StartupShareAccount) StartupShareAccount {
@@ -172,19 +319,23 @@ func startupShareAccountFromDB(s pdb.StartupShareAccount) StartupShareAccount {
 		AccountID:               s.AccountID,
 		TaxRate:                 s.TaxRate,
 		ValuationDiscountFactor: s.ValuationDiscountFactor,
+		MarginalIncomeTaxRate:   s.IncomeTaxRate,
 	}
 }
 
 func investmentRoundFromDB(i pdb.InvestmentRound) InvestmentRound {
 	return InvestmentRound{
-		ID:             i.ID,
-		AccountID:      i.AccountID,
-		Date:           date.Date(i.Date),
-		Valuation:      i.Valuation,
-		PreMoneyShares: i.PreMoneyShares,
-		Investment:     i.Investment,
-		CreatedAt:      time.UnixMilli(i.CreatedAt),
-		UpdatedAt:      time.UnixMilli(i.UpdatedAt),
+		ID:                    i.ID,
+		AccountID:             i.AccountID,
+		Date:                  date.Date(i.Date),
+		Valuation:             i.Valuation,
+		PreMoneyShares:        i.PreMoneyShares,
+		Investment:            i.Investment,
+		LiquidationPreference: i.LiquidationPreference,
+		Participating:         i.Participating,
+		Seniority:             i.Seniority,
+		CreatedAt:             time.UnixMilli(i.CreatedAt),
+		UpdatedAt:             time.UnixMilli(i.UpdatedAt),
 	}
 }
 
@@ -202,15 +353,21 @@ func shareChangeFromDB(s pdb.ShareChange) ShareChange {
 
 func startupShareOptionFromDB(o pdb.StartupShareOption) StartupShareOption {
 	return StartupShareOption{
-		ID:                  o.ID,
-		AccountID:           o.AccountID,
-		SourceAccountID:     o.SourceAccountID,
-		Shares:              o.Shares,
-		StrikePricePerShare: o.StrikePricePerShare,
-		GrantDate:           date.Date(o.GrantDate),
-		EndDate:             date.Date(o.EndDate),
-		CreatedAt:           time.UnixMilli(o.CreatedAt),
-		UpdatedAt:           time.UnixMilli(o.UpdatedAt),
+		ID:                    o.ID,
+		AccountID:             o.AccountID,
+		SourceAccountID:       o.SourceAccountID,
+		Shares:                o.Shares,
+		StrikePricePerShare:   o.StrikePricePerShare,
+		GrantDate:             date.Date(o.GrantDate),
+		EndDate:               date.Date(o.EndDate),
+		CliffMonths:           o.CliffMonths,
+		VestingMonths:         o.VestingMonths,
+		VestingIntervalMonths: o.VestingIntervalMonths,
+		AccelerateOnExit:      o.AccelerateOnExit,
+		ExercisePolicy:        finance.StartupOptionExercisePolicy(o.ExercisePolicy),
+		TaxTreatment:          finance.StartupGrantTax(o.TaxTreatment),
+		CreatedAt:             time.UnixMilli(o.CreatedAt),
+		UpdatedAt:             time.UnixMilli(o.UpdatedAt),
 	}
 }
 
@@ -219,6 +376,7 @@ func (s *Service) UpsertStartupShareAccount(ctx context.Context, inp StartupShar
 		AccountID:               inp.AccountID,
 		TaxRate:                 inp.TaxRate,
 		ValuationDiscountFactor: inp.ValuationDiscountFactor,
+		IncomeTaxRate:           inp.MarginalIncomeTaxRate,
 	})
 	if err != nil {
 		return StartupShareAccount{}, fmt.Errorf("failed to upsert startup share account: %w", err)
@@ -242,18 +400,24 @@ func (s *Service) DeleteStartupShareAccount(ctx context.Context, accountID strin
 }
 
 func (s *Service) UpsertInvestmentRound(ctx context.Context, inp InvestmentRoundInput) (InvestmentRound, error) {
+	if inp.LiquidationPreference < 0 {
+		return InvestmentRound{}, fmt.Errorf("invalid liquidation preference: %g", inp.LiquidationPreference)
+	}
 	if inp.ID == "" {
 		inp.ID = sid.MustNewString(32)
 	}
 	ir, err := s.q.UpsertInvestmentRound(ctx, pdb.UpsertInvestmentRoundParams{
-		ID:             inp.ID,
-		AccountID:      inp.AccountID,
-		Date:           int64(inp.Date),
-		Valuation:      inp.Valuation,
-		PreMoneyShares: inp.PreMoneyShares,
-		Investment:     inp.Investment,
-		CreatedAt:      time.Now().UnixMilli(),
-		UpdatedAt:      time.Now().UnixMilli(),
+		ID:                    inp.ID,
+		AccountID:             inp.AccountID,
+		Date:                  int64(inp.Date),
+		Valuation:             inp.Valuation,
+		PreMoneyShares:        inp.PreMoneyShares,
+		Investment:            inp.Investment,
+		LiquidationPreference: inp.LiquidationPreference,
+		Participating:         inp.Participating,
+		Seniority:             inp.Seniority,
+		CreatedAt:             time.Now().UnixMilli(),
+		UpdatedAt:             time.Now().UnixMilli(),
 	})
 	if err != nil {
 		return InvestmentRound{}, fmt.Errorf("failed to upsert investment round: %w", err)
@@ -384,19 +548,45 @@ func PostMoneyValuationAndShares(preMoneyValuation, preMoneyShares, investment f
 }
 
 func (s *Service) UpsertStartupShareOption(ctx context.Context, inp StartupShareOptionInput) (StartupShareOption, error) {
+	switch inp.ExercisePolicy {
+	case "":
+		inp.ExercisePolicy = finance.StartupOptionExerciseInTheMoney
+	case finance.StartupOptionExerciseInTheMoney, finance.StartupOptionExerciseAtVesting, finance.StartupOptionExerciseAtExit:
+	default:
+		return StartupShareOption{}, fmt.Errorf("invalid exercise policy: %q", inp.ExercisePolicy)
+	}
+	switch inp.TaxTreatment {
+	case "":
+		inp.TaxTreatment = finance.StartupGrantQualified
+	case finance.StartupGrantQualified, finance.StartupGrantEmployee, finance.StartupGrantPurchase:
+	default:
+		return StartupShareOption{}, fmt.Errorf("invalid tax treatment: %q", inp.TaxTreatment)
+	}
+	if inp.CliffMonths < 0 || inp.VestingMonths < 0 || inp.CliffMonths > inp.VestingMonths && inp.VestingMonths > 0 {
+		return StartupShareOption{}, fmt.Errorf("invalid vesting of %d months with a %d month cliff", inp.VestingMonths, inp.CliffMonths)
+	}
+	if inp.VestingIntervalMonths <= 0 {
+		inp.VestingIntervalMonths = 1
+	}
 	if inp.ID == "" {
 		inp.ID = sid.MustNewString(32)
 	}
 	opt, err := s.q.UpsertStartupShareOption(ctx, pdb.UpsertStartupShareOptionParams{
-		ID:                  inp.ID,
-		AccountID:           inp.AccountID,
-		SourceAccountID:     inp.SourceAccountID,
-		Shares:              inp.Shares,
-		StrikePricePerShare: inp.StrikePricePerShare,
-		GrantDate:           int64(inp.GrantDate),
-		EndDate:             int64(inp.EndDate),
-		CreatedAt:           time.Now().UnixMilli(),
-		UpdatedAt:           time.Now().UnixMilli(),
+		ID:                    inp.ID,
+		AccountID:             inp.AccountID,
+		SourceAccountID:       inp.SourceAccountID,
+		Shares:                inp.Shares,
+		StrikePricePerShare:   inp.StrikePricePerShare,
+		GrantDate:             int64(inp.GrantDate),
+		EndDate:               int64(inp.EndDate),
+		CliffMonths:           inp.CliffMonths,
+		VestingMonths:         inp.VestingMonths,
+		VestingIntervalMonths: inp.VestingIntervalMonths,
+		AccelerateOnExit:      inp.AccelerateOnExit,
+		ExercisePolicy:        string(inp.ExercisePolicy),
+		TaxTreatment:          string(inp.TaxTreatment),
+		CreatedAt:             time.Now().UnixMilli(),
+		UpdatedAt:             time.Now().UnixMilli(),
 	})
 	if err != nil {
 		return StartupShareOption{}, fmt.Errorf("failed to upsert startup share option: %w", err)
@@ -433,8 +623,266 @@ func (s *Service) DeleteStartupShareOption(ctx context.Context, id string) error
 	return nil
 }
 
-func CalculateStartupShareBalance(cfg *uncertain.Config, valuation uncertain.Value, sharesOwned float64, purchasePricePerShare float64, taxRate float64, totalShares float64, discountFactor float64) uncertain.Value {
+// ProjectedInvestmentRound is a future funding round that happens with
+// Probability at an uncertain pre-money valuation.
+type ProjectedInvestmentRound struct {
+	ID                string
+	AccountID         string
+	Date              date.Date
+	Probability       float64
+	PreMoneyValuation uncertain.Value
+	Investment        uncertain.Value
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (pr ProjectedInvestmentRound) GetDateString() string {
+	if pr.ID == "" {
+		return ""
+	}
+	return pr.Date.String()
+}
+
+func (pr ProjectedInvestmentRound) GetProbabilityString() string {
+	if pr.ID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%.0f", pr.Probability*100)
+}
+
+func (pr ProjectedInvestmentRound) GetPreMoneyValuationString() string {
+	if pr.ID == "" {
+		return ""
+	}
+	return pr.PreMoneyValuation.SimpleEncode()
+}
+
+func (pr ProjectedInvestmentRound) GetInvestmentString() string {
+	if pr.ID == "" {
+		return ""
+	}
+	return pr.Investment.SimpleEncode()
+}
+
+// StartupExit is the acquisition or IPO of the company at an uncertain
+// multiple of the last valuation, or its failure with FailureProbability. The
+// payout after tax goes to PayoutAccountID.
+type StartupExit struct {
+	AccountID          string
+	Date               date.Date
+	Kind               finance.StartupExitKind
+	Multiple           uncertain.Value
+	FailureProbability float64
+	PayoutAccountID    string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (e StartupExit) GetDateString() string {
+	if e.PayoutAccountID == "" {
+		return ""
+	}
+	return e.Date.String()
+}
+
+func (e StartupExit) GetMultipleString() string {
+	if e.PayoutAccountID == "" {
+		return ""
+	}
+	return e.Multiple.SimpleEncode()
+}
+
+func (e StartupExit) GetFailureProbabilityString() string {
+	if e.PayoutAccountID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%.0f", e.FailureProbability*100)
+}
+
+type ProjectedInvestmentRoundInput struct {
+	ID                string
+	AccountID         string
+	Date              date.Date
+	Probability       float64
+	PreMoneyValuation uncertain.Value
+	Investment        uncertain.Value
+}
+
+type StartupExitInput struct {
+	AccountID          string
+	Date               date.Date
+	Kind               finance.StartupExitKind
+	Multiple           uncertain.Value
+	FailureProbability float64
+	PayoutAccountID    string
+}
+
+func projectedInvestmentRoundFromDB(r pdb.ProjectedInvestmentRound) (ProjectedInvestmentRound, error) {
+	valuation, err := uncertain.Decode(r.PreMoneyValuation)
+	if err != nil {
+		return ProjectedInvestmentRound{}, fmt.Errorf("failed to decode pre-money valuation: %w", err)
+	}
+	investment, err := uncertain.Decode(r.Investment)
+	if err != nil {
+		return ProjectedInvestmentRound{}, fmt.Errorf("failed to decode investment: %w", err)
+	}
+	return ProjectedInvestmentRound{
+		ID:                r.ID,
+		AccountID:         r.AccountID,
+		Date:              date.Date(r.Date),
+		Probability:       r.Probability,
+		PreMoneyValuation: valuation,
+		Investment:        investment,
+		CreatedAt:         time.UnixMilli(r.CreatedAt),
+		UpdatedAt:         time.UnixMilli(r.UpdatedAt),
+	}, nil
+}
+
+func startupExitFromDB(e pdb.StartupExit) (StartupExit, error) {
+	multiple, err := uncertain.Decode(e.Multiple)
+	if err != nil {
+		return StartupExit{}, fmt.Errorf("failed to decode exit multiple: %w", err)
+	}
+	return StartupExit{
+		AccountID:          e.AccountID,
+		Date:               date.Date(e.Date),
+		Kind:               finance.StartupExitKind(e.Kind),
+		Multiple:           multiple,
+		FailureProbability: e.FailureProbability,
+		PayoutAccountID:    e.PayoutAccountID,
+		CreatedAt:          time.UnixMilli(e.CreatedAt),
+		UpdatedAt:          time.UnixMilli(e.UpdatedAt),
+	}, nil
+}
+
+func validProbability(p float64) bool {
+	return p >= 0 && p <= 1
+}
+
+func (s *Service) UpsertProjectedInvestmentRound(ctx context.Context, inp ProjectedInvestmentRoundInput) (ProjectedInvestmentRound, error) {
+	if !validProbability(inp.Probability) {
+		return ProjectedInvestmentRound{}, fmt.Errorf("invalid round probability: %g", inp.Probability)
+	}
+	if inp.PreMoneyValuation.Mean() <= 0 {
+		return ProjectedInvestmentRound{}, fmt.Errorf("a positive pre-money valuation is required")
+	}
+	if inp.ID == "" {
+		inp.ID = sid.MustNewString(32)
+	}
+	valuation, err := inp.PreMoneyValuation.Encode()
+	if err != nil {
+		return ProjectedInvestmentRound{}, fmt.Errorf("failed to encode pre-money valuation: %w", err)
+	}
+	investment, err := inp.Investment.Encode()
+	if err != nil {
+		return ProjectedInvestmentRound{}, fmt.Errorf("failed to encode investment: %w", err)
+	}
+	r, err := s.q.UpsertProjectedInvestmentRound(ctx, pdb.UpsertProjectedInvestmentRoundParams{
+		ID:                inp.ID,
+		AccountID:         inp.AccountID,
+		Date:              int64(inp.Date),
+		Probability:       inp.Probability,
+		PreMoneyValuation: valuation,
+		Investment:        investment,
+		CreatedAt:         time.Now().UnixMilli(),
+		UpdatedAt:         time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return ProjectedInvestmentRound{}, fmt.Errorf("failed to upsert projected investment round: %w", err)
+	}
+	s.invalidateForecast()
+	return projectedInvestmentRoundFromDB(r)
+}
+
+func (s *Service) GetProjectedInvestmentRound(ctx context.Context, id string) (ProjectedInvestmentRound, error) {
+	r, err := s.q.GetProjectedInvestmentRound(ctx, id)
+	if err != nil {
+		return ProjectedInvestmentRound{}, fmt.Errorf("failed to get projected investment round: %w", err)
+	}
+	return projectedInvestmentRoundFromDB(r)
+}
+
+func (s *Service) ListProjectedInvestmentRounds(ctx context.Context, accountID string) ([]ProjectedInvestmentRound, error) {
+	rounds, err := s.q.ListProjectedInvestmentRounds(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projected investment rounds: %w", err)
+	}
+	result := make([]ProjectedInvestmentRound, len(rounds))
+	for i, r := range rounds {
+		if result[i], err = projectedInvestmentRoundFromDB(r); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) DeleteProjectedInvestmentRound(ctx context.Context, id string) error {
+	if err := s.q.DeleteProjectedInvestmentRound(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete projected investment round: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+func (s *Service) UpsertStartupExit(ctx context.Context, inp StartupExitInput) (StartupExit, error) {
+	if inp.Kind != finance.StartupExitAcquisition && inp.Kind != finance.StartupExitIPO {
+		return StartupExit{}, fmt.Errorf("invalid exit kind: %q", inp.Kind)
+	}
+	if !validProbability(inp.FailureProbability) {
+		return StartupExit{}, fmt.Errorf("invalid failure probability: %g", inp.FailureProbability)
+	}
+	if inp.PayoutAccountID == "" || inp.PayoutAccountID == inp.AccountID {
+		return StartupExit{}, fmt.Errorf("a payout account other than the startup account is required")
+	}
+	multiple, err := inp.Multiple.Encode()
+	if err != nil {
+		return StartupExit{}, fmt.Errorf("failed to encode exit multiple: %w", err)
+	}
+	e, err := s.q.UpsertStartupExit(ctx, pdb.UpsertStartupExitParams{
+		AccountID:          inp.AccountID,
+		Date:               int64(inp.Date),
+		Kind:               string(inp.Kind),
+		Multiple:           multiple,
+		FailureProbability: inp.FailureProbability,
+		PayoutAccountID:    inp.PayoutAccountID,
+		CreatedAt:          time.Now().UnixMilli(),
+		UpdatedAt:          time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return StartupExit{}, fmt.Errorf("failed to upsert startup exit: %w", err)
+	}
+	s.invalidateForecast()
+	return startupExitFromDB(e)
+}
+
+// GetStartupExit returns the exit of a startup share account, or
+// sql.ErrNoRows when none is planned.
+func (s *Service) GetStartupExit(ctx context.Context, accountID string) (StartupExit, error) {
+	e, err := s.q.GetStartupExit(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return StartupExit{}, sql.ErrNoRows
+		}
+		return StartupExit{}, fmt.Errorf("failed to get startup exit: %w", err)
+	}
+	return startupExitFromDB(e)
+}
+
+func (s *Service) DeleteStartupExit(ctx context.Context, accountID string) error {
+	if err := s.q.DeleteStartupExit(ctx, accountID); err != nil {
+		return fmt.Errorf("failed to delete startup exit: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+func CalculateStartupShareBalance(cfg *uncertain.Config, valuation uncertain.Value, sharesOwned float64, purchasePricePerShare float64, taxRate float64, totalShares float64, discountFactor float64, preferences []finance.LiquidationPreference) uncertain.Value {
 	grossValue := valuation.Mul(cfg, uncertain.NewFixed(sharesOwned/totalShares*discountFactor))
+	if len(preferences) > 0 {
+		grossValue = uncertain.NewMapped(func(c *uncertain.Config) float64 {
+			return finance.CommonPricePerShare(valuation.Sample(c)*discountFactor, totalShares, preferences) * sharesOwned
+		}).Materialize(cfg)
+	}
 	purchasePrice := purchasePricePerShare * sharesOwned
 
 	if purchasePrice > grossValue.Mean() {
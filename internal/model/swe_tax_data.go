@@ -0,0 +1,28 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+// ImportTaxData loads an official Skatteverket tax rate or tax table export
+// into the cache, so salary calculations work without reaching the API.
+func (s *Service) ImportTaxData(ctx context.Context, r io.Reader) (swe.TaxDataset, int, error) {
+	dataset, n, err := s.sweClient.ImportTaxData(ctx, r)
+	if err != nil {
+		return "", 0, fmt.Errorf("importing tax data: %w", err)
+	}
+	s.invalidateForecast()
+	return dataset, n, nil
+}
+
+func (s *Service) ExportTaxData(ctx context.Context, dataset swe.TaxDataset) (swe.RowStoreResponse, error) {
+	resp, err := s.sweClient.ExportTaxData(ctx, dataset)
+	if err != nil {
+		return swe.RowStoreResponse{}, fmt.Errorf("exporting tax data: %w", err)
+	}
+	return resp, nil
+}
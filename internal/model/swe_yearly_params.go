@@ -8,6 +8,7 @@ import (
 	"github.com/SimonSchneider/goslu/date"
 	"github.com/SimonSchneider/goslu/sid"
 	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
 )
 
 type SweYearlyParams struct {
@@ -16,17 +17,29 @@ type SweYearlyParams struct {
 	Prisbasbelopp float64
 	SchablonRanta float64
 	IskFribelopp  float64
-	ValidFrom     date.Date
+	// StateTaxThreshold is the skiktgräns for statlig inkomstskatt.
+	StateTaxThreshold   float64
+	PublicServiceFeeMax float64
+	// CsnRanta is the interest rate on CSN annuity loans.
+	CsnRanta float64
+	// Statslaneranta is statslåneräntan at the end of November the year
+	// before.
+	Statslaneranta float64
+	ValidFrom      date.Date
 }
 
 func sweYearlyParamsFromDB(row pdb.SweYearlyParam) SweYearlyParams {
 	return SweYearlyParams{
-		ID:            row.ID,
-		Amount:        row.Amount,
-		Prisbasbelopp: row.Prisbasbelopp,
-		SchablonRanta: row.SchablonRanta,
-		IskFribelopp:  row.IskFribelopp,
-		ValidFrom:     date.Date(row.ValidFrom),
+		ID:                  row.ID,
+		Amount:              row.Amount,
+		Prisbasbelopp:       row.Prisbasbelopp,
+		SchablonRanta:       row.SchablonRanta,
+		IskFribelopp:        row.IskFribelopp,
+		StateTaxThreshold:   row.StateTaxThreshold,
+		PublicServiceFeeMax: row.PublicServiceFeeMax,
+		CsnRanta:            row.CsnRanta,
+		Statslaneranta:      row.Statslaneranta,
+		ValidFrom:           date.Date(row.ValidFrom),
 	}
 }
 
@@ -56,14 +69,18 @@ func (s *Service) UpsertSweYearlyParams(ctx context.Context, inp SweYearlyParams
 	}
 	now := time.Now().Unix()
 	row, err := s.q.UpsertSweYearlyParams(ctx, pdb.UpsertSweYearlyParamsParams{
-		ID:            inp.ID,
-		Amount:        inp.Amount,
-		Prisbasbelopp: inp.Prisbasbelopp,
-		SchablonRanta: inp.SchablonRanta,
-		IskFribelopp:  inp.IskFribelopp,
-		ValidFrom:     int64(inp.ValidFrom),
-		CreatedAt:     now,
-		UpdatedAt:     now,
+		ID:                  inp.ID,
+		Amount:              inp.Amount,
+		Prisbasbelopp:       inp.Prisbasbelopp,
+		SchablonRanta:       inp.SchablonRanta,
+		IskFribelopp:        inp.IskFribelopp,
+		StateTaxThreshold:   inp.StateTaxThreshold,
+		PublicServiceFeeMax: inp.PublicServiceFeeMax,
+		CsnRanta:            inp.CsnRanta,
+		Statslaneranta:      inp.Statslaneranta,
+		ValidFrom:           int64(inp.ValidFrom),
+		CreatedAt:           now,
+		UpdatedAt:           now,
 	})
 	if err != nil {
 		return SweYearlyParams{}, fmt.Errorf("upserting swe yearly params: %w", err)
@@ -111,6 +128,32 @@ func activeSchablonRantaAt(params []SweYearlyParams, d date.Date) float64 {
 	return active
 }
 
+// activeCsnRantaAt returns the CSN interest rate active at a given date.
+func activeCsnRantaAt(params []SweYearlyParams, d date.Date) float64 {
+	var active float64
+	for _, p := range params {
+		if p.ValidFrom <= d {
+			active = p.CsnRanta
+		}
+	}
+	return active
+}
+
+// activeStatslanerantaAt returns the statslåneränta active at a given date,
+// or the default when it has not been set.
+func activeStatslanerantaAt(params []SweYearlyParams, d date.Date) float64 {
+	var active float64
+	for _, p := range params {
+		if p.ValidFrom <= d {
+			active = p.Statslaneranta
+		}
+	}
+	if active == 0 {
+		return swe.DefaultStatslaneranta
+	}
+	return active
+}
+
 // activeISKFribeloppAt returns the ISK fribelopp active at a given date.
 func activeISKFribeloppAt(params []SweYearlyParams, d date.Date) float64 {
 	var active float64
@@ -121,3 +164,33 @@ func activeISKFribeloppAt(params []SweYearlyParams, d date.Date) float64 {
 	}
 	return active
 }
+
+// incomeTaxParamsAt returns the formula income tax parameters active at a
+// given date. Dates after the last yearly params reuse the latest values and
+// dates before the first use the earliest. Without any yearly params the
+// prisbasbelopp is swe.DefaultPrisbasbelopp, so the grundavdrag and
+// jobbskatteavdrag are never left out. Unset thresholds fall back to the swe
+// defaults.
+func incomeTaxParamsAt(params []SweYearlyParams, d date.Date, municipalTaxRate float64) swe.IncomeTaxParams {
+	p := swe.IncomeTaxParams{
+		Prisbasbelopp:       swe.DefaultPrisbasbelopp,
+		MunicipalTaxRate:    municipalTaxRate,
+		StateTaxThreshold:   swe.DefaultStateTaxThreshold,
+		StateTaxRate:        swe.DefaultStateTaxRate,
+		PublicServiceFeeMax: swe.DefaultPublicServiceFeeMax,
+	}
+	for i, yp := range params {
+		if yp.ValidFrom <= d || i == 0 {
+			if yp.Prisbasbelopp > 0 {
+				p.Prisbasbelopp = yp.Prisbasbelopp
+			}
+			if yp.StateTaxThreshold > 0 {
+				p.StateTaxThreshold = yp.StateTaxThreshold
+			}
+			if yp.PublicServiceFeeMax > 0 {
+				p.PublicServiceFeeMax = yp.PublicServiceFeeMax
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+// taxReturnsToFinance sets up the slutskattebesked of each person with a tax
+// account, keyed by person ID. The earned income of a year is taken from the
+// salaries the person owns, including benefits during leave and bonuses,
+// while capital income is recorded by the forecast.
+func (s *Service) taxReturnsToFinance(ctx context.Context, persons Persons) (map[string]*swe.TaxReturn, error) {
+	returns := make(map[string]*swe.TaxReturn)
+	for id, p := range persons {
+		if p.TaxAccountID != "" {
+			returns[id] = &swe.TaxReturn{Earned: make(map[int]swe.EarnedIncome)}
+		}
+	}
+	if len(returns) == 0 {
+		return nil, nil
+	}
+	salaries, ibbs, err := s.listComputedSalaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range salaryTaxGroups(salaries) {
+		r, ok := returns[group[0].OwnerID]
+		if !ok {
+			continue
+		}
+		years, err := s.finalSalaryTaxYears(ctx, group, ibbs)
+		if err != nil {
+			return nil, fmt.Errorf("computing salary tax for %s: %w", persons[group[0].OwnerID].Name, err)
+		}
+		for year, y := range years {
+			r.Earned[year] = swe.EarnedIncome{Income: y.Income, Withheld: y.Withheld, FinalTax: y.FinalTax}
+		}
+	}
+	return returns, nil
+}
@@ -35,12 +35,19 @@ type TransferTemplate struct {
 	AmountPercent float64
 	Priority      int64
 	Recurrence    date.Cron
+	// Currency of a fixed amount. Empty for the currency of the source
+	// account, or the destination account without a source.
+	Currency string
 
 	StartDate        date.Date
 	EndDate          *date.Date
 	Enabled          bool
 	BudgetCategoryID *string
 	GroupMembers     []TransferTemplate
+	// StartAtAge and EndAtAge make the start or end date relative to a
+	// person, in which case StartDate and EndDate are resolved from them.
+	StartAtAge *PersonAge
+	EndAtAge   *PersonAge
 
 	Source TransferTemplateSource
 }
@@ -86,13 +93,29 @@ func transferTemplateFromDB(t pdb.TransferTemplate) (TransferTemplate, error) {
 		AmountPercent:    t.AmountPercent,
 		Priority:         t.Priority,
 		Recurrence:       date.Cron(t.Recurrence),
+		Currency:         t.Currency,
 		StartDate:        date.Date(t.StartDate),
 		EndDate:          endDate,
 		Enabled:          t.Enabled,
 		BudgetCategoryID: t.BudgetCategoryID,
+		StartAtAge:       personAgeFromDB(t.StartPersonID, t.StartAge),
+		EndAtAge:         personAgeFromDB(t.EndPersonID, t.EndAge),
 	}, nil
 }
 
+func (t *TransferTemplate) resolve(persons Persons) {
+	t.StartDate = persons.resolve(t.StartAtAge, t.StartDate)
+	if t.EndAtAge != nil {
+		var fallback date.Date
+		if t.EndDate != nil {
+			fallback = *t.EndDate
+		}
+		if end := persons.resolve(t.EndAtAge, fallback); !end.IsZero() {
+			t.EndDate = &end
+		}
+	}
+}
+
 type TransferTemplateInput struct {
 	ID               string
 	Name             string
@@ -110,6 +133,12 @@ type TransferTemplateInput struct {
 }
 
 func (s *Service) UpsertTransferTemplate(ctx context.Context, inp TransferTemplate) (TransferTemplate, error) {
+	persons, err := s.listPersonsByID(ctx)
+	if err != nil {
+		return TransferTemplate{}, err
+	}
+	// The resolved dates are stored as well, so they are kept if the person is removed.
+	inp.resolve(persons)
 	var endDate *int64
 	if inp.EndDate != nil {
 		d := int64(*inp.EndDate)
@@ -122,6 +151,8 @@ func (s *Service) UpsertTransferTemplate(ctx context.Context, inp TransferTempla
 	if inp.ID == "" {
 		inp.ID = sid.MustNewString(32)
 	}
+	startPersonID, startAge := personAgeToDB(inp.StartAtAge)
+	endPersonID, endAge := personAgeToDB(inp.EndAtAge)
 	t, err := s.q.UpsertTransferTemplate(ctx, pdb.UpsertTransferTemplateParams{
 		ID:               inp.ID,
 		Name:             inp.Name,
@@ -136,6 +167,11 @@ func (s *Service) UpsertTransferTemplate(ctx context.Context, inp TransferTempla
 		EndDate:          endDate,
 		Enabled:          inp.Enabled,
 		BudgetCategoryID: inp.BudgetCategoryID,
+		StartPersonID:    startPersonID,
+		StartAge:         startAge,
+		EndPersonID:      endPersonID,
+		EndAge:           endAge,
+		Currency:         inp.Currency,
 		CreatedAt:        time.Now().Unix(),
 		UpdatedAt:        time.Now().Unix(),
 	})
@@ -143,7 +179,12 @@ func (s *Service) UpsertTransferTemplate(ctx context.Context, inp TransferTempla
 		return TransferTemplate{}, fmt.Errorf("failed to upsert template: %w", err)
 	}
 	s.invalidateForecast()
-	return transferTemplateFromDB(t)
+	template, err := transferTemplateFromDB(t)
+	if err != nil {
+		return TransferTemplate{}, err
+	}
+	template.resolve(persons)
+	return template, nil
 }
 
 func (s *Service) DuplicateTransferTemplate(ctx context.Context, id string) (TransferTemplate, error) {
@@ -160,12 +201,17 @@ func (s *Service) ListTransferTemplates(ctx context.Context) ([]TransferTemplate
 	if err != nil {
 		return nil, fmt.Errorf("failed to list templates: %w", err)
 	}
+	persons, err := s.listPersonsByID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var parsedTemplates []TransferTemplate
 	for _, t := range templates {
 		template, err := transferTemplateFromDB(t)
 		if err != nil {
 			return nil, fmt.Errorf("converting transfer template from DB: %w", err)
 		}
+		template.resolve(persons)
 		parsedTemplates = append(parsedTemplates, template)
 	}
 	return parsedTemplates, nil
@@ -184,8 +230,28 @@ func (s *Service) ListAllTransferTemplates(ctx context.Context) ([]TransferTempl
 	if err != nil {
 		return nil, fmt.Errorf("generating bill transfer templates: %w", err)
 	}
+	childBenefitTemplates, err := s.generateChildBenefitTransferTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("generating child benefit transfer templates: %w", err)
+	}
+	companyTemplates, err := s.generateCompanyTransferTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("generating company transfer templates: %w", err)
+	}
+	soleProprietorshipTemplates, err := s.generateSoleProprietorshipTransferTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("generating sole proprietorship transfer templates: %w", err)
+	}
+	equityGrantTemplates, err := s.generateEquityGrantTransferTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("generating equity grant transfer templates: %w", err)
+	}
 	all := append(templates, salaryTemplates...)
 	all = append(all, billTemplates...)
+	all = append(all, childBenefitTemplates...)
+	all = append(all, companyTemplates...)
+	all = append(all, soleProprietorshipTemplates...)
+	all = append(all, equityGrantTemplates...)
 	sortTransferTemplates(all)
 	return all, nil
 }
@@ -300,7 +366,16 @@ func (s *Service) GetTransferTemplate(ctx context.Context, id string) (TransferT
 	if err != nil {
 		return TransferTemplate{}, fmt.Errorf("failed to get transfer template: %w", err)
 	}
-	return transferTemplateFromDB(t)
+	persons, err := s.listPersonsByID(ctx)
+	if err != nil {
+		return TransferTemplate{}, err
+	}
+	template, err := transferTemplateFromDB(t)
+	if err != nil {
+		return TransferTemplate{}, err
+	}
+	template.resolve(persons)
+	return template, nil
 }
 
 func (s *Service) DeleteTransferTemplate(ctx context.Context, id string) error {
@@ -60,6 +60,11 @@ func (s *Service) ComputeTransfersView(ctx context.Context, day date.Date, amoun
 		if err != nil {
 			return nil, fmt.Errorf("listing accounts: %w", err)
 		}
+		rateModels, err := s.ListInterestRateModels(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rates := interestRateProcesses(rateModels)
 		view.Accounts = make(map[string]Account)
 		for _, a := range accounts {
 			if a.StartupShareAccount != nil {
@@ -83,7 +88,7 @@ func (s *Service) ComputeTransfersView(ctx context.Context, day date.Date, amoun
 				Snapshots: []finance2.BalanceSnapshot{a.LastSnapshot.ToFinance()},
 			}
 			if a.GrowthModel != nil {
-				entity.GrowthModel = GrowthModels([]GrowthModel{*a.GrowthModel}).ToFinance()
+				entity.GrowthModel = GrowthModels([]GrowthModel{*a.GrowthModel}).ToFinance(rates)
 			}
 			entities = append(entities, entity)
 		}
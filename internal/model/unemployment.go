@@ -0,0 +1,119 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/goslu/sid"
+	"github.com/SimonSchneider/pefigo/internal/pdb"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+// Unemployment is a period without work where a-kassa, and optionally union
+// income insurance, replaces the salary.
+type Unemployment struct {
+	ID           string
+	SalaryID     string
+	StartDate    date.Date
+	EndDate      date.Date
+	AKassaMember bool
+	// IncomeInsuranceCeiling is the highest monthly salary the income
+	// insurance covers, or 0 without income insurance.
+	IncomeInsuranceCeiling float64
+	IncomeInsuranceDays    int64
+}
+
+func (u Unemployment) incomeInsurance() swe.IncomeInsurance {
+	return swe.IncomeInsurance{Ceiling: u.IncomeInsuranceCeiling, Days: int(u.IncomeInsuranceDays)}
+}
+
+func (u Unemployment) GetStartDateString() string {
+	if u.ID == "" {
+		return ""
+	}
+	return u.StartDate.String()
+}
+
+func (u Unemployment) GetEndDateString() string {
+	if u.ID == "" {
+		return ""
+	}
+	return u.EndDate.String()
+}
+
+func (u Unemployment) GetIncomeInsuranceDaysString() string {
+	if u.IncomeInsuranceDays == 0 {
+		return ""
+	}
+	return strconv.FormatInt(u.IncomeInsuranceDays, 10)
+}
+
+func unemploymentFromDB(u pdb.Unemployment) Unemployment {
+	return Unemployment{
+		ID:                     u.ID,
+		SalaryID:               u.SalaryID,
+		StartDate:              date.Date(u.StartDate),
+		EndDate:                date.Date(u.EndDate),
+		AKassaMember:           u.AkassaMember,
+		IncomeInsuranceCeiling: u.IncomeInsuranceCeiling,
+		IncomeInsuranceDays:    u.IncomeInsuranceDays,
+	}
+}
+
+func (s *Service) UpsertUnemployment(ctx context.Context, inp Unemployment) (Unemployment, error) {
+	if inp.EndDate <= inp.StartDate {
+		return Unemployment{}, fmt.Errorf("unemployment must end after it starts")
+	}
+	if inp.ID == "" {
+		inp.ID = sid.MustNewString(32)
+	}
+	now := time.Now().Unix()
+	u, err := s.q.UpsertUnemployment(ctx, pdb.UpsertUnemploymentParams{
+		ID:                     inp.ID,
+		SalaryID:               inp.SalaryID,
+		StartDate:              int64(inp.StartDate),
+		EndDate:                int64(inp.EndDate),
+		AkassaMember:           inp.AKassaMember,
+		IncomeInsuranceCeiling: inp.IncomeInsuranceCeiling,
+		IncomeInsuranceDays:    inp.IncomeInsuranceDays,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+	})
+	if err != nil {
+		return Unemployment{}, fmt.Errorf("upserting unemployment: %w", err)
+	}
+	s.invalidateForecast()
+	return unemploymentFromDB(u), nil
+}
+
+func (s *Service) ListUnemployments(ctx context.Context, salaryID string) ([]Unemployment, error) {
+	rows, err := s.q.ListUnemployments(ctx, salaryID)
+	if err != nil {
+		return nil, fmt.Errorf("listing unemployments: %w", err)
+	}
+	result := make([]Unemployment, len(rows))
+	for i, r := range rows {
+		result[i] = unemploymentFromDB(r)
+	}
+	return result, nil
+}
+
+func (s *Service) DeleteUnemployment(ctx context.Context, id string) error {
+	if err := s.q.DeleteUnemployment(ctx, id); err != nil {
+		return fmt.Errorf("deleting unemployment: %w", err)
+	}
+	s.invalidateForecast()
+	return nil
+}
+
+func activeUnemploymentAt(periods []Unemployment, d date.Date) *Unemployment {
+	for i := range periods {
+		if periods[i].StartDate <= d && d < periods[i].EndDate {
+			return &periods[i]
+		}
+	}
+	return nil
+}
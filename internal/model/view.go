@@ -10,6 +10,8 @@ import (
 
 	"github.com/SimonSchneider/goslu/date"
 	"github.com/SimonSchneider/pefigo/pkg/currency"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
 	"github.com/SimonSchneider/pefigo/pkg/ui"
 )
 
@@ -17,6 +19,7 @@ type TransferTemplateEditView struct {
 	TransferTemplate TransferTemplate
 	Accounts         []Account
 	Categories       []TransferTemplateCategory
+	Persons          []Person
 }
 
 func (c TransferTemplateEditView) IsEdit() bool {
@@ -220,13 +223,26 @@ type AccountEditView2 struct {
 	Account                    Account
 	Accounts                   []Account
 	GrowthModels               []GrowthModel
+	InterestRateModels         []InterestRateModel
 	AccountTypes               AccountTypesWithFilter
 	Categories                 []TransferTemplateCategory
 	StartupShareAccount        *StartupShareAccount
 	InvestmentRounds           []InvestmentRound
 	ShareChanges               []ShareChange
 	Options                    []StartupShareOption
+	ProjectedRounds            []ProjectedInvestmentRound
+	StartupExit                *StartupExit
 	DerivedStartupShareSummary *DerivedStartupShareSummary
+	PensionPayout              *PensionPayout
+	InterestDeduction          *InterestDeduction
+	Amortization               *AmortizationRequirement
+	AmortizationEstimate       *AmortizationEstimate
+	CSNLoan                    *CSNLoan
+	CSNLoanEstimate            *CSNLoanEstimate
+	Salaries                   []Salary
+	Persons                    []Person
+	Owners                     []AccountOwner
+	LatestBalance              float64
 }
 
 func (v *AccountEditView2) GetStartupShareSharesOwned() string {
@@ -236,6 +252,13 @@ func (v *AccountEditView2) GetStartupShareSharesOwned() string {
 	return ui.FormatWithThousands(v.DerivedStartupShareSummary.SharesOwned)
 }
 
+func (v *AccountEditView2) GetStartupShareVestedOptions() string {
+	if v.DerivedStartupShareSummary == nil {
+		return "0"
+	}
+	return ui.FormatWithThousands(v.DerivedStartupShareSummary.VestedOptions)
+}
+
 func (v *AccountEditView2) GetStartupShareTotalShares() string {
 	if v.DerivedStartupShareSummary == nil {
 		return "0"
@@ -257,6 +280,13 @@ func (v *AccountEditView2) GetStartupShareTaxRate() string {
 	return fmt.Sprintf("%.2f", v.StartupShareAccount.TaxRate*100)
 }
 
+func (v *AccountEditView2) GetStartupShareMarginalIncomeTaxRate() string {
+	if v.StartupShareAccount == nil {
+		return "50"
+	}
+	return fmt.Sprintf("%.2f", v.StartupShareAccount.MarginalIncomeTaxRate*100)
+}
+
 func (v *AccountEditView2) GetStartupShareDiscountFactor() string {
 	if v.StartupShareAccount == nil {
 		return "50"
@@ -275,6 +305,65 @@ func (v *AccountEditView2) GetStartupShareFieldsStyle() string {
 	return "display: none;"
 }
 
+// GetPensionPayoutForm returns the pension payout to render in the payout
+// form, defaulting to a lifetime payout when none is configured.
+func (v *AccountEditView2) GetPensionPayoutForm() PensionPayout {
+	if v.PensionPayout == nil {
+		return PensionPayout{AccountID: v.Account.ID}
+	}
+	return *v.PensionPayout
+}
+
+// GetStartupExitForm returns the exit to render in the exit form, defaulting
+// to an acquisition at the last valuation.
+func (v *AccountEditView2) GetStartupExitForm() StartupExit {
+	if v.StartupExit == nil {
+		return StartupExit{AccountID: v.Account.ID, Kind: finance.StartupExitAcquisition}
+	}
+	return *v.StartupExit
+}
+
+// GetInterestDeductionForm returns the interest deduction to render in the
+// ränteavdrag form, defaulting to the usual April settlement.
+func (v *AccountEditView2) GetInterestDeductionForm() InterestDeduction {
+	if v.InterestDeduction == nil {
+		return InterestDeduction{AccountID: v.Account.ID, SettlementDate: swe.DefaultInterestSettlementDate, Borrowers: 1}
+	}
+	return *v.InterestDeduction
+}
+
+// GetAmortizationForm returns the amortization requirement to render in the
+// amorteringskrav form, defaulting to monthly payments and the usual
+// revaluation interval.
+func (v *AccountEditView2) GetAmortizationForm() AmortizationRequirement {
+	if v.Amortization == nil {
+		return AmortizationRequirement{AccountID: v.Account.ID, Recurrence: DefaultAmortizationRecurrence, RevaluationYears: swe.DefaultRevaluationYears}
+	}
+	return *v.Amortization
+}
+
+// GetCSNLoanForm returns the CSN loan to render in the CSN form, defaulting
+// to monthly payments.
+func (v *AccountEditView2) GetCSNLoanForm() CSNLoan {
+	if v.CSNLoan == nil {
+		return CSNLoan{AccountID: v.Account.ID, Recurrence: DefaultCSNRecurrence}
+	}
+	return *v.CSNLoan
+}
+
+// GetPensionPayoutEstimate returns the monthly gross payment the payout would
+// yield if it started with today's balance.
+func (v *AccountEditView2) GetPensionPayoutEstimate() string {
+	if v.PensionPayout == nil {
+		return "-"
+	}
+	months := v.PensionPayout.PayoutMonths()
+	if months <= 0 {
+		return "-"
+	}
+	return ui.FormatWithThousands(v.LatestBalance / float64(months))
+}
+
 func (v *AccountEditView2) IsEdit() bool {
 	return v.Account.ID != ""
 }
@@ -324,6 +413,12 @@ type SettingsPageView struct {
 	ForecastConfidence       float64
 	ForecastSamples          int64
 	ForecastSnapshotInterval string
+	InterestRateModels       []InterestRateModel
+	FXRateModels             []FXRateModel
+	Persons                  []Person
+	Accounts                 []Account
+	ChildBenefitAccountID    string
+	ChildBenefit             float64
 }
 
 func (s *Service) GetSettingsPageData(ctx context.Context) (*SettingsPageView, error) {
@@ -359,6 +454,26 @@ func (s *Service) GetSettingsPageData(ctx context.Context) (*SettingsPageView, e
 	if err != nil {
 		return nil, fmt.Errorf("getting forecast snapshot interval: %w", err)
 	}
+	rateModels, err := s.ListInterestRateModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fxModels, err := s.ListFXRateModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.ListAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+	childBenefitAccountID, err := s.GetChildBenefitAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
 	return &SettingsPageView{
 		AccountTypes:             accountTypes,
 		Categories:               categories,
@@ -369,6 +484,12 @@ func (s *Service) GetSettingsPageData(ctx context.Context) (*SettingsPageView, e
 		ForecastConfidence:       forecastConfidence,
 		ForecastSamples:          forecastSamples,
 		ForecastSnapshotInterval: forecastSnapshotInterval,
+		InterestRateModels:       rateModels,
+		FXRateModels:             fxModels,
+		Persons:                  persons,
+		Accounts:                 accounts,
+		ChildBenefitAccountID:    childBenefitAccountID,
+		ChildBenefit:             ChildBenefitOn(persons, date.Today()),
 	}, nil
 }
 
@@ -440,10 +561,15 @@ func (s *Service) GetAccountNewPageData(ctx context.Context, excludedTypeIDs []s
 	if err != nil {
 		return nil, fmt.Errorf("listing categories: %w", err)
 	}
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
 	return &AccountEditView2{
 		Accounts:     accs,
 		AccountTypes: accountTypes,
 		Categories:   categories,
+		Persons:      persons,
 	}, nil
 }
 
@@ -456,6 +582,10 @@ func (s *Service) GetAccountEditPageData(ctx context.Context, accountID string,
 	if err != nil {
 		return nil, fmt.Errorf("getting account: %w", err)
 	}
+	rateModels, err := s.ListInterestRateModels(ctx)
+	if err != nil {
+		return nil, err
+	}
 	growthModels, err := s.ListAccountGrowthModels(ctx, acc.ID)
 	if err != nil {
 		return nil, fmt.Errorf("listing growth models: %w", err)
@@ -469,6 +599,8 @@ func (s *Service) GetAccountEditPageData(ctx context.Context, accountID string,
 	var investmentRounds []InvestmentRound
 	var shareChanges []ShareChange
 	var options []StartupShareOption
+	var projectedRounds []ProjectedInvestmentRound
+	var startupExit *StartupExit
 	var derivedSummary *DerivedStartupShareSummary
 	ssa, err := s.GetStartupShareAccount(ctx, acc.ID)
 	if err == nil {
@@ -485,6 +617,15 @@ func (s *Service) GetAccountEditPageData(ctx context.Context, accountID string,
 		if err != nil {
 			return nil, fmt.Errorf("listing startup share options: %w", err)
 		}
+		projectedRounds, err = s.ListProjectedInvestmentRounds(ctx, acc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("listing projected investment rounds: %w", err)
+		}
+		if exit, err := s.GetStartupExit(ctx, acc.ID); err == nil {
+			startupExit = &exit
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("getting startup exit: %w", err)
+		}
 		today := date.Today()
 		var postShares float64
 		round, roundErr := s.GetLatestInvestmentRound(ctx, acc.ID, today)
@@ -501,10 +642,84 @@ func (s *Service) GetAccountEditPageData(ctx context.Context, accountID string,
 			TotalShares:              postShares,
 			AvgPurchasePricePerShare: avgPrice,
 		}
+		for _, opt := range options {
+			if today <= opt.EndDate {
+				derivedSummary.VestedOptions += opt.VestedShares(today)
+			}
+		}
 	} else if !errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("getting startup share account: %w", err)
 	}
 
+	var pensionPayout *PensionPayout
+	pp, err := s.GetPensionPayout(ctx, acc.ID)
+	if err == nil {
+		pensionPayout = &pp
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting pension payout: %w", err)
+	}
+	var interestDeduction *InterestDeduction
+	id, err := s.GetInterestDeduction(ctx, acc.ID)
+	if err == nil {
+		interestDeduction = &id
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting interest deduction: %w", err)
+	}
+	var amortization *AmortizationRequirement
+	var amortizationEstimate *AmortizationEstimate
+	ar, err := s.GetAmortizationRequirement(ctx, acc.ID)
+	if err == nil {
+		amortization = &ar
+		est, err := s.EstimateAmortization(ctx, ar)
+		if err != nil {
+			return nil, fmt.Errorf("estimating amortization: %w", err)
+		}
+		amortizationEstimate = &est
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting amortization requirement: %w", err)
+	}
+	snaps, err := s.ListAccountSnapshots(ctx, acc.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+	var latestBalance float64
+	var latestDate date.Date
+	for _, snap := range snaps {
+		if snap.Date >= latestDate {
+			latestDate = snap.Date
+			latestBalance = snap.Balance.Mean()
+		}
+	}
+	var csnLoan *CSNLoan
+	var csnLoanEstimate *CSNLoanEstimate
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cl, err := s.GetCSNLoan(ctx, acc.ID)
+	if err == nil {
+		csnLoan = &cl
+		estimated := cl
+		if owner, ok := KeyBy(persons, func(p Person) string { return p.ID })[acc.OwnerID]; ok && estimated.BirthDate.IsZero() {
+			estimated.BirthDate = owner.BirthDate
+		}
+		est, err := s.EstimateCSNLoan(ctx, estimated, max(0, -latestBalance))
+		if err != nil {
+			return nil, fmt.Errorf("estimating csn loan: %w", err)
+		}
+		csnLoanEstimate = &est
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting csn loan: %w", err)
+	}
+	salaries, err := s.ListSalaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing salaries: %w", err)
+	}
+	owners, err := s.ListAccountOwners(ctx, acc.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	categories, err := s.ListCategories(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("listing categories: %w", err)
@@ -513,13 +728,26 @@ func (s *Service) GetAccountEditPageData(ctx context.Context, accountID string,
 		Account:                    acc,
 		Accounts:                   accs,
 		GrowthModels:               growthModels,
+		InterestRateModels:         rateModels,
 		AccountTypes:               accountTypes,
 		Categories:                 categories,
 		StartupShareAccount:        startupShareAccount,
 		InvestmentRounds:           investmentRounds,
 		ShareChanges:               shareChanges,
 		Options:                    options,
+		ProjectedRounds:            projectedRounds,
+		StartupExit:                startupExit,
 		DerivedStartupShareSummary: derivedSummary,
+		PensionPayout:              pensionPayout,
+		InterestDeduction:          interestDeduction,
+		Amortization:               amortization,
+		AmortizationEstimate:       amortizationEstimate,
+		CSNLoan:                    csnLoan,
+		CSNLoanEstimate:            csnLoanEstimate,
+		Salaries:                   salaries,
+		Persons:                    persons,
+		Owners:                     owners,
+		LatestBalance:              latestBalance,
 	}, nil
 }
 
@@ -548,9 +776,14 @@ func (s *Service) GetTransferTemplateNewPageData(ctx context.Context) (*Transfer
 	if err != nil {
 		return nil, fmt.Errorf("listing categories: %w", err)
 	}
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
 	return &TransferTemplateEditView{
 		Accounts:   accs,
 		Categories: categories,
+		Persons:    persons,
 	}, nil
 }
 
@@ -567,10 +800,15 @@ func (s *Service) GetTransferTemplateEditPageData(ctx context.Context, id string
 	if err != nil {
 		return nil, fmt.Errorf("listing categories: %w", err)
 	}
+	persons, err := s.ListPersons(ctx)
+	if err != nil {
+		return nil, err
+	}
 	return &TransferTemplateEditView{
 		Accounts:         accs,
 		TransferTemplate: t,
 		Categories:       categories,
+		Persons:          persons,
 	}, nil
 }
 
@@ -20,11 +20,13 @@ INSERT INTO account (
     type_id,
     budget_category_id,
     is_isk,
+    owner_id,
+    currency,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk, currency
 `
 
 type CreateAccountParams struct {
@@ -36,6 +38,8 @@ type CreateAccountParams struct {
 	TypeID                *string
 	BudgetCategoryID      *string
 	IsIsk                 int64
+	OwnerID               *string
+	Currency              string
 	CreatedAt             int64
 	UpdatedAt             int64
 }
@@ -50,6 +54,8 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		arg.TypeID,
 		arg.BudgetCategoryID,
 		arg.IsIsk,
+		arg.OwnerID,
+		arg.Currency,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -66,6 +72,7 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.TypeID,
 		&i.BudgetCategoryID,
 		&i.IsIsk,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -73,7 +80,7 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 const deleteAccount = `-- name: DeleteAccount :one
 DELETE FROM account
 WHERE id = ?
-RETURNING id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk
+RETURNING id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk, currency
 `
 
 func (q *Queries) DeleteAccount(ctx context.Context, id string) (Account, error) {
@@ -91,6 +98,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id string) (Account, error)
 		&i.TypeID,
 		&i.BudgetCategoryID,
 		&i.IsIsk,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -125,6 +133,16 @@ func (q *Queries) DeleteInvestmentRound(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteProjectedInvestmentRound = `-- name: DeleteProjectedInvestmentRound :exec
+DELETE FROM projected_investment_round
+WHERE id = ?
+`
+
+func (q *Queries) DeleteProjectedInvestmentRound(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteProjectedInvestmentRound, id)
+	return err
+}
+
 const deleteShareChange = `-- name: DeleteShareChange :exec
 DELETE FROM share_change
 WHERE id = ?
@@ -161,6 +179,16 @@ func (q *Queries) DeleteSpecialDate(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteStartupExit = `-- name: DeleteStartupExit :exec
+DELETE FROM startup_exit
+WHERE account_id = ?
+`
+
+func (q *Queries) DeleteStartupExit(ctx context.Context, accountID string) error {
+	_, err := q.db.ExecContext(ctx, deleteStartupExit, accountID)
+	return err
+}
+
 const deleteStartupShareAccount = `-- name: DeleteStartupShareAccount :exec
 DELETE FROM startup_share_account
 WHERE account_id = ?
@@ -202,7 +230,7 @@ func (q *Queries) DeleteTransferTemplateCategory(ctx context.Context, id string)
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk
+SELECT id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk, currency
 FROM account
 WHERE id = ?
 `
@@ -222,6 +250,7 @@ func (q *Queries) GetAccount(ctx context.Context, id string) (Account, error) {
 		&i.TypeID,
 		&i.BudgetCategoryID,
 		&i.IsIsk,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -240,7 +269,7 @@ func (q *Queries) GetAccountType(ctx context.Context, id string) (AccountType, e
 }
 
 const getBudgetAccounts = `-- name: GetBudgetAccounts :many
-SELECT id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk
+SELECT id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk, currency
 FROM account
 WHERE budget_category_id IS NOT NULL
 ORDER BY name,
@@ -268,6 +297,7 @@ func (q *Queries) GetBudgetAccounts(ctx context.Context) ([]Account, error) {
 			&i.TypeID,
 			&i.BudgetCategoryID,
 			&i.IsIsk,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -283,7 +313,7 @@ func (q *Queries) GetBudgetAccounts(ctx context.Context) ([]Account, error) {
 }
 
 const getGrowthModel = `-- name: GetGrowthModel :one
-SELECT id, account_id, model_type, annual_growth_rate, annual_volatility, start_date, end_date, created_at, updated_at
+SELECT id, account_id, model_type, annual_growth_rate, annual_volatility, start_date, end_date, created_at, updated_at, rate_model_id, binding_years
 FROM growth_model
 WHERE id = ?
 `
@@ -301,12 +331,14 @@ func (q *Queries) GetGrowthModel(ctx context.Context, id string) (GrowthModel, e
 		&i.EndDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RateModelID,
+		&i.BindingYears,
 	)
 	return i, err
 }
 
 const getGrowthModelsByAccount = `-- name: GetGrowthModelsByAccount :many
-SELECT id, account_id, model_type, annual_growth_rate, annual_volatility, start_date, end_date, created_at, updated_at
+SELECT id, account_id, model_type, annual_growth_rate, annual_volatility, start_date, end_date, created_at, updated_at, rate_model_id, binding_years
 FROM growth_model
 WHERE account_id = ?
 `
@@ -330,6 +362,8 @@ func (q *Queries) GetGrowthModelsByAccount(ctx context.Context, accountID string
 			&i.EndDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RateModelID,
+			&i.BindingYears,
 		); err != nil {
 			return nil, err
 		}
@@ -345,7 +379,7 @@ func (q *Queries) GetGrowthModelsByAccount(ctx context.Context, accountID string
 }
 
 const getInvestmentRound = `-- name: GetInvestmentRound :one
-SELECT id, account_id, date, valuation, created_at, updated_at, pre_money_shares, investment
+SELECT id, account_id, date, valuation, created_at, updated_at, pre_money_shares, investment, liquidation_preference, participating, seniority
 FROM investment_round
 WHERE id = ?
 `
@@ -362,12 +396,15 @@ func (q *Queries) GetInvestmentRound(ctx context.Context, id string) (Investment
 		&i.UpdatedAt,
 		&i.PreMoneyShares,
 		&i.Investment,
+		&i.LiquidationPreference,
+		&i.Participating,
+		&i.Seniority,
 	)
 	return i, err
 }
 
 const getLatestInvestmentRound = `-- name: GetLatestInvestmentRound :one
-SELECT id, account_id, date, valuation, created_at, updated_at, pre_money_shares, investment
+SELECT id, account_id, date, valuation, created_at, updated_at, pre_money_shares, investment, liquidation_preference, participating, seniority
 FROM investment_round
 WHERE account_id = ?
   AND date <= ?
@@ -392,6 +429,31 @@ func (q *Queries) GetLatestInvestmentRound(ctx context.Context, arg GetLatestInv
 		&i.UpdatedAt,
 		&i.PreMoneyShares,
 		&i.Investment,
+		&i.LiquidationPreference,
+		&i.Participating,
+		&i.Seniority,
+	)
+	return i, err
+}
+
+const getProjectedInvestmentRound = `-- name: GetProjectedInvestmentRound :one
+SELECT id, account_id, date, probability, pre_money_valuation, investment, created_at, updated_at
+FROM projected_investment_round
+WHERE id = ?
+`
+
+func (q *Queries) GetProjectedInvestmentRound(ctx context.Context, id string) (ProjectedInvestmentRound, error) {
+	row := q.db.QueryRowContext(ctx, getProjectedInvestmentRound, id)
+	var i ProjectedInvestmentRound
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Date,
+		&i.Probability,
+		&i.PreMoneyValuation,
+		&i.Investment,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -507,7 +569,7 @@ func (q *Queries) GetSnapshotsByAccounts(ctx context.Context, ids []string) ([]A
 }
 
 const getSpecialDate = `-- name: GetSpecialDate :one
-SELECT id, name, date, color
+SELECT id, name, date, color, person_id, person_age
 FROM special_date
 WHERE id = ?
 `
@@ -520,12 +582,14 @@ func (q *Queries) GetSpecialDate(ctx context.Context, id string) (SpecialDate, e
 		&i.Name,
 		&i.Date,
 		&i.Color,
+		&i.PersonID,
+		&i.PersonAge,
 	)
 	return i, err
 }
 
 const getSpecialDates = `-- name: GetSpecialDates :many
-SELECT id, name, date, color
+SELECT id, name, date, color, person_id, person_age
 FROM special_date
 ORDER BY date,
   name,
@@ -546,6 +610,8 @@ func (q *Queries) GetSpecialDates(ctx context.Context) ([]SpecialDate, error) {
 			&i.Name,
 			&i.Date,
 			&i.Color,
+			&i.PersonID,
+			&i.PersonAge,
 		); err != nil {
 			return nil, err
 		}
@@ -560,8 +626,30 @@ func (q *Queries) GetSpecialDates(ctx context.Context) ([]SpecialDate, error) {
 	return items, nil
 }
 
+const getStartupExit = `-- name: GetStartupExit :one
+SELECT account_id, date, kind, multiple, failure_probability, payout_account_id, created_at, updated_at
+FROM startup_exit
+WHERE account_id = ?
+`
+
+func (q *Queries) GetStartupExit(ctx context.Context, accountID string) (StartupExit, error) {
+	row := q.db.QueryRowContext(ctx, getStartupExit, accountID)
+	var i StartupExit
+	err := row.Scan(
+		&i.AccountID,
+		&i.Date,
+		&i.Kind,
+		&i.Multiple,
+		&i.FailureProbability,
+		&i.PayoutAccountID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getStartupShareAccount = `-- name: GetStartupShareAccount :one
-SELECT account_id, tax_rate, valuation_discount_factor
+SELECT account_id, tax_rate, valuation_discount_factor, income_tax_rate
 FROM startup_share_account
 WHERE account_id = ?
 `
@@ -569,12 +657,17 @@ WHERE account_id = ?
 func (q *Queries) GetStartupShareAccount(ctx context.Context, accountID string) (StartupShareAccount, error) {
 	row := q.db.QueryRowContext(ctx, getStartupShareAccount, accountID)
 	var i StartupShareAccount
-	err := row.Scan(&i.AccountID, &i.TaxRate, &i.ValuationDiscountFactor)
+	err := row.Scan(
+		&i.AccountID,
+		&i.TaxRate,
+		&i.ValuationDiscountFactor,
+		&i.IncomeTaxRate,
+	)
 	return i, err
 }
 
 const getStartupShareOption = `-- name: GetStartupShareOption :one
-SELECT id, account_id, source_account_id, shares, strike_price_per_share, grant_date, end_date, created_at, updated_at
+SELECT id, account_id, source_account_id, shares, strike_price_per_share, grant_date, end_date, created_at, updated_at, cliff_months, vesting_months, vesting_interval_months, accelerate_on_exit, exercise_policy, tax_treatment
 FROM startup_share_option
 WHERE id = ?
 `
@@ -592,12 +685,18 @@ func (q *Queries) GetStartupShareOption(ctx context.Context, id string) (Startup
 		&i.EndDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CliffMonths,
+		&i.VestingMonths,
+		&i.VestingIntervalMonths,
+		&i.AccelerateOnExit,
+		&i.ExercisePolicy,
+		&i.TaxTreatment,
 	)
 	return i, err
 }
 
 const getTransferTemplate = `-- name: GetTransferTemplate :one
-SELECT id, name, from_account_id, to_account_id, amount_type, amount_fixed, amount_percent, priority, recurrence, start_date, end_date, enabled, created_at, updated_at, budget_category_id
+SELECT id, name, from_account_id, to_account_id, amount_type, amount_fixed, amount_percent, priority, recurrence, start_date, end_date, enabled, created_at, updated_at, budget_category_id, start_person_id, start_age, end_person_id, end_age, currency
 FROM transfer_template
 WHERE id = ?
 `
@@ -621,6 +720,11 @@ func (q *Queries) GetTransferTemplate(ctx context.Context, id string) (TransferT
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.BudgetCategoryID,
+		&i.StartPersonID,
+		&i.StartAge,
+		&i.EndPersonID,
+		&i.EndAge,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -645,7 +749,7 @@ func (q *Queries) GetTransferTemplateCategory(ctx context.Context, id string) (T
 }
 
 const getTransferTemplates = `-- name: GetTransferTemplates :many
-SELECT id, name, from_account_id, to_account_id, amount_type, amount_fixed, amount_percent, priority, recurrence, start_date, end_date, enabled, created_at, updated_at, budget_category_id
+SELECT id, name, from_account_id, to_account_id, amount_type, amount_fixed, amount_percent, priority, recurrence, start_date, end_date, enabled, created_at, updated_at, budget_category_id, start_person_id, start_age, end_person_id, end_age, currency
 FROM transfer_template
 ORDER BY recurrence,
   priority,
@@ -680,6 +784,11 @@ func (q *Queries) GetTransferTemplates(ctx context.Context) ([]TransferTemplate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.BudgetCategoryID,
+			&i.StartPersonID,
+			&i.StartAge,
+			&i.EndPersonID,
+			&i.EndAge,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -725,7 +834,7 @@ func (q *Queries) ListAccountTypes(ctx context.Context) ([]AccountType, error) {
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk
+SELECT id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk, currency
 FROM account
 ORDER BY name,
   id
@@ -752,6 +861,7 @@ func (q *Queries) ListAccounts(ctx context.Context) ([]Account, error) {
 			&i.TypeID,
 			&i.BudgetCategoryID,
 			&i.IsIsk,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -767,7 +877,7 @@ func (q *Queries) ListAccounts(ctx context.Context) ([]Account, error) {
 }
 
 const listActiveGrowthModels = `-- name: ListActiveGrowthModels :many
-SELECT id, account_id, model_type, annual_growth_rate, annual_volatility, start_date, end_date, created_at, updated_at
+SELECT id, account_id, model_type, annual_growth_rate, annual_volatility, start_date, end_date, created_at, updated_at, rate_model_id, binding_years
 FROM growth_model
 WHERE (end_date IS NULL OR end_date > ?1)
   AND start_date <= ?1
@@ -792,6 +902,8 @@ func (q *Queries) ListActiveGrowthModels(ctx context.Context, param1 *int64) ([]
 			&i.EndDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RateModelID,
+			&i.BindingYears,
 		); err != nil {
 			return nil, err
 		}
@@ -807,7 +919,7 @@ func (q *Queries) ListActiveGrowthModels(ctx context.Context, param1 *int64) ([]
 }
 
 const listInvestmentRounds = `-- name: ListInvestmentRounds :many
-SELECT id, account_id, date, valuation, created_at, updated_at, pre_money_shares, investment
+SELECT id, account_id, date, valuation, created_at, updated_at, pre_money_shares, investment, liquidation_preference, participating, seniority
 FROM investment_round
 WHERE account_id = ?
 ORDER BY date DESC,
@@ -832,6 +944,9 @@ func (q *Queries) ListInvestmentRounds(ctx context.Context, accountID string) ([
 			&i.UpdatedAt,
 			&i.PreMoneyShares,
 			&i.Investment,
+			&i.LiquidationPreference,
+			&i.Participating,
+			&i.Seniority,
 		); err != nil {
 			return nil, err
 		}
@@ -881,6 +996,46 @@ func (q *Queries) ListLatestSnapshotPerAccount(ctx context.Context) ([]AccountSn
 	return items, nil
 }
 
+const listProjectedInvestmentRounds = `-- name: ListProjectedInvestmentRounds :many
+SELECT id, account_id, date, probability, pre_money_valuation, investment, created_at, updated_at
+FROM projected_investment_round
+WHERE account_id = ?
+ORDER BY date,
+  id
+`
+
+func (q *Queries) ListProjectedInvestmentRounds(ctx context.Context, accountID string) ([]ProjectedInvestmentRound, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectedInvestmentRounds, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectedInvestmentRound
+	for rows.Next() {
+		var i ProjectedInvestmentRound
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Date,
+			&i.Probability,
+			&i.PreMoneyValuation,
+			&i.Investment,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listShareChanges = `-- name: ListShareChanges :many
 SELECT id, account_id, date, delta_shares, total_price, created_at, updated_at
 FROM share_change
@@ -924,7 +1079,8 @@ const listSnapshotHistoryWithType = `-- name: ListSnapshotHistoryWithType :many
 SELECT s.account_id,
   s.date,
   s.balance,
-  COALESCE(a.type_id, '') AS type_id
+  COALESCE(a.type_id, '') AS type_id,
+  a.currency
 FROM account_snapshot s
   JOIN account a ON a.id = s.account_id
 ORDER BY s.date,
@@ -937,6 +1093,7 @@ type ListSnapshotHistoryWithTypeRow struct {
 	Date      int64
 	Balance   string
 	TypeID    string
+	Currency  string
 }
 
 // Returns all snapshots with account type_id for grouping (full history).
@@ -954,6 +1111,7 @@ func (q *Queries) ListSnapshotHistoryWithType(ctx context.Context) ([]ListSnapsh
 			&i.Date,
 			&i.Balance,
 			&i.TypeID,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -969,7 +1127,7 @@ func (q *Queries) ListSnapshotHistoryWithType(ctx context.Context) ([]ListSnapsh
 }
 
 const listStartupShareOptions = `-- name: ListStartupShareOptions :many
-SELECT id, account_id, source_account_id, shares, strike_price_per_share, grant_date, end_date, created_at, updated_at
+SELECT id, account_id, source_account_id, shares, strike_price_per_share, grant_date, end_date, created_at, updated_at, cliff_months, vesting_months, vesting_interval_months, accelerate_on_exit, exercise_policy, tax_treatment
 FROM startup_share_option
 WHERE account_id = ?
 ORDER BY grant_date,
@@ -995,6 +1153,12 @@ func (q *Queries) ListStartupShareOptions(ctx context.Context, accountID string)
 			&i.EndDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CliffMonths,
+			&i.VestingMonths,
+			&i.VestingIntervalMonths,
+			&i.AccelerateOnExit,
+			&i.ExercisePolicy,
+			&i.TaxTreatment,
 		); err != nil {
 			return nil, err
 		}
@@ -1015,6 +1179,9 @@ SELECT ir.account_id,
   ir.valuation,
   ir.pre_money_shares,
   ir.investment,
+  ir.liquidation_preference,
+  ir.participating,
+  ir.seniority,
   ssa.tax_rate,
   ssa.valuation_discount_factor,
   COALESCE(a.type_id, '') AS type_id
@@ -1032,6 +1199,9 @@ type ListStartupShareSnapshotHistoryRow struct {
 	Valuation               float64
 	PreMoneyShares          float64
 	Investment              float64
+	LiquidationPreference   float64
+	Participating           bool
+	Seniority               int64
 	TaxRate                 float64
 	ValuationDiscountFactor float64
 	TypeID                  string
@@ -1054,6 +1224,9 @@ func (q *Queries) ListStartupShareSnapshotHistory(ctx context.Context) ([]ListSt
 			&i.Valuation,
 			&i.PreMoneyShares,
 			&i.Investment,
+			&i.LiquidationPreference,
+			&i.Participating,
+			&i.Seniority,
 			&i.TaxRate,
 			&i.ValuationDiscountFactor,
 			&i.TypeID,
@@ -1116,9 +1289,11 @@ SET name = ?,
   cash_flow_destination_id = ?,
   type_id = ?,
   budget_category_id = ?,
-  is_isk = ?
+  is_isk = ?,
+  owner_id = ?,
+  currency = ?
 WHERE id = ?
-RETURNING id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk
+RETURNING id, name, owner_id, created_at, updated_at, balance_upper_limit, cash_flow_frequency, cash_flow_destination_id, type_id, budget_category_id, is_isk, currency
 `
 
 type UpdateAccountParams struct {
@@ -1130,6 +1305,8 @@ type UpdateAccountParams struct {
 	TypeID                *string
 	BudgetCategoryID      *string
 	IsIsk                 int64
+	OwnerID               *string
+	Currency              string
 	ID                    string
 }
 
@@ -1143,6 +1320,8 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		arg.TypeID,
 		arg.BudgetCategoryID,
 		arg.IsIsk,
+		arg.OwnerID,
+		arg.Currency,
 		arg.ID,
 	)
 	var i Account
@@ -1158,6 +1337,7 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.TypeID,
 		&i.BudgetCategoryID,
 		&i.IsIsk,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -1228,18 +1408,22 @@ INSERT INTO growth_model (
     annual_volatility,
     start_date,
     end_date,
+    rate_model_id,
+    binding_years,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET model_type = EXCLUDED.model_type,
   annual_growth_rate = EXCLUDED.annual_growth_rate,
   annual_volatility = EXCLUDED.annual_volatility,
   start_date = EXCLUDED.start_date,
   end_date = EXCLUDED.end_date,
+  rate_model_id = EXCLUDED.rate_model_id,
+  binding_years = EXCLUDED.binding_years,
   updated_at = EXCLUDED.updated_at
-RETURNING id, account_id, model_type, annual_growth_rate, annual_volatility, start_date, end_date, created_at, updated_at
+RETURNING id, account_id, model_type, annual_growth_rate, annual_volatility, start_date, end_date, created_at, updated_at, rate_model_id, binding_years
 `
 
 type UpsertGrowthModelParams struct {
@@ -1250,6 +1434,8 @@ type UpsertGrowthModelParams struct {
 	AnnualVolatility string
 	StartDate        int64
 	EndDate          *int64
+	RateModelID      *string
+	BindingYears     int64
 	CreatedAt        int64
 	UpdatedAt        int64
 }
@@ -1263,6 +1449,8 @@ func (q *Queries) UpsertGrowthModel(ctx context.Context, arg UpsertGrowthModelPa
 		arg.AnnualVolatility,
 		arg.StartDate,
 		arg.EndDate,
+		arg.RateModelID,
+		arg.BindingYears,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -1277,6 +1465,8 @@ func (q *Queries) UpsertGrowthModel(ctx context.Context, arg UpsertGrowthModelPa
 		&i.EndDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RateModelID,
+		&i.BindingYears,
 	)
 	return i, err
 }
@@ -1289,27 +1479,36 @@ INSERT INTO investment_round (
     valuation,
     pre_money_shares,
     investment,
+    liquidation_preference,
+    participating,
+    seniority,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id, date) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id, date) DO
 UPDATE
 SET valuation = EXCLUDED.valuation,
   pre_money_shares = EXCLUDED.pre_money_shares,
   investment = EXCLUDED.investment,
+  liquidation_preference = EXCLUDED.liquidation_preference,
+  participating = EXCLUDED.participating,
+  seniority = EXCLUDED.seniority,
   updated_at = EXCLUDED.updated_at
-RETURNING id, account_id, date, valuation, created_at, updated_at, pre_money_shares, investment
+RETURNING id, account_id, date, valuation, created_at, updated_at, pre_money_shares, investment, liquidation_preference, participating, seniority
 `
 
 type UpsertInvestmentRoundParams struct {
-	ID             string
-	AccountID      string
-	Date           int64
-	Valuation      float64
-	PreMoneyShares float64
-	Investment     float64
-	CreatedAt      int64
-	UpdatedAt      int64
+	ID                    string
+	AccountID             string
+	Date                  int64
+	Valuation             float64
+	PreMoneyShares        float64
+	Investment            float64
+	LiquidationPreference float64
+	Participating         bool
+	Seniority             int64
+	CreatedAt             int64
+	UpdatedAt             int64
 }
 
 func (q *Queries) UpsertInvestmentRound(ctx context.Context, arg UpsertInvestmentRoundParams) (InvestmentRound, error) {
@@ -1320,6 +1519,9 @@ func (q *Queries) UpsertInvestmentRound(ctx context.Context, arg UpsertInvestmen
 		arg.Valuation,
 		arg.PreMoneyShares,
 		arg.Investment,
+		arg.LiquidationPreference,
+		arg.Participating,
+		arg.Seniority,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -1333,6 +1535,66 @@ func (q *Queries) UpsertInvestmentRound(ctx context.Context, arg UpsertInvestmen
 		&i.UpdatedAt,
 		&i.PreMoneyShares,
 		&i.Investment,
+		&i.LiquidationPreference,
+		&i.Participating,
+		&i.Seniority,
+	)
+	return i, err
+}
+
+const upsertProjectedInvestmentRound = `-- name: UpsertProjectedInvestmentRound :one
+INSERT INTO projected_investment_round (
+    id,
+    account_id,
+    date,
+    probability,
+    pre_money_valuation,
+    investment,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET date = EXCLUDED.date,
+  probability = EXCLUDED.probability,
+  pre_money_valuation = EXCLUDED.pre_money_valuation,
+  investment = EXCLUDED.investment,
+  updated_at = EXCLUDED.updated_at
+RETURNING id, account_id, date, probability, pre_money_valuation, investment, created_at, updated_at
+`
+
+type UpsertProjectedInvestmentRoundParams struct {
+	ID                string
+	AccountID         string
+	Date              int64
+	Probability       float64
+	PreMoneyValuation string
+	Investment        string
+	CreatedAt         int64
+	UpdatedAt         int64
+}
+
+func (q *Queries) UpsertProjectedInvestmentRound(ctx context.Context, arg UpsertProjectedInvestmentRoundParams) (ProjectedInvestmentRound, error) {
+	row := q.db.QueryRowContext(ctx, upsertProjectedInvestmentRound,
+		arg.ID,
+		arg.AccountID,
+		arg.Date,
+		arg.Probability,
+		arg.PreMoneyValuation,
+		arg.Investment,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i ProjectedInvestmentRound
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Date,
+		&i.Probability,
+		&i.PreMoneyValuation,
+		&i.Investment,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -1412,20 +1674,24 @@ func (q *Queries) UpsertSnapshot(ctx context.Context, arg UpsertSnapshotParams)
 }
 
 const upsertSpecialDate = `-- name: UpsertSpecialDate :one
-INSERT INTO special_date (id, name, date, color)
-VALUES (?, ?, ?, ?) ON CONFLICT (id) DO
+INSERT INTO special_date (id, name, date, color, person_id, person_age)
+VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET name = EXCLUDED.name,
   date = EXCLUDED.date,
-  color = EXCLUDED.color
-RETURNING id, name, date, color
+  color = EXCLUDED.color,
+  person_id = EXCLUDED.person_id,
+  person_age = EXCLUDED.person_age
+RETURNING id, name, date, color, person_id, person_age
 `
 
 type UpsertSpecialDateParams struct {
-	ID    string
-	Name  string
-	Date  string
-	Color *string
+	ID        string
+	Name      string
+	Date      string
+	Color     *string
+	PersonID  *string
+	PersonAge int64
 }
 
 func (q *Queries) UpsertSpecialDate(ctx context.Context, arg UpsertSpecialDateParams) (SpecialDate, error) {
@@ -1434,6 +1700,8 @@ func (q *Queries) UpsertSpecialDate(ctx context.Context, arg UpsertSpecialDatePa
 		arg.Name,
 		arg.Date,
 		arg.Color,
+		arg.PersonID,
+		arg.PersonAge,
 	)
 	var i SpecialDate
 	err := row.Scan(
@@ -1441,29 +1709,106 @@ func (q *Queries) UpsertSpecialDate(ctx context.Context, arg UpsertSpecialDatePa
 		&i.Name,
 		&i.Date,
 		&i.Color,
+		&i.PersonID,
+		&i.PersonAge,
+	)
+	return i, err
+}
+
+const upsertStartupExit = `-- name: UpsertStartupExit :one
+INSERT INTO startup_exit (
+    account_id,
+    date,
+    kind,
+    multiple,
+    failure_probability,
+    payout_account_id,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id) DO
+UPDATE
+SET date = EXCLUDED.date,
+  kind = EXCLUDED.kind,
+  multiple = EXCLUDED.multiple,
+  failure_probability = EXCLUDED.failure_probability,
+  payout_account_id = EXCLUDED.payout_account_id,
+  updated_at = EXCLUDED.updated_at
+RETURNING account_id, date, kind, multiple, failure_probability, payout_account_id, created_at, updated_at
+`
+
+type UpsertStartupExitParams struct {
+	AccountID          string
+	Date               int64
+	Kind               string
+	Multiple           string
+	FailureProbability float64
+	PayoutAccountID    string
+	CreatedAt          int64
+	UpdatedAt          int64
+}
+
+func (q *Queries) UpsertStartupExit(ctx context.Context, arg UpsertStartupExitParams) (StartupExit, error) {
+	row := q.db.QueryRowContext(ctx, upsertStartupExit,
+		arg.AccountID,
+		arg.Date,
+		arg.Kind,
+		arg.Multiple,
+		arg.FailureProbability,
+		arg.PayoutAccountID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i StartupExit
+	err := row.Scan(
+		&i.AccountID,
+		&i.Date,
+		&i.Kind,
+		&i.Multiple,
+		&i.FailureProbability,
+		&i.PayoutAccountID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const upsertStartupShareAccount = `-- name: UpsertStartupShareAccount :one
-INSERT INTO startup_share_account (account_id, tax_rate, valuation_discount_factor)
-VALUES (?, ?, ?) ON CONFLICT (account_id) DO
+INSERT INTO startup_share_account (
+    account_id,
+    tax_rate,
+    valuation_discount_factor,
+    income_tax_rate
+  )
+VALUES (?, ?, ?, ?) ON CONFLICT (account_id) DO
 UPDATE
 SET tax_rate = EXCLUDED.tax_rate,
-  valuation_discount_factor = EXCLUDED.valuation_discount_factor
-RETURNING account_id, tax_rate, valuation_discount_factor
+  valuation_discount_factor = EXCLUDED.valuation_discount_factor,
+  income_tax_rate = EXCLUDED.income_tax_rate
+RETURNING account_id, tax_rate, valuation_discount_factor, income_tax_rate
 `
 
 type UpsertStartupShareAccountParams struct {
 	AccountID               string
 	TaxRate                 float64
 	ValuationDiscountFactor float64
+	IncomeTaxRate           float64
 }
 
 func (q *Queries) UpsertStartupShareAccount(ctx context.Context, arg UpsertStartupShareAccountParams) (StartupShareAccount, error) {
-	row := q.db.QueryRowContext(ctx, upsertStartupShareAccount, arg.AccountID, arg.TaxRate, arg.ValuationDiscountFactor)
+	row := q.db.QueryRowContext(ctx, upsertStartupShareAccount,
+		arg.AccountID,
+		arg.TaxRate,
+		arg.ValuationDiscountFactor,
+		arg.IncomeTaxRate,
+	)
 	var i StartupShareAccount
-	err := row.Scan(&i.AccountID, &i.TaxRate, &i.ValuationDiscountFactor)
+	err := row.Scan(
+		&i.AccountID,
+		&i.TaxRate,
+		&i.ValuationDiscountFactor,
+		&i.IncomeTaxRate,
+	)
 	return i, err
 }
 
@@ -1476,10 +1821,16 @@ INSERT INTO startup_share_option (
     strike_price_per_share,
     grant_date,
     end_date,
+    cliff_months,
+    vesting_months,
+    vesting_interval_months,
+    accelerate_on_exit,
+    exercise_policy,
+    tax_treatment,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET account_id = EXCLUDED.account_id,
   source_account_id = EXCLUDED.source_account_id,
@@ -1487,20 +1838,32 @@ SET account_id = EXCLUDED.account_id,
   strike_price_per_share = EXCLUDED.strike_price_per_share,
   grant_date = EXCLUDED.grant_date,
   end_date = EXCLUDED.end_date,
+  cliff_months = EXCLUDED.cliff_months,
+  vesting_months = EXCLUDED.vesting_months,
+  vesting_interval_months = EXCLUDED.vesting_interval_months,
+  accelerate_on_exit = EXCLUDED.accelerate_on_exit,
+  exercise_policy = EXCLUDED.exercise_policy,
+  tax_treatment = EXCLUDED.tax_treatment,
   updated_at = EXCLUDED.updated_at
-RETURNING id, account_id, source_account_id, shares, strike_price_per_share, grant_date, end_date, created_at, updated_at
+RETURNING id, account_id, source_account_id, shares, strike_price_per_share, grant_date, end_date, created_at, updated_at, cliff_months, vesting_months, vesting_interval_months, accelerate_on_exit, exercise_policy, tax_treatment
 `
 
 type UpsertStartupShareOptionParams struct {
-	ID                  string
-	AccountID           string
-	SourceAccountID     string
-	Shares              float64
-	StrikePricePerShare float64
-	GrantDate           int64
-	EndDate             int64
-	CreatedAt           int64
-	UpdatedAt           int64
+	ID                    string
+	AccountID             string
+	SourceAccountID       string
+	Shares                float64
+	StrikePricePerShare   float64
+	GrantDate             int64
+	EndDate               int64
+	CliffMonths           int64
+	VestingMonths         int64
+	VestingIntervalMonths int64
+	AccelerateOnExit      bool
+	ExercisePolicy        string
+	TaxTreatment          string
+	CreatedAt             int64
+	UpdatedAt             int64
 }
 
 func (q *Queries) UpsertStartupShareOption(ctx context.Context, arg UpsertStartupShareOptionParams) (StartupShareOption, error) {
@@ -1512,6 +1875,12 @@ func (q *Queries) UpsertStartupShareOption(ctx context.Context, arg UpsertStartu
 		arg.StrikePricePerShare,
 		arg.GrantDate,
 		arg.EndDate,
+		arg.CliffMonths,
+		arg.VestingMonths,
+		arg.VestingIntervalMonths,
+		arg.AccelerateOnExit,
+		arg.ExercisePolicy,
+		arg.TaxTreatment,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -1526,6 +1895,12 @@ func (q *Queries) UpsertStartupShareOption(ctx context.Context, arg UpsertStartu
 		&i.EndDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CliffMonths,
+		&i.VestingMonths,
+		&i.VestingIntervalMonths,
+		&i.AccelerateOnExit,
+		&i.ExercisePolicy,
+		&i.TaxTreatment,
 	)
 	return i, err
 }
@@ -1545,10 +1920,15 @@ INSERT INTO transfer_template (
     end_date,
     enabled,
     budget_category_id,
+    start_person_id,
+    start_age,
+    end_person_id,
+    end_age,
+    currency,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET name = EXCLUDED.name,
   from_account_id = EXCLUDED.from_account_id,
@@ -1562,8 +1942,13 @@ SET name = EXCLUDED.name,
   end_date = EXCLUDED.end_date,
   enabled = EXCLUDED.enabled,
   budget_category_id = EXCLUDED.budget_category_id,
+  start_person_id = EXCLUDED.start_person_id,
+  start_age = EXCLUDED.start_age,
+  end_person_id = EXCLUDED.end_person_id,
+  end_age = EXCLUDED.end_age,
+  currency = EXCLUDED.currency,
   updated_at = EXCLUDED.updated_at
-RETURNING id, name, from_account_id, to_account_id, amount_type, amount_fixed, amount_percent, priority, recurrence, start_date, end_date, enabled, created_at, updated_at, budget_category_id
+RETURNING id, name, from_account_id, to_account_id, amount_type, amount_fixed, amount_percent, priority, recurrence, start_date, end_date, enabled, created_at, updated_at, budget_category_id, start_person_id, start_age, end_person_id, end_age, currency
 `
 
 type UpsertTransferTemplateParams struct {
@@ -1580,6 +1965,11 @@ type UpsertTransferTemplateParams struct {
 	EndDate          *int64
 	Enabled          bool
 	BudgetCategoryID *string
+	StartPersonID    *string
+	StartAge         int64
+	EndPersonID      *string
+	EndAge           int64
+	Currency         string
 	CreatedAt        int64
 	UpdatedAt        int64
 }
@@ -1599,6 +1989,11 @@ func (q *Queries) UpsertTransferTemplate(ctx context.Context, arg UpsertTransfer
 		arg.EndDate,
 		arg.Enabled,
 		arg.BudgetCategoryID,
+		arg.StartPersonID,
+		arg.StartAge,
+		arg.EndPersonID,
+		arg.EndAge,
+		arg.Currency,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -1619,6 +2014,11 @@ func (q *Queries) UpsertTransferTemplate(ctx context.Context, arg UpsertTransfer
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.BudgetCategoryID,
+		&i.StartPersonID,
+		&i.StartAge,
+		&i.EndPersonID,
+		&i.EndAge,
+		&i.Currency,
 	)
 	return i, err
 }
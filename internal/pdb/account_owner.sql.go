@@ -0,0 +1,107 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: account_owner.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteAccountOwner = `-- name: DeleteAccountOwner :exec
+DELETE FROM account_owner
+WHERE account_id = ?
+  AND person_id = ?
+`
+
+type DeleteAccountOwnerParams struct {
+	AccountID string
+	PersonID  string
+}
+
+func (q *Queries) DeleteAccountOwner(ctx context.Context, arg DeleteAccountOwnerParams) error {
+	_, err := q.db.ExecContext(ctx, deleteAccountOwner, arg.AccountID, arg.PersonID)
+	return err
+}
+
+const listAccountOwners = `-- name: ListAccountOwners :many
+SELECT account_id, person_id, share
+FROM account_owner
+ORDER BY account_id,
+  person_id
+`
+
+func (q *Queries) ListAccountOwners(ctx context.Context) ([]AccountOwner, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountOwners)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AccountOwner
+	for rows.Next() {
+		var i AccountOwner
+		if err := rows.Scan(&i.AccountID, &i.PersonID, &i.Share); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountOwnersByAccount = `-- name: ListAccountOwnersByAccount :many
+SELECT account_id, person_id, share
+FROM account_owner
+WHERE account_id = ?
+ORDER BY person_id
+`
+
+func (q *Queries) ListAccountOwnersByAccount(ctx context.Context, accountID string) ([]AccountOwner, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountOwnersByAccount, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AccountOwner
+	for rows.Next() {
+		var i AccountOwner
+		if err := rows.Scan(&i.AccountID, &i.PersonID, &i.Share); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertAccountOwner = `-- name: UpsertAccountOwner :one
+INSERT INTO account_owner (account_id, person_id, share)
+VALUES (?, ?, ?) ON CONFLICT (account_id, person_id) DO
+UPDATE
+SET share = EXCLUDED.share
+RETURNING account_id, person_id, share
+`
+
+type UpsertAccountOwnerParams struct {
+	AccountID string
+	PersonID  string
+	Share     float64
+}
+
+func (q *Queries) UpsertAccountOwner(ctx context.Context, arg UpsertAccountOwnerParams) (AccountOwner, error) {
+	row := q.db.QueryRowContext(ctx, upsertAccountOwner, arg.AccountID, arg.PersonID, arg.Share)
+	var i AccountOwner
+	err := row.Scan(&i.AccountID, &i.PersonID, &i.Share)
+	return i, err
+}
@@ -0,0 +1,138 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: amortization_requirement.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteAmortizationRequirement = `-- name: DeleteAmortizationRequirement :exec
+DELETE FROM amortization_requirement
+WHERE account_id = ?
+`
+
+func (q *Queries) DeleteAmortizationRequirement(ctx context.Context, accountID string) error {
+	_, err := q.db.ExecContext(ctx, deleteAmortizationRequirement, accountID)
+	return err
+}
+
+const getAmortizationRequirement = `-- name: GetAmortizationRequirement :one
+SELECT account_id, property_account_id, from_account_id, recurrence, valuation_date, revaluation_years, created_at, updated_at
+FROM amortization_requirement
+WHERE account_id = ?
+`
+
+func (q *Queries) GetAmortizationRequirement(ctx context.Context, accountID string) (AmortizationRequirement, error) {
+	row := q.db.QueryRowContext(ctx, getAmortizationRequirement, accountID)
+	var i AmortizationRequirement
+	err := row.Scan(
+		&i.AccountID,
+		&i.PropertyAccountID,
+		&i.FromAccountID,
+		&i.Recurrence,
+		&i.ValuationDate,
+		&i.RevaluationYears,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listAmortizationRequirements = `-- name: ListAmortizationRequirements :many
+SELECT account_id, property_account_id, from_account_id, recurrence, valuation_date, revaluation_years, created_at, updated_at
+FROM amortization_requirement
+ORDER BY account_id
+`
+
+func (q *Queries) ListAmortizationRequirements(ctx context.Context) ([]AmortizationRequirement, error) {
+	rows, err := q.db.QueryContext(ctx, listAmortizationRequirements)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AmortizationRequirement
+	for rows.Next() {
+		var i AmortizationRequirement
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.PropertyAccountID,
+			&i.FromAccountID,
+			&i.Recurrence,
+			&i.ValuationDate,
+			&i.RevaluationYears,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertAmortizationRequirement = `-- name: UpsertAmortizationRequirement :one
+INSERT INTO amortization_requirement (
+    account_id,
+    property_account_id,
+    from_account_id,
+    recurrence,
+    valuation_date,
+    revaluation_years,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id) DO
+UPDATE
+SET property_account_id = EXCLUDED.property_account_id,
+  from_account_id = EXCLUDED.from_account_id,
+  recurrence = EXCLUDED.recurrence,
+  valuation_date = EXCLUDED.valuation_date,
+  revaluation_years = EXCLUDED.revaluation_years,
+  updated_at = EXCLUDED.updated_at
+RETURNING account_id, property_account_id, from_account_id, recurrence, valuation_date, revaluation_years, created_at, updated_at
+`
+
+type UpsertAmortizationRequirementParams struct {
+	AccountID         string
+	PropertyAccountID *string
+	FromAccountID     *string
+	Recurrence        string
+	ValuationDate     int64
+	RevaluationYears  int64
+	CreatedAt         int64
+	UpdatedAt         int64
+}
+
+func (q *Queries) UpsertAmortizationRequirement(ctx context.Context, arg UpsertAmortizationRequirementParams) (AmortizationRequirement, error) {
+	row := q.db.QueryRowContext(ctx, upsertAmortizationRequirement,
+		arg.AccountID,
+		arg.PropertyAccountID,
+		arg.FromAccountID,
+		arg.Recurrence,
+		arg.ValuationDate,
+		arg.RevaluationYears,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i AmortizationRequirement
+	err := row.Scan(
+		&i.AccountID,
+		&i.PropertyAccountID,
+		&i.FromAccountID,
+		&i.Recurrence,
+		&i.ValuationDate,
+		&i.RevaluationYears,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -50,6 +50,41 @@ func (q *Queries) GetCacheEntryIfFresh(ctx context.Context, arg GetCacheEntryIfF
 	return value, err
 }
 
+const listCacheEntriesByPrefix = `-- name: ListCacheEntriesByPrefix :many
+SELECT cache_key, value
+FROM api_cache
+WHERE cache_key LIKE ?
+ORDER BY cache_key
+`
+
+type ListCacheEntriesByPrefixRow struct {
+	CacheKey string
+	Value    string
+}
+
+func (q *Queries) ListCacheEntriesByPrefix(ctx context.Context, cacheKey string) ([]ListCacheEntriesByPrefixRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCacheEntriesByPrefix, cacheKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCacheEntriesByPrefixRow
+	for rows.Next() {
+		var i ListCacheEntriesByPrefixRow
+		if err := rows.Scan(&i.CacheKey, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const upsertCacheEntry = `-- name: UpsertCacheEntry :exec
 INSERT INTO api_cache (cache_key, value, created_at)
 VALUES (?, ?, ?)
@@ -0,0 +1,201 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: company.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteCompany = `-- name: DeleteCompany :exec
+DELETE FROM company
+WHERE id = ?
+`
+
+func (q *Queries) DeleteCompany(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteCompany, id)
+	return err
+}
+
+const getCompany = `-- name: GetCompany :one
+SELECT id, name, owner_id, ownership_share, account_id, dividend_account_id, salary_id, revenue, expenses, gransbelopp_rule, acquisition_cost, capital_rate, saved_dividend_space, dividend_date, start_date, created_at, updated_at
+FROM company
+WHERE id = ?
+`
+
+func (q *Queries) GetCompany(ctx context.Context, id string) (Company, error) {
+	row := q.db.QueryRowContext(ctx, getCompany, id)
+	var i Company
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerID,
+		&i.OwnershipShare,
+		&i.AccountID,
+		&i.DividendAccountID,
+		&i.SalaryID,
+		&i.Revenue,
+		&i.Expenses,
+		&i.GransbeloppRule,
+		&i.AcquisitionCost,
+		&i.CapitalRate,
+		&i.SavedDividendSpace,
+		&i.DividendDate,
+		&i.StartDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCompanies = `-- name: ListCompanies :many
+SELECT id, name, owner_id, ownership_share, account_id, dividend_account_id, salary_id, revenue, expenses, gransbelopp_rule, acquisition_cost, capital_rate, saved_dividend_space, dividend_date, start_date, created_at, updated_at
+FROM company
+ORDER BY name, id
+`
+
+func (q *Queries) ListCompanies(ctx context.Context) ([]Company, error) {
+	rows, err := q.db.QueryContext(ctx, listCompanies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Company
+	for rows.Next() {
+		var i Company
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OwnerID,
+			&i.OwnershipShare,
+			&i.AccountID,
+			&i.DividendAccountID,
+			&i.SalaryID,
+			&i.Revenue,
+			&i.Expenses,
+			&i.GransbeloppRule,
+			&i.AcquisitionCost,
+			&i.CapitalRate,
+			&i.SavedDividendSpace,
+			&i.DividendDate,
+			&i.StartDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertCompany = `-- name: UpsertCompany :one
+INSERT INTO company (
+    id,
+    name,
+    owner_id,
+    ownership_share,
+    account_id,
+    dividend_account_id,
+    salary_id,
+    revenue,
+    expenses,
+    gransbelopp_rule,
+    acquisition_cost,
+    capital_rate,
+    saved_dividend_space,
+    dividend_date,
+    start_date,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET name = EXCLUDED.name,
+  owner_id = EXCLUDED.owner_id,
+  ownership_share = EXCLUDED.ownership_share,
+  account_id = EXCLUDED.account_id,
+  dividend_account_id = EXCLUDED.dividend_account_id,
+  salary_id = EXCLUDED.salary_id,
+  revenue = EXCLUDED.revenue,
+  expenses = EXCLUDED.expenses,
+  gransbelopp_rule = EXCLUDED.gransbelopp_rule,
+  acquisition_cost = EXCLUDED.acquisition_cost,
+  capital_rate = EXCLUDED.capital_rate,
+  saved_dividend_space = EXCLUDED.saved_dividend_space,
+  dividend_date = EXCLUDED.dividend_date,
+  start_date = EXCLUDED.start_date,
+  updated_at = EXCLUDED.updated_at
+RETURNING id, name, owner_id, ownership_share, account_id, dividend_account_id, salary_id, revenue, expenses, gransbelopp_rule, acquisition_cost, capital_rate, saved_dividend_space, dividend_date, start_date, created_at, updated_at
+`
+
+type UpsertCompanyParams struct {
+	ID                 string
+	Name               string
+	OwnerID            *string
+	OwnershipShare     float64
+	AccountID          string
+	DividendAccountID  *string
+	SalaryID           *string
+	Revenue            string
+	Expenses           string
+	GransbeloppRule    string
+	AcquisitionCost    float64
+	CapitalRate        float64
+	SavedDividendSpace float64
+	DividendDate       string
+	StartDate          int64
+	CreatedAt          int64
+	UpdatedAt          int64
+}
+
+func (q *Queries) UpsertCompany(ctx context.Context, arg UpsertCompanyParams) (Company, error) {
+	row := q.db.QueryRowContext(ctx, upsertCompany,
+		arg.ID,
+		arg.Name,
+		arg.OwnerID,
+		arg.OwnershipShare,
+		arg.AccountID,
+		arg.DividendAccountID,
+		arg.SalaryID,
+		arg.Revenue,
+		arg.Expenses,
+		arg.GransbeloppRule,
+		arg.AcquisitionCost,
+		arg.CapitalRate,
+		arg.SavedDividendSpace,
+		arg.DividendDate,
+		arg.StartDate,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Company
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerID,
+		&i.OwnershipShare,
+		&i.AccountID,
+		&i.DividendAccountID,
+		&i.SalaryID,
+		&i.Revenue,
+		&i.Expenses,
+		&i.GransbeloppRule,
+		&i.AcquisitionCost,
+		&i.CapitalRate,
+		&i.SavedDividendSpace,
+		&i.DividendDate,
+		&i.StartDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
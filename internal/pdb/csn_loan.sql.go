@@ -0,0 +1,145 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: csn_loan.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteCsnLoan = `-- name: DeleteCsnLoan :exec
+DELETE FROM csn_loan
+WHERE account_id = ?
+`
+
+func (q *Queries) DeleteCsnLoan(ctx context.Context, accountID string) error {
+	_, err := q.db.ExecContext(ctx, deleteCsnLoan, accountID)
+	return err
+}
+
+const getCsnLoan = `-- name: GetCsnLoan :one
+SELECT account_id, from_account_id, salary_id, birth_date, final_year, recurrence, reduction, created_at, updated_at
+FROM csn_loan
+WHERE account_id = ?
+`
+
+func (q *Queries) GetCsnLoan(ctx context.Context, accountID string) (CsnLoan, error) {
+	row := q.db.QueryRowContext(ctx, getCsnLoan, accountID)
+	var i CsnLoan
+	err := row.Scan(
+		&i.AccountID,
+		&i.FromAccountID,
+		&i.SalaryID,
+		&i.BirthDate,
+		&i.FinalYear,
+		&i.Recurrence,
+		&i.Reduction,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCsnLoans = `-- name: ListCsnLoans :many
+SELECT account_id, from_account_id, salary_id, birth_date, final_year, recurrence, reduction, created_at, updated_at
+FROM csn_loan
+ORDER BY account_id
+`
+
+func (q *Queries) ListCsnLoans(ctx context.Context) ([]CsnLoan, error) {
+	rows, err := q.db.QueryContext(ctx, listCsnLoans)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CsnLoan
+	for rows.Next() {
+		var i CsnLoan
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.FromAccountID,
+			&i.SalaryID,
+			&i.BirthDate,
+			&i.FinalYear,
+			&i.Recurrence,
+			&i.Reduction,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertCsnLoan = `-- name: UpsertCsnLoan :one
+INSERT INTO csn_loan (
+    account_id,
+    from_account_id,
+    salary_id,
+    birth_date,
+    final_year,
+    recurrence,
+    reduction,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id) DO
+UPDATE
+SET from_account_id = EXCLUDED.from_account_id,
+  salary_id = EXCLUDED.salary_id,
+  birth_date = EXCLUDED.birth_date,
+  final_year = EXCLUDED.final_year,
+  recurrence = EXCLUDED.recurrence,
+  reduction = EXCLUDED.reduction,
+  updated_at = EXCLUDED.updated_at
+RETURNING account_id, from_account_id, salary_id, birth_date, final_year, recurrence, reduction, created_at, updated_at
+`
+
+type UpsertCsnLoanParams struct {
+	AccountID     string
+	FromAccountID *string
+	SalaryID      *string
+	BirthDate     int64
+	FinalYear     int64
+	Recurrence    string
+	Reduction     int64
+	CreatedAt     int64
+	UpdatedAt     int64
+}
+
+func (q *Queries) UpsertCsnLoan(ctx context.Context, arg UpsertCsnLoanParams) (CsnLoan, error) {
+	row := q.db.QueryRowContext(ctx, upsertCsnLoan,
+		arg.AccountID,
+		arg.FromAccountID,
+		arg.SalaryID,
+		arg.BirthDate,
+		arg.FinalYear,
+		arg.Recurrence,
+		arg.Reduction,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i CsnLoan
+	err := row.Scan(
+		&i.AccountID,
+		&i.FromAccountID,
+		&i.SalaryID,
+		&i.BirthDate,
+		&i.FinalYear,
+		&i.Recurrence,
+		&i.Reduction,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,215 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: equity_grant.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteEquityGrant = `-- name: DeleteEquityGrant :exec
+DELETE FROM equity_grant
+WHERE id = ?
+`
+
+func (q *Queries) DeleteEquityGrant(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteEquityGrant, id)
+	return err
+}
+
+const getEquityGrant = `-- name: GetEquityGrant :one
+SELECT id, name, owner_id, kind, holding_account_id, salary_account_id, share_price, shares, cliff_months, vesting_months, vesting_interval_months, contribution, discount, recurrence, municipal_tax_rate, start_date, enabled, created_at, updated_at
+FROM equity_grant
+WHERE id = ?
+`
+
+func (q *Queries) GetEquityGrant(ctx context.Context, id string) (EquityGrant, error) {
+	row := q.db.QueryRowContext(ctx, getEquityGrant, id)
+	var i EquityGrant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerID,
+		&i.Kind,
+		&i.HoldingAccountID,
+		&i.SalaryAccountID,
+		&i.SharePrice,
+		&i.Shares,
+		&i.CliffMonths,
+		&i.VestingMonths,
+		&i.VestingIntervalMonths,
+		&i.Contribution,
+		&i.Discount,
+		&i.Recurrence,
+		&i.MunicipalTaxRate,
+		&i.StartDate,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listEquityGrants = `-- name: ListEquityGrants :many
+SELECT id, name, owner_id, kind, holding_account_id, salary_account_id, share_price, shares, cliff_months, vesting_months, vesting_interval_months, contribution, discount, recurrence, municipal_tax_rate, start_date, enabled, created_at, updated_at
+FROM equity_grant
+ORDER BY name, id
+`
+
+func (q *Queries) ListEquityGrants(ctx context.Context) ([]EquityGrant, error) {
+	rows, err := q.db.QueryContext(ctx, listEquityGrants)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EquityGrant
+	for rows.Next() {
+		var i EquityGrant
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OwnerID,
+			&i.Kind,
+			&i.HoldingAccountID,
+			&i.SalaryAccountID,
+			&i.SharePrice,
+			&i.Shares,
+			&i.CliffMonths,
+			&i.VestingMonths,
+			&i.VestingIntervalMonths,
+			&i.Contribution,
+			&i.Discount,
+			&i.Recurrence,
+			&i.MunicipalTaxRate,
+			&i.StartDate,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertEquityGrant = `-- name: UpsertEquityGrant :one
+INSERT INTO equity_grant (
+    id,
+    name,
+    owner_id,
+    kind,
+    holding_account_id,
+    salary_account_id,
+    share_price,
+    shares,
+    cliff_months,
+    vesting_months,
+    vesting_interval_months,
+    contribution,
+    discount,
+    recurrence,
+    municipal_tax_rate,
+    start_date,
+    enabled,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET name = EXCLUDED.name,
+  owner_id = EXCLUDED.owner_id,
+  kind = EXCLUDED.kind,
+  holding_account_id = EXCLUDED.holding_account_id,
+  salary_account_id = EXCLUDED.salary_account_id,
+  share_price = EXCLUDED.share_price,
+  shares = EXCLUDED.shares,
+  cliff_months = EXCLUDED.cliff_months,
+  vesting_months = EXCLUDED.vesting_months,
+  vesting_interval_months = EXCLUDED.vesting_interval_months,
+  contribution = EXCLUDED.contribution,
+  discount = EXCLUDED.discount,
+  recurrence = EXCLUDED.recurrence,
+  municipal_tax_rate = EXCLUDED.municipal_tax_rate,
+  start_date = EXCLUDED.start_date,
+  enabled = EXCLUDED.enabled,
+  updated_at = EXCLUDED.updated_at
+RETURNING id, name, owner_id, kind, holding_account_id, salary_account_id, share_price, shares, cliff_months, vesting_months, vesting_interval_months, contribution, discount, recurrence, municipal_tax_rate, start_date, enabled, created_at, updated_at
+`
+
+type UpsertEquityGrantParams struct {
+	ID                    string
+	Name                  string
+	OwnerID               *string
+	Kind                  string
+	HoldingAccountID      string
+	SalaryAccountID       *string
+	SharePrice            float64
+	Shares                float64
+	CliffMonths           int64
+	VestingMonths         int64
+	VestingIntervalMonths int64
+	Contribution          string
+	Discount              float64
+	Recurrence            string
+	MunicipalTaxRate      float64
+	StartDate             int64
+	Enabled               bool
+	CreatedAt             int64
+	UpdatedAt             int64
+}
+
+func (q *Queries) UpsertEquityGrant(ctx context.Context, arg UpsertEquityGrantParams) (EquityGrant, error) {
+	row := q.db.QueryRowContext(ctx, upsertEquityGrant,
+		arg.ID,
+		arg.Name,
+		arg.OwnerID,
+		arg.Kind,
+		arg.HoldingAccountID,
+		arg.SalaryAccountID,
+		arg.SharePrice,
+		arg.Shares,
+		arg.CliffMonths,
+		arg.VestingMonths,
+		arg.VestingIntervalMonths,
+		arg.Contribution,
+		arg.Discount,
+		arg.Recurrence,
+		arg.MunicipalTaxRate,
+		arg.StartDate,
+		arg.Enabled,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i EquityGrant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerID,
+		&i.Kind,
+		&i.HoldingAccountID,
+		&i.SalaryAccountID,
+		&i.SharePrice,
+		&i.Shares,
+		&i.CliffMonths,
+		&i.VestingMonths,
+		&i.VestingIntervalMonths,
+		&i.Contribution,
+		&i.Discount,
+		&i.Recurrence,
+		&i.MunicipalTaxRate,
+		&i.StartDate,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
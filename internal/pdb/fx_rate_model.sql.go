@@ -0,0 +1,160 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: fx_rate_model.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteFXRateModel = `-- name: DeleteFXRateModel :exec
+DELETE FROM fx_rate_model
+WHERE currency = ?
+`
+
+func (q *Queries) DeleteFXRateModel(ctx context.Context, currency string) error {
+	_, err := q.db.ExecContext(ctx, deleteFXRateModel, currency)
+	return err
+}
+
+const listAccountCurrencies = `-- name: ListAccountCurrencies :many
+SELECT DISTINCT currency
+FROM account
+`
+
+func (q *Queries) ListAccountCurrencies(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var currency string
+		if err := rows.Scan(&currency); err != nil {
+			return nil, err
+		}
+		items = append(items, currency)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFXRateModels = `-- name: ListFXRateModels :many
+SELECT currency, initial_rate, annual_drift, annual_volatility, created_at, updated_at
+FROM fx_rate_model
+ORDER BY currency
+`
+
+func (q *Queries) ListFXRateModels(ctx context.Context) ([]FxRateModel, error) {
+	rows, err := q.db.QueryContext(ctx, listFXRateModels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FxRateModel
+	for rows.Next() {
+		var i FxRateModel
+		if err := rows.Scan(
+			&i.Currency,
+			&i.InitialRate,
+			&i.AnnualDrift,
+			&i.AnnualVolatility,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransferTemplateCurrencies = `-- name: ListTransferTemplateCurrencies :many
+SELECT DISTINCT currency
+FROM transfer_template
+`
+
+func (q *Queries) ListTransferTemplateCurrencies(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listTransferTemplateCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var currency string
+		if err := rows.Scan(&currency); err != nil {
+			return nil, err
+		}
+		items = append(items, currency)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertFXRateModel = `-- name: UpsertFXRateModel :one
+INSERT INTO fx_rate_model (
+    currency,
+    initial_rate,
+    annual_drift,
+    annual_volatility,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (currency) DO
+UPDATE
+SET initial_rate = EXCLUDED.initial_rate,
+  annual_drift = EXCLUDED.annual_drift,
+  annual_volatility = EXCLUDED.annual_volatility,
+  updated_at = EXCLUDED.updated_at
+RETURNING currency, initial_rate, annual_drift, annual_volatility, created_at, updated_at
+`
+
+type UpsertFXRateModelParams struct {
+	Currency         string
+	InitialRate      float64
+	AnnualDrift      float64
+	AnnualVolatility float64
+	CreatedAt        int64
+	UpdatedAt        int64
+}
+
+func (q *Queries) UpsertFXRateModel(ctx context.Context, arg UpsertFXRateModelParams) (FxRateModel, error) {
+	row := q.db.QueryRowContext(ctx, upsertFXRateModel,
+		arg.Currency,
+		arg.InitialRate,
+		arg.AnnualDrift,
+		arg.AnnualVolatility,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i FxRateModel
+	err := row.Scan(
+		&i.Currency,
+		&i.InitialRate,
+		&i.AnnualDrift,
+		&i.AnnualVolatility,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
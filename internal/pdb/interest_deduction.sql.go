@@ -0,0 +1,124 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: interest_deduction.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteInterestDeduction = `-- name: DeleteInterestDeduction :exec
+DELETE FROM interest_deduction
+WHERE account_id = ?
+`
+
+func (q *Queries) DeleteInterestDeduction(ctx context.Context, accountID string) error {
+	_, err := q.db.ExecContext(ctx, deleteInterestDeduction, accountID)
+	return err
+}
+
+const getInterestDeduction = `-- name: GetInterestDeduction :one
+SELECT account_id, to_account_id, settlement_date, borrowers, created_at, updated_at
+FROM interest_deduction
+WHERE account_id = ?
+`
+
+func (q *Queries) GetInterestDeduction(ctx context.Context, accountID string) (InterestDeduction, error) {
+	row := q.db.QueryRowContext(ctx, getInterestDeduction, accountID)
+	var i InterestDeduction
+	err := row.Scan(
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.SettlementDate,
+		&i.Borrowers,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listInterestDeductions = `-- name: ListInterestDeductions :many
+SELECT account_id, to_account_id, settlement_date, borrowers, created_at, updated_at
+FROM interest_deduction
+ORDER BY account_id
+`
+
+func (q *Queries) ListInterestDeductions(ctx context.Context) ([]InterestDeduction, error) {
+	rows, err := q.db.QueryContext(ctx, listInterestDeductions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InterestDeduction
+	for rows.Next() {
+		var i InterestDeduction
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.ToAccountID,
+			&i.SettlementDate,
+			&i.Borrowers,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertInterestDeduction = `-- name: UpsertInterestDeduction :one
+INSERT INTO interest_deduction (
+    account_id,
+    to_account_id,
+    settlement_date,
+    borrowers,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (account_id) DO
+UPDATE
+SET to_account_id = EXCLUDED.to_account_id,
+  settlement_date = EXCLUDED.settlement_date,
+  borrowers = EXCLUDED.borrowers,
+  updated_at = EXCLUDED.updated_at
+RETURNING account_id, to_account_id, settlement_date, borrowers, created_at, updated_at
+`
+
+type UpsertInterestDeductionParams struct {
+	AccountID      string
+	ToAccountID    *string
+	SettlementDate string
+	Borrowers      int64
+	CreatedAt      int64
+	UpdatedAt      int64
+}
+
+func (q *Queries) UpsertInterestDeduction(ctx context.Context, arg UpsertInterestDeductionParams) (InterestDeduction, error) {
+	row := q.db.QueryRowContext(ctx, upsertInterestDeduction,
+		arg.AccountID,
+		arg.ToAccountID,
+		arg.SettlementDate,
+		arg.Borrowers,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i InterestDeduction
+	err := row.Scan(
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.SettlementDate,
+		&i.Borrowers,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,145 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: interest_rate_model.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteInterestRateModel = `-- name: DeleteInterestRateModel :exec
+DELETE FROM interest_rate_model
+WHERE id = ?
+`
+
+func (q *Queries) DeleteInterestRateModel(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteInterestRateModel, id)
+	return err
+}
+
+const getInterestRateModel = `-- name: GetInterestRateModel :one
+SELECT id, name, model_type, initial_rate, long_term_rate, reversion_speed, volatility, created_at, updated_at
+FROM interest_rate_model
+WHERE id = ?
+`
+
+func (q *Queries) GetInterestRateModel(ctx context.Context, id string) (InterestRateModel, error) {
+	row := q.db.QueryRowContext(ctx, getInterestRateModel, id)
+	var i InterestRateModel
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ModelType,
+		&i.InitialRate,
+		&i.LongTermRate,
+		&i.ReversionSpeed,
+		&i.Volatility,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listInterestRateModels = `-- name: ListInterestRateModels :many
+SELECT id, name, model_type, initial_rate, long_term_rate, reversion_speed, volatility, created_at, updated_at
+FROM interest_rate_model
+ORDER BY name, id
+`
+
+func (q *Queries) ListInterestRateModels(ctx context.Context) ([]InterestRateModel, error) {
+	rows, err := q.db.QueryContext(ctx, listInterestRateModels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InterestRateModel
+	for rows.Next() {
+		var i InterestRateModel
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ModelType,
+			&i.InitialRate,
+			&i.LongTermRate,
+			&i.ReversionSpeed,
+			&i.Volatility,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertInterestRateModel = `-- name: UpsertInterestRateModel :one
+INSERT INTO interest_rate_model (
+    id,
+    name,
+    model_type,
+    initial_rate,
+    long_term_rate,
+    reversion_speed,
+    volatility,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET name = EXCLUDED.name,
+  model_type = EXCLUDED.model_type,
+  initial_rate = EXCLUDED.initial_rate,
+  long_term_rate = EXCLUDED.long_term_rate,
+  reversion_speed = EXCLUDED.reversion_speed,
+  volatility = EXCLUDED.volatility,
+  updated_at = EXCLUDED.updated_at
+RETURNING id, name, model_type, initial_rate, long_term_rate, reversion_speed, volatility, created_at, updated_at
+`
+
+type UpsertInterestRateModelParams struct {
+	ID             string
+	Name           string
+	ModelType      string
+	InitialRate    float64
+	LongTermRate   float64
+	ReversionSpeed float64
+	Volatility     float64
+	CreatedAt      int64
+	UpdatedAt      int64
+}
+
+func (q *Queries) UpsertInterestRateModel(ctx context.Context, arg UpsertInterestRateModelParams) (InterestRateModel, error) {
+	row := q.db.QueryRowContext(ctx, upsertInterestRateModel,
+		arg.ID,
+		arg.Name,
+		arg.ModelType,
+		arg.InitialRate,
+		arg.LongTermRate,
+		arg.ReversionSpeed,
+		arg.Volatility,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i InterestRateModel
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ModelType,
+		&i.InitialRate,
+		&i.LongTermRate,
+		&i.ReversionSpeed,
+		&i.Volatility,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
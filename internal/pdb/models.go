@@ -16,6 +16,13 @@ type Account struct {
 	TypeID                *string
 	BudgetCategoryID      *string
 	IsIsk                 int64
+	Currency              string
+}
+
+type AccountOwner struct {
+	AccountID string
+	PersonID  string
+	Share     float64
 }
 
 type AccountSnapshot struct {
@@ -30,6 +37,17 @@ type AccountType struct {
 	Color *string
 }
 
+type AmortizationRequirement struct {
+	AccountID         string
+	PropertyAccountID *string
+	FromAccountID     *string
+	Recurrence        string
+	ValuationDate     int64
+	RevaluationYears  int64
+	CreatedAt         int64
+	UpdatedAt         int64
+}
+
 type ApiCache struct {
 	CacheKey  string
 	Value     string
@@ -76,6 +94,60 @@ type BillAmount struct {
 	Currency  string
 }
 
+type Company struct {
+	ID                 string
+	Name               string
+	OwnerID            *string
+	OwnershipShare     float64
+	AccountID          string
+	DividendAccountID  *string
+	SalaryID           *string
+	Revenue            string
+	Expenses           string
+	GransbeloppRule    string
+	AcquisitionCost    float64
+	CapitalRate        float64
+	SavedDividendSpace float64
+	DividendDate       string
+	StartDate          int64
+	CreatedAt          int64
+	UpdatedAt          int64
+}
+
+type CsnLoan struct {
+	AccountID     string
+	FromAccountID *string
+	SalaryID      *string
+	BirthDate     int64
+	FinalYear     int64
+	Recurrence    string
+	Reduction     int64
+	CreatedAt     int64
+	UpdatedAt     int64
+}
+
+type EquityGrant struct {
+	ID                    string
+	Name                  string
+	OwnerID               *string
+	Kind                  string
+	HoldingAccountID      string
+	SalaryAccountID       *string
+	SharePrice            float64
+	Shares                float64
+	CliffMonths           int64
+	VestingMonths         int64
+	VestingIntervalMonths int64
+	Contribution          string
+	Discount              float64
+	Recurrence            string
+	MunicipalTaxRate      float64
+	StartDate             int64
+	Enabled               bool
+	CreatedAt             int64
+	UpdatedAt             int64
+}
+
 type FaviconCache struct {
 	Domain      string
 	IconData    []byte
@@ -101,6 +173,15 @@ type FullParentalLeave struct {
 	UpdatedAt       int64
 }
 
+type FxRateModel struct {
+	Currency         string
+	InitialRate      float64
+	AnnualDrift      float64
+	AnnualVolatility float64
+	CreatedAt        int64
+	UpdatedAt        int64
+}
+
 type GrowthModel struct {
 	ID               string
 	AccountID        string
@@ -111,17 +192,43 @@ type GrowthModel struct {
 	EndDate          *int64
 	CreatedAt        int64
 	UpdatedAt        int64
+	RateModelID      *string
+	BindingYears     int64
 }
 
-type InvestmentRound struct {
-	ID             string
+type InterestDeduction struct {
 	AccountID      string
-	Date           int64
-	Valuation      float64
+	ToAccountID    *string
+	SettlementDate string
+	Borrowers      int64
+	CreatedAt      int64
+	UpdatedAt      int64
+}
+
+type InterestRateModel struct {
+	ID             string
+	Name           string
+	ModelType      string
+	InitialRate    float64
+	LongTermRate   float64
+	ReversionSpeed float64
+	Volatility     float64
 	CreatedAt      int64
 	UpdatedAt      int64
-	PreMoneyShares float64
-	Investment     float64
+}
+
+type InvestmentRound struct {
+	ID                    string
+	AccountID             string
+	Date                  int64
+	Valuation             float64
+	CreatedAt             int64
+	UpdatedAt             int64
+	PreMoneyShares        float64
+	Investment            float64
+	LiquidationPreference float64
+	Participating         bool
+	Seniority             int64
 }
 
 type PartialParentalLeave struct {
@@ -136,21 +243,56 @@ type PartialParentalLeave struct {
 	UpdatedAt              int64
 }
 
+type PensionPayout struct {
+	AccountID    string
+	ToAccountID  *string
+	StartDate    int64
+	BirthDate    *int64
+	StartAge     *int64
+	PeriodYears  int64
+	Kommun       string
+	Forsamling   string
+	ChurchMember bool
+	CreatedAt    int64
+	UpdatedAt    int64
+}
+
+type ProjectedInvestmentRound struct {
+	ID                string
+	AccountID         string
+	Date              int64
+	Probability       float64
+	PreMoneyValuation string
+	Investment        string
+	CreatedAt         int64
+	UpdatedAt         int64
+}
+
 type Salary struct {
-	ID               string
-	Name             string
-	ToAccountID      *string
-	Priority         int64
-	Recurrence       string
-	BudgetCategoryID *string
-	Enabled          bool
-	CreatedAt        int64
-	UpdatedAt        int64
-	PensionAccountID *string
-	Kommun           string
-	Forsamling       string
-	ChurchMember     bool
-	IsGross          bool
+	ID                          string
+	Name                        string
+	ToAccountID                 *string
+	Priority                    int64
+	Recurrence                  string
+	BudgetCategoryID            *string
+	Enabled                     bool
+	CreatedAt                   int64
+	UpdatedAt                   int64
+	PensionAccountID            *string
+	Kommun                      string
+	Forsamling                  string
+	ChurchMember                bool
+	IsGross                     bool
+	TaxMethod                   string
+	MunicipalTaxRate            float64
+	OwnerID                     *string
+	ParentalPayRate             float64
+	ParentalPayAboveCeilingRate float64
+	ParentalPayMaxDays          int64
+	SalaryExchangeAmount        float64
+	SalaryExchangeUplift        float64
+	SecondaryEmployer           bool
+	SecondaryTaxRate            float64
 }
 
 type SalaryAdjustment struct {
@@ -174,6 +316,18 @@ type SalaryAmount struct {
 	UpdatedAt int64
 }
 
+type SalaryBonus struct {
+	ID          string
+	SalaryID    string
+	Name        string
+	Amount      string
+	Probability float64
+	PayDate     int64
+	Yearly      bool
+	CreatedAt   int64
+	UpdatedAt   int64
+}
+
 type ShareChange struct {
 	ID          string
 	AccountID   string
@@ -184,40 +338,92 @@ type ShareChange struct {
 	UpdatedAt   int64
 }
 
+type SickLeave struct {
+	ID                string
+	SalaryID          string
+	StartDate         int64
+	EndDate           int64
+	Extent            float64
+	EmployerTopUpRate float64
+	EmployerTopUpDays int64
+	CreatedAt         int64
+	UpdatedAt         int64
+}
+
+type SoleProprietorship struct {
+	ID                      string
+	Name                    string
+	OwnerID                 *string
+	ToAccountID             string
+	Profit                  string
+	Recurrence              string
+	PeriodiseringsfondShare float64
+	Expansionsfond          float64
+	MunicipalTaxRate        float64
+	StartDate               int64
+	Enabled                 bool
+	CreatedAt               int64
+	UpdatedAt               int64
+}
+
 type SpecialDate struct {
-	ID    string
-	Name  string
-	Date  string
-	Color *string
+	ID        string
+	Name      string
+	Date      string
+	Color     *string
+	PersonID  *string
+	PersonAge int64
+}
+
+type StartupExit struct {
+	AccountID          string
+	Date               int64
+	Kind               string
+	Multiple           string
+	FailureProbability float64
+	PayoutAccountID    string
+	CreatedAt          int64
+	UpdatedAt          int64
 }
 
 type StartupShareAccount struct {
 	AccountID               string
 	TaxRate                 float64
 	ValuationDiscountFactor float64
+	IncomeTaxRate           float64
 }
 
 type StartupShareOption struct {
-	ID                  string
-	AccountID           string
-	SourceAccountID     string
-	Shares              float64
-	StrikePricePerShare float64
-	GrantDate           int64
-	EndDate             int64
-	CreatedAt           int64
-	UpdatedAt           int64
+	ID                    string
+	AccountID             string
+	SourceAccountID       string
+	Shares                float64
+	StrikePricePerShare   float64
+	GrantDate             int64
+	EndDate               int64
+	CreatedAt             int64
+	UpdatedAt             int64
+	CliffMonths           int64
+	VestingMonths         int64
+	VestingIntervalMonths int64
+	AccelerateOnExit      bool
+	ExercisePolicy        string
+	TaxTreatment          string
 }
 
 type SweYearlyParam struct {
-	ID            string
-	Amount        float64
-	ValidFrom     int64
-	CreatedAt     int64
-	UpdatedAt     int64
-	Prisbasbelopp float64
-	SchablonRanta float64
-	IskFribelopp  float64
+	ID                  string
+	Amount              float64
+	ValidFrom           int64
+	CreatedAt           int64
+	UpdatedAt           int64
+	Prisbasbelopp       float64
+	SchablonRanta       float64
+	IskFribelopp        float64
+	StateTaxThreshold   float64
+	PublicServiceFeeMax float64
+	CsnRanta            float64
+	Statslaneranta      float64
 }
 
 type TransferTemplate struct {
@@ -236,6 +442,11 @@ type TransferTemplate struct {
 	CreatedAt        int64
 	UpdatedAt        int64
 	BudgetCategoryID *string
+	StartPersonID    *string
+	StartAge         int64
+	EndPersonID      *string
+	EndAge           int64
+	Currency         string
 }
 
 type TransferTemplateCategory struct {
@@ -246,9 +457,24 @@ type TransferTemplateCategory struct {
 	UpdatedAt int64
 }
 
+type Unemployment struct {
+	ID                     string
+	SalaryID               string
+	StartDate              int64
+	EndDate                int64
+	AkassaMember           bool
+	IncomeInsuranceCeiling float64
+	IncomeInsuranceDays    int64
+	CreatedAt              int64
+	UpdatedAt              int64
+}
+
 type User struct {
-	ID        string
-	Name      string
-	CreatedAt int64
-	UpdatedAt int64
+	ID           string
+	Name         string
+	CreatedAt    int64
+	UpdatedAt    int64
+	BirthDate    *int64
+	IsChild      bool
+	TaxAccountID *string
 }
@@ -0,0 +1,159 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: pension_payout.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deletePensionPayout = `-- name: DeletePensionPayout :exec
+DELETE FROM pension_payout
+WHERE account_id = ?
+`
+
+func (q *Queries) DeletePensionPayout(ctx context.Context, accountID string) error {
+	_, err := q.db.ExecContext(ctx, deletePensionPayout, accountID)
+	return err
+}
+
+const getPensionPayout = `-- name: GetPensionPayout :one
+SELECT account_id, to_account_id, start_date, birth_date, start_age, period_years, kommun, forsamling, church_member, created_at, updated_at
+FROM pension_payout
+WHERE account_id = ?
+`
+
+func (q *Queries) GetPensionPayout(ctx context.Context, accountID string) (PensionPayout, error) {
+	row := q.db.QueryRowContext(ctx, getPensionPayout, accountID)
+	var i PensionPayout
+	err := row.Scan(
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.StartDate,
+		&i.BirthDate,
+		&i.StartAge,
+		&i.PeriodYears,
+		&i.Kommun,
+		&i.Forsamling,
+		&i.ChurchMember,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listPensionPayouts = `-- name: ListPensionPayouts :many
+SELECT account_id, to_account_id, start_date, birth_date, start_age, period_years, kommun, forsamling, church_member, created_at, updated_at
+FROM pension_payout
+ORDER BY account_id
+`
+
+func (q *Queries) ListPensionPayouts(ctx context.Context) ([]PensionPayout, error) {
+	rows, err := q.db.QueryContext(ctx, listPensionPayouts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PensionPayout
+	for rows.Next() {
+		var i PensionPayout
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.ToAccountID,
+			&i.StartDate,
+			&i.BirthDate,
+			&i.StartAge,
+			&i.PeriodYears,
+			&i.Kommun,
+			&i.Forsamling,
+			&i.ChurchMember,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertPensionPayout = `-- name: UpsertPensionPayout :one
+INSERT INTO pension_payout (
+    account_id,
+    to_account_id,
+    start_date,
+    birth_date,
+    start_age,
+    period_years,
+    kommun,
+    forsamling,
+    church_member,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id) DO
+UPDATE
+SET to_account_id = EXCLUDED.to_account_id,
+  start_date = EXCLUDED.start_date,
+  birth_date = EXCLUDED.birth_date,
+  start_age = EXCLUDED.start_age,
+  period_years = EXCLUDED.period_years,
+  kommun = EXCLUDED.kommun,
+  forsamling = EXCLUDED.forsamling,
+  church_member = EXCLUDED.church_member,
+  updated_at = EXCLUDED.updated_at
+RETURNING account_id, to_account_id, start_date, birth_date, start_age, period_years, kommun, forsamling, church_member, created_at, updated_at
+`
+
+type UpsertPensionPayoutParams struct {
+	AccountID    string
+	ToAccountID  *string
+	StartDate    int64
+	BirthDate    *int64
+	StartAge     *int64
+	PeriodYears  int64
+	Kommun       string
+	Forsamling   string
+	ChurchMember bool
+	CreatedAt    int64
+	UpdatedAt    int64
+}
+
+func (q *Queries) UpsertPensionPayout(ctx context.Context, arg UpsertPensionPayoutParams) (PensionPayout, error) {
+	row := q.db.QueryRowContext(ctx, upsertPensionPayout,
+		arg.AccountID,
+		arg.ToAccountID,
+		arg.StartDate,
+		arg.BirthDate,
+		arg.StartAge,
+		arg.PeriodYears,
+		arg.Kommun,
+		arg.Forsamling,
+		arg.ChurchMember,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i PensionPayout
+	err := row.Scan(
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.StartDate,
+		&i.BirthDate,
+		&i.StartAge,
+		&i.PeriodYears,
+		&i.Kommun,
+		&i.Forsamling,
+		&i.ChurchMember,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,145 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: person.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const clearAccountOwner = `-- name: ClearAccountOwner :exec
+UPDATE account
+SET owner_id = NULL
+WHERE owner_id = ?
+`
+
+// account.owner_id cascades on delete, so owned accounts are released first.
+func (q *Queries) ClearAccountOwner(ctx context.Context, ownerID *string) error {
+	_, err := q.db.ExecContext(ctx, clearAccountOwner, ownerID)
+	return err
+}
+
+const deletePerson = `-- name: DeletePerson :exec
+DELETE FROM user
+WHERE id = ?
+`
+
+func (q *Queries) DeletePerson(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deletePerson, id)
+	return err
+}
+
+const getPerson = `-- name: GetPerson :one
+SELECT id, name, created_at, updated_at, birth_date, is_child, tax_account_id
+FROM user
+WHERE id = ?
+`
+
+func (q *Queries) GetPerson(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getPerson, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BirthDate,
+		&i.IsChild,
+		&i.TaxAccountID,
+	)
+	return i, err
+}
+
+const listPersons = `-- name: ListPersons :many
+SELECT id, name, created_at, updated_at, birth_date, is_child, tax_account_id
+FROM user
+ORDER BY birth_date,
+  name,
+  id
+`
+
+func (q *Queries) ListPersons(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listPersons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.BirthDate,
+			&i.IsChild,
+			&i.TaxAccountID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertPerson = `-- name: UpsertPerson :one
+INSERT INTO user (
+    id,
+    name,
+    birth_date,
+    is_child,
+    tax_account_id,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET name = EXCLUDED.name,
+  birth_date = EXCLUDED.birth_date,
+  is_child = EXCLUDED.is_child,
+  tax_account_id = EXCLUDED.tax_account_id,
+  updated_at = EXCLUDED.updated_at
+RETURNING id, name, created_at, updated_at, birth_date, is_child, tax_account_id
+`
+
+type UpsertPersonParams struct {
+	ID           string
+	Name         string
+	BirthDate    *int64
+	IsChild      bool
+	TaxAccountID *string
+	CreatedAt    int64
+	UpdatedAt    int64
+}
+
+func (q *Queries) UpsertPerson(ctx context.Context, arg UpsertPersonParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, upsertPerson,
+		arg.ID,
+		arg.Name,
+		arg.BirthDate,
+		arg.IsChild,
+		arg.TaxAccountID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BirthDate,
+		&i.IsChild,
+		&i.TaxAccountID,
+	)
+	return i, err
+}
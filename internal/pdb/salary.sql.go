@@ -40,7 +40,7 @@ func (q *Queries) DeleteSalaryAmount(ctx context.Context, id string) error {
 }
 
 const getSalary = `-- name: GetSalary :one
-SELECT id, name, to_account_id, priority, recurrence, budget_category_id, enabled, created_at, updated_at, pension_account_id, kommun, forsamling, church_member, is_gross
+SELECT id, name, to_account_id, priority, recurrence, budget_category_id, enabled, created_at, updated_at, pension_account_id, kommun, forsamling, church_member, is_gross, tax_method, municipal_tax_rate, owner_id, parental_pay_rate, parental_pay_above_ceiling_rate, parental_pay_max_days, salary_exchange_amount, salary_exchange_uplift, secondary_employer, secondary_tax_rate
 FROM salary
 WHERE id = ?
 `
@@ -63,6 +63,16 @@ func (q *Queries) GetSalary(ctx context.Context, id string) (Salary, error) {
 		&i.Forsamling,
 		&i.ChurchMember,
 		&i.IsGross,
+		&i.TaxMethod,
+		&i.MunicipalTaxRate,
+		&i.OwnerID,
+		&i.ParentalPayRate,
+		&i.ParentalPayAboveCeilingRate,
+		&i.ParentalPayMaxDays,
+		&i.SalaryExchangeAmount,
+		&i.SalaryExchangeUplift,
+		&i.SecondaryEmployer,
+		&i.SecondaryTaxRate,
 	)
 	return i, err
 }
@@ -163,7 +173,7 @@ func (q *Queries) ListAllSalaryAmounts(ctx context.Context) ([]SalaryAmount, err
 }
 
 const listSalaries = `-- name: ListSalaries :many
-SELECT id, name, to_account_id, priority, recurrence, budget_category_id, enabled, created_at, updated_at, pension_account_id, kommun, forsamling, church_member, is_gross
+SELECT id, name, to_account_id, priority, recurrence, budget_category_id, enabled, created_at, updated_at, pension_account_id, kommun, forsamling, church_member, is_gross, tax_method, municipal_tax_rate, owner_id, parental_pay_rate, parental_pay_above_ceiling_rate, parental_pay_max_days, salary_exchange_amount, salary_exchange_uplift, secondary_employer, secondary_tax_rate
 FROM salary
 ORDER BY name, id
 `
@@ -192,6 +202,16 @@ func (q *Queries) ListSalaries(ctx context.Context) ([]Salary, error) {
 			&i.Forsamling,
 			&i.ChurchMember,
 			&i.IsGross,
+			&i.TaxMethod,
+			&i.MunicipalTaxRate,
+			&i.OwnerID,
+			&i.ParentalPayRate,
+			&i.ParentalPayAboveCeilingRate,
+			&i.ParentalPayMaxDays,
+			&i.SalaryExchangeAmount,
+			&i.SalaryExchangeUplift,
+			&i.SecondaryEmployer,
+			&i.SecondaryTaxRate,
 		); err != nil {
 			return nil, err
 		}
@@ -297,10 +317,20 @@ INSERT INTO salary (
     forsamling,
     church_member,
     is_gross,
+    tax_method,
+    municipal_tax_rate,
+    owner_id,
+    parental_pay_rate,
+    parental_pay_above_ceiling_rate,
+    parental_pay_max_days,
+    salary_exchange_amount,
+    salary_exchange_uplift,
+    secondary_employer,
+    secondary_tax_rate,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET name = EXCLUDED.name,
   to_account_id = EXCLUDED.to_account_id,
@@ -313,25 +343,45 @@ SET name = EXCLUDED.name,
   forsamling = EXCLUDED.forsamling,
   church_member = EXCLUDED.church_member,
   is_gross = EXCLUDED.is_gross,
+  tax_method = EXCLUDED.tax_method,
+  municipal_tax_rate = EXCLUDED.municipal_tax_rate,
+  owner_id = EXCLUDED.owner_id,
+  parental_pay_rate = EXCLUDED.parental_pay_rate,
+  parental_pay_above_ceiling_rate = EXCLUDED.parental_pay_above_ceiling_rate,
+  parental_pay_max_days = EXCLUDED.parental_pay_max_days,
+  salary_exchange_amount = EXCLUDED.salary_exchange_amount,
+  salary_exchange_uplift = EXCLUDED.salary_exchange_uplift,
+  secondary_employer = EXCLUDED.secondary_employer,
+  secondary_tax_rate = EXCLUDED.secondary_tax_rate,
   updated_at = EXCLUDED.updated_at
-RETURNING id, name, to_account_id, priority, recurrence, budget_category_id, enabled, created_at, updated_at, pension_account_id, kommun, forsamling, church_member, is_gross
+RETURNING id, name, to_account_id, priority, recurrence, budget_category_id, enabled, created_at, updated_at, pension_account_id, kommun, forsamling, church_member, is_gross, tax_method, municipal_tax_rate, owner_id, parental_pay_rate, parental_pay_above_ceiling_rate, parental_pay_max_days, salary_exchange_amount, salary_exchange_uplift, secondary_employer, secondary_tax_rate
 `
 
 type UpsertSalaryParams struct {
-	ID               string
-	Name             string
-	ToAccountID      *string
-	PensionAccountID *string
-	Priority         int64
-	Recurrence       string
-	BudgetCategoryID *string
-	Enabled          bool
-	Kommun           string
-	Forsamling       string
-	ChurchMember     bool
-	IsGross          bool
-	CreatedAt        int64
-	UpdatedAt        int64
+	ID                          string
+	Name                        string
+	ToAccountID                 *string
+	PensionAccountID            *string
+	Priority                    int64
+	Recurrence                  string
+	BudgetCategoryID            *string
+	Enabled                     bool
+	Kommun                      string
+	Forsamling                  string
+	ChurchMember                bool
+	IsGross                     bool
+	TaxMethod                   string
+	MunicipalTaxRate            float64
+	OwnerID                     *string
+	ParentalPayRate             float64
+	ParentalPayAboveCeilingRate float64
+	ParentalPayMaxDays          int64
+	SalaryExchangeAmount        float64
+	SalaryExchangeUplift        float64
+	SecondaryEmployer           bool
+	SecondaryTaxRate            float64
+	CreatedAt                   int64
+	UpdatedAt                   int64
 }
 
 func (q *Queries) UpsertSalary(ctx context.Context, arg UpsertSalaryParams) (Salary, error) {
@@ -348,6 +398,16 @@ func (q *Queries) UpsertSalary(ctx context.Context, arg UpsertSalaryParams) (Sal
 		arg.Forsamling,
 		arg.ChurchMember,
 		arg.IsGross,
+		arg.TaxMethod,
+		arg.MunicipalTaxRate,
+		arg.OwnerID,
+		arg.ParentalPayRate,
+		arg.ParentalPayAboveCeilingRate,
+		arg.ParentalPayMaxDays,
+		arg.SalaryExchangeAmount,
+		arg.SalaryExchangeUplift,
+		arg.SecondaryEmployer,
+		arg.SecondaryTaxRate,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -367,6 +427,16 @@ func (q *Queries) UpsertSalary(ctx context.Context, arg UpsertSalaryParams) (Sal
 		&i.Forsamling,
 		&i.ChurchMember,
 		&i.IsGross,
+		&i.TaxMethod,
+		&i.MunicipalTaxRate,
+		&i.OwnerID,
+		&i.ParentalPayRate,
+		&i.ParentalPayAboveCeilingRate,
+		&i.ParentalPayMaxDays,
+		&i.SalaryExchangeAmount,
+		&i.SalaryExchangeUplift,
+		&i.SecondaryEmployer,
+		&i.SecondaryTaxRate,
 	)
 	return i, err
 }
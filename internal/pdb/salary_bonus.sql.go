@@ -0,0 +1,162 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: salary_bonus.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteSalaryBonus = `-- name: DeleteSalaryBonus :exec
+DELETE FROM salary_bonus
+WHERE id = ?
+`
+
+func (q *Queries) DeleteSalaryBonus(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteSalaryBonus, id)
+	return err
+}
+
+const listAllSalaryBonuses = `-- name: ListAllSalaryBonuses :many
+SELECT id, salary_id, name, amount, probability, pay_date, yearly, created_at, updated_at
+FROM salary_bonus
+ORDER BY salary_id, pay_date, id
+`
+
+func (q *Queries) ListAllSalaryBonuses(ctx context.Context) ([]SalaryBonus, error) {
+	rows, err := q.db.QueryContext(ctx, listAllSalaryBonuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SalaryBonus
+	for rows.Next() {
+		var i SalaryBonus
+		if err := rows.Scan(
+			&i.ID,
+			&i.SalaryID,
+			&i.Name,
+			&i.Amount,
+			&i.Probability,
+			&i.PayDate,
+			&i.Yearly,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSalaryBonuses = `-- name: ListSalaryBonuses :many
+SELECT id, salary_id, name, amount, probability, pay_date, yearly, created_at, updated_at
+FROM salary_bonus
+WHERE salary_id = ?
+ORDER BY pay_date, id
+`
+
+func (q *Queries) ListSalaryBonuses(ctx context.Context, salaryID string) ([]SalaryBonus, error) {
+	rows, err := q.db.QueryContext(ctx, listSalaryBonuses, salaryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SalaryBonus
+	for rows.Next() {
+		var i SalaryBonus
+		if err := rows.Scan(
+			&i.ID,
+			&i.SalaryID,
+			&i.Name,
+			&i.Amount,
+			&i.Probability,
+			&i.PayDate,
+			&i.Yearly,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertSalaryBonus = `-- name: UpsertSalaryBonus :one
+INSERT INTO salary_bonus (
+    id,
+    salary_id,
+    name,
+    amount,
+    probability,
+    pay_date,
+    yearly,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET salary_id = EXCLUDED.salary_id,
+  name = EXCLUDED.name,
+  amount = EXCLUDED.amount,
+  probability = EXCLUDED.probability,
+  pay_date = EXCLUDED.pay_date,
+  yearly = EXCLUDED.yearly,
+  updated_at = EXCLUDED.updated_at
+RETURNING id, salary_id, name, amount, probability, pay_date, yearly, created_at, updated_at
+`
+
+type UpsertSalaryBonusParams struct {
+	ID          string
+	SalaryID    string
+	Name        string
+	Amount      string
+	Probability float64
+	PayDate     int64
+	Yearly      bool
+	CreatedAt   int64
+	UpdatedAt   int64
+}
+
+func (q *Queries) UpsertSalaryBonus(ctx context.Context, arg UpsertSalaryBonusParams) (SalaryBonus, error) {
+	row := q.db.QueryRowContext(ctx, upsertSalaryBonus,
+		arg.ID,
+		arg.SalaryID,
+		arg.Name,
+		arg.Amount,
+		arg.Probability,
+		arg.PayDate,
+		arg.Yearly,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i SalaryBonus
+	err := row.Scan(
+		&i.ID,
+		&i.SalaryID,
+		&i.Name,
+		&i.Amount,
+		&i.Probability,
+		&i.PayDate,
+		&i.Yearly,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,162 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: sick_leave.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteSickLeave = `-- name: DeleteSickLeave :exec
+DELETE FROM sick_leave
+WHERE id = ?
+`
+
+func (q *Queries) DeleteSickLeave(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteSickLeave, id)
+	return err
+}
+
+const listAllSickLeaves = `-- name: ListAllSickLeaves :many
+SELECT id, salary_id, start_date, end_date, extent, employer_top_up_rate, employer_top_up_days, created_at, updated_at
+FROM sick_leave
+ORDER BY salary_id, start_date, id
+`
+
+func (q *Queries) ListAllSickLeaves(ctx context.Context) ([]SickLeave, error) {
+	rows, err := q.db.QueryContext(ctx, listAllSickLeaves)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SickLeave
+	for rows.Next() {
+		var i SickLeave
+		if err := rows.Scan(
+			&i.ID,
+			&i.SalaryID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Extent,
+			&i.EmployerTopUpRate,
+			&i.EmployerTopUpDays,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSickLeaves = `-- name: ListSickLeaves :many
+SELECT id, salary_id, start_date, end_date, extent, employer_top_up_rate, employer_top_up_days, created_at, updated_at
+FROM sick_leave
+WHERE salary_id = ?
+ORDER BY start_date, id
+`
+
+func (q *Queries) ListSickLeaves(ctx context.Context, salaryID string) ([]SickLeave, error) {
+	rows, err := q.db.QueryContext(ctx, listSickLeaves, salaryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SickLeave
+	for rows.Next() {
+		var i SickLeave
+		if err := rows.Scan(
+			&i.ID,
+			&i.SalaryID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Extent,
+			&i.EmployerTopUpRate,
+			&i.EmployerTopUpDays,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertSickLeave = `-- name: UpsertSickLeave :one
+INSERT INTO sick_leave (
+    id,
+    salary_id,
+    start_date,
+    end_date,
+    extent,
+    employer_top_up_rate,
+    employer_top_up_days,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET salary_id = EXCLUDED.salary_id,
+  start_date = EXCLUDED.start_date,
+  end_date = EXCLUDED.end_date,
+  extent = EXCLUDED.extent,
+  employer_top_up_rate = EXCLUDED.employer_top_up_rate,
+  employer_top_up_days = EXCLUDED.employer_top_up_days,
+  updated_at = EXCLUDED.updated_at
+RETURNING id, salary_id, start_date, end_date, extent, employer_top_up_rate, employer_top_up_days, created_at, updated_at
+`
+
+type UpsertSickLeaveParams struct {
+	ID                string
+	SalaryID          string
+	StartDate         int64
+	EndDate           int64
+	Extent            float64
+	EmployerTopUpRate float64
+	EmployerTopUpDays int64
+	CreatedAt         int64
+	UpdatedAt         int64
+}
+
+func (q *Queries) UpsertSickLeave(ctx context.Context, arg UpsertSickLeaveParams) (SickLeave, error) {
+	row := q.db.QueryRowContext(ctx, upsertSickLeave,
+		arg.ID,
+		arg.SalaryID,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Extent,
+		arg.EmployerTopUpRate,
+		arg.EmployerTopUpDays,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i SickLeave
+	err := row.Scan(
+		&i.ID,
+		&i.SalaryID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Extent,
+		&i.EmployerTopUpRate,
+		&i.EmployerTopUpDays,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,173 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: sole_proprietorship.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteSoleProprietorship = `-- name: DeleteSoleProprietorship :exec
+DELETE FROM sole_proprietorship
+WHERE id = ?
+`
+
+func (q *Queries) DeleteSoleProprietorship(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteSoleProprietorship, id)
+	return err
+}
+
+const getSoleProprietorship = `-- name: GetSoleProprietorship :one
+SELECT id, name, owner_id, to_account_id, profit, recurrence, periodiseringsfond_share, expansionsfond, municipal_tax_rate, start_date, enabled, created_at, updated_at
+FROM sole_proprietorship
+WHERE id = ?
+`
+
+func (q *Queries) GetSoleProprietorship(ctx context.Context, id string) (SoleProprietorship, error) {
+	row := q.db.QueryRowContext(ctx, getSoleProprietorship, id)
+	var i SoleProprietorship
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerID,
+		&i.ToAccountID,
+		&i.Profit,
+		&i.Recurrence,
+		&i.PeriodiseringsfondShare,
+		&i.Expansionsfond,
+		&i.MunicipalTaxRate,
+		&i.StartDate,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listSoleProprietorships = `-- name: ListSoleProprietorships :many
+SELECT id, name, owner_id, to_account_id, profit, recurrence, periodiseringsfond_share, expansionsfond, municipal_tax_rate, start_date, enabled, created_at, updated_at
+FROM sole_proprietorship
+ORDER BY name, id
+`
+
+func (q *Queries) ListSoleProprietorships(ctx context.Context) ([]SoleProprietorship, error) {
+	rows, err := q.db.QueryContext(ctx, listSoleProprietorships)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SoleProprietorship
+	for rows.Next() {
+		var i SoleProprietorship
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OwnerID,
+			&i.ToAccountID,
+			&i.Profit,
+			&i.Recurrence,
+			&i.PeriodiseringsfondShare,
+			&i.Expansionsfond,
+			&i.MunicipalTaxRate,
+			&i.StartDate,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertSoleProprietorship = `-- name: UpsertSoleProprietorship :one
+INSERT INTO sole_proprietorship (
+    id,
+    name,
+    owner_id,
+    to_account_id,
+    profit,
+    recurrence,
+    periodiseringsfond_share,
+    expansionsfond,
+    municipal_tax_rate,
+    start_date,
+    enabled,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET name = EXCLUDED.name,
+  owner_id = EXCLUDED.owner_id,
+  to_account_id = EXCLUDED.to_account_id,
+  profit = EXCLUDED.profit,
+  recurrence = EXCLUDED.recurrence,
+  periodiseringsfond_share = EXCLUDED.periodiseringsfond_share,
+  expansionsfond = EXCLUDED.expansionsfond,
+  municipal_tax_rate = EXCLUDED.municipal_tax_rate,
+  start_date = EXCLUDED.start_date,
+  enabled = EXCLUDED.enabled,
+  updated_at = EXCLUDED.updated_at
+RETURNING id, name, owner_id, to_account_id, profit, recurrence, periodiseringsfond_share, expansionsfond, municipal_tax_rate, start_date, enabled, created_at, updated_at
+`
+
+type UpsertSoleProprietorshipParams struct {
+	ID                      string
+	Name                    string
+	OwnerID                 *string
+	ToAccountID             string
+	Profit                  string
+	Recurrence              string
+	PeriodiseringsfondShare float64
+	Expansionsfond          float64
+	MunicipalTaxRate        float64
+	StartDate               int64
+	Enabled                 bool
+	CreatedAt               int64
+	UpdatedAt               int64
+}
+
+func (q *Queries) UpsertSoleProprietorship(ctx context.Context, arg UpsertSoleProprietorshipParams) (SoleProprietorship, error) {
+	row := q.db.QueryRowContext(ctx, upsertSoleProprietorship,
+		arg.ID,
+		arg.Name,
+		arg.OwnerID,
+		arg.ToAccountID,
+		arg.Profit,
+		arg.Recurrence,
+		arg.PeriodiseringsfondShare,
+		arg.Expansionsfond,
+		arg.MunicipalTaxRate,
+		arg.StartDate,
+		arg.Enabled,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i SoleProprietorship
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerID,
+		&i.ToAccountID,
+		&i.Profit,
+		&i.Recurrence,
+		&i.PeriodiseringsfondShare,
+		&i.Expansionsfond,
+		&i.MunicipalTaxRate,
+		&i.StartDate,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -20,7 +20,7 @@ func (q *Queries) DeleteSweYearlyParams(ctx context.Context, id string) error {
 }
 
 const getSweYearlyParams = `-- name: GetSweYearlyParams :one
-SELECT id, amount, valid_from, created_at, updated_at, prisbasbelopp, schablon_ranta, isk_fribelopp
+SELECT id, amount, valid_from, created_at, updated_at, prisbasbelopp, schablon_ranta, isk_fribelopp, state_tax_threshold, public_service_fee_max, csn_ranta, statslaneranta
 FROM swe_yearly_params
 WHERE id = ?
 `
@@ -37,12 +37,16 @@ func (q *Queries) GetSweYearlyParams(ctx context.Context, id string) (SweYearlyP
 		&i.Prisbasbelopp,
 		&i.SchablonRanta,
 		&i.IskFribelopp,
+		&i.StateTaxThreshold,
+		&i.PublicServiceFeeMax,
+		&i.CsnRanta,
+		&i.Statslaneranta,
 	)
 	return i, err
 }
 
 const listSweYearlyParams = `-- name: ListSweYearlyParams :many
-SELECT id, amount, valid_from, created_at, updated_at, prisbasbelopp, schablon_ranta, isk_fribelopp
+SELECT id, amount, valid_from, created_at, updated_at, prisbasbelopp, schablon_ranta, isk_fribelopp, state_tax_threshold, public_service_fee_max, csn_ranta, statslaneranta
 FROM swe_yearly_params
 ORDER BY valid_from, id
 `
@@ -65,6 +69,10 @@ func (q *Queries) ListSweYearlyParams(ctx context.Context) ([]SweYearlyParam, er
 			&i.Prisbasbelopp,
 			&i.SchablonRanta,
 			&i.IskFribelopp,
+			&i.StateTaxThreshold,
+			&i.PublicServiceFeeMax,
+			&i.CsnRanta,
+			&i.Statslaneranta,
 		); err != nil {
 			return nil, err
 		}
@@ -86,30 +94,42 @@ INSERT INTO swe_yearly_params (
     prisbasbelopp,
     schablon_ranta,
     isk_fribelopp,
+    state_tax_threshold,
+    public_service_fee_max,
+    csn_ranta,
+    statslaneranta,
     valid_from,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET amount = EXCLUDED.amount,
   prisbasbelopp = EXCLUDED.prisbasbelopp,
   schablon_ranta = EXCLUDED.schablon_ranta,
   isk_fribelopp = EXCLUDED.isk_fribelopp,
+  state_tax_threshold = EXCLUDED.state_tax_threshold,
+  public_service_fee_max = EXCLUDED.public_service_fee_max,
+  csn_ranta = EXCLUDED.csn_ranta,
+  statslaneranta = EXCLUDED.statslaneranta,
   valid_from = EXCLUDED.valid_from,
   updated_at = EXCLUDED.updated_at
-RETURNING id, amount, valid_from, created_at, updated_at, prisbasbelopp, schablon_ranta, isk_fribelopp
+RETURNING id, amount, valid_from, created_at, updated_at, prisbasbelopp, schablon_ranta, isk_fribelopp, state_tax_threshold, public_service_fee_max, csn_ranta, statslaneranta
 `
 
 type UpsertSweYearlyParamsParams struct {
-	ID            string
-	Amount        float64
-	Prisbasbelopp float64
-	SchablonRanta float64
-	IskFribelopp  float64
-	ValidFrom     int64
-	CreatedAt     int64
-	UpdatedAt     int64
+	ID                  string
+	Amount              float64
+	Prisbasbelopp       float64
+	SchablonRanta       float64
+	IskFribelopp        float64
+	StateTaxThreshold   float64
+	PublicServiceFeeMax float64
+	CsnRanta            float64
+	Statslaneranta      float64
+	ValidFrom           int64
+	CreatedAt           int64
+	UpdatedAt           int64
 }
 
 func (q *Queries) UpsertSweYearlyParams(ctx context.Context, arg UpsertSweYearlyParamsParams) (SweYearlyParam, error) {
@@ -119,6 +139,10 @@ func (q *Queries) UpsertSweYearlyParams(ctx context.Context, arg UpsertSweYearly
 		arg.Prisbasbelopp,
 		arg.SchablonRanta,
 		arg.IskFribelopp,
+		arg.StateTaxThreshold,
+		arg.PublicServiceFeeMax,
+		arg.CsnRanta,
+		arg.Statslaneranta,
 		arg.ValidFrom,
 		arg.CreatedAt,
 		arg.UpdatedAt,
@@ -133,6 +157,10 @@ func (q *Queries) UpsertSweYearlyParams(ctx context.Context, arg UpsertSweYearly
 		&i.Prisbasbelopp,
 		&i.SchablonRanta,
 		&i.IskFribelopp,
+		&i.StateTaxThreshold,
+		&i.PublicServiceFeeMax,
+		&i.CsnRanta,
+		&i.Statslaneranta,
 	)
 	return i, err
 }
@@ -0,0 +1,162 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: unemployment.sql
+
+package pdb
+
+import (
+	"context"
+)
+
+const deleteUnemployment = `-- name: DeleteUnemployment :exec
+DELETE FROM unemployment
+WHERE id = ?
+`
+
+func (q *Queries) DeleteUnemployment(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteUnemployment, id)
+	return err
+}
+
+const listAllUnemployments = `-- name: ListAllUnemployments :many
+SELECT id, salary_id, start_date, end_date, akassa_member, income_insurance_ceiling, income_insurance_days, created_at, updated_at
+FROM unemployment
+ORDER BY salary_id, start_date, id
+`
+
+func (q *Queries) ListAllUnemployments(ctx context.Context) ([]Unemployment, error) {
+	rows, err := q.db.QueryContext(ctx, listAllUnemployments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Unemployment
+	for rows.Next() {
+		var i Unemployment
+		if err := rows.Scan(
+			&i.ID,
+			&i.SalaryID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.AkassaMember,
+			&i.IncomeInsuranceCeiling,
+			&i.IncomeInsuranceDays,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnemployments = `-- name: ListUnemployments :many
+SELECT id, salary_id, start_date, end_date, akassa_member, income_insurance_ceiling, income_insurance_days, created_at, updated_at
+FROM unemployment
+WHERE salary_id = ?
+ORDER BY start_date, id
+`
+
+func (q *Queries) ListUnemployments(ctx context.Context, salaryID string) ([]Unemployment, error) {
+	rows, err := q.db.QueryContext(ctx, listUnemployments, salaryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Unemployment
+	for rows.Next() {
+		var i Unemployment
+		if err := rows.Scan(
+			&i.ID,
+			&i.SalaryID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.AkassaMember,
+			&i.IncomeInsuranceCeiling,
+			&i.IncomeInsuranceDays,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertUnemployment = `-- name: UpsertUnemployment :one
+INSERT INTO unemployment (
+    id,
+    salary_id,
+    start_date,
+    end_date,
+    akassa_member,
+    income_insurance_ceiling,
+    income_insurance_days,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET salary_id = EXCLUDED.salary_id,
+  start_date = EXCLUDED.start_date,
+  end_date = EXCLUDED.end_date,
+  akassa_member = EXCLUDED.akassa_member,
+  income_insurance_ceiling = EXCLUDED.income_insurance_ceiling,
+  income_insurance_days = EXCLUDED.income_insurance_days,
+  updated_at = EXCLUDED.updated_at
+RETURNING id, salary_id, start_date, end_date, akassa_member, income_insurance_ceiling, income_insurance_days, created_at, updated_at
+`
+
+type UpsertUnemploymentParams struct {
+	ID                     string
+	SalaryID               string
+	StartDate              int64
+	EndDate                int64
+	AkassaMember           bool
+	IncomeInsuranceCeiling float64
+	IncomeInsuranceDays    int64
+	CreatedAt              int64
+	UpdatedAt              int64
+}
+
+func (q *Queries) UpsertUnemployment(ctx context.Context, arg UpsertUnemploymentParams) (Unemployment, error) {
+	row := q.db.QueryRowContext(ctx, upsertUnemployment,
+		arg.ID,
+		arg.SalaryID,
+		arg.StartDate,
+		arg.EndDate,
+		arg.AkassaMember,
+		arg.IncomeInsuranceCeiling,
+		arg.IncomeInsuranceDays,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Unemployment
+	err := row.Scan(
+		&i.ID,
+		&i.SalaryID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.AkassaMember,
+		&i.IncomeInsuranceCeiling,
+		&i.IncomeInsuranceDays,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
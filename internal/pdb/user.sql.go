@@ -12,7 +12,7 @@ import (
 const createUser = `-- name: CreateUser :one
 INSERT INTO user
     (id, name, created_at, updated_at)
-VALUES (?, ?, ?, ?) RETURNING id, name, created_at, updated_at
+VALUES (?, ?, ?, ?) RETURNING id, name, created_at, updated_at, birth_date, is_child, tax_account_id
 `
 
 type CreateUserParams struct {
@@ -35,6 +35,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BirthDate,
+		&i.IsChild,
+		&i.TaxAccountID,
 	)
 	return i, err
 }
@@ -42,7 +45,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 const deleteUser = `-- name: DeleteUser :one
 DELETE
 FROM user
-WHERE id = ? RETURNING id, name, created_at, updated_at
+WHERE id = ? RETURNING id, name, created_at, updated_at, birth_date, is_child, tax_account_id
 `
 
 func (q *Queries) DeleteUser(ctx context.Context, id string) (User, error) {
@@ -53,12 +56,15 @@ func (q *Queries) DeleteUser(ctx context.Context, id string) (User, error) {
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BirthDate,
+		&i.IsChild,
+		&i.TaxAccountID,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, name, created_at, updated_at
+SELECT id, name, created_at, updated_at, birth_date, is_child, tax_account_id
 FROM user
 WHERE id = ?
 `
@@ -71,12 +77,15 @@ func (q *Queries) GetUser(ctx context.Context, id string) (User, error) {
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BirthDate,
+		&i.IsChild,
+		&i.TaxAccountID,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, name, created_at, updated_at
+SELECT id, name, created_at, updated_at, birth_date, is_child, tax_account_id
 FROM user
 ORDER BY name, id
 `
@@ -95,6 +104,9 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.BirthDate,
+			&i.IsChild,
+			&i.TaxAccountID,
 		); err != nil {
 			return nil, err
 		}
@@ -113,7 +125,7 @@ const updateUser = `-- name: UpdateUser :one
 UPDATE user
 SET name       = ?,
     updated_at = ?
-WHERE id = ? RETURNING id, name, created_at, updated_at
+WHERE id = ? RETURNING id, name, created_at, updated_at, birth_date, is_child, tax_account_id
 `
 
 type UpdateUserParams struct {
@@ -130,6 +142,9 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BirthDate,
+		&i.IsChild,
+		&i.TaxAccountID,
 	)
 	return i, err
 }
@@ -67,7 +67,7 @@ func BudgetContent(view *BudgetView) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = Header("Monthly Budget").Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = Header("Monthly Budget", PersonFilter(view.Persons, view.PersonID)).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -66,7 +66,7 @@ func DashboardMainContent(view *DashboardView) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = Header("Dashboard").Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = Header("Dashboard", PersonFilter(view.Persons, view.PersonID)).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
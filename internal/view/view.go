@@ -6,6 +6,7 @@ import (
 	"net/url"
 
 	"github.com/SimonSchneider/pefigo/internal/model"
+	"github.com/SimonSchneider/pefigo/pkg/finance"
 	"github.com/SimonSchneider/pefigo/pkg/swe"
 	"github.com/a-h/templ"
 )
@@ -84,9 +85,20 @@ type (
 	ShareChangeInput                 = model.ShareChangeInput
 	StartupShareOption               = model.StartupShareOption
 	StartupShareOptionInput          = model.StartupShareOptionInput
+	ProjectedInvestmentRound         = model.ProjectedInvestmentRound
+	StartupExit                      = model.StartupExit
 	StartupShareAccount              = model.StartupShareAccount
 	StartupShareAccountInput         = model.StartupShareAccountInput
 	DerivedStartupShareSummary       = model.DerivedStartupShareSummary
+	PensionPayout                    = model.PensionPayout
+	InterestDeduction                = model.InterestDeduction
+	AmortizationRequirement          = model.AmortizationRequirement
+	InterestRateModel                = model.InterestRateModel
+	FXRateModel                      = model.FXRateModel
+	CSNLoan                          = model.CSNLoan
+	Person                           = model.Person
+	PersonAge                        = model.PersonAge
+	AccountOwner                     = model.AccountOwner
 	SpecialDate                      = model.SpecialDate
 	SpecialDateInput                 = model.SpecialDateInput
 	DashboardView                    = model.DashboardView
@@ -129,6 +141,9 @@ type (
 	SalaryEditView                   = model.SalaryEditView
 	PartialParentalLeave             = model.PartialParentalLeave
 	FullParentalLeave                = model.FullParentalLeave
+	Unemployment                     = model.Unemployment
+	SickLeave                        = model.SickLeave
+	SalaryBonus                      = model.SalaryBonus
 	SweYearlyParams                  = model.SweYearlyParams
 	TransferTemplateSource           = model.TransferTemplateSource
 	NetSalarySegmentBreakdown        = model.NetSalarySegmentBreakdown
@@ -139,6 +154,16 @@ type (
 	BillsPageData                    = model.BillsPageData
 	BillAccountEditView              = model.BillAccountEditView
 	BillEditView                     = model.BillEditView
+	Company                          = model.Company
+	CompanyEditView                  = model.CompanyEditView
+	CompanyPlanYear                  = model.CompanyPlanYear
+	SoleProprietorship               = model.SoleProprietorship
+	SoleProprietorshipEditView       = model.SoleProprietorshipEditView
+	SoleProprietorshipYear           = model.SoleProprietorshipYear
+	EquityGrant                      = model.EquityGrant
+	EquityGrantKind                  = model.EquityGrantKind
+	EquityGrantEditView              = model.EquityGrantEditView
+	EquityGrantYear                  = model.EquityGrantYear
 )
 
 const (
@@ -173,3 +198,44 @@ func billFaviconURL(bill Bill) string {
 func billCompanyName(bill Bill) string {
 	return model.ExtractCompanyName(bill.URL)
 }
+
+func optionExercisePolicies() []finance.StartupOptionExercisePolicy {
+	return model.StartupOptionExercisePolicies
+}
+
+func optionExercisePolicyLabel(policy finance.StartupOptionExercisePolicy) string {
+	return model.StartupOptionExercisePolicyLabel(policy)
+}
+
+func startupGrantTaxes() []finance.StartupGrantTax {
+	return model.StartupGrantTaxes
+}
+
+func startupGrantTaxLabel(treatment finance.StartupGrantTax) string {
+	return model.StartupGrantTaxLabel(treatment)
+}
+
+func equityGrantKinds() []EquityGrantKind {
+	return model.EquityGrantKinds
+}
+
+func equityGrantKindLabel(kind EquityGrantKind) string {
+	return model.EquityGrantKindLabel(kind)
+}
+
+func pensionPayoutPeriods() []int64 {
+	return model.PensionPayoutPeriods
+}
+
+func pensionPayoutPeriodLabel(years int64) string {
+	return model.PensionPayoutPeriodLabel(years)
+}
+
+// growthModelGridStyle lays out the growth model rows, with the rate model
+// and binding columns only when there are interest rate models to pick.
+func growthModelGridStyle(rateModels []InterestRateModel) templ.SafeCSS {
+	if len(rateModels) > 0 {
+		return "grid-template-columns: auto 1fr 1fr 1fr 1fr 1fr auto auto auto"
+	}
+	return "grid-template-columns: auto 1fr 1fr 1fr 1fr auto auto"
+}
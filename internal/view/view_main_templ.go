@@ -241,6 +241,9 @@ func SidebarContent(page string) templ.Component {
 			NavItem("/transfer-templates", IconTransfer("w-5 h-5"), "Transfer Templates", page),
 			NavItem("/salaries", IconCoin("w-5 h-5"), "Salaries", page),
 			NavItem("/bills", IconReceipt("w-5 h-5"), "Bills", page),
+			NavItem("/companies", IconBuilding("w-5 h-5"), "Companies", page),
+			NavItem("/sole-proprietorships", IconBriefcase("w-5 h-5"), "Sole Proprietorships", page),
+			NavItem("/equity-grants", IconChartCandle("w-5 h-5"), "Equity Grants", page),
 			NavItem("/budget", IconCashBanknote("w-5 h-5"), "Budget", page),
 			NavItem("/transfers", IconTransfer("w-5 h-5"), "Transfer Calculator", page),
 		).Render(ctx, templ_7745c5c3_Buffer)
@@ -337,7 +340,7 @@ func Header(title string, btns ...templ.Component) templ.Component {
 		var templ_7745c5c3_Var11 string
 		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(title)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/view/view_main.templ`, Line: 152, Col: 59}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/view/view_main.templ`, Line: 155, Col: 59}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
 		if templ_7745c5c3_Err != nil {
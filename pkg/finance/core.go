@@ -3,6 +3,7 @@ package finance
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 
 	"github.com/SimonSchneider/goslu/date"
@@ -19,17 +20,99 @@ type CashFlowModel struct {
 	DestinationID string
 }
 
+// PayoutModel drains an entity in equal parts over a payout period. Each
+// payout is the current balance divided by the number of remaining payouts,
+// so growth during the period is paid out as well. A payout without an
+// EndDate never ends, and is divided by RemainingPayouts instead.
+type PayoutModel struct {
+	StartDate     date.Date
+	EndDate       date.Date // Exclusive, no payouts are made on or after this date
+	Frequency     date.Cron
+	DestinationID string
+	// RemainingPayouts returns the number of payouts to spread the balance
+	// over on day, for payouts without an EndDate.
+	RemainingPayouts func(day date.Date) float64
+	// Net converts a gross payout into the amount received by the destination, for example after income tax.
+	// Optional, if not set the gross amount is paid out.
+	Net func(day date.Date, gross float64) float64
+}
+
+func (pm *PayoutModel) remainingPayouts(day date.Date) int {
+	n := 0
+	for d := range date.Iter(day, pm.EndDate, date.Day) {
+		if pm.Frequency.Matches(d) {
+			n++
+		}
+	}
+	return n
+}
+
+// AmortizationModel pays down a loan entity from a source entity. The yearly
+// amount is determined on the first payment and redetermined on each
+// revaluation date, and spread evenly over the payments of a year. Payments
+// never exceed the remaining loan.
+type AmortizationModel struct {
+	Frequency    date.Cron
+	SourceID     string
+	Revaluations []date.Date // Sorted dates on which the yearly amount is redetermined
+	// YearlyAmount returns the yearly amortization, given the current balance of any entity.
+	// Balances and the amount are in the base currency.
+	YearlyAmount func(day date.Date, balance func(id string) float64) float64
+}
+
+func (am *AmortizationModel) revaluedBetween(from, to date.Date) bool {
+	i := sort.Search(len(am.Revaluations), func(i int) bool { return am.Revaluations[i].After(from) })
+	return i < len(am.Revaluations) && !am.Revaluations[i].After(to)
+}
+
+func (am *AmortizationModel) paymentsPerYear(day date.Date) int {
+	n := 0
+	for d := range date.Iter(day, day.Add(date.Year), date.Day) {
+		if am.Frequency.Matches(d) {
+			n++
+		}
+	}
+	return max(1, n)
+}
+
 type BalanceLimit struct {
 	Upper uncertain.Value // Optional upper limit, if not set, no limit is applied
 }
 
+// TaxModel returns the tax to withdraw from the balance on a day. A negative
+// amount is a refund and is added to the balance. The balance, deposits and
+// tax are in the base currency.
 type TaxModel interface {
 	Apply(ucfg *uncertain.Config, day date.Date, balance uncertain.Value, dayDeposits uncertain.Value) uncertain.Value
 }
 
+// TaxModels combines several tax models on one entity, e.g. ISK tax and a
+// yearly tax refund paid into the same account.
+type TaxModels []TaxModel
+
+func (tms TaxModels) Apply(ucfg *uncertain.Config, day date.Date, balance uncertain.Value, dayDeposits uncertain.Value) uncertain.Value {
+	var total uncertain.Value
+	for _, tm := range tms {
+		tax := tm.Apply(ucfg, day, balance, dayDeposits)
+		if tax.Zero() {
+			continue
+		}
+		if total.Zero() {
+			total = tax
+		} else {
+			total = total.Add(ucfg, tax)
+		}
+	}
+	return total
+}
+
 type Entity struct {
 	ID   string
 	Name string
+	// FX is the currency the balance is kept in, nil for the base currency.
+	// Transfers into and out of the entity are converted to it, and its
+	// balance is recorded in the base currency.
+	FX *FXRateProcess
 
 	BalanceLimit BalanceLimit // Optional balance limit, if not set, no limit is applied
 
@@ -39,6 +122,9 @@ type Entity struct {
 	GrowthModel GrowthModel
 	CashFlow    *CashFlowModel // Optional cash flow model, if not set, no cash flow is applied
 	TaxModel    TaxModel       // Optional tax model, if not set, no tax is applied
+	Payout      *PayoutModel   // Optional payout model, if not set, the balance is never paid out
+	// Optional amortization model, if not set, the loan is only paid down by transfers
+	Amortization *AmortizationModel
 }
 
 func (fe *Entity) GetLatestSnapshot(day date.Date) BalanceSnapshot {
@@ -59,6 +145,9 @@ type ModeledEntity struct {
 	balance             uncertain.Value
 	accruedAppreciation uncertain.Value
 	dayDeposits         uncertain.Value // deposits received today, reset each day
+	payoutsLeft         int             // remaining payouts including the next one, 0 until the first payout
+	amortization        uncertain.Value // amortization per payment, unset until the first payment
+	amortizedAt         date.Date       // date the amortization was last determined
 }
 
 func (fe *ModeledEntity) Init(day date.Date) {
@@ -67,9 +156,9 @@ func (fe *ModeledEntity) Init(day date.Date) {
 	fe.balance = latestSnapshot.Balance
 }
 
-func (fe *ModeledEntity) ApplyGrowth(ucfg *uncertain.Config, entities map[string]*ModeledEntity, date date.Date) {
+func (fe *ModeledEntity) ApplyGrowth(ucfg *uncertain.Config, entities map[string]*ModeledEntity, date date.Date, recorder TransferRecorder, gainRecorder CapitalGainRecorder) error {
 	if fe.GrowthModel == nil || !fe.GrowthModel.IsActiveOn(date) {
-		return // No growth model or not active on this date
+		return nil // No growth model or not active on this date
 	}
 	if fe.accruedAppreciation.Distribution == "" {
 		fe.accruedAppreciation = uncertain.NewFixed(0.0) // Initialize if not set
@@ -77,11 +166,24 @@ func (fe *ModeledEntity) ApplyGrowth(ucfg *uncertain.Config, entities map[string
 	totalBalance := fe.balance.Add(ucfg, fe.accruedAppreciation)
 	dailyGrowth := fe.GrowthModel.Apply(ucfg, date, entities, totalBalance)
 	fe.accruedAppreciation = fe.accruedAppreciation.Add(ucfg, dailyGrowth)
+	if sg, ok := fe.GrowthModel.(*StartupGrowth); ok {
+		if exit, ok := sg.takeExitPayout(); ok {
+			if err := fe.payTo(ucfg, entities, date, exit.accountID, exit.amount, recorder); err != nil {
+				return fmt.Errorf("failed to record exit payout from %s to %s on %s: %w", fe.ID, exit.accountID, date, err)
+			}
+			if gainRecorder != nil {
+				if err := gainRecorder.OnCapitalGain(fe.ID, date, convertFX(ucfg, date, exit.gain, fe.FX, nil)); err != nil {
+					return fmt.Errorf("failed to record capital gain of %s on %s: %w", fe.ID, date, err)
+				}
+			}
+		}
+	}
+	return nil
 }
 
-func (fe *ModeledEntity) ApplyAppreciation(ucfg *uncertain.Config, entities map[string]*ModeledEntity, day date.Date) {
+func (fe *ModeledEntity) ApplyAppreciation(ucfg *uncertain.Config, entities map[string]*ModeledEntity, day date.Date, recorder InterestRecorder) error {
 	if fe.accruedAppreciation.Zero() {
-		return
+		return nil
 	}
 	if fe.CashFlow == nil || (fe.CashFlow.Frequency.Matches(day) && fe.CashFlow.DestinationID == "") {
 		fe.balance = fe.balance.Add(ucfg, fe.accruedAppreciation)
@@ -90,17 +192,130 @@ func (fe *ModeledEntity) ApplyAppreciation(ucfg *uncertain.Config, entities map[
 		// If a destination account is specified, add interest to that account
 		if destAccount, ok := entities[fe.CashFlow.DestinationID]; ok {
 			if destAccount.lastSnapshotDate.Before(day) {
-				destAccount.balance = destAccount.balance.Add(ucfg, fe.accruedAppreciation)
+				destAccount.balance = destAccount.balance.Add(ucfg, convertFX(ucfg, day, fe.accruedAppreciation, fe.FX, destAccount.FX))
 			}
 		} else {
 			panic("Could not find account with ID " + fe.CashFlow.DestinationID)
 		}
+		if recorder != nil {
+			if err := recorder.OnInterest(fe.ID, fe.CashFlow.DestinationID, day, convertFX(ucfg, day, fe.accruedAppreciation, fe.FX, nil)); err != nil {
+				return fmt.Errorf("failed to record interest from %s to %s on %s: %w", fe.ID, fe.CashFlow.DestinationID, day, err)
+			}
+		}
 		fe.accruedAppreciation = uncertain.NewFixed(0.0)
 	}
+	return nil
+}
+
+func (fe *ModeledEntity) ApplyPayout(ucfg *uncertain.Config, entities map[string]*ModeledEntity, day date.Date, recorder TransferRecorder) error {
+	pm := fe.Payout
+	if pm == nil || day.Before(pm.StartDate) || (!pm.EndDate.IsZero() && !day.Before(pm.EndDate)) || !pm.Frequency.Matches(day) {
+		return nil
+	}
+	var share float64
+	if pm.EndDate.IsZero() {
+		share = 1 / math.Max(1, pm.RemainingPayouts(day))
+	} else {
+		if fe.payoutsLeft <= 0 {
+			fe.payoutsLeft = pm.remainingPayouts(day)
+		}
+		share = 1 / float64(fe.payoutsLeft)
+		fe.payoutsLeft--
+	}
+	gross := fe.balance.ApplyFixed(ucfg, share, func(a, b float64) float64 { return math.Max(0, a*b) })
+	fe.balance = fe.balance.Sub(ucfg, gross)
+	net := gross
+	if pm.Net != nil {
+		net = uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+			return pm.Net(day, gross.Sample(cfg))
+		})
+	}
+	if err := fe.payTo(ucfg, entities, day, pm.DestinationID, net, recorder); err != nil {
+		return fmt.Errorf("failed to record payout from %s to %s on %s: %w", fe.ID, pm.DestinationID, day, err)
+	}
+	return nil
+}
+
+// payTo records amount, in the currency of the entity, as paid to the
+// destination and deposits it there.
+func (fe *ModeledEntity) payTo(ucfg *uncertain.Config, entities map[string]*ModeledEntity, day date.Date, destinationID string, amount uncertain.Value, recorder TransferRecorder) error {
+	if err := recorder.OnTransfer(fe.ID, destinationID, day, convertFX(ucfg, day, amount, fe.FX, nil)); err != nil {
+		return err
+	}
+	if destAccount, ok := entities[destinationID]; ok {
+		received := convertFX(ucfg, day, amount, fe.FX, destAccount.FX)
+		destAccount.balance = destAccount.balance.Add(ucfg, received)
+		destAccount.dayDeposits = destAccount.dayDeposits.Add(ucfg, received)
+	}
+	return nil
+}
+
+func (fe *ModeledEntity) ApplyAmortization(ucfg *uncertain.Config, entities map[string]*ModeledEntity, day date.Date, recorder TransferRecorder) error {
+	am := fe.Amortization
+	if am == nil || !am.Frequency.Matches(day) {
+		return nil
+	}
+	if fe.amortization.Distribution == "" || am.revaluedBetween(fe.amortizedAt, day) {
+		rates := make(map[string]uncertain.Value)
+		for id, e := range entities {
+			if e.FX != nil {
+				rates[id] = e.FX.RateOn(ucfg, day)
+			}
+		}
+		yearly := uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+			return am.YearlyAmount(day, func(id string) float64 {
+				e, ok := entities[id]
+				if !ok {
+					return 0
+				}
+				if rate, ok := rates[id]; ok {
+					return e.balance.Sample(cfg) * rate.Sample(cfg)
+				}
+				return e.balance.Sample(cfg)
+			})
+		}).Materialize(ucfg)
+		yearly = convertFX(ucfg, day, yearly, nil, fe.FX)
+		fe.amortization = yearly.ApplyFixed(ucfg, float64(am.paymentsPerYear(day)), func(a, b float64) float64 { return a / b })
+		fe.amortizedAt = day
+	}
+	balance, payment := payDown(ucfg, fe.balance, fe.amortization)
+	if payment.Zero() || payment.Mean() == 0 {
+		return nil // Nothing required or the loan is paid off
+	}
+	fe.balance = balance
+	if source, ok := entities[am.SourceID]; ok {
+		source.balance = source.balance.Sub(ucfg, convertFX(ucfg, day, payment, fe.FX, source.FX))
+	}
+	if err := recorder.OnTransfer(am.SourceID, fe.ID, day, convertFX(ucfg, day, payment, fe.FX, nil)); err != nil {
+		return fmt.Errorf("failed to record amortization from %s to %s on %s: %w", am.SourceID, fe.ID, day, err)
+	}
+	return nil
+}
+
+// payDown pays amount off the loan balance, capped at what is owed. Each
+// payment is drawn together with the balance it reduces, so no draw of the
+// loan is paid past zero. It returns the new balance and the payments made.
+func payDown(ucfg *uncertain.Config, balance, amount uncertain.Value) (uncertain.Value, uncertain.Value) {
+	pay := func(bal, p float64) float64 { return math.Max(0, math.Min(p, -bal)) }
+	if balance.IsFixed() && amount.IsFixed() {
+		p := pay(balance.Fixed.Value, amount.Fixed.Value)
+		return uncertain.NewFixed(balance.Fixed.Value + p), uncertain.NewFixed(p)
+	}
+	bals := make([]float64, ucfg.Samples)
+	paid := make([]float64, ucfg.Samples)
+	for i := range bals {
+		bal := balance.Sample(ucfg)
+		paid[i] = pay(bal, amount.Sample(ucfg))
+		bals[i] = bal + paid[i]
+	}
+	return uncertain.Value{Distribution: uncertain.DistEmpirical, Samples: bals},
+		uncertain.Value{Distribution: uncertain.DistEmpirical, Samples: paid}
 }
 
 func RunPrediction(ctx context.Context, ucfg *uncertain.Config, from, to date.Date, snapshotCron date.Cron, financialEntities []Entity, transfers []TransferTemplate, recorder Recorder) error {
 	dailyTransfers := make([]TransferTemplate, 0)
+	interestRecorder, _ := recorder.(InterestRecorder)
+	gainRecorder, _ := recorder.(CapitalGainRecorder)
 	fes := make(map[string]*ModeledEntity)
 	earliestDate := from
 	for _, fe := range financialEntities {
@@ -112,6 +327,15 @@ func RunPrediction(ctx context.Context, ucfg *uncertain.Config, from, to date.Da
 			earliestDate = mfe.lastSnapshotDate // Find the earliest date across all financialEntities
 		}
 		fes[fe.ID] = mfe
+		// Start the rate paths today, also for entities simulated from an
+		// earlier snapshot.
+		fe.FX.RateOn(ucfg, from)
+		for _, p := range rateProcesses(fe.GrowthModel) {
+			p.RateOn(ucfg, from)
+		}
+	}
+	for _, transfer := range transfers {
+		transfer.FX.RateOn(ucfg, from)
 	}
 	for day := range date.Iter(earliestDate, to, date.Day) {
 		for _, fe := range fes {
@@ -129,30 +353,44 @@ func RunPrediction(ctx context.Context, ucfg *uncertain.Config, from, to date.Da
 					return fmt.Errorf("failed to apply daily transfers: %w", err)
 				}
 			}
+			for _, fe := range fes {
+				if err := fe.ApplyPayout(ucfg, fes, day, recorder); err != nil {
+					return fmt.Errorf("failed to apply payout: %w", err)
+				}
+				if err := fe.ApplyAmortization(ucfg, fes, day, recorder); err != nil {
+					return fmt.Errorf("failed to apply amortization: %w", err)
+				}
+			}
 		}
 
 		for _, fe := range fes {
 			if fe.lastSnapshotDate.Before(day) {
-				fe.ApplyGrowth(ucfg, fes, day)
+				if err := fe.ApplyGrowth(ucfg, fes, day, recorder, gainRecorder); err != nil {
+					return fmt.Errorf("failed to apply growth: %w", err)
+				}
 			}
 		}
 		for _, fe := range fes {
 			if fe.lastSnapshotDate.Before(day) {
-				fe.ApplyAppreciation(ucfg, fes, day)
+				if err := fe.ApplyAppreciation(ucfg, fes, day, interestRecorder); err != nil {
+					return fmt.Errorf("failed to apply appreciation: %w", err)
+				}
 			}
 		}
 		for _, fe := range fes {
 			if fe.TaxModel != nil && fe.lastSnapshotDate.Before(day) {
-				tax := fe.TaxModel.Apply(ucfg, day, fe.balance, fe.dayDeposits)
+				balance := convertFX(ucfg, day, fe.balance, fe.FX, nil)
+				deposits := convertFX(ucfg, day, fe.dayDeposits, fe.FX, nil)
+				tax := fe.TaxModel.Apply(ucfg, day, balance, deposits)
 				if !tax.Zero() {
-					fe.balance = fe.balance.Sub(ucfg, tax)
+					fe.balance = fe.balance.Sub(ucfg, convertFX(ucfg, day, tax, nil, fe.FX))
 				}
 			}
 		}
 		if snapshotCron.Matches(day) {
 			for _, fe := range fes {
 				if fe.lastSnapshotDate.Before(day) {
-					if err := recorder.OnSnapshot(fe.ID, day, fe.balance); err != nil {
+					if err := recorder.OnSnapshot(fe.ID, day, convertFX(ucfg, day, fe.balance, fe.FX, nil)); err != nil {
 						return fmt.Errorf("failed to record snapshot for %s: %w", fe.ID, err)
 					}
 					fe.lastSnapshotDate = day
@@ -3,6 +3,7 @@ package finance_test
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -307,6 +308,205 @@ func TestTaxModelWithDeposits(t *testing.T) {
 	}
 }
 
+func TestTaxModelsCombine(t *testing.T) {
+	acc := newAccount("ISK Account",
+		withBalance(firstDate, uncertain.NewFixed(100_000)),
+		withTaxModel(finance2.TaxModels{&fixedAnnualTax{rate: 0.01}, &fixedAnnualTax{rate: -0.03}}),
+	)
+	bals, err := runPredict(t.Context(), mks(*acc), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	// 1% tax and a 3% refund on both Jan 1 balances in the period
+	if bal := bals[acc.ID].Mean(); math.Abs(bal-104_040) > 0.01 {
+		t.Errorf("balance after combined tax is %f, expected 104040", bal)
+	}
+}
+
+func TestInterestRecorderReceivesPaidInterest(t *testing.T) {
+	checkAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(1000)))
+	mortgAcc := newAccount("Mortgage Account",
+		withInterest(uncertain.NewFixed(0.03), "*-*-01", checkAcc.ID),
+		withBalance(firstDate, uncertain.NewFixed(-10000)),
+	)
+	var paid float64
+	var payments int
+	interestRecorder := finance2.InterestRecorderFunc(func(accountID, destinationAccountID string, day date.Date, amount uncertain.Value) error {
+		if accountID != mortgAcc.ID || destinationAccountID != checkAcc.ID {
+			t.Errorf("unexpected interest from %s to %s", accountID, destinationAccountID)
+		}
+		paid += amount.Mean()
+		payments++
+		return nil
+	})
+	err := finance2.RunPrediction(t.Context(), uncertain.NewConfig(0, 1), startDate, startDate.Add(1*date.Year).Add(2*date.Day), "*-*-01",
+		mks(*checkAcc, *mortgAcc), nil, finance2.CompositeRecorder{InterestRecorder: interestRecorder})
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	// the first payment on the start date only covers the day since the snapshot
+	if payments != 13 {
+		t.Errorf("expected 13 interest payments, got %d", payments)
+	}
+	if math.Abs(paid+300) > 6 {
+		t.Errorf("total interest paid is %f, expected around -300", paid)
+	}
+}
+
+func withPayout(start, end date.Date, destinationID string, net func(date.Date, float64) float64) func(*finance2.Entity) {
+	return func(acc *finance2.Entity) {
+		acc.Payout = &finance2.PayoutModel{
+			StartDate:     start,
+			EndDate:       end,
+			Frequency:     "*-*-25",
+			DestinationID: destinationID,
+			Net:           net,
+		}
+	}
+}
+
+func TestPayoutWithoutEndDateKeepsPaying(t *testing.T) {
+	checkAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+	pensionAcc := newAccount("Pension Account",
+		withBalance(firstDate, uncertain.NewFixed(120_000)),
+		withPayout(startDate, 0, checkAcc.ID, nil),
+	)
+	// Fewer payouts remain than there are months left, but the payout
+	// continues and never drains the balance.
+	pensionAcc.Payout.RemainingPayouts = func(date.Date) float64 { return 6 }
+	bals, err := runPredict(t.Context(), mks(*checkAcc, *pensionAcc), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	want := 120_000 * math.Pow(5.0/6, 12)
+	if bal := bals[pensionAcc.ID].Mean(); math.Abs(bal-want) > 0.01 {
+		t.Errorf("pension balance after a year is %f, expected %f", bal, want)
+	}
+	if bal := bals[checkAcc.ID].Mean(); math.Abs(bal-(120_000-want)) > 0.01 {
+		t.Errorf("checking balance after a year is %f, expected %f", bal, 120_000-want)
+	}
+}
+
+func TestPayoutDrainsBalanceOverPeriod(t *testing.T) {
+	checkAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+	pensionAcc := newAccount("Pension Account",
+		withBalance(firstDate, uncertain.NewFixed(120_000)),
+		withPayout(startDate, startDate.Add(1*date.Year), checkAcc.ID, nil),
+	)
+	bals, err := runPredict(t.Context(), mks(*checkAcc, *pensionAcc), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	if bal := bals[pensionAcc.ID].Mean(); math.Abs(bal) > 0.01 {
+		t.Errorf("pension balance after payout period is %f, expected 0", bal)
+	}
+	if bal := bals[checkAcc.ID].Mean(); math.Abs(bal-120_000) > 0.01 {
+		t.Errorf("checking balance after payout period is %f, expected 120000", bal)
+	}
+}
+
+func TestPayoutAppliesNetFunc(t *testing.T) {
+	checkAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+	pensionAcc := newAccount("Pension Account",
+		withBalance(firstDate, uncertain.NewFixed(120_000)),
+		withPayout(startDate, startDate.Add(2*date.Year), checkAcc.ID, func(_ date.Date, gross float64) float64 {
+			return gross * 0.7
+		}),
+	)
+	bals, err := runPredict(t.Context(), mks(*checkAcc, *pensionAcc), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	// half of the 24 payouts are made within the simulated year
+	if bal := bals[pensionAcc.ID].Mean(); math.Abs(bal-60_000) > 0.01 {
+		t.Errorf("pension balance after first payout year is %f, expected 60000", bal)
+	}
+	if bal := bals[checkAcc.ID].Mean(); math.Abs(bal-42_000) > 0.01 {
+		t.Errorf("checking balance after first payout year is %f, expected 42000", bal)
+	}
+}
+
+func TestAmortizationRedeterminedOnRevaluation(t *testing.T) {
+	checkAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(100_000)))
+	loanAcc := newAccount("Mortgage Account", withBalance(firstDate, uncertain.NewFixed(-1_200_000)))
+	revaluation := startDate.Add(180 * date.Day)
+	loanAcc.Amortization = &finance2.AmortizationModel{
+		Frequency:    "*-*-25",
+		SourceID:     checkAcc.ID,
+		Revaluations: []date.Date{revaluation},
+		YearlyAmount: func(day date.Date, balance func(string) float64) float64 {
+			// 2% before the revaluation, 1% after
+			if day.Before(revaluation) {
+				return -balance(loanAcc.ID) * 0.02
+			}
+			return -balance(loanAcc.ID) * 0.01
+		},
+	}
+	bals, err := runPredict(t.Context(), mks(*checkAcc, *loanAcc), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	// 6 payments of 2 000 before the revaluation on 2000-06-29, then 6 of ~988
+	paid := bals[loanAcc.ID].Mean() + 1_200_000
+	want := 6*2_000.0 + 6*(1_200_000-12_000)*0.01/12
+	if math.Abs(paid-want) > 0.01 {
+		t.Errorf("amortized %f, expected %f", paid, want)
+	}
+	if bal := bals[checkAcc.ID].Mean(); math.Abs(bal-(100_000-paid)) > 0.01 {
+		t.Errorf("checking balance is %f, expected %f", bal, 100_000-paid)
+	}
+}
+
+func TestAmortizationStopsWhenPaidOff(t *testing.T) {
+	checkAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+	loanAcc := newAccount("Loan Account", withBalance(firstDate, uncertain.NewFixed(-5_000)))
+	loanAcc.Amortization = &finance2.AmortizationModel{
+		Frequency: "*-*-25",
+		SourceID:  checkAcc.ID,
+		YearlyAmount: func(date.Date, func(string) float64) float64 {
+			return 12_000
+		},
+	}
+	bals, err := runPredict(t.Context(), mks(*checkAcc, *loanAcc), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	if bal := bals[loanAcc.ID].Mean(); bal != 0 {
+		t.Errorf("loan balance is %f, expected 0", bal)
+	}
+	if bal := bals[checkAcc.ID].Mean(); bal != -5_000 {
+		t.Errorf("checking balance is %f, expected -5000", bal)
+	}
+}
+
+func TestAmortizationNeverOverpaysUncertainLoan(t *testing.T) {
+	checkAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+	loanAcc := newAccount("Loan Account", withBalance(firstDate, uncertain.NewUniform(-6_000, -4_000)))
+	loanAcc.Amortization = &finance2.AmortizationModel{
+		Frequency: "*-*-25",
+		SourceID:  checkAcc.ID,
+		YearlyAmount: func(date.Date, func(string) float64) float64 {
+			return 12_000
+		},
+	}
+	bals, err := runPredict(t.Context(), mks(*checkAcc, *loanAcc), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	loan := bals[loanAcc.ID]
+	if loan.Distribution != uncertain.DistEmpirical {
+		t.Fatalf("loan balance is %s, expected an empirical distribution", loan)
+	}
+	for _, bal := range loan.Samples {
+		if bal != 0 {
+			t.Fatalf("loan balance sample is %f, expected every draw to be paid off exactly", bal)
+		}
+	}
+	if bal := bals[checkAcc.ID].Mean(); math.Abs(bal+5_000) > 100 {
+		t.Errorf("checking balance is %f, expected about -5000", bal)
+	}
+}
+
 func TestSimulation(t *testing.T) {
 	type RecordedTransfer struct {
 		From   string
@@ -338,3 +538,633 @@ func TestSimulation(t *testing.T) {
 		fmt.Printf("Transfer from %s to %s: %.0f\n", t.From, t.To, t.Amount.Mean())
 	}
 }
+
+func TestInterestRateProcessRevertsToLongTermRate(t *testing.T) {
+	ucfg := uncertain.NewConfig(1, 100)
+	p := &finance2.InterestRateProcess{
+		Type:           finance2.InterestRateVasicek,
+		InitialRate:    0.01,
+		LongTermRate:   0.04,
+		ReversionSpeed: 0.5,
+	}
+	if r := p.RateOn(ucfg, startDate).Mean(); r != 0.01 {
+		t.Errorf("initial rate is %f, expected 0.01", r)
+	}
+	got := p.RateOn(ucfg, startDate.Add(365*date.Day)).Mean()
+	want := 0.04 + (0.01-0.04)*math.Pow(1-0.5/365, 365)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("rate after a year is %f, expected %f", got, want)
+	}
+
+	cir := &finance2.InterestRateProcess{
+		Type:           finance2.InterestRateCIR,
+		InitialRate:    0.001,
+		LongTermRate:   0.001,
+		ReversionSpeed: 0.1,
+		Volatility:     0.2,
+	}
+	rates := cir.RateOn(ucfg, startDate)
+	for d := range 365 {
+		rates = cir.RateOn(ucfg, startDate.Add(date.Duration(d)*date.Day))
+	}
+	if q := rates.Quantiles(); q(0) < 0 {
+		t.Errorf("CIR rate went negative: %f", q(0))
+	}
+}
+
+func TestVariableRateGrowthLocksRateDuringBinding(t *testing.T) {
+	rates := &finance2.InterestRateProcess{
+		Type:           finance2.InterestRateVasicek,
+		InitialRate:    0.01,
+		LongTermRate:   0.05,
+		ReversionSpeed: 2,
+	}
+	bound := newAccount("Bound Mortgage", withBalance(firstDate, uncertain.NewFixed(-10_000)))
+	bound.GrowthModel = &finance2.VariableRateGrowth{
+		Rates:       rates,
+		Margin:      uncertain.NewFixed(0.01),
+		LockedUntil: startDate.Add(2 * date.Year),
+	}
+	floating := newAccount("Floating Mortgage", withBalance(firstDate, uncertain.NewFixed(-10_000)))
+	floating.GrowthModel = &finance2.VariableRateGrowth{
+		Rates:  rates,
+		Margin: uncertain.NewFixed(0.01),
+	}
+	bals, err := runPredict(t.Context(), mks(*bound, *floating), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	// The bound loan keeps the 2% it started at, slightly compounded daily.
+	if bal := bals[bound.ID].Mean(); math.Abs(bal+10_000*1.02) > 5 {
+		t.Errorf("bound mortgage balance is %f, expected about %f", bal, -10_000*1.02)
+	}
+	if bal := bals[floating.ID].Mean(); bal > -10_000*1.035 {
+		t.Errorf("floating mortgage balance is %f, expected the rising rate to cost more than 3.5%%", bal)
+	}
+}
+
+func TestInterestRateProcessStartsOnForecastStart(t *testing.T) {
+	rates := &finance2.InterestRateProcess{
+		Type:           finance2.InterestRateVasicek,
+		InitialRate:    0.01,
+		LongTermRate:   0.05,
+		ReversionSpeed: 2,
+	}
+	// A loan last snapshotted a year ago accrues the initial rate up to the
+	// start of the forecast, and then follows the same path as a loan
+	// snapshotted today.
+	old := newAccount("Old Mortgage", withBalance(firstDate.Add(-date.Year), uncertain.NewFixed(-10_000)))
+	old.GrowthModel = &finance2.VariableRateGrowth{Rates: rates}
+	recent := newAccount("Recent Mortgage", withBalance(firstDate, uncertain.NewFixed(-10_100)))
+	recent.GrowthModel = &finance2.VariableRateGrowth{Rates: rates}
+	bals, err := runPredict(t.Context(), mks(*old, *recent), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	if o, r := bals[old.ID].Mean(), bals[recent.ID].Mean(); math.Abs(o-r) > 5 {
+		t.Errorf("old mortgage balance is %f, expected about the recent mortgage's %f", o, r)
+	}
+}
+
+func TestStartupProjectedRoundAndExit(t *testing.T) {
+	checkingAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+	// 100 of 1000 shares bought at 10 with the company valued at 10 000.
+	growth := &finance2.StartupGrowth{
+		TotalShares:           uncertain.NewFixed(1000),
+		OwnedShares:           uncertain.NewFixed(100),
+		Valuation:             uncertain.NewFixed(10_000),
+		TaxRate:               uncertain.NewFixed(0.25),
+		DiscountFactor:        uncertain.NewFixed(1),
+		PurchasePricePerShare: uncertain.NewFixed(10),
+		ProjectedRounds: map[date.Date]finance2.StartupGrowthProjectedRound{
+			startDate.Add(90 * date.Day): {
+				Probability:       1,
+				PreMoneyValuation: uncertain.NewFixed(30_000),
+				Investment:        uncertain.NewFixed(10_000),
+			},
+		},
+		Exits: map[date.Date]finance2.StartupGrowthExit{
+			startDate.Add(180 * date.Day): {
+				Kind:               finance2.StartupExitAcquisition,
+				Multiple:           uncertain.NewFixed(2),
+				FailureProbability: 0.5,
+				PayoutAccountID:    checkingAcc.ID,
+			},
+		},
+	}
+	startupAcc := newAccount("Startup", withBalance(firstDate, uncertain.NewFixed(1000)), func(acc *finance2.Entity) {
+		acc.GrowthModel = growth
+	})
+	bals, err := runPredict(t.Context(), mks(*checkingAcc, *startupAcc), nil)
+	if err != nil {
+		t.Fatalf("failed to run simulation: %s", err)
+	}
+	// The round prices the shares at 30, the exit sells them at 60 and the
+	// gain of 5000 is taxed at 25%, paid out in half of the samples.
+	if bal := bals[startupAcc.ID]; bal.Mean() != 0 {
+		t.Errorf("startup balance after exit is %s, expected 0", bal)
+	}
+	bal := bals[checkingAcc.ID]
+	if mean := bal.Mean(); math.Abs(mean-0.5*4750) > 250 {
+		t.Errorf("checking mean after exit is %v, expected around %v", mean, 0.5*4750)
+	}
+	q := bal.Quantiles()
+	if q(0.1) != 0 || q(0.9) != 4750 {
+		t.Errorf("expected payouts of either 0 or 4750, got q10=%v q90=%v", q(0.1), q(0.9))
+	}
+}
+
+type depositRecorderTax struct {
+	deposits float64
+}
+
+func (d *depositRecorderTax) Apply(ucfg *uncertain.Config, day date.Date, balance uncertain.Value, dayDeposits uncertain.Value) uncertain.Value {
+	d.deposits += dayDeposits.Mean()
+	return uncertain.Value{}
+}
+
+func TestStartupExitPaidAsTransfer(t *testing.T) {
+	deposits := &depositRecorderTax{}
+	checkingAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)), withTaxModel(deposits))
+	exitDay := startDate.Add(180 * date.Day)
+	// 100 of 1000 shares bought at 10 and sold at 20, with the gain of 1000
+	// taxed at 25%.
+	startupAcc := newAccount("Startup", withBalance(firstDate, uncertain.NewFixed(1000)), func(acc *finance2.Entity) {
+		acc.GrowthModel = &finance2.StartupGrowth{
+			TotalShares:           uncertain.NewFixed(1000),
+			OwnedShares:           uncertain.NewFixed(100),
+			Valuation:             uncertain.NewFixed(10_000),
+			TaxRate:               uncertain.NewFixed(0.25),
+			DiscountFactor:        uncertain.NewFixed(1),
+			PurchasePricePerShare: uncertain.NewFixed(10),
+			Exits: map[date.Date]finance2.StartupGrowthExit{
+				exitDay: {
+					Kind:            finance2.StartupExitAcquisition,
+					Multiple:        uncertain.NewFixed(2),
+					PayoutAccountID: checkingAcc.ID,
+				},
+			},
+		}
+	})
+	var transferred float64
+	transferRecorder := finance2.TransferRecorderFunc(func(from, to string, day date.Date, amount uncertain.Value) error {
+		if from != startupAcc.ID || to != checkingAcc.ID || day != exitDay {
+			t.Errorf("unexpected transfer from %s to %s on %s", from, to, day)
+		}
+		transferred += amount.Mean()
+		return nil
+	})
+	err := finance2.RunPrediction(t.Context(), uncertain.NewConfig(1, 100), startDate, startDate.Add(date.Year), "*-*-01",
+		mks(*checkingAcc, *startupAcc), nil, finance2.CompositeRecorder{TransferRecorder: transferRecorder})
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	if math.Abs(transferred-1750) > 1e-6 {
+		t.Errorf("recorded exit payout is %f, expected 1750", transferred)
+	}
+	if math.Abs(deposits.deposits-1750) > 1e-6 {
+		t.Errorf("exit payout deposited %f, expected 1750", deposits.deposits)
+	}
+}
+
+func TestStartupExitReportsDeclaredGain(t *testing.T) {
+	checkingAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+	exitDay := startDate.Add(180 * date.Day)
+	// 100 of 1000 shares bought at 10 and sold at 20 unless the company
+	// fails, with the gain declared by the owners instead of taxed here.
+	startupAcc := newAccount("Startup", withBalance(firstDate, uncertain.NewFixed(1000)), func(acc *finance2.Entity) {
+		acc.GrowthModel = &finance2.StartupGrowth{
+			TotalShares:           uncertain.NewFixed(1000),
+			OwnedShares:           uncertain.NewFixed(100),
+			Valuation:             uncertain.NewFixed(10_000),
+			TaxRate:               uncertain.NewFixed(0.25),
+			DiscountFactor:        uncertain.NewFixed(1),
+			PurchasePricePerShare: uncertain.NewFixed(10),
+			DeclaredGainShare:     1,
+			Exits: map[date.Date]finance2.StartupGrowthExit{
+				exitDay: {
+					Kind:               finance2.StartupExitAcquisition,
+					Multiple:           uncertain.NewFixed(2),
+					FailureProbability: 0.5,
+					PayoutAccountID:    checkingAcc.ID,
+				},
+			},
+		}
+	})
+	var payout, gain uncertain.Value
+	recorder := finance2.CompositeRecorder{
+		TransferRecorder: finance2.TransferRecorderFunc(func(from, to string, day date.Date, amount uncertain.Value) error {
+			payout = amount
+			return nil
+		}),
+		CapitalGainRecorder: finance2.CapitalGainRecorderFunc(func(accountID string, day date.Date, g uncertain.Value) error {
+			if accountID != startupAcc.ID || day != exitDay {
+				t.Errorf("unexpected gain of %s on %s", accountID, day)
+			}
+			gain = g
+			return nil
+		}),
+	}
+	err := finance2.RunPrediction(t.Context(), uncertain.NewConfig(1, 1_000), startDate, startDate.Add(date.Year), "*-*-01",
+		mks(*checkingAcc, *startupAcc), nil, recorder)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	if len(gain.Samples) != len(payout.Samples) {
+		t.Fatalf("got %d gains for %d payouts", len(gain.Samples), len(payout.Samples))
+	}
+	// A sold exit pays 2000 untaxed with a gain of 1000, and a failed exit
+	// realises the 1000 paid as a loss.
+	for i, g := range gain.Samples {
+		if p := payout.Samples[i]; !(p == 2000 && g == 1000) && !(p == 0 && g == -1000) {
+			t.Fatalf("sample %d pays %f with a gain of %f", i, p, g)
+		}
+	}
+	if mean := gain.Mean(); math.Abs(mean) > 100 {
+		t.Errorf("mean gain is %f, expected about 0", mean)
+	}
+}
+
+func TestStartupRoundPreferenceSampledPerDraw(t *testing.T) {
+	checkingAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+	// 10 of 100 shares in a company valued at 100, raising either nothing or
+	// 1000 with a 1x preference, and sold at 0.4 times its valuation.
+	growth := &finance2.StartupGrowth{
+		TotalShares:           uncertain.NewFixed(100),
+		OwnedShares:           uncertain.NewFixed(10),
+		Valuation:             uncertain.NewFixed(100),
+		TaxRate:               uncertain.NewFixed(0),
+		DiscountFactor:        uncertain.NewFixed(1),
+		PurchasePricePerShare: uncertain.NewFixed(0),
+		InvestmentRounds: map[date.Date]finance2.StartupGrowthInvestmentRound{
+			startDate.Add(90 * date.Day): {
+				PreMoneyValuation:  uncertain.NewFixed(100),
+				PreMoneyShares:     uncertain.NewFixed(100),
+				Investment:         uncertain.Value{Distribution: uncertain.DistEmpirical, Samples: []float64{0, 1000}},
+				PreferenceMultiple: 1,
+			},
+		},
+		Exits: map[date.Date]finance2.StartupGrowthExit{
+			startDate.Add(180 * date.Day): {
+				Kind:            finance2.StartupExitAcquisition,
+				Multiple:        uncertain.NewFixed(0.4),
+				PayoutAccountID: checkingAcc.ID,
+			},
+		},
+	}
+	startupAcc := newAccount("Startup", withBalance(firstDate, uncertain.NewFixed(100)), func(acc *finance2.Entity) {
+		acc.GrowthModel = growth
+	})
+	bals, err := runPredict(t.Context(), mks(*checkingAcc, *startupAcc), nil)
+	if err != nil {
+		t.Fatalf("failed to run simulation: %s", err)
+	}
+	// The expected preference of 500 would take all of the proceeds, but it
+	// only exists in the draws where the round raised money.
+	q := bals[checkingAcc.ID].Quantiles()
+	if q(0.25) != 0 || q(0.75) <= 0 {
+		t.Errorf("expected the common shares to be paid only without a preference, got q25=%v q75=%v", q(0.25), q(0.75))
+	}
+}
+
+func TestStartupOptionVestedFraction(t *testing.T) {
+	opt := finance2.StartupGrowthOption{
+		VestingStart:          Must(date.ParseDate("2020-01-15")),
+		CliffMonths:           12,
+		VestingMonths:         48,
+		VestingIntervalMonths: 3,
+	}
+	tests := []struct {
+		day  string
+		want float64
+	}{
+		{"2020-01-14", 0},
+		{"2021-01-14", 0},
+		{"2021-01-15", 0.25},
+		{"2021-04-14", 0.25},
+		{"2021-04-15", 0.3125},
+		{"2024-01-15", 1},
+		{"2030-01-01", 1},
+	}
+	for _, tt := range tests {
+		if got := opt.VestedFraction(Must(date.ParseDate(tt.day))); got != tt.want {
+			t.Errorf("vested on %s = %v, want %v", tt.day, got, tt.want)
+		}
+	}
+	opt.VestingMonths = 0
+	if got := opt.VestedFraction(opt.VestingStart); got != 1 {
+		t.Errorf("grant without vesting vested %v, want 1", got)
+	}
+}
+
+func TestStartupOptionExercisePolicies(t *testing.T) {
+	exitDate := Must(date.ParseDate("2000-07-01"))
+	expiry := Must(date.ParseDate("2000-06-01"))
+	tests := []struct {
+		name         string
+		strike       float64
+		policy       finance2.StartupOptionExercisePolicy
+		vesting      int
+		accelerate   bool
+		wantChecking float64
+	}{
+		// 18 of 48 months vested at the exit, 45 options with a spread of 5
+		// taxed at 25%.
+		{name: "cashed out at exit", strike: 5, policy: finance2.StartupOptionExerciseAtExit, vesting: 48, wantChecking: 45 * 5 * 0.75},
+		{name: "accelerated at exit", strike: 5, policy: finance2.StartupOptionExerciseAtExit, vesting: 48, accelerate: true, wantChecking: 120 * 5 * 0.75},
+		{name: "underwater at exit", strike: 20, policy: finance2.StartupOptionExerciseAtExit, vesting: 48, wantChecking: 0},
+		// Bought at expiry for 5 each, diluting the price to 10 600 / 1 120,
+		// and sold at the exit with the gain taxed at 25%.
+		{name: "exercised at expiry", strike: 5, policy: finance2.StartupOptionExerciseInTheMoney, wantChecking: -120*5 + 120*10600.0/1120 - (120*10600.0/1120-120*5)*0.25},
+		{name: "never exercised underwater", strike: 20, policy: finance2.StartupOptionExerciseInTheMoney, wantChecking: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkingAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+			growth := &finance2.StartupGrowth{
+				TotalShares:           uncertain.NewFixed(1000),
+				OwnedShares:           uncertain.NewFixed(0),
+				Valuation:             uncertain.NewFixed(10_000),
+				TaxRate:               uncertain.NewFixed(0.25),
+				DiscountFactor:        uncertain.NewFixed(1),
+				PurchasePricePerShare: uncertain.NewFixed(0),
+				Options: []finance2.StartupGrowthOption{{
+					StrikePricePerShare:   uncertain.NewFixed(tt.strike),
+					NumShares:             uncertain.NewFixed(120),
+					SourceAccountID:       checkingAcc.ID,
+					VestingStart:          Must(date.ParseDate("1999-01-01")),
+					Expiry:                expiry,
+					CliffMonths:           12,
+					VestingMonths:         tt.vesting,
+					VestingIntervalMonths: 3,
+					AccelerateOnExit:      tt.accelerate,
+					ExercisePolicy:        tt.policy,
+				}},
+				Exits: map[date.Date]finance2.StartupGrowthExit{
+					exitDate: {
+						Kind:            finance2.StartupExitAcquisition,
+						Multiple:        uncertain.NewFixed(1),
+						PayoutAccountID: checkingAcc.ID,
+					},
+				},
+			}
+			if tt.policy == finance2.StartupOptionExerciseAtExit {
+				growth.Options[0].Expiry = exitDate.Add(date.Year)
+			}
+			startupAcc := newAccount("Startup", withBalance(firstDate, uncertain.NewFixed(0)), func(acc *finance2.Entity) {
+				acc.GrowthModel = growth
+			})
+			bals, err := runPredict(t.Context(), mks(*checkingAcc, *startupAcc), nil)
+			if err != nil {
+				t.Fatalf("failed to run simulation: %s", err)
+			}
+			if got := bals[checkingAcc.ID].Mean(); math.Abs(got-tt.wantChecking) > 1e-6 {
+				t.Errorf("checking balance is %v, expected %v", got, tt.wantChecking)
+			}
+			if got := bals[startupAcc.ID].Mean(); got != 0 {
+				t.Errorf("startup balance after exit is %v, expected 0", got)
+			}
+		})
+	}
+}
+
+func TestCommonPricePerShare(t *testing.T) {
+	// 60 common shares, with series A investing 10M and series B 30M for 20
+	// shares each.
+	seriesA := finance2.LiquidationPreference{Shares: 20, Invested: 10_000_000, Multiple: 1}
+	seriesB := finance2.LiquidationPreference{Shares: 20, Invested: 30_000_000, Multiple: 1}
+	participating := func(p finance2.LiquidationPreference) finance2.LiquidationPreference {
+		p.Participating = true
+		return p
+	}
+	senior := func(p finance2.LiquidationPreference) finance2.LiquidationPreference {
+		p.Seniority = 1
+		return p
+	}
+	tests := []struct {
+		name     string
+		proceeds float64
+		prefs    []finance2.LiquidationPreference
+		want     float64
+	}{
+		{name: "pro rata without preferences", proceeds: 50_000_000, want: 500_000},
+		{name: "non-participating take their preference", proceeds: 50_000_000, prefs: mks(seriesA, seriesB), want: 10_000_000.0 / 60},
+		{name: "participating share the rest", proceeds: 50_000_000, prefs: mks(participating(seriesA), participating(seriesB)), want: 100_000},
+		{name: "non-participating convert in large exits", proceeds: 500_000_000, prefs: mks(seriesA, seriesB), want: 5_000_000},
+		{name: "only the cheaper preference converts", proceeds: 100_000_000, prefs: mks(seriesA, seriesB), want: 70_000_000.0 / 80},
+		{name: "nothing left for common", proceeds: 35_000_000, prefs: mks(seriesA, senior(seriesB)), want: 0},
+		{name: "multiple above 1x", proceeds: 50_000_000, prefs: mks(finance2.LiquidationPreference{Shares: 20, Invested: 10_000_000, Multiple: 2}), want: 30_000_000.0 / 80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := finance2.CommonPricePerShare(tt.proceeds, 100, tt.prefs); math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("common price = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartupExitThroughLiquidationWaterfall(t *testing.T) {
+	checkingAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+	// 10 of 100 shares in a company valued at 50M that raised 40M with 1x
+	// non-participating preferences.
+	growth := &finance2.StartupGrowth{
+		TotalShares:           uncertain.NewFixed(100),
+		OwnedShares:           uncertain.NewFixed(10),
+		Valuation:             uncertain.NewFixed(50_000_000),
+		TaxRate:               uncertain.NewFixed(0),
+		DiscountFactor:        uncertain.NewFixed(1),
+		PurchasePricePerShare: uncertain.NewFixed(0),
+		Preferences: []finance2.LiquidationPreference{
+			{Shares: 20, Invested: 10_000_000, Multiple: 1},
+			{Shares: 20, Invested: 30_000_000, Multiple: 1},
+		},
+		Exits: map[date.Date]finance2.StartupGrowthExit{
+			startDate.Add(180 * date.Day): {
+				Kind:            finance2.StartupExitAcquisition,
+				Multiple:        uncertain.NewFixed(1),
+				PayoutAccountID: checkingAcc.ID,
+			},
+		},
+	}
+	if got, want := growth.Balance(uncertain.NewConfig(1, 10)).Mean(), 10*10_000_000.0/60; math.Abs(got-want) > 1e-6 {
+		t.Errorf("balance before exit is %v, expected %v", got, want)
+	}
+	startupAcc := newAccount("Startup", withBalance(firstDate, uncertain.NewFixed(0)), func(acc *finance2.Entity) {
+		acc.GrowthModel = growth
+	})
+	bals, err := runPredict(t.Context(), mks(*checkingAcc, *startupAcc), nil)
+	if err != nil {
+		t.Fatalf("failed to run simulation: %s", err)
+	}
+	if got, want := bals[checkingAcc.ID].Mean(), 10*10_000_000.0/60; math.Abs(got-want) > 1e-6 {
+		t.Errorf("checking balance after exit is %v, expected %v", got, want)
+	}
+}
+
+func TestStartupOptionTaxTreatment(t *testing.T) {
+	exitPrice := 2 * 10_500.0 / 1100
+	tests := []struct {
+		treatment    finance2.StartupGrantTax
+		wantBalance  float64
+		wantChecking float64
+	}{
+		// Untaxed at exercise, with the gain over the strike taxed as capital
+		// at the exit.
+		{treatment: finance2.StartupGrantQualified, wantBalance: 500 * 0.75, wantChecking: -500 + 100*exitPrice - (100*exitPrice-500)*0.25},
+		{treatment: finance2.StartupGrantPurchase, wantBalance: 500 * 0.75, wantChecking: -500 + 100*exitPrice - (100*exitPrice-500)*0.25},
+		// The spread of 5 per share is taxed as salary at exercise and only
+		// the gain above the market value then as capital.
+		{treatment: finance2.StartupGrantEmployee, wantBalance: 500 * 0.5, wantChecking: -500 - 500*0.5 + 100*exitPrice - (100*exitPrice-1000)*0.25},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.treatment), func(t *testing.T) {
+			checkingAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(0)))
+			growth := &finance2.StartupGrowth{
+				TotalShares:           uncertain.NewFixed(1000),
+				OwnedShares:           uncertain.NewFixed(0),
+				Valuation:             uncertain.NewFixed(10_000),
+				TaxRate:               uncertain.NewFixed(0.25),
+				MarginalIncomeTaxRate: uncertain.NewFixed(0.5),
+				DiscountFactor:        uncertain.NewFixed(1),
+				PurchasePricePerShare: uncertain.NewFixed(0),
+				Options: []finance2.StartupGrowthOption{{
+					StrikePricePerShare: uncertain.NewFixed(5),
+					NumShares:           uncertain.NewFixed(100),
+					SourceAccountID:     checkingAcc.ID,
+					VestingStart:        firstDate,
+					Expiry:              Must(date.ParseDate("2000-03-01")),
+					ExercisePolicy:      finance2.StartupOptionExerciseInTheMoney,
+					TaxTreatment:        tt.treatment,
+				}},
+				Exits: map[date.Date]finance2.StartupGrowthExit{
+					Must(date.ParseDate("2000-07-01")): {
+						Kind:            finance2.StartupExitIPO,
+						Multiple:        uncertain.NewFixed(2),
+						PayoutAccountID: checkingAcc.ID,
+					},
+				},
+			}
+			growth.VestOptions(startDate)
+			if got := growth.Balance(uncertain.NewConfig(1, 10)).Mean(); math.Abs(got-tt.wantBalance) > 1e-6 {
+				t.Errorf("balance of vested options is %v, expected %v", got, tt.wantBalance)
+			}
+			startupAcc := newAccount("Startup", withBalance(firstDate, uncertain.NewFixed(0)), func(acc *finance2.Entity) {
+				acc.GrowthModel = growth
+			})
+			bals, err := runPredict(t.Context(), mks(*checkingAcc, *startupAcc), nil)
+			if err != nil {
+				t.Fatalf("failed to run simulation: %s", err)
+			}
+			if got := bals[checkingAcc.ID].Mean(); math.Abs(got-tt.wantChecking) > 1e-6 {
+				t.Errorf("checking balance after exit is %v, expected %v", got, tt.wantChecking)
+			}
+		})
+	}
+}
+
+func TestTransfersConvertBetweenCurrencies(t *testing.T) {
+	usd := &finance2.FXRateProcess{Currency: "USD", InitialRate: 10}
+	checkingAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(13000)))
+	brokerageAcc := newAccount("USD Brokerage", withBalance(firstDate, uncertain.NewFixed(1000)), func(acc *finance2.Entity) {
+		acc.FX = usd
+	})
+	depositTrn := newTransfer(checkingAcc.ID, brokerageAcc.ID, 1, "*-*-25", withFixed(uncertain.NewFixed(1000)))
+	bals, err := runPredict(t.Context(), mks(*checkingAcc, *brokerageAcc), mks(depositTrn))
+	if err != nil {
+		t.Fatalf("failed to run simulation: %s", err)
+	}
+	if bal := bals[checkingAcc.ID].Mean(); bal != 1000 {
+		t.Errorf("checking account balance after transfers is %f, expected to be 1000", bal)
+	}
+	// 1000 USD plus 12 deposits of 100 USD, recorded in the base currency.
+	if bal := bals[brokerageAcc.ID].Mean(); math.Abs(bal-22000) > 1e-6 {
+		t.Errorf("brokerage account balance after transfers is %f, expected to be 22000", bal)
+	}
+}
+
+func TestAmortizationInBaseCurrency(t *testing.T) {
+	usd := &finance2.FXRateProcess{Currency: "USD", InitialRate: 10}
+	checkAcc := newAccount("Checking Account", withBalance(firstDate, uncertain.NewFixed(100_000)))
+	houseAcc := newAccount("USD House", withBalance(firstDate, uncertain.NewFixed(100_000)), func(acc *finance2.Entity) {
+		acc.FX = usd
+	})
+	loanAcc := newAccount("Mortgage Account", withBalance(firstDate, uncertain.NewFixed(-400_000)))
+	loanAcc.Amortization = &finance2.AmortizationModel{
+		Frequency: "*-*-25",
+		SourceID:  checkAcc.ID,
+		YearlyAmount: func(day date.Date, balance func(string) float64) float64 {
+			// Only amortize above 50% loan-to-value.
+			if -balance(loanAcc.ID) > 0.5*balance(houseAcc.ID) {
+				return 12_000
+			}
+			return 0
+		},
+	}
+	usdLoanAcc := newAccount("USD Loan", withBalance(firstDate, uncertain.NewFixed(-10_000)), func(acc *finance2.Entity) {
+		acc.FX = usd
+	})
+	usdLoanAcc.Amortization = &finance2.AmortizationModel{
+		Frequency: "*-*-25",
+		SourceID:  checkAcc.ID,
+		YearlyAmount: func(day date.Date, balance func(string) float64) float64 {
+			return -balance(usdLoanAcc.ID) * 0.12
+		},
+	}
+	bals, err := runPredict(t.Context(), mks(*checkAcc, *houseAcc, *loanAcc, *usdLoanAcc), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	// The house is worth 1 000 000 in the base currency, so the loan is at 40%.
+	if bal := bals[loanAcc.ID].Mean(); bal != -400_000 {
+		t.Errorf("loan balance is %f, expected -400000", bal)
+	}
+	// 12% of 100 000 is 1 200 USD a year, recorded in the base currency.
+	if bal := bals[usdLoanAcc.ID].Mean(); math.Abs(bal+88_000) > 1e-6 {
+		t.Errorf("usd loan balance is %f, expected -88000", bal)
+	}
+	if bal := bals[checkAcc.ID].Mean(); math.Abs(bal-88_000) > 1e-6 {
+		t.Errorf("checking balance is %f, expected 88000", bal)
+	}
+}
+
+type fixedAmountTax struct {
+	amount float64
+}
+
+func (f *fixedAmountTax) Apply(ucfg *uncertain.Config, day date.Date, balance uncertain.Value, dayDeposits uncertain.Value) uncertain.Value {
+	if day.Month() == 1 && day.Day() == 1 {
+		return uncertain.NewFixed(f.amount)
+	}
+	return uncertain.Value{}
+}
+
+func TestTaxModelInBaseCurrency(t *testing.T) {
+	usd := &finance2.FXRateProcess{Currency: "USD", InitialRate: 10}
+	acc := newAccount("USD ISK", withBalance(firstDate, uncertain.NewFixed(1000)), withTaxModel(&fixedAmountTax{amount: 1000}), func(acc *finance2.Entity) {
+		acc.FX = usd
+	})
+	bals, err := runPredict(t.Context(), mks(*acc), nil)
+	if err != nil {
+		t.Fatalf("failed to run prediction: %s", err)
+	}
+	// A tax of 1000 in the base currency is 100 USD, on both Jan 1 in the period.
+	if bal := bals[acc.ID].Mean(); math.Abs(bal-8000) > 1e-6 {
+		t.Errorf("balance after tax is %f, expected 8000", bal)
+	}
+}
+
+func TestFXRateProcessFollowsDrift(t *testing.T) {
+	ucfg := uncertain.NewConfig(1, 2_000)
+	p := &finance2.FXRateProcess{Currency: "EUR", InitialRate: 11, AnnualDrift: 0.05, AnnualVolatility: 0.1}
+	if r := p.RateOn(ucfg, startDate).Mean(); r != 11 {
+		t.Errorf("initial rate is %f, expected 11", r)
+	}
+	rates := p.RateOn(ucfg, startDate.Add(365*date.Day))
+	if median, want := rates.Quantiles()(0.5), 11*math.Exp(0.05); math.Abs(median-want) > 0.1 {
+		t.Errorf("median rate after a year is %f, expected around %f", median, want)
+	}
+	if q := rates.Quantiles(); q(0.975)-q(0.025) < 3 {
+		t.Errorf("rate spread after a year is %f, expected the volatility to widen it", q(0.975)-q(0.025))
+	}
+}
@@ -22,6 +22,10 @@ type TransferTemplate struct {
 	FromAccountID string
 	ToAccountID   string
 
+	// FX is the currency of a fixed amount, nil for the base currency. A
+	// percentage is of the balance, in the currency of the source.
+	FX *FXRateProcess
+
 	AmountType    TransferAmountType
 	AmountFixed   TransferFixed
 	AmountPercent TransferPercent
@@ -75,34 +79,39 @@ func applyDailyTransfers(ucfg *uncertain.Config, accounts map[string]*ModeledEnt
 
 		// These transfers will transfer as given, no uncertainty here, for empirical they are just changed by the transfer amount
 		var transferAmount uncertain.Value
+		fx := transfer.FX
 		switch transfer.AmountType {
 		case AmountFixed:
 			transferAmount = transfer.AmountFixed.Amount
 		case AmountPercent:
 			transferAmount = uncertain.NewFixed(sourceBalance * transfer.AmountPercent.Percent)
+			if okFrom {
+				fx = fromAccount.FX
+			}
 		default:
 			continue // Unknown amount type
 		}
 
 		amount := transferAmount
 		if okTo && toAccount.BalanceLimit.Upper.Valid() {
-			destTransferLimit := toAccount.BalanceLimit.Upper.Sub(ucfg, toAccount.balance)
+			destTransferLimit := convertFX(ucfg, day, toAccount.BalanceLimit.Upper.Sub(ucfg, toAccount.balance), toAccount.FX, fx)
 			amount = uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
 				return math.Min(destTransferLimit.Sample(cfg), transferAmount.Sample(cfg))
 			})
 		}
 
-		if err := recorder.OnTransfer(transfer.FromAccountID, transfer.ToAccountID, day, amount); err != nil {
+		if err := recorder.OnTransfer(transfer.FromAccountID, transfer.ToAccountID, day, convertFX(ucfg, day, amount, fx, nil)); err != nil {
 			return fmt.Errorf("failed to record transfer %s from %s to %s on %s: %w", transfer.ID, transfer.FromAccountID, transfer.ToAccountID, day, err)
 		}
 
 		if okFrom {
 			// Perform the transfer
-			fromAccount.balance = fromAccount.balance.Sub(ucfg, amount)
+			fromAccount.balance = fromAccount.balance.Sub(ucfg, convertFX(ucfg, day, amount, fx, fromAccount.FX))
 		}
 		if okTo {
-			toAccount.balance = toAccount.balance.Add(ucfg, amount)
-			toAccount.dayDeposits = toAccount.dayDeposits.Add(ucfg, amount)
+			received := convertFX(ucfg, day, amount, fx, toAccount.FX)
+			toAccount.balance = toAccount.balance.Add(ucfg, received)
+			toAccount.dayDeposits = toAccount.dayDeposits.Add(ucfg, received)
 		}
 	}
 	return nil
@@ -0,0 +1,61 @@
+package finance
+
+import (
+	"math"
+	"slices"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+// FXRateProcess simulates the price of a currency in the base currency as a
+// geometric Brownian motion. It starts at InitialRate on the first day it is
+// asked for and is advanced one day at a time, so every entity and transfer
+// in the currency sees the same rate path. A nil process is the base
+// currency.
+type FXRateProcess struct {
+	Currency         string
+	InitialRate      float64
+	AnnualDrift      float64 // Expected change of the log rate per year
+	AnnualVolatility float64
+
+	day   date.Date
+	rates []float64
+}
+
+// RateOn returns the distribution of the rate on day. Days must be requested
+// in non-decreasing order, days before the first stay at the initial rate.
+func (p *FXRateProcess) RateOn(ucfg *uncertain.Config, day date.Date) uncertain.Value {
+	if p == nil {
+		return uncertain.NewFixed(1)
+	}
+	if p.rates == nil {
+		p.rates = make([]float64, max(1, ucfg.Samples))
+		for i := range p.rates {
+			p.rates[i] = p.InitialRate
+		}
+		p.day = day
+	}
+	const dt = 1.0 / 365.0
+	for ; p.day.Before(day); p.day = p.day.Add(date.Day) {
+		for i, r := range p.rates {
+			p.rates[i] = r * math.Exp(p.AnnualDrift*dt+p.AnnualVolatility*math.Sqrt(dt)*ucfg.RNG.NormFloat64())
+		}
+	}
+	if p.AnnualVolatility == 0 {
+		return uncertain.NewFixed(p.rates[0])
+	}
+	return uncertain.Value{Distribution: uncertain.DistEmpirical, Samples: slices.Clone(p.rates)}
+}
+
+// convertFX converts an amount in the currency of from to the currency of to
+// at the rates on day.
+func convertFX(ucfg *uncertain.Config, day date.Date, amount uncertain.Value, from, to *FXRateProcess) uncertain.Value {
+	if from == to || amount.Zero() {
+		return amount
+	}
+	fromRate, toRate := from.RateOn(ucfg, day), to.RateOn(ucfg, day)
+	return uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+		return amount.Sample(cfg) * fromRate.Sample(cfg) / toRate.Sample(cfg)
+	}).Materialize(ucfg)
+}
@@ -3,6 +3,7 @@ package finance
 import (
 	"fmt"
 	"math"
+	"slices"
 	"sort"
 
 	"github.com/SimonSchneider/goslu/date"
@@ -124,10 +125,30 @@ func (i *LogNormalGrowth) Apply(ucfg *uncertain.Config, day date.Date, entities
 	return totalBalance.Mul(ucfg, dailyGrowth)
 }
 
+// StartupGrowthInvestmentRound is a funding round, issuing preferred shares
+// with a liquidation preference of PreferenceMultiple times the investment
+// when it is above zero.
 type StartupGrowthInvestmentRound struct {
-	PreMoneyValuation uncertain.Value
-	PreMoneyShares    uncertain.Value
-	Investment        uncertain.Value
+	PreMoneyValuation  uncertain.Value
+	PreMoneyShares     uncertain.Value
+	Investment         uncertain.Value
+	PreferenceMultiple float64
+	Participating      bool
+	Seniority          int
+}
+
+// preference returns the liquidation preference of the round for one draw of
+// its investment and the price of the shares issued for it.
+func (r StartupGrowthInvestmentRound) preference(cfg *uncertain.Config) LiquidationPreference {
+	invested := r.Investment.Sample(cfg)
+	price := r.PreMoneyValuation.Sample(cfg) / r.PreMoneyShares.Sample(cfg)
+	return LiquidationPreference{
+		Shares:        invested / price,
+		Invested:      invested,
+		Multiple:      r.PreferenceMultiple,
+		Participating: r.Participating,
+		Seniority:     r.Seniority,
+	}
 }
 
 type StartupGrowthShareChange struct {
@@ -135,10 +156,113 @@ type StartupGrowthShareChange struct {
 	TotalPrice  uncertain.Value
 }
 
+type StartupOptionExercisePolicy string
+
+const (
+	// StartupOptionExerciseInTheMoney exercises the vested options at expiry,
+	// but never when they are underwater.
+	StartupOptionExerciseInTheMoney StartupOptionExercisePolicy = "in_the_money"
+	// StartupOptionExerciseAtVesting exercises the options as they vest.
+	StartupOptionExerciseAtVesting StartupOptionExercisePolicy = "vesting"
+	// StartupOptionExerciseAtExit keeps the options until the exit, where the
+	// vested ones are cashed out.
+	StartupOptionExerciseAtExit StartupOptionExercisePolicy = "exit"
+)
+
+// StartupGrantTax is how a grant of shares is taxed.
+type StartupGrantTax string
+
+const (
+	// StartupGrantQualified is kvalificerade personaloptioner, untaxed until
+	// the shares are sold, when the gain over the strike is capital income.
+	StartupGrantQualified StartupGrantTax = "qualified"
+	// StartupGrantEmployee is regular personaloptioner, where the value over
+	// the strike is taxed as salary at exercise, at the flat
+	// MarginalIncomeTaxRate, and later gains as capital.
+	StartupGrantEmployee StartupGrantTax = "employee"
+	// StartupGrantPurchase is shares or warrants bought at market value, with
+	// the gain taxed as capital at sale.
+	StartupGrantPurchase StartupGrantTax = "purchase"
+)
+
+// StartupGrowthOption is a grant of options on NumShares vesting from
+// VestingStart over VestingMonths, in steps of VestingIntervalMonths after a
+// cliff of CliffMonths. A grant without vesting months is vested from the
+// start. Options not exercised by Expiry lapse, and the exercise cost is paid
+// from SourceAccountID. Vested options held at an exit are cashed out at the
+// exit price, and AccelerateOnExit vests the whole grant first.
 type StartupGrowthOption struct {
-	StrikePricePerShare uncertain.Value
-	NumShares           uncertain.Value
-	SourceAccountID     string
+	StrikePricePerShare   uncertain.Value
+	NumShares             uncertain.Value
+	SourceAccountID       string
+	VestingStart          date.Date
+	Expiry                date.Date
+	CliffMonths           int
+	VestingMonths         int
+	VestingIntervalMonths int
+	AccelerateOnExit      bool
+	ExercisePolicy        StartupOptionExercisePolicy
+	TaxTreatment          StartupGrantTax
+	// Exercised is the number of options already exercised.
+	Exercised uncertain.Value
+
+	vested float64
+	closed bool
+}
+
+// VestedFraction returns the part of the grant vested on day.
+func (o StartupGrowthOption) VestedFraction(day date.Date) float64 {
+	if day < o.VestingStart {
+		return 0
+	}
+	if o.VestingMonths <= 0 {
+		return 1
+	}
+	months := 0
+	for date.FromTime(o.VestingStart.ToStdTime().AddDate(0, months+1, 0)) <= day {
+		months++
+	}
+	if months < o.CliffMonths {
+		return 0
+	}
+	interval := max(1, o.VestingIntervalMonths)
+	return min(1, float64(months/interval*interval)/float64(o.VestingMonths))
+}
+
+// unexercised returns the vested options not yet exercised.
+func (o *StartupGrowthOption) unexercised(ucfg *uncertain.Config) uncertain.Value {
+	vested := o.NumShares.Mul(ucfg, uncertain.NewFixed(o.vested))
+	if o.Exercised.Zero() {
+		return vested
+	}
+	return vested.Sub(ucfg, o.Exercised)
+}
+
+// StartupGrowthProjectedRound is a future funding round that happens with
+// Probability, pricing the shares at PreMoneyValuation over the shares
+// outstanding before it.
+type StartupGrowthProjectedRound struct {
+	Probability       float64
+	PreMoneyValuation uncertain.Value
+	Investment        uncertain.Value
+}
+
+type StartupExitKind string
+
+const (
+	StartupExitAcquisition StartupExitKind = "acquisition"
+	StartupExitIPO         StartupExitKind = "ipo"
+)
+
+// StartupGrowthExit sells the owned shares at Multiple times the price of the
+// last round, unless the company fails with FailureProbability and the shares
+// become worthless. The payout is paid to PayoutAccountID after the capital
+// gains tax on the part of the gain not declared by the owners.
+type StartupGrowthExit struct {
+	Kind               StartupExitKind
+	Multiple           uncertain.Value
+	FailureProbability float64
+	PayoutAccountID    string
 }
 
 type StartupGrowth struct {
@@ -147,13 +271,49 @@ type StartupGrowth struct {
 	OwnedShares uncertain.Value
 	Valuation   uncertain.Value
 
+	// TaxRate is the capital gains tax on the shares, and MarginalIncomeTaxRate
+	// a flat rate approximating the marginal tax on salary, paid on the
+	// benefit of regular employee options.
 	TaxRate               uncertain.Value
+	MarginalIncomeTaxRate uncertain.Value
 	DiscountFactor        uncertain.Value
 	PurchasePricePerShare uncertain.Value
+	// DeclaredGainShare is the part of the gains realised at an exit that the
+	// owners declare themselves, through the CapitalGainRecorder, and that is
+	// paid out without the TaxRate.
+	DeclaredGainShare float64
 
 	InvestmentRounds map[date.Date]StartupGrowthInvestmentRound
 	ShareChanges     map[date.Date]StartupGrowthShareChange
-	Options          map[date.Date]StartupGrowthOption
+	Options          []StartupGrowthOption
+	ProjectedRounds  map[date.Date]StartupGrowthProjectedRound
+	Exits            map[date.Date]StartupGrowthExit
+	// Preferences are the liquidation preferences of the rounds so far, which
+	// come before the common shares in the value of the company.
+	Preferences []LiquidationPreference
+
+	roundPreferences []StartupGrowthInvestmentRound // rounds with preferences in the forecast, sampled per draw
+	exitPayout       *startupExitPayout
+}
+
+// startupExitPayout is the payout of an exit, in the currency of the shares,
+// waiting to be paid to the payout account, and the capital gain realised by
+// the exit.
+type startupExitPayout struct {
+	accountID string
+	amount    uncertain.Value
+	gain      uncertain.Value
+}
+
+// takeExitPayout returns the payout of an exit applied since it was last
+// called.
+func (s *StartupGrowth) takeExitPayout() (startupExitPayout, bool) {
+	if s.exitPayout == nil {
+		return startupExitPayout{}, false
+	}
+	p := *s.exitPayout
+	s.exitPayout = nil
+	return p, true
 }
 
 func (s *StartupGrowth) Apply(ucfg *uncertain.Config, day date.Date, entities map[string]*ModeledEntity, totalBalance uncertain.Value) uncertain.Value {
@@ -163,6 +323,13 @@ func (s *StartupGrowth) Apply(ucfg *uncertain.Config, day date.Date, entities ma
 		issuedShares := round.Investment.Div(ucfg, pricePerShare)
 		s.TotalShares = round.PreMoneyShares.Add(ucfg, issuedShares)
 		s.Valuation = round.PreMoneyValuation.Add(ucfg, round.Investment)
+		if round.PreferenceMultiple > 0 {
+			s.roundPreferences = append(s.roundPreferences, round)
+		}
+		changed = true
+	}
+	if round, ok := s.ProjectedRounds[day]; ok && s.TotalShares.Mean() > 0 {
+		s.applyProjectedRound(ucfg, round)
 		changed = true
 	}
 	if sc, ok := s.ShareChanges[day]; ok {
@@ -175,20 +342,12 @@ func (s *StartupGrowth) Apply(ucfg *uncertain.Config, day date.Date, entities ma
 		}
 		changed = true
 	}
-	if opt, ok := s.Options[day]; ok {
-		sourceAccount, ok := entities[opt.SourceAccountID]
-		if !ok {
-			panic(fmt.Sprintf("no account found for %s", opt.SourceAccountID))
-		}
-		currentPricePerShare := s.Valuation.Div(ucfg, s.TotalShares)
-		if currentPricePerShare.Mean() > opt.StrikePricePerShare.Mean() {
-			strikeCost := opt.StrikePricePerShare.Mul(ucfg, opt.NumShares)
-			sourceAccount.balance = sourceAccount.balance.Sub(ucfg, strikeCost)
-			s.TotalShares = s.TotalShares.Add(ucfg, opt.NumShares)
-			s.Valuation = s.Valuation.Add(ucfg, strikeCost)
-			s.OwnedShares = s.OwnedShares.Add(ucfg, opt.NumShares)
-			changed = true
-		}
+	if s.applyOptions(ucfg, day, entities) {
+		changed = true
+	}
+	if exit, ok := s.Exits[day]; ok {
+		s.applyExit(ucfg, exit, entities)
+		changed = true
 	}
 	if changed {
 		return s.Balance(ucfg).Sub(ucfg, totalBalance)
@@ -196,10 +355,229 @@ func (s *StartupGrowth) Apply(ucfg *uncertain.Config, day date.Date, entities ma
 	return uncertain.NewFixed(0.0)
 }
 
+// VestOptions brings the vesting of the options up to day and reports whether
+// any more of them vested.
+func (s *StartupGrowth) VestOptions(day date.Date) bool {
+	var vested bool
+	for i := range s.Options {
+		if opt := &s.Options[i]; !opt.closed && opt.vest(day) {
+			vested = true
+		}
+	}
+	return vested
+}
+
+func (o *StartupGrowthOption) vest(day date.Date) bool {
+	v := o.VestedFraction(day)
+	if v == o.vested {
+		return false
+	}
+	o.vested = v
+	return true
+}
+
+// applyOptions vests the options and exercises them by their policy. Whether
+// an option is underwater is decided per sample.
+func (s *StartupGrowth) applyOptions(ucfg *uncertain.Config, day date.Date, entities map[string]*ModeledEntity) bool {
+	var changed bool
+	for i := range s.Options {
+		opt := &s.Options[i]
+		if opt.closed {
+			continue
+		}
+		if opt.vest(day) {
+			if opt.ExercisePolicy == StartupOptionExerciseAtVesting {
+				s.exerciseOption(ucfg, opt, entities, false)
+			}
+			changed = true
+		}
+		if day == opt.Expiry {
+			if opt.ExercisePolicy != StartupOptionExerciseAtExit {
+				s.exerciseOption(ucfg, opt, entities, opt.ExercisePolicy == StartupOptionExerciseInTheMoney)
+			}
+			opt.closed = true
+			changed = true
+		}
+	}
+	return changed
+}
+
+// exerciseOption buys the vested and unexercised shares of the option at the
+// strike price, skipping the samples where the shares are worth less than
+// the strike when inTheMoney is set.
+func (s *StartupGrowth) exerciseOption(ucfg *uncertain.Config, opt *StartupGrowthOption, entities map[string]*ModeledEntity, inTheMoney bool) {
+	sourceAccount, ok := entities[opt.SourceAccountID]
+	if !ok {
+		panic(fmt.Sprintf("no account found for %s", opt.SourceAccountID))
+	}
+	available := opt.unexercised(ucfg)
+	if available.Mean() <= 0 {
+		return
+	}
+	price := s.commonPrice(ucfg, s.Valuation)
+	shares := available
+	if inTheMoney {
+		shares = uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+			if price.Sample(cfg) <= opt.StrikePricePerShare.Sample(cfg) {
+				return 0
+			}
+			return available.Sample(cfg)
+		}).Materialize(ucfg)
+	}
+	strikeCost := opt.StrikePricePerShare.Mul(ucfg, shares)
+	sourceAccount.balance = sourceAccount.balance.Sub(ucfg, strikeCost)
+	costBasis := s.PurchasePricePerShare.Mul(ucfg, s.OwnedShares).Add(ucfg, strikeCost)
+	if opt.TaxTreatment == StartupGrantEmployee {
+		// The benefit is taxed as salary now, at the flat marginal rate, and
+		// becomes part of what the shares cost when they are sold.
+		benefit := uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+			return max(0, price.Sample(cfg)-opt.StrikePricePerShare.Sample(cfg)) * shares.Sample(cfg)
+		}).Materialize(ucfg)
+		sourceAccount.balance = sourceAccount.balance.Sub(ucfg, benefit.Mul(ucfg, s.MarginalIncomeTaxRate))
+		costBasis = costBasis.Add(ucfg, benefit)
+	}
+	s.TotalShares = s.TotalShares.Add(ucfg, shares)
+	s.Valuation = s.Valuation.Add(ucfg, strikeCost)
+	s.OwnedShares = s.OwnedShares.Add(ucfg, shares)
+	if s.OwnedShares.Mean() > 0 {
+		s.PurchasePricePerShare = costBasis.Div(ucfg, s.OwnedShares)
+	}
+	if opt.Exercised.Zero() {
+		opt.Exercised = shares
+	} else {
+		opt.Exercised = opt.Exercised.Add(ucfg, shares)
+	}
+}
+
+// optionValue returns the spread over the strike of the vested options not
+// yet exercised at a price per share, per sample and never below zero, after
+// the tax on it by the tax treatment of each grant.
+func (s *StartupGrowth) optionValue(ucfg *uncertain.Config, price uncertain.Value) uncertain.Value {
+	capital, salary := s.optionSpreads(ucfg, price)
+	return capital.Combine(ucfg, s.TaxRate, func(v, r float64) float64 { return v * (1 - r) }).Add(ucfg, salary)
+}
+
+// optionSpreads returns the spread over the strike of the vested options not
+// yet exercised at a price per share, per sample and never below zero. The
+// spread of the grants taxed as capital is returned before the tax, and that
+// of regular employee options after the tax on it as salary.
+func (s *StartupGrowth) optionSpreads(ucfg *uncertain.Config, price uncertain.Value) (capital, salary uncertain.Value) {
+	capital, salary = uncertain.NewFixed(0), uncertain.NewFixed(0)
+	for i := range s.Options {
+		opt := &s.Options[i]
+		if opt.closed || opt.vested == 0 {
+			continue
+		}
+		available := opt.unexercised(ucfg)
+		if available.Mean() <= 0 {
+			continue
+		}
+		spread := uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+			return max(0, price.Sample(cfg)-opt.StrikePricePerShare.Sample(cfg)) * available.Sample(cfg)
+		}).Materialize(ucfg)
+		if opt.TaxTreatment == StartupGrantEmployee {
+			salary = salary.Add(ucfg, spread.Combine(ucfg, s.MarginalIncomeTaxRate, func(v, r float64) float64 { return v * (1 - r) }))
+		} else {
+			capital = capital.Add(ucfg, spread)
+		}
+	}
+	return capital, salary
+}
+
+// applyProjectedRound sets the price per share of each sample to that of the
+// round when it happens, and keeps the previous price when it does not. The
+// shares issued are counted at their expected number, so the price of later
+// rounds is set from the expected dilution.
+func (s *StartupGrowth) applyProjectedRound(ucfg *uncertain.Config, round StartupGrowthProjectedRound) {
+	preShares := s.TotalShares.Mean()
+	prevPrice := s.Valuation.Div(ucfg, s.TotalShares)
+	issued := round.Probability * round.Investment.Mean() / (round.PreMoneyValuation.Mean() / preShares)
+	postShares := preShares + issued
+	s.TotalShares = uncertain.NewFixed(postShares)
+	s.Valuation = uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+		if cfg.RNG.Float64() < round.Probability {
+			return round.PreMoneyValuation.Sample(cfg) / preShares * postShares
+		}
+		return prevPrice.Sample(cfg) * postShares
+	}).Materialize(ucfg)
+}
+
+// applyExit sells the owned shares and cashes out the vested options,
+// evaluating the failure and the multiple per sample, and leaves no shares
+// behind. The payout is paid to the payout account and the gain reported by
+// the entity, see takeExitPayout.
+func (s *StartupGrowth) applyExit(ucfg *uncertain.Config, exit StartupGrowthExit, entities map[string]*ModeledEntity) {
+	if _, ok := entities[exit.PayoutAccountID]; !ok {
+		panic(fmt.Sprintf("no account found for %s", exit.PayoutAccountID))
+	}
+	for i := range s.Options {
+		if opt := &s.Options[i]; !opt.closed && opt.AccelerateOnExit {
+			opt.vested = 1
+		}
+	}
+	proceeds := uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+		return s.Valuation.Sample(cfg) * exit.Multiple.Sample(cfg)
+	}).Materialize(ucfg)
+	exitPrice := s.commonPrice(ucfg, proceeds)
+	capitalOptions, salaryOptions := s.optionSpreads(ucfg, exitPrice)
+	// The payout and the gain are drawn together, so a failed exit realises
+	// the cost of the shares as a loss.
+	payouts := make([]float64, ucfg.Samples)
+	gains := make([]float64, ucfg.Samples)
+	for i := range payouts {
+		shares := s.OwnedShares.Sample(ucfg)
+		cost := s.PurchasePricePerShare.Sample(ucfg) * shares
+		if ucfg.RNG.Float64() < exit.FailureProbability {
+			gains[i] = -cost
+			continue
+		}
+		gross := exitPrice.Sample(ucfg)*shares + capitalOptions.Sample(ucfg)
+		gains[i] = gross - cost
+		if gains[i] > 0 {
+			gross -= gains[i] * (1 - s.DeclaredGainShare) * s.TaxRate.Sample(ucfg)
+		}
+		payouts[i] = gross + salaryOptions.Sample(ucfg)
+	}
+	s.exitPayout = &startupExitPayout{
+		accountID: exit.PayoutAccountID,
+		amount:    uncertain.Value{Distribution: uncertain.DistEmpirical, Samples: payouts},
+		gain:      uncertain.Value{Distribution: uncertain.DistEmpirical, Samples: gains},
+	}
+	s.OwnedShares = uncertain.NewFixed(0)
+	s.PurchasePricePerShare = uncertain.NewFixed(0)
+	for i := range s.Options {
+		s.Options[i].closed = true
+	}
+}
+
+// Balance returns the value of the owned shares and the vested options after
+// the valuation discount and the tax on the gain.
 func (s *StartupGrowth) Balance(ucfg *uncertain.Config) uncertain.Value {
-	fractionOwned := s.OwnedShares.Div(ucfg, s.TotalShares)
-	grossValue := s.Valuation.Mul(ucfg, fractionOwned)
-	discountedGrossValue := grossValue.Mul(ucfg, s.DiscountFactor)
+	return s.shareBalance(ucfg).Add(ucfg, s.optionBalance(ucfg))
+}
+
+// commonPrice returns what a common share gets, per sample, when the company
+// is worth value, with the liquidation preferences paid first.
+func (s *StartupGrowth) commonPrice(ucfg *uncertain.Config, value uncertain.Value) uncertain.Value {
+	if len(s.Preferences) == 0 && len(s.roundPreferences) == 0 {
+		return value.Div(ucfg, s.TotalShares)
+	}
+	totalShares, prefs, rounds := s.TotalShares, slices.Clone(s.Preferences), slices.Clone(s.roundPreferences)
+	return uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+		drawn := prefs
+		if len(rounds) > 0 {
+			drawn = slices.Clone(prefs)
+			for _, round := range rounds {
+				drawn = append(drawn, round.preference(cfg))
+			}
+		}
+		return CommonPricePerShare(value.Sample(cfg), totalShares.Sample(cfg), drawn)
+	}).Materialize(ucfg)
+}
+
+func (s *StartupGrowth) shareBalance(ucfg *uncertain.Config) uncertain.Value {
+	price := s.commonPrice(ucfg, s.Valuation.Mul(ucfg, s.DiscountFactor))
+	discountedGrossValue := price.Mul(ucfg, s.OwnedShares)
 	purchasePrice := s.PurchasePricePerShare.Mul(ucfg, s.OwnedShares)
 	if purchasePrice.Mean() > discountedGrossValue.Mean() {
 		return discountedGrossValue
@@ -208,3 +586,11 @@ func (s *StartupGrowth) Balance(ucfg *uncertain.Config) uncertain.Value {
 	tax := capitalGains.Mul(ucfg, s.TaxRate)
 	return discountedGrossValue.Sub(ucfg, tax)
 }
+
+func (s *StartupGrowth) optionBalance(ucfg *uncertain.Config) uncertain.Value {
+	if len(s.Options) == 0 || s.TotalShares.Mean() <= 0 {
+		return uncertain.NewFixed(0)
+	}
+	price := s.commonPrice(ucfg, s.Valuation.Mul(ucfg, s.DiscountFactor))
+	return s.optionValue(ucfg, price)
+}
@@ -0,0 +1,111 @@
+package finance
+
+import (
+	"math"
+	"slices"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+type InterestRateModelType string
+
+const (
+	// InterestRateVasicek lets the rate move normally around its long-term
+	// level, which allows negative rates.
+	InterestRateVasicek InterestRateModelType = "vasicek"
+	// InterestRateCIR scales the volatility by the square root of the rate,
+	// which keeps the rate from going negative.
+	InterestRateCIR InterestRateModelType = "cir"
+)
+
+// InterestRateProcess simulates a mean-reverting short rate, such as the
+// Riksbank policy rate. It starts at InitialRate on the first day it is asked
+// for and is advanced one day at a time, so every growth model sharing the
+// process sees the same rate path.
+type InterestRateProcess struct {
+	Type           InterestRateModelType
+	InitialRate    float64
+	LongTermRate   float64 // The level the rate reverts to
+	ReversionSpeed float64 // How quickly the rate reverts, per year
+	Volatility     float64 // Annual volatility of the rate
+
+	day   date.Date
+	rates []float64
+}
+
+// RateOn returns the distribution of the rate on day. Days must be requested
+// in non-decreasing order, days before the first stay at the initial rate.
+func (p *InterestRateProcess) RateOn(ucfg *uncertain.Config, day date.Date) uncertain.Value {
+	if p.rates == nil {
+		p.rates = make([]float64, max(1, ucfg.Samples))
+		for i := range p.rates {
+			p.rates[i] = p.InitialRate
+		}
+		p.day = day
+	}
+	for ; p.day.Before(day); p.day = p.day.Add(date.Day) {
+		for i, r := range p.rates {
+			p.rates[i] = p.step(r, ucfg.RNG.NormFloat64())
+		}
+	}
+	if p.Volatility == 0 {
+		return uncertain.NewFixed(p.rates[0])
+	}
+	return uncertain.Value{Distribution: uncertain.DistEmpirical, Samples: slices.Clone(p.rates)}
+}
+
+func (p *InterestRateProcess) step(r, z float64) float64 {
+	const dt = 1.0 / 365.0
+	drift := p.ReversionSpeed * (p.LongTermRate - r) * dt
+	switch p.Type {
+	case InterestRateCIR:
+		return math.Max(0, r+drift+p.Volatility*math.Sqrt(math.Max(0, r)*dt)*z)
+	default:
+		return r + drift + p.Volatility*math.Sqrt(dt)*z
+	}
+}
+
+// rateProcesses returns the interest rate processes a growth model follows.
+func rateProcesses(g GrowthModel) []*InterestRateProcess {
+	switch g := g.(type) {
+	case *VariableRateGrowth:
+		return []*InterestRateProcess{g.Rates}
+	case *GrowthCombined:
+		var processes []*InterestRateProcess
+		for _, gr := range g.Growths {
+			processes = append(processes, rateProcesses(gr)...)
+		}
+		return processes
+	}
+	return nil
+}
+
+var _ GrowthModel = &VariableRateGrowth{}
+
+// VariableRateGrowth accrues interest at a shared InterestRateProcess plus a
+// margin. During a binding period (bindningstid) the rate on the first day is
+// locked until LockedUntil, after which it floats with the process.
+type VariableRateGrowth struct {
+	TimeFrameGrowth
+	Rates       *InterestRateProcess
+	Margin      uncertain.Value // Added to the process rate, e.g. the bank's margin
+	LockedUntil date.Date       // Optional end of the binding period
+
+	locked uncertain.Value
+}
+
+func (g *VariableRateGrowth) Apply(ucfg *uncertain.Config, day date.Date, entities map[string]*ModeledEntity, totalBalance uncertain.Value) uncertain.Value {
+	rate := g.locked
+	if rate.Distribution == "" || !day.Before(g.LockedUntil) {
+		rate = g.Rates.RateOn(ucfg, day)
+		if g.Margin.Valid() {
+			rate = rate.Add(ucfg, g.Margin)
+		}
+		if day.Before(g.LockedUntil) {
+			g.locked = rate
+		}
+	}
+	dailyGrowthFactor := rate.Add(ucfg, uncertain.NewFixed(1)).Pow(ucfg, uncertain.NewFixed(1.0/365.0))
+	return totalBalance.Mul(ucfg, dailyGrowthFactor.Sub(ucfg, uncertain.NewFixed(1)))
+}
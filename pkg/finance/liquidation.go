@@ -0,0 +1,83 @@
+package finance
+
+import (
+	"slices"
+)
+
+// LiquidationPreference is the preference of the preferred shares issued in
+// a round. At an exit the holders get Multiple times what they Invested
+// before the common shares, most senior first and pari passu within the same
+// Seniority. Participating holders also share in what is left as if their
+// shares were common, while non-participating holders convert to common
+// shares when that pays more than the preference.
+type LiquidationPreference struct {
+	Shares        float64
+	Invested      float64
+	Multiple      float64
+	Participating bool
+	Seniority     int
+}
+
+func (p LiquidationPreference) claim() float64 {
+	return p.Multiple * p.Invested
+}
+
+// CommonPricePerShare returns what each common share receives when proceeds
+// are distributed through the liquidation preferences. Without preferences
+// that is the pro-rata share of the proceeds.
+func CommonPricePerShare(proceeds, totalShares float64, prefs []LiquidationPreference) float64 {
+	converted := make([]bool, len(prefs))
+	for {
+		price := distributeProceeds(proceeds, totalShares, prefs, converted)
+		// The non-participating holder with the lowest preference per share
+		// is the first to gain from converting.
+		next := -1
+		for i, p := range prefs {
+			if converted[i] || p.Participating || p.Multiple <= 0 || p.Shares <= 0 {
+				continue
+			}
+			if p.claim()/p.Shares < price && (next < 0 || p.claim()/p.Shares < prefs[next].claim()/prefs[next].Shares) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return price
+		}
+		converted[next] = true
+	}
+}
+
+// distributeProceeds pays the preferences not converted to common shares by
+// seniority and returns what is left per share taking part in the rest.
+func distributeProceeds(proceeds, totalShares float64, prefs []LiquidationPreference, converted []bool) float64 {
+	var seniorities []int
+	for i, p := range prefs {
+		if !converted[i] && p.Multiple > 0 && !slices.Contains(seniorities, p.Seniority) {
+			seniorities = append(seniorities, p.Seniority)
+		}
+	}
+	slices.Sort(seniorities)
+	slices.Reverse(seniorities)
+
+	remaining := proceeds
+	for _, seniority := range seniorities {
+		var claim float64
+		for i, p := range prefs {
+			if !converted[i] && p.Multiple > 0 && p.Seniority == seniority {
+				claim += p.claim()
+			}
+		}
+		remaining -= min(max(0, remaining), claim)
+	}
+
+	shares := totalShares
+	for i, p := range prefs {
+		if !converted[i] && p.Multiple > 0 && !p.Participating {
+			shares -= p.Shares
+		}
+	}
+	if shares <= 0 || remaining <= 0 {
+		return 0
+	}
+	return remaining / shares
+}
@@ -36,6 +36,31 @@ func EmptyTransferRecorder() TransferRecorder {
 	})
 }
 
+// InterestRecorder is notified when accrued interest is paid to the cash flow
+// destination of an entity. Interest paid on a loan has a negative amount.
+type InterestRecorder interface {
+	OnInterest(accountID, destinationAccountID string, day date.Date, amount uncertain.Value) error
+}
+
+type InterestRecorderFunc func(accountID, destinationAccountID string, day date.Date, amount uncertain.Value) error
+
+func (f InterestRecorderFunc) OnInterest(accountID, destinationAccountID string, day date.Date, amount uncertain.Value) error {
+	return f(accountID, destinationAccountID, day, amount)
+}
+
+// CapitalGainRecorder is notified of a capital gain realised by selling the
+// holdings of an entity, such as the shares sold at a startup exit. A loss has
+// a negative amount.
+type CapitalGainRecorder interface {
+	OnCapitalGain(accountID string, day date.Date, gain uncertain.Value) error
+}
+
+type CapitalGainRecorderFunc func(accountID string, day date.Date, gain uncertain.Value) error
+
+func (f CapitalGainRecorderFunc) OnCapitalGain(accountID string, day date.Date, gain uncertain.Value) error {
+	return f(accountID, day, gain)
+}
+
 type Recorder interface {
 	SnapshotRecorder
 	TransferRecorder
@@ -44,6 +69,8 @@ type Recorder interface {
 type CompositeRecorder struct {
 	SnapshotRecorder
 	TransferRecorder
+	InterestRecorder    // Optional, RunPrediction only reports interest to recorders implementing InterestRecorder
+	CapitalGainRecorder // Optional, RunPrediction only reports gains to recorders implementing CapitalGainRecorder
 }
 
 func (r CompositeRecorder) OnSnapshot(accountID string, day date.Date, balance uncertain.Value) error {
@@ -59,3 +86,17 @@ func (r CompositeRecorder) OnTransfer(sourceAccountID, destinationAccountID stri
 	}
 	return r.TransferRecorder.OnTransfer(sourceAccountID, destinationAccountID, day, amount)
 }
+
+func (r CompositeRecorder) OnInterest(accountID, destinationAccountID string, day date.Date, amount uncertain.Value) error {
+	if r.InterestRecorder == nil {
+		return nil
+	}
+	return r.InterestRecorder.OnInterest(accountID, destinationAccountID, day, amount)
+}
+
+func (r CompositeRecorder) OnCapitalGain(accountID string, day date.Date, gain uncertain.Value) error {
+	if r.CapitalGainRecorder == nil {
+		return nil
+	}
+	return r.CapitalGainRecorder.OnCapitalGain(accountID, day, gain)
+}
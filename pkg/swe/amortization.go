@@ -0,0 +1,67 @@
+package swe
+
+const (
+	// AmortizationLTVHigh is the loan-to-value above which 2% of the
+	// mortgage is amortized yearly.
+	AmortizationLTVHigh = 0.70
+	// AmortizationLTVLow is the loan-to-value above which 1% is amortized.
+	AmortizationLTVLow = 0.50
+	// AmortizationDTIThreshold is the debt-to-income ratio (skuldkvot) above
+	// which another 1% is amortized.
+	AmortizationDTIThreshold = 4.5
+	// DefaultRevaluationYears is how often the bank allows the property to be
+	// revalued to lower the amortization requirement.
+	DefaultRevaluationYears = 5
+)
+
+// AmortizationInput holds the figures the amorteringskrav is based on. All
+// amounts are positive.
+type AmortizationInput struct {
+	// PropertyLoans is the total mortgage on the property.
+	PropertyLoans float64
+	PropertyValue float64
+	// TotalDebt is the household's total mortgage debt, used for the
+	// debt-to-income rule.
+	TotalDebt float64
+	// GrossYearlyIncome is the household's gross yearly income. When zero the
+	// debt-to-income rule is not applied.
+	GrossYearlyIncome float64
+}
+
+// LoanToValue returns the mortgage as a fraction of the property value.
+func (in AmortizationInput) LoanToValue() float64 {
+	if in.PropertyValue <= 0 {
+		return 1
+	}
+	return in.PropertyLoans / in.PropertyValue
+}
+
+// DebtToIncome returns the total debt as a multiple of the gross yearly
+// income, or 0 when the income is unknown.
+func (in AmortizationInput) DebtToIncome() float64 {
+	if in.GrossYearlyIncome <= 0 {
+		return 0
+	}
+	return in.TotalDebt / in.GrossYearlyIncome
+}
+
+// RequiredAmortizationRate returns the minimum yearly amortization as a
+// fraction of the mortgage under Finansinspektionen's amorteringskrav: 2%
+// above 70% loan-to-value, 1% above 50%, plus 1% when the debt exceeds 4.5
+// times the gross yearly income.
+func RequiredAmortizationRate(in AmortizationInput) float64 {
+	if in.PropertyLoans <= 0 {
+		return 0
+	}
+	var rate float64
+	switch ltv := in.LoanToValue(); {
+	case ltv > AmortizationLTVHigh:
+		rate = 0.02
+	case ltv > AmortizationLTVLow:
+		rate = 0.01
+	}
+	if in.DebtToIncome() > AmortizationDTIThreshold {
+		rate += 0.01
+	}
+	return rate
+}
@@ -0,0 +1,44 @@
+package swe_test
+
+import (
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+func TestRequiredAmortizationRate(t *testing.T) {
+	tests := []struct {
+		name string
+		in   swe.AmortizationInput
+		want float64
+	}{
+		{"no loan", swe.AmortizationInput{PropertyValue: 5_000_000}, 0},
+		{"above 70% ltv", swe.AmortizationInput{PropertyLoans: 3_600_000, PropertyValue: 5_000_000}, 0.02},
+		{"exactly 70% ltv", swe.AmortizationInput{PropertyLoans: 3_500_000, PropertyValue: 5_000_000}, 0.01},
+		{"above 50% ltv", swe.AmortizationInput{PropertyLoans: 3_000_000, PropertyValue: 5_000_000}, 0.01},
+		{"below 50% ltv", swe.AmortizationInput{PropertyLoans: 2_000_000, PropertyValue: 5_000_000}, 0},
+		{"unknown property value", swe.AmortizationInput{PropertyLoans: 1_000_000}, 0.02},
+		{
+			"high debt to income",
+			swe.AmortizationInput{PropertyLoans: 3_600_000, PropertyValue: 5_000_000, TotalDebt: 3_600_000, GrossYearlyIncome: 700_000},
+			0.03,
+		},
+		{
+			"debt to income below threshold",
+			swe.AmortizationInput{PropertyLoans: 2_000_000, PropertyValue: 5_000_000, TotalDebt: 3_000_000, GrossYearlyIncome: 700_000},
+			0,
+		},
+		{
+			"debt to income counts all debt",
+			swe.AmortizationInput{PropertyLoans: 2_000_000, PropertyValue: 5_000_000, TotalDebt: 3_600_000, GrossYearlyIncome: 700_000},
+			0.01,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := swe.RequiredAmortizationRate(tt.in); got != tt.want {
+				t.Errorf("RequiredAmortizationRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package swe
+
+// CalculateBonusTax returns the tax on a bonus (engångsbelopp) paid on top of
+// a monthly taxable income. Skatteverket's engångsskatt tables set the rate
+// from the expected annual income, which is approximated by the marginal tax
+// of spreading the bonus over the year with the monthly taxFunc.
+func CalculateBonusTax(monthlyIncome, bonus float64, taxFunc func(float64) (float64, error)) (float64, error) {
+	if bonus == 0 {
+		return 0, nil
+	}
+	base, err := taxFunc(monthlyIncome)
+	if err != nil {
+		return 0, err
+	}
+	total, err := taxFunc(monthlyIncome + bonus/12)
+	if err != nil {
+		return 0, err
+	}
+	return 12 * (total - base), nil
+}
+
+// CalculateBonusITP1Pension returns the ITP1 pension contribution on a bonus,
+// the increase in the yearly premium from adding it to the pensionable salary.
+func CalculateBonusITP1Pension(grossMonthlySalary, bonus, inkomstbasbelopp float64) float64 {
+	base := CalculateITP1Pension(grossMonthlySalary, inkomstbasbelopp)
+	return 12 * (CalculateITP1Pension(grossMonthlySalary+bonus/12, inkomstbasbelopp) - base)
+}
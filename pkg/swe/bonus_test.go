@@ -0,0 +1,51 @@
+package swe_test
+
+import (
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+func TestCalculateBonusTax(t *testing.T) {
+	// 30% up to 50 000 kr per month, 50% above.
+	taxFunc := func(income float64) (float64, error) {
+		if income <= 50000 {
+			return income * 0.30, nil
+		}
+		return 50000*0.30 + (income-50000)*0.50, nil
+	}
+
+	tests := []struct {
+		name          string
+		monthlyIncome float64
+		bonus         float64
+		want          float64
+	}{
+		{name: "no bonus", monthlyIncome: 40000, bonus: 0, want: 0},
+		{name: "below breakpoint", monthlyIncome: 40000, bonus: 60000, want: 18000},
+		{name: "crosses breakpoint", monthlyIncome: 45000, bonus: 120000, want: 60000*0.30 + 60000*0.50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := swe.CalculateBonusTax(tt.monthlyIncome, tt.bonus, taxFunc)
+			if err != nil {
+				t.Fatalf("CalculateBonusTax: %v", err)
+			}
+			if !approxEqual(got, tt.want, 0.01) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateBonusITP1Pension(t *testing.T) {
+	const ibb = 76200.0
+	cutoffYearly := ibb * 7.5
+
+	// 40 000 kr/month is 480 000 kr/year, 91 500 kr below the cutoff.
+	got := swe.CalculateBonusITP1Pension(40000, 100000, ibb)
+	want := (cutoffYearly-480000)*0.045 + (580000-cutoffYearly)*0.30
+	if !approxEqual(got, want, 0.01) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package swe
+
+import (
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+)
+
+const (
+	// BarnbidragMonthly is the monthly child benefit per child.
+	BarnbidragMonthly = 1250
+	// BarnbidragEndAge is the age after which barnbidrag stops.
+	BarnbidragEndAge = 16
+)
+
+// flerbarnstillaggMonthly is the monthly large-family supplement by number of
+// children, indexed from 0.
+var flerbarnstillaggMonthly = []float64{0, 0, 150, 730, 1740, 2990}
+
+// FlerbarnstillaggMonthly returns the monthly large-family supplement for a
+// household receiving barnbidrag for n children. Each child beyond five adds
+// another 1250 kr.
+func FlerbarnstillaggMonthly(n int) float64 {
+	if n < len(flerbarnstillaggMonthly) {
+		return flerbarnstillaggMonthly[max(0, n)]
+	}
+	last := len(flerbarnstillaggMonthly) - 1
+	return flerbarnstillaggMonthly[last] + float64(n-last)*BarnbidragMonthly
+}
+
+// ChildBenefitMonthly returns the household's monthly barnbidrag including
+// flerbarnstillägg for n children.
+func ChildBenefitMonthly(n int) float64 {
+	if n <= 0 {
+		return 0
+	}
+	return float64(n)*BarnbidragMonthly + FlerbarnstillaggMonthly(n)
+}
+
+// BarnbidragPeriod returns the first day of the first month barnbidrag is paid
+// for a child born on birthDate, which is the month after birth, and the first
+// day of the month after the last payment. Payments end with the quarter in
+// which the child turns 16.
+func BarnbidragPeriod(birthDate date.Date) (start, end date.Date) {
+	born := birthDate.ToStdTime()
+	start = date.FromTime(time.Date(born.Year(), born.Month()+1, 1, 0, 0, 0, 0, time.UTC))
+	endYear := born.Year() + BarnbidragEndAge
+	quarterEnd := ((born.Month()-1)/3 + 1) * 3
+	end = date.FromTime(time.Date(endYear, quarterEnd+1, 1, 0, 0, 0, 0, time.UTC))
+	return start, end
+}
@@ -0,0 +1,46 @@
+package swe_test
+
+import (
+	"testing"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+func TestChildBenefitMonthly(t *testing.T) {
+	tests := []struct {
+		children int
+		want     float64
+	}{
+		{0, 0},
+		{1, 1250},
+		{2, 2650},
+		{3, 4480},
+		{4, 6740},
+		{5, 9240},
+		{6, 11740},
+	}
+	for _, tt := range tests {
+		if got := swe.ChildBenefitMonthly(tt.children); got != tt.want {
+			t.Errorf("ChildBenefitMonthly(%d) = %f, want %f", tt.children, got, tt.want)
+		}
+	}
+}
+
+func TestBarnbidragPeriod(t *testing.T) {
+	tests := []struct {
+		birthDate  string
+		start, end string
+	}{
+		{"2020-05-14", "2020-06-01", "2036-07-01"},
+		{"2020-12-31", "2021-01-01", "2037-01-01"},
+		{"2021-01-01", "2021-02-01", "2037-04-01"},
+	}
+	for _, tt := range tests {
+		birthDate, _ := date.ParseDate(tt.birthDate)
+		start, end := swe.BarnbidragPeriod(birthDate)
+		if start.String() != tt.start || end.String() != tt.end {
+			t.Errorf("BarnbidragPeriod(%s) = %s, %s, want %s, %s", tt.birthDate, start, end, tt.start, tt.end)
+		}
+	}
+}
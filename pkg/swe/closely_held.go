@@ -0,0 +1,148 @@
+package swe
+
+import "math"
+
+const (
+	// CorporateTaxRate is the bolagsskatt on the profit of an AB.
+	CorporateTaxRate = 0.206
+	// EmployerContributionRate is the full arbetsgivaravgift on salaries.
+	EmployerContributionRate = 0.3142
+	// QualifiedDividendTaxRate is the tax on dividends on qualified shares in
+	// a fåmansbolag within the gränsbelopp, two thirds of the 30% capital
+	// income tax.
+	QualifiedDividendTaxRate = 0.20
+	// SimplifiedRuleIBB is the gränsbelopp under förenklingsregeln, in
+	// inkomstbasbelopp of the year before the dividend.
+	SimplifiedRuleIBB = 2.75
+	// WageBasedShare is the part of the company's cash salaries added to the
+	// gränsbelopp under huvudregeln (lönebaserat utrymme).
+	WageBasedShare = 0.5
+	// DefaultCapitalRate is the yearly return on the omkostnadsbelopp under
+	// huvudregeln, statslåneräntan plus 9 percentage points.
+	DefaultCapitalRate = 0.1162
+	// DefaultStatslaneranta is the statslåneränta DefaultCapitalRate is based
+	// on.
+	DefaultStatslaneranta = 0.0262
+	// SavedSpaceUplift is what the sparat utdelningsutrymme grows by each year
+	// on top of statslåneräntan.
+	SavedSpaceUplift = 0.03
+)
+
+// GransbeloppRule is how the yearly gränsbelopp for qualified dividends is
+// computed.
+type GransbeloppRule string
+
+const (
+	GransbeloppSimplified GransbeloppRule = "simplified"
+	GransbeloppMain       GransbeloppRule = "main"
+)
+
+// GransbeloppInput holds what the gränsbelopp for a dividend is based on.
+// IBB and the salaries are those of the year before the dividend.
+type GransbeloppInput struct {
+	Rule            GransbeloppRule
+	IBB             float64
+	OwnershipShare  float64
+	AcquisitionCost float64
+	CapitalRate     float64
+	// TotalSalaries is the cash salary paid by the company to all employees,
+	// and OwnerSalary the part paid to the owner.
+	TotalSalaries float64
+	OwnerSalary   float64
+	// Saved is the sparat utdelningsutrymme from earlier years.
+	Saved float64
+}
+
+// UpliftSavedSpace returns the sparat utdelningsutrymme carried into the next
+// year, uppräknat by statslåneräntan plus 3 percentage points.
+func UpliftSavedSpace(saved, statslaneranta float64) float64 {
+	return saved * (1 + statslaneranta + SavedSpaceUplift)
+}
+
+// SalaryRequirement returns the löneuttagskrav the owner's salary must meet
+// for the lönebaserat utrymme: 6 IBB plus 5% of the company's cash salaries,
+// but never more than 9.6 IBB.
+func SalaryRequirement(ibb, totalSalaries float64) float64 {
+	return math.Min(6*ibb+0.05*totalSalaries, 9.6*ibb)
+}
+
+// CalculateGransbelopp returns the dividend the owner can take at the
+// qualified dividend tax rate. Förenklingsregeln gives 2.75 IBB split by
+// ownership, while huvudregeln gives a return on the omkostnadsbelopp plus
+// half of the company's salaries when the owner meets the löneuttagskrav.
+func CalculateGransbelopp(in GransbeloppInput) float64 {
+	share := in.OwnershipShare
+	if share <= 0 {
+		share = 1
+	}
+	var g float64
+	switch in.Rule {
+	case GransbeloppMain:
+		g = in.AcquisitionCost * in.CapitalRate
+		if in.OwnerSalary > 0 && in.OwnerSalary >= SalaryRequirement(in.IBB, in.TotalSalaries) {
+			g += WageBasedShare * in.TotalSalaries * share
+		}
+	default:
+		g = SimplifiedRuleIBB * in.IBB * share
+	}
+	return g + in.Saved
+}
+
+// OwnerSplit is how a year's company profit reaches the owner as salary and
+// dividend, with the taxes paid on the way. Retained is the profit after
+// corporate tax left in the company.
+type OwnerSplit struct {
+	Salary                float64
+	EmployerContributions float64
+	CorporateTax          float64
+	Dividend              float64
+	IncomeTax             float64
+	DividendTax           float64
+	Retained              float64
+	Net                   float64
+}
+
+// CalculateOwnerSplit pays the owner a yearly salary out of profit (before
+// salary costs) and a dividend of what remains after corporate tax, up to
+// gransbelopp. incomeTax returns the yearly income tax on a salary.
+func CalculateOwnerSplit(profit, salary, gransbelopp float64, incomeTax func(annual float64) float64) OwnerSplit {
+	sp := OwnerSplit{
+		Salary:                salary,
+		EmployerContributions: salary * EmployerContributionRate,
+	}
+	taxable := profit - salary - sp.EmployerContributions
+	sp.CorporateTax = math.Max(0, taxable) * CorporateTaxRate
+	distributable := math.Max(0, taxable-sp.CorporateTax)
+	sp.Dividend = math.Min(distributable, math.Max(0, gransbelopp))
+	sp.DividendTax = sp.Dividend * QualifiedDividendTaxRate
+	sp.Retained = distributable - sp.Dividend
+	if salary > 0 {
+		sp.IncomeTax = incomeTax(salary)
+	}
+	sp.Net = salary - sp.IncomeTax + sp.Dividend - sp.DividendTax
+	return sp
+}
+
+// PlanOwnerSplit returns the salary and dividend giving the owner the most,
+// trying salaries in steps of step up to what the profit can pay for. Profit
+// left in the company will eventually be paid out above the gränsbelopp and
+// taxed as earned income, so it is valued after the top marginal income tax.
+// That makes salary pay off up to the state income tax threshold and
+// dividends up to the gränsbelopp, which may depend on the salary as it does
+// under huvudregeln.
+func PlanOwnerSplit(profit, step float64, gransbelopp func(salary float64) float64, incomeTax func(annual float64) float64) OwnerSplit {
+	const high = 5_000_000.0
+	retainedValue := 1 - (incomeTax(high+step)-incomeTax(high))/step
+	value := func(sp OwnerSplit) float64 {
+		return sp.Net + sp.Retained*retainedValue
+	}
+	best := CalculateOwnerSplit(profit, 0, gransbelopp(0), incomeTax)
+	maxSalary := profit / (1 + EmployerContributionRate)
+	for salary := step; salary <= maxSalary; salary += step {
+		sp := CalculateOwnerSplit(profit, salary, gransbelopp(salary), incomeTax)
+		if value(sp) > value(best)+1e-6 {
+			best = sp
+		}
+	}
+	return best
+}
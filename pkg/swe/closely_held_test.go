@@ -0,0 +1,103 @@
+package swe_test
+
+import (
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+func TestCalculateGransbelopp(t *testing.T) {
+	const ibb = 80600.0
+	tests := []struct {
+		name string
+		in   swe.GransbeloppInput
+		want float64
+	}{
+		{
+			name: "förenklingsregeln",
+			in:   swe.GransbeloppInput{IBB: ibb},
+			want: 2.75 * ibb,
+		},
+		{
+			name: "förenklingsregeln split by ownership with saved space",
+			in:   swe.GransbeloppInput{IBB: ibb, OwnershipShare: 0.5, Saved: 10000},
+			want: 2.75*ibb*0.5 + 10000,
+		},
+		{
+			name: "huvudregeln meeting the löneuttagskrav",
+			in: swe.GransbeloppInput{
+				Rule: swe.GransbeloppMain, IBB: ibb, AcquisitionCost: 25000, CapitalRate: swe.DefaultCapitalRate,
+				TotalSalaries: 600000, OwnerSalary: 600000,
+			},
+			want: 25000*swe.DefaultCapitalRate + 300000,
+		},
+		{
+			name: "huvudregeln below the löneuttagskrav",
+			in: swe.GransbeloppInput{
+				Rule: swe.GransbeloppMain, IBB: ibb, AcquisitionCost: 25000, CapitalRate: swe.DefaultCapitalRate,
+				TotalSalaries: 500000, OwnerSalary: 500000,
+			},
+			want: 25000 * swe.DefaultCapitalRate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := swe.CalculateGransbelopp(tt.in); !approxEqual(got, tt.want, 0.01) {
+				t.Errorf("CalculateGransbelopp() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalaryRequirement(t *testing.T) {
+	if got := swe.SalaryRequirement(80600, 600000); !approxEqual(got, 6*80600+30000, 0.01) {
+		t.Errorf("SalaryRequirement() = %v, want 6 IBB + 5%%", got)
+	}
+	if got := swe.SalaryRequirement(80600, 10_000_000); !approxEqual(got, 9.6*80600, 0.01) {
+		t.Errorf("SalaryRequirement() = %v, want capped at 9.6 IBB", got)
+	}
+}
+
+func TestCalculateOwnerSplit(t *testing.T) {
+	flat := func(annual float64) float64 { return annual * 0.3 }
+	sp := swe.CalculateOwnerSplit(1_000_000, 400000, 200000, flat)
+
+	taxable := 1_000_000 - 400000*(1+swe.EmployerContributionRate)
+	if !approxEqual(sp.CorporateTax, taxable*swe.CorporateTaxRate, 0.01) {
+		t.Errorf("CorporateTax = %v, want %v", sp.CorporateTax, taxable*swe.CorporateTaxRate)
+	}
+	if sp.Dividend != 200000 || sp.DividendTax != 40000 {
+		t.Errorf("dividend = %v taxed %v, want 200000 taxed 40000", sp.Dividend, sp.DividendTax)
+	}
+	if !approxEqual(sp.Retained, taxable*(1-swe.CorporateTaxRate)-200000, 0.01) {
+		t.Errorf("Retained = %v", sp.Retained)
+	}
+	if !approxEqual(sp.Net, 400000*0.7+160000, 0.01) {
+		t.Errorf("Net = %v, want %v", sp.Net, 400000*0.7+160000)
+	}
+}
+
+func TestPlanOwnerSplit_SalaryUpToStateTaxThreshold(t *testing.T) {
+	p := swe.IncomeTaxParams{
+		Prisbasbelopp:       58800,
+		MunicipalTaxRate:    0.32,
+		StateTaxThreshold:   swe.DefaultStateTaxThreshold,
+		StateTaxRate:        swe.DefaultStateTaxRate,
+		PublicServiceFeeMax: swe.DefaultPublicServiceFeeMax,
+	}
+	incomeTax := func(annual float64) float64 { return swe.CalculateAnnualIncomeTax(annual, p).TotalTax }
+	gransbelopp := swe.CalculateGransbelopp(swe.GransbeloppInput{IBB: 80600})
+
+	sp := swe.PlanOwnerSplit(2_000_000, 12000, func(float64) float64 { return gransbelopp }, incomeTax)
+	// The state income tax starts a little above the threshold, after the
+	// grundavdrag, so the salary ends just above it.
+	if sp.Salary < swe.DefaultStateTaxThreshold || sp.Salary > swe.DefaultStateTaxThreshold+36000 {
+		t.Errorf("Salary = %v, want just above the state tax threshold %v", sp.Salary, swe.DefaultStateTaxThreshold)
+	}
+	if sp.Dividend != gransbelopp {
+		t.Errorf("Dividend = %v, want the whole gränsbelopp %v", sp.Dividend, gransbelopp)
+	}
+	if sp.Retained <= 0 {
+		t.Errorf("expected profit above the gränsbelopp to be retained, got %v", sp.Retained)
+	}
+}
@@ -0,0 +1,47 @@
+package swe
+
+import "math"
+
+const (
+	// CSNMaxRepaymentYears is the longest repayment period of an annuity loan.
+	CSNMaxRepaymentYears = 25
+	// CSNFinalRepaymentAge is the age by which the loan must be repaid.
+	CSNFinalRepaymentAge = 64
+	// CSNAnnualIncrease is how much the yearly amount grows each year.
+	CSNAnnualIncrease = 0.02
+	// CSNReductionRate is the share of the income paid under nedsättning.
+	CSNReductionRate = 0.05
+)
+
+// CSNFinalRepaymentYear returns the last year of repayment for a borrower
+// born in birthYear.
+func CSNFinalRepaymentYear(birthYear int) int {
+	return birthYear + CSNFinalRepaymentAge
+}
+
+// CSNRepaymentYears returns the years left to repay in year, including year,
+// given the final repayment year. It is at least 1 and at most 25.
+func CSNRepaymentYears(year, finalYear int) int {
+	return max(1, min(CSNMaxRepaymentYears, finalYear-year+1))
+}
+
+// CSNAnnualAmount returns this year's payment (årsbelopp) on an annuity loan.
+// The amount grows by 2% each year, and is set so that the debt is repaid
+// with interest over the remaining years.
+func CSNAnnualAmount(debt, rate float64, years int) float64 {
+	if debt <= 0 || years <= 0 {
+		return 0
+	}
+	// Present value of one krona growing by CSNAnnualIncrease per year.
+	var factor float64
+	for k := range years {
+		factor += math.Pow(1+CSNAnnualIncrease, float64(k)) / math.Pow(1+rate, float64(k+1))
+	}
+	return debt / factor
+}
+
+// CSNReducedAnnualAmount returns the annual amount after nedsättning, which
+// caps the payment at 5% of the gross yearly income.
+func CSNReducedAnnualAmount(annual, grossIncome float64) float64 {
+	return math.Max(0, math.Min(annual, grossIncome*CSNReductionRate))
+}
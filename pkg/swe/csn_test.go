@@ -0,0 +1,55 @@
+package swe_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+func TestCSNAnnualAmountRepaysLoan(t *testing.T) {
+	debt, rate, years := 300_000.0, 0.02, 20
+	amount := swe.CSNAnnualAmount(debt, rate, years)
+	for range years {
+		debt = debt*(1+rate) - amount
+		amount *= 1 + swe.CSNAnnualIncrease
+	}
+	if math.Abs(debt) > 0.01 {
+		t.Errorf("remaining debt after %d years = %f, want 0", years, debt)
+	}
+	if got := swe.CSNAnnualAmount(0, rate, years); got != 0 {
+		t.Errorf("CSNAnnualAmount() without debt = %f, want 0", got)
+	}
+}
+
+func TestCSNRepaymentYears(t *testing.T) {
+	tests := []struct {
+		name            string
+		year, finalYear int
+		want            int
+	}{
+		{"capped at 25 years", 2025, 2060, 25},
+		{"until final year", 2040, 2049, 10},
+		{"final year", 2049, 2049, 1},
+		{"past final year", 2055, 2049, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := swe.CSNRepaymentYears(tt.year, tt.finalYear); got != tt.want {
+				t.Errorf("CSNRepaymentYears() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+	if got := swe.CSNFinalRepaymentYear(1985); got != 2049 {
+		t.Errorf("CSNFinalRepaymentYear() = %d, want 2049", got)
+	}
+}
+
+func TestCSNReducedAnnualAmount(t *testing.T) {
+	if got := swe.CSNReducedAnnualAmount(20_000, 200_000); got != 10_000 {
+		t.Errorf("reduced amount = %f, want 10000", got)
+	}
+	if got := swe.CSNReducedAnnualAmount(8_000, 200_000); got != 8_000 {
+		t.Errorf("amount below reduction = %f, want 8000", got)
+	}
+}
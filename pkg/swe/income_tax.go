@@ -0,0 +1,122 @@
+package swe
+
+import "math"
+
+// IncomeTaxParams holds the yearly parameters for the formula-based income
+// tax calculation. It follows the rules for employment income for people
+// under 66 at the start of the year.
+type IncomeTaxParams struct {
+	Prisbasbelopp float64
+	// MunicipalTaxRate is the total local tax rate (kommun + region, plus
+	// church fee for members), e.g. 0.32 for 32%.
+	MunicipalTaxRate float64
+	// StateTaxThreshold is the skiktgräns, the taxable income above which
+	// statlig inkomstskatt is paid.
+	StateTaxThreshold   float64
+	StateTaxRate        float64
+	PublicServiceFeeMax float64
+	// NonWorkIncome taxes income other than work, such as pension, a-kassa
+	// and sjukpenning, which gets no jobbskatteavdrag. The grundavdrag is
+	// the one for people under 66.
+	NonWorkIncome bool
+}
+
+const (
+	DefaultStateTaxRate        = 0.20
+	PublicServiceFeeRate       = 0.01
+	DefaultStateTaxThreshold   = 625_800 // 2025
+	DefaultPublicServiceFeeMax = 1_249
+	// DefaultPrisbasbelopp is used when no yearly params are known.
+	DefaultPrisbasbelopp = 58_800 // 2025
+	// AverageMunicipalTaxRate is the average kommunalskatt, used when no
+	// municipality is known.
+	AverageMunicipalTaxRate = 0.3241 // 2025
+)
+
+// IncomeTaxResult is an itemized annual income tax calculation.
+type IncomeTaxResult struct {
+	AnnualIncome     float64
+	Grundavdrag      float64
+	TaxableIncome    float64
+	MunicipalTax     float64
+	StateTax         float64
+	Jobbskatteavdrag float64
+	PublicServiceFee float64
+	TotalTax         float64
+}
+
+// CalculateGrundavdrag returns the basic deduction for the given annual
+// income (fastställd förvärvsinkomst), rounded up to the nearest 100 kr.
+func CalculateGrundavdrag(annualIncome, prisbasbelopp float64) float64 {
+	pbb := prisbasbelopp
+	var ga float64
+	switch {
+	case annualIncome <= 0.99*pbb:
+		ga = 0.423 * pbb
+	case annualIncome <= 2.72*pbb:
+		ga = 0.423*pbb + 0.2*(annualIncome-0.99*pbb)
+	case annualIncome <= 3.11*pbb:
+		ga = 0.77 * pbb
+	case annualIncome <= 7.88*pbb:
+		ga = 0.77*pbb - 0.1*(annualIncome-3.11*pbb)
+	default:
+		ga = 0.293 * pbb
+	}
+	ga = math.Ceil(ga/100) * 100
+	return math.Min(ga, math.Max(0, annualIncome))
+}
+
+// CalculateJobbskatteavdrag returns the earned income tax credit for the
+// given annual work income. The credit never exceeds the municipal tax.
+func CalculateJobbskatteavdrag(annualIncome, grundavdrag, prisbasbelopp, municipalTaxRate float64) float64 {
+	pbb := prisbasbelopp
+	var base float64
+	switch {
+	case annualIncome <= 0.91*pbb:
+		base = annualIncome
+	case annualIncome <= 3.24*pbb:
+		base = 0.91*pbb + 0.3874*(annualIncome-0.91*pbb)
+	case annualIncome <= 8.08*pbb:
+		base = 1.813*pbb + 0.251*(annualIncome-3.24*pbb)
+	default:
+		base = 3.027 * pbb
+	}
+	return math.Max(0, (base-grundavdrag)*municipalTaxRate)
+}
+
+// CalculateAnnualIncomeTax computes the annual income tax on employment
+// income: municipal tax and state tax on the taxable income, reduced by the
+// jobbskatteavdrag, plus the public service fee.
+func CalculateAnnualIncomeTax(annualIncome float64, p IncomeTaxParams) IncomeTaxResult {
+	income := math.Max(0, math.Floor(annualIncome/100)*100)
+	ga := CalculateGrundavdrag(income, p.Prisbasbelopp)
+	taxable := math.Max(0, income-ga)
+
+	municipal := taxable * p.MunicipalTaxRate
+	state := math.Max(0, taxable-p.StateTaxThreshold) * p.StateTaxRate
+	var jsa float64
+	if !p.NonWorkIncome {
+		jsa = math.Min(municipal, CalculateJobbskatteavdrag(income, ga, p.Prisbasbelopp, p.MunicipalTaxRate))
+	}
+	fee := math.Min(taxable*PublicServiceFeeRate, p.PublicServiceFeeMax)
+
+	return IncomeTaxResult{
+		AnnualIncome:     income,
+		Grundavdrag:      ga,
+		TaxableIncome:    taxable,
+		MunicipalTax:     municipal,
+		StateTax:         state,
+		Jobbskatteavdrag: jsa,
+		PublicServiceFee: fee,
+		TotalTax:         municipal + state - jsa + fee,
+	}
+}
+
+// NewFormulaTaxFunc returns a monthly tax function computed from the annual
+// income tax on twelve times the monthly gross. It has the same signature as
+// the tax table lookup and can be used wherever a taxFunc is expected.
+func NewFormulaTaxFunc(p IncomeTaxParams) func(grossMonthly float64) (float64, error) {
+	return func(grossMonthly float64) (float64, error) {
+		return CalculateAnnualIncomeTax(grossMonthly*12, p).TotalTax / 12, nil
+	}
+}
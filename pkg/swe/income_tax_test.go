@@ -0,0 +1,129 @@
+package swe_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+var testIncomeTaxParams = swe.IncomeTaxParams{
+	Prisbasbelopp:       58800,
+	MunicipalTaxRate:    0.32,
+	StateTaxThreshold:   625800,
+	StateTaxRate:        0.20,
+	PublicServiceFeeMax: 1249,
+}
+
+func TestCalculateGrundavdrag(t *testing.T) {
+	const pbb = 58800.0
+	tests := []struct {
+		name   string
+		income float64
+		want   float64
+	}{
+		{name: "capped at income", income: 20000, want: 20000},
+		{name: "lowest bracket", income: 50000, want: 24900},
+		{name: "increasing bracket", income: 100000, want: 33300},
+		{name: "max bracket", income: 170000, want: 45300},
+		{name: "decreasing bracket", income: 300000, want: 33600},
+		{name: "high income", income: 900000, want: 17300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := swe.CalculateGrundavdrag(tt.income, pbb); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("CalculateGrundavdrag(%v) = %v, want %v", tt.income, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateAnnualIncomeTax(t *testing.T) {
+	tests := []struct {
+		name          string
+		annualIncome  float64
+		wantTaxable   float64
+		wantMunicipal float64
+		wantState     float64
+		wantJSA       float64
+		wantFee       float64
+		wantTotal     float64
+	}{
+		{
+			name:         "zero income",
+			annualIncome: 0,
+		},
+		{
+			name:          "low income",
+			annualIncome:  180000,
+			wantTaxable:   134700,
+			wantMunicipal: 43104,
+			wantJSA:       18307.520256,
+			wantFee:       1249,
+			wantTotal:     26045.479744,
+		},
+		{
+			name:          "below state tax threshold",
+			annualIncome:  480000,
+			wantTaxable:   462700,
+			wantMunicipal: 148064,
+			wantJSA:       51420.032,
+			wantFee:       1249,
+			wantTotal:     97892.968,
+		},
+		{
+			name:          "above state tax threshold",
+			annualIncome:  960000,
+			wantTaxable:   942700,
+			wantMunicipal: 301664,
+			wantState:     63380,
+			wantJSA:       51420.032,
+			wantFee:       1249,
+			wantTotal:     314872.968,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := swe.CalculateAnnualIncomeTax(tt.annualIncome, testIncomeTaxParams)
+			for _, c := range []struct {
+				field     string
+				got, want float64
+			}{
+				{"TaxableIncome", got.TaxableIncome, tt.wantTaxable},
+				{"MunicipalTax", got.MunicipalTax, tt.wantMunicipal},
+				{"StateTax", got.StateTax, tt.wantState},
+				{"Jobbskatteavdrag", got.Jobbskatteavdrag, tt.wantJSA},
+				{"PublicServiceFee", got.PublicServiceFee, tt.wantFee},
+				{"TotalTax", got.TotalTax, tt.wantTotal},
+			} {
+				if math.Abs(c.got-c.want) > 0.01 {
+					t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateAnnualIncomeTax_NonWorkIncome(t *testing.T) {
+	p := testIncomeTaxParams
+	p.NonWorkIncome = true
+	work := swe.CalculateAnnualIncomeTax(300000, testIncomeTaxParams)
+	got := swe.CalculateAnnualIncomeTax(300000, p)
+	if got.Jobbskatteavdrag != 0 {
+		t.Errorf("jobbskatteavdrag = %v, want 0 for non-work income", got.Jobbskatteavdrag)
+	}
+	if want := work.TotalTax + work.Jobbskatteavdrag; math.Abs(got.TotalTax-want) > 0.01 {
+		t.Errorf("total tax = %v, want %v", got.TotalTax, want)
+	}
+}
+
+func TestNewFormulaTaxFunc(t *testing.T) {
+	taxFunc := swe.NewFormulaTaxFunc(testIncomeTaxParams)
+	tax, err := taxFunc(40000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := 97892.968 / 12; math.Abs(tax-want) > 0.01 {
+		t.Errorf("monthly tax = %v, want %v", tax, want)
+	}
+}
@@ -0,0 +1,101 @@
+package swe
+
+import (
+	"math"
+	"sort"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+const (
+	// InterestDeductionRate is the tax reduction on interest expenses up to
+	// InterestDeductionThreshold per person and year.
+	InterestDeductionRate = 0.30
+	// InterestDeductionReducedRate applies to interest expenses above the threshold.
+	InterestDeductionReducedRate = 0.21
+	InterestDeductionThreshold   = 100_000
+	// DefaultInterestSettlementDate is when the skatteåterbäring is paid for
+	// tax returns filed digitally without changes.
+	DefaultInterestSettlementDate = date.Cron("*-04-07")
+)
+
+// CalculateInterestDeduction returns the ränteavdrag for one person's yearly
+// interest expenses: 30% up to 100 000 kr and 21% on the remainder.
+func CalculateInterestDeduction(interest float64) float64 {
+	if interest <= 0 {
+		return 0
+	}
+	below := math.Min(interest, InterestDeductionThreshold)
+	return below*InterestDeductionRate + (interest-below)*InterestDeductionReducedRate
+}
+
+// InterestDeduction accumulates the interest paid on loans per calendar year
+// and refunds the ränteavdrag on the first settlement date of the following
+// year. It is fed through OnInterest (finance.InterestRecorder) and pays the
+// refund as a negative tax on the account it is attached to (finance.TaxModel).
+type InterestDeduction struct {
+	SettlementDate date.Cron
+	// Borrowers is the number of people sharing the interest expenses. The
+	// interest is split evenly and the threshold applies per person.
+	Borrowers int
+
+	interest map[int][]uncertain.Value
+}
+
+// OnInterest records interest paid by accountID. Loans accrue negative
+// interest, which is what is deductible; positive amounts are ignored.
+func (d *InterestDeduction) OnInterest(accountID, destinationAccountID string, day date.Date, amount uncertain.Value) error {
+	if amount.IsFixed() && amount.Fixed.Value >= 0 {
+		return nil
+	}
+	if d.interest == nil {
+		d.interest = make(map[int][]uncertain.Value)
+	}
+	if !amount.IsFixed() {
+		amount = uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+			return math.Min(0, amount.Sample(cfg))
+		})
+	}
+	d.interest[day.Year()] = append(d.interest[day.Year()], amount)
+	return nil
+}
+
+func (d *InterestDeduction) Apply(ucfg *uncertain.Config, day date.Date, balance uncertain.Value, dayDeposits uncertain.Value) uncertain.Value {
+	if !d.SettlementDate.Matches(day) {
+		return uncertain.Value{}
+	}
+	years := make([]int, 0, len(d.interest))
+	for year := range d.interest {
+		if year < day.Year() {
+			years = append(years, year)
+		}
+	}
+	sort.Ints(years)
+	borrowers := float64(max(1, d.Borrowers))
+	var refund uncertain.Value
+	for _, year := range years {
+		var paid uncertain.Value
+		for _, a := range d.interest[year] {
+			if paid.Zero() {
+				paid = a
+			} else {
+				paid = paid.Add(ucfg, a)
+			}
+		}
+		delete(d.interest, year)
+		if paid.Zero() {
+			continue
+		}
+		// The threshold is applied per year, so each year is settled separately.
+		yearRefund := paid.ApplyFixed(ucfg, borrowers, func(interest, borrowers float64) float64 {
+			return -CalculateInterestDeduction(-interest/borrowers) * borrowers
+		})
+		if refund.Zero() {
+			refund = yearRefund
+		} else {
+			refund = refund.Add(ucfg, yearRefund)
+		}
+	}
+	return refund
+}
@@ -0,0 +1,62 @@
+package swe_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+func TestCalculateInterestDeduction(t *testing.T) {
+	tests := []struct {
+		interest float64
+		want     float64
+	}{
+		{0, 0},
+		{-5_000, 0},
+		{50_000, 15_000},
+		{100_000, 30_000},
+		{150_000, 30_000 + 10_500},
+	}
+	for _, tt := range tests {
+		if got := swe.CalculateInterestDeduction(tt.interest); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("CalculateInterestDeduction(%f) = %f, want %f", tt.interest, got, tt.want)
+		}
+	}
+}
+
+func TestInterestDeduction_RefundsPreviousYearOnSettlementDate(t *testing.T) {
+	ucfg := uncertain.NewConfig(0, 1)
+	d := &swe.InterestDeduction{SettlementDate: "*-04-07", Borrowers: 2}
+	for day := range date.Iter(mustParseDate("2025-01-01"), mustParseDate("2026-01-01"), date.Day) {
+		if day.Day() != 1 {
+			continue
+		}
+		if err := d.OnInterest("loan", "checking", day, uncertain.NewFixed(-12_500)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Interest earned while the balance is positive is not deductible.
+	if err := d.OnInterest("loan", "checking", mustParseDate("2025-12-15"), uncertain.NewFixed(5_000)); err != nil {
+		t.Fatal(err)
+	}
+	// Interest paid in 2026 before settlement belongs to the next settlement.
+	if err := d.OnInterest("loan", "checking", mustParseDate("2026-02-01"), uncertain.NewFixed(-10_000)); err != nil {
+		t.Fatal(err)
+	}
+
+	if refund := d.Apply(ucfg, mustParseDate("2026-04-06"), uncertain.Value{}, uncertain.Value{}); !refund.Zero() {
+		t.Errorf("expected no refund before the settlement date, got %f", refund.Mean())
+	}
+	// 150 000 kr split over two borrowers stays below the threshold: 30%.
+	refund := d.Apply(ucfg, mustParseDate("2026-04-07"), uncertain.Value{}, uncertain.Value{})
+	if got := refund.Mean(); math.Abs(got+45_000) > 0.001 {
+		t.Errorf("expected refund of 45000 (negative tax), got %f", got)
+	}
+	refund = d.Apply(ucfg, mustParseDate("2027-04-07"), uncertain.Value{}, uncertain.Value{})
+	if got := refund.Mean(); math.Abs(got+3_000) > 0.001 {
+		t.Errorf("expected refund of 3000 for 2026, got %f", got)
+	}
+}
@@ -19,6 +19,9 @@ type ISKParams struct {
 // Tax is calculated annually on Jan 1 based on quarterly account values and deposits.
 type ISKTax struct {
 	ParamsFunc func(date.Date) ISKParams
+	// Recorder is optionally notified of the tax withdrawn each year, e.g. to
+	// include the schablonintäkt in a TaxReturn.
+	Recorder ISKTaxRecorder
 
 	quarterlyValues [4]uncertain.Value
 	yearDeposits    uncertain.Value
@@ -29,6 +32,9 @@ func (t *ISKTax) Apply(ucfg *uncertain.Config, day date.Date, balance uncertain.
 	var tax uncertain.Value
 	if day.Month() == time.January && day.Day() == 1 && t.initialized {
 		tax = t.computeTax(ucfg, day)
+		if t.Recorder != nil {
+			t.Recorder.OnISKTax(day.Year()-1, tax)
+		}
 		t.reset()
 	}
 
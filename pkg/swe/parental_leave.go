@@ -36,3 +36,25 @@ func CalculateFullParentalLeaveCompensation(monthlyGross, sjukDaysPerWeek, prisb
 	sjukDaysPerYear := sjukDaysPerWeek / 7 * 365
 	return sjukDaysPerYear * sjukDailyRate / 12
 }
+
+// ParentalPay is föräldralön, the employer top-up on parental leave paid under
+// many collective agreements.
+type ParentalPay struct {
+	// Rate is the share of the salary up to the SGI ceiling (10 PBB) paid on
+	// top of föräldrapenning, such as 0.10.
+	Rate float64
+	// AboveCeilingRate is the share of the salary above the SGI ceiling paid
+	// to compensate for föräldrapenning not covering it, such as 0.90.
+	AboveCeilingRate float64
+}
+
+// CalculateParentalPay returns the monthly föräldralön during full parental
+// leave with föräldrapenning for sjukDaysPerWeek days per week. The employer
+// pays Rate of the salary up to 10 PBB and AboveCeilingRate of the salary
+// above it, for the days with föräldrapenning.
+func CalculateParentalPay(monthlyGross, sjukDaysPerWeek, prisbasbelopp float64, p ParentalPay) float64 {
+	annualSalary := monthlyGross * 12
+	ceiling := 10 * prisbasbelopp
+	annualPay := p.Rate*math.Min(annualSalary, ceiling) + p.AboveCeilingRate*math.Max(0, annualSalary-ceiling)
+	return annualPay * sjukDaysPerWeek / 7 / 12
+}
@@ -185,3 +185,64 @@ func TestCalculateFullParentalLeaveCompensation(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateParentalPay(t *testing.T) {
+	const pbb = 57300.0
+	pay := swe.ParentalPay{Rate: 0.10, AboveCeilingRate: 0.90}
+
+	tests := []struct {
+		name              string
+		monthlyGross      float64
+		sjukDaysPerWeek   float64
+		wantMonthlyApprox float64
+	}{
+		{
+			name:              "below SGI ceiling",
+			monthlyGross:      40000,
+			sjukDaysPerWeek:   7,
+			wantMonthlyApprox: 4000,
+		},
+		{
+			name:            "above SGI ceiling",
+			monthlyGross:    70000,
+			sjukDaysPerWeek: 7,
+			wantMonthlyApprox: func() float64 {
+				annual := 70000.0 * 12
+				return (0.10*10*pbb + 0.90*(annual-10*pbb)) / 12
+			}(),
+		},
+		{
+			name:              "5 days/week",
+			monthlyGross:      40000,
+			sjukDaysPerWeek:   5,
+			wantMonthlyApprox: 4000 * 5.0 / 7,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := swe.CalculateParentalPay(tt.monthlyGross, tt.sjukDaysPerWeek, pbb, pay)
+			if !approxEqual(got, tt.wantMonthlyApprox, 0.01) {
+				t.Errorf("got %v, want ~%v", got, tt.wantMonthlyApprox)
+			}
+		})
+	}
+}
+
+func TestAddParentalPay(t *testing.T) {
+	bd := swe.CalculateFullParentalLeaveBreakdown(40000, 7, 57300)
+	taxFunc := func(income float64) (float64, error) { return income * 0.3, nil }
+
+	got := swe.AddParentalPay(bd, 4000, taxFunc)
+	if got.ParentalPay != 4000 {
+		t.Errorf("ParentalPay = %v, want 4000", got.ParentalPay)
+	}
+	if !approxEqual(got.Tax, 1200, 0.01) {
+		t.Errorf("Tax = %v, want 1200", got.Tax)
+	}
+	if !approxEqual(got.NetMonthly, bd.FKSjukCompensation+2800, 0.01) {
+		t.Errorf("NetMonthly = %v, want %v", got.NetMonthly, bd.FKSjukCompensation+2800)
+	}
+	if unchanged := swe.AddParentalPay(bd, 0, taxFunc); unchanged != bd {
+		t.Errorf("AddParentalPay with 0 changed breakdown: %+v", unchanged)
+	}
+}
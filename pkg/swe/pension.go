@@ -11,3 +11,50 @@ func CalculateITP1Pension(grossMonthlySalary, inkomstbasbelopp float64) float64
 	aboveCutoff := math.Max(0, grossMonthlySalary-cutoffMonthly)
 	return belowCutoff*0.045 + aboveCutoff*0.3
 }
+
+// remainingLifeExpectancy is the approximate remaining life expectancy in
+// years at each age, for men and women together (SCB).
+var remainingLifeExpectancy = []struct{ age, years float64 }{
+	{60, 25.0}, {65, 20.6}, {70, 16.6}, {75, 12.8}, {80, 9.4},
+	{85, 6.4}, {90, 4.2}, {95, 2.7}, {100, 1.8},
+}
+
+// RemainingLifeExpectancy returns the expected number of years left to live
+// at age. It is the divisor a lifetime (livsvarig) pension is sized with, and
+// never reaches zero, so the payout continues however long the holder lives.
+func RemainingLifeExpectancy(age float64) float64 {
+	first, last := remainingLifeExpectancy[0], remainingLifeExpectancy[len(remainingLifeExpectancy)-1]
+	switch {
+	case age <= first.age:
+		return first.years + first.age - age
+	case age >= last.age:
+		return last.years
+	}
+	for i := 1; i < len(remainingLifeExpectancy); i++ {
+		lo, hi := remainingLifeExpectancy[i-1], remainingLifeExpectancy[i]
+		if age <= hi.age {
+			return lo.years + (age-lo.age)/(hi.age-lo.age)*(hi.years-lo.years)
+		}
+	}
+	return last.years
+}
+
+// DefaultPensionStartAge is the assumed age at the start of a pension payout
+// when the birth date of the pension holder is unknown.
+const DefaultPensionStartAge = 65
+
+// Tax table columns used for pension income.
+const (
+	TaxColumnPensionOver66  = 2
+	TaxColumnPensionUnder66 = 3
+)
+
+// PensionTaxColumn returns the Skatteverket tax table column for pension
+// income in the given year. Column 2 applies to those who had turned 66 at
+// the start of the year, column 3 to everyone younger.
+func PensionTaxColumn(birthYear, year int) int {
+	if year-birthYear-1 >= 66 {
+		return TaxColumnPensionOver66
+	}
+	return TaxColumnPensionUnder66
+}
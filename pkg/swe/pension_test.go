@@ -59,3 +59,44 @@ func TestCalculateITP1Pension(t *testing.T) {
 		})
 	}
 }
+
+func TestPensionTaxColumn(t *testing.T) {
+	tests := []struct {
+		name      string
+		birthYear int
+		year      int
+		want      int
+	}{
+		{name: "turns 66 during the year", birthYear: 1960, year: 2026, want: swe.TaxColumnPensionUnder66},
+		{name: "turned 66 the year before", birthYear: 1960, year: 2027, want: swe.TaxColumnPensionOver66},
+		{name: "early retirement", birthYear: 1965, year: 2027, want: swe.TaxColumnPensionUnder66},
+		{name: "well past 66", birthYear: 1940, year: 2027, want: swe.TaxColumnPensionOver66},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := swe.PensionTaxColumn(tt.birthYear, tt.year); got != tt.want {
+				t.Errorf("PensionTaxColumn(%d, %d) = %d, want %d", tt.birthYear, tt.year, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemainingLifeExpectancy(t *testing.T) {
+	tests := []struct {
+		name string
+		age  float64
+		want float64
+	}{
+		{name: "table age", age: 65, want: 20.6},
+		{name: "between table ages", age: 67.5, want: 18.6},
+		{name: "before the table", age: 55, want: 30},
+		{name: "past the table", age: 110, want: 1.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := swe.RemainingLifeExpectancy(tt.age); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("RemainingLifeExpectancy(%v) = %v, want %v", tt.age, got, tt.want)
+			}
+		})
+	}
+}
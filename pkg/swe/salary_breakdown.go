@@ -2,6 +2,7 @@ package swe
 
 type SalaryBreakdown struct {
 	GrossMonthly             float64
+	SalaryExchange           float64
 	VacationSupplement       float64
 	SickPayDeduction         float64
 	VABDeduction             float64
@@ -11,6 +12,16 @@ type SalaryBreakdown struct {
 	NetMonthly               float64
 	IsFullParentalLeave      bool
 	FKSjukCompensation       float64
+	ParentalPay              float64
+	IsUnemployment           bool
+	AKassa                   float64
+	IncomeInsuranceTopUp     float64
+	IsSickLeave              bool
+	Sjuklon                  float64
+	EmployerTopUp            float64
+	// BelowSalaryExchangeThreshold is set when the salary remaining after
+	// SalaryExchange is below 8.07 IBB a year.
+	BelowSalaryExchangeThreshold bool
 }
 
 // CalculateSalaryBreakdown produces an itemized breakdown of a normal (non-full-
@@ -53,3 +64,75 @@ func CalculateFullParentalLeaveBreakdown(grossMonthly, sjukDaysPerWeek, prisbasb
 		NetMonthly:          sjukComp,
 	}
 }
+
+// AddParentalPay adds taxable föräldralön to a full parental leave breakdown.
+// The tax is the marginal tax on the föräldralön on top of the FK compensation.
+func AddParentalPay(bd SalaryBreakdown, parentalPay float64, taxFunc func(float64) (float64, error)) SalaryBreakdown {
+	if parentalPay == 0 {
+		return bd
+	}
+	base, _ := taxFunc(bd.FKSjukCompensation)
+	total, _ := taxFunc(bd.FKSjukCompensation + parentalPay)
+	bd.ParentalPay = parentalPay
+	bd.Tax = total - base
+	bd.NetMonthly += parentalPay - bd.Tax
+	return bd
+}
+
+// CalculateUnemploymentBreakdown produces a breakdown for month (0-based) of an
+// unemployment period, where a-kassa and any income insurance replace the
+// salary. taxFunc should tax them as income other than work.
+func CalculateUnemploymentBreakdown(grossMonthly float64, month int, member bool, ins IncomeInsurance, taxFunc func(float64) (float64, error)) SalaryBreakdown {
+	akassa, topUp := UnemploymentMonthlyBenefit(grossMonthly, month, member, ins)
+	benefit := akassa + topUp
+	tax, _ := taxFunc(benefit)
+	return SalaryBreakdown{
+		GrossMonthly:         grossMonthly,
+		IsUnemployment:       true,
+		AKassa:               akassa,
+		IncomeInsuranceTopUp: topUp,
+		AdjustedGross:        benefit,
+		Tax:                  tax,
+		NetMonthly:           benefit - tax,
+	}
+}
+
+// CalculateSickLeaveBreakdown produces a breakdown for a month of long-term
+// sick leave, where sjuklön, sjukpenning and any employer top-up replace the
+// salary. Sjukpenning is taxed with benefitTax as income other than work.
+func CalculateSickLeaveBreakdown(grossMonthly float64, firstDay, days int, p SickLeaveParams, taxFunc, benefitTax func(float64) (float64, error)) SalaryBreakdown {
+	income := CalculateSickLeaveIncome(grossMonthly, firstDay, days, p)
+	taxable := income.Taxable()
+	tax, _ := TaxSickLeaveIncome(income, taxFunc, benefitTax)
+	return SalaryBreakdown{
+		GrossMonthly:       grossMonthly,
+		IsSickLeave:        true,
+		AdjustedGross:      taxable,
+		Sjuklon:            income.Sjuklon,
+		FKSjukCompensation: income.Sjukpenning,
+		EmployerTopUp:      income.EmployerTopUp,
+		Tax:                tax,
+		NetMonthly:         taxable - tax,
+	}
+}
+
+// TaxableIncome returns the income in the breakdown that tax was withheld on.
+// Föräldrapenning is left out as it is paid untaxed in the forecast.
+func (bd SalaryBreakdown) TaxableIncome() float64 {
+	if bd.IsFullParentalLeave {
+		return bd.ParentalPay
+	}
+	return bd.AdjustedGross
+}
+
+// BenefitIncome returns the part of TaxableIncome that is not work income,
+// a-kassa, income insurance and sjukpenning, which get no jobbskatteavdrag.
+func (bd SalaryBreakdown) BenefitIncome() float64 {
+	switch {
+	case bd.IsUnemployment:
+		return bd.AKassa + bd.IncomeInsuranceTopUp
+	case bd.IsSickLeave:
+		return bd.FKSjukCompensation
+	}
+	return 0
+}
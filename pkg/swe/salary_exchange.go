@@ -0,0 +1,39 @@
+package swe
+
+const (
+	// DefaultSalaryExchangeUplift is the share employers typically add to
+	// the exchanged salary, compensating for the lower arbetsgivaravgifter
+	// on pension premiums.
+	DefaultSalaryExchangeUplift = 0.058
+	// SalaryExchangeMinIBB is the yearly salary, in inkomstbasbelopp, below
+	// which löneväxling lowers the allmän pension and the social insurance
+	// benefits based on the salary.
+	SalaryExchangeMinIBB = 8.07
+)
+
+// SalaryExchange is löneväxling, where part of the gross salary is paid as a
+// pension premium instead.
+type SalaryExchange struct {
+	// Amount is the monthly gross salary exchanged.
+	Amount float64
+	// Uplift is the share of Amount the employer adds to the premium.
+	Uplift float64
+}
+
+// Exchanged returns the part of monthlyGross exchanged, which can't exceed
+// the salary.
+func (e SalaryExchange) Exchanged(monthlyGross float64) float64 {
+	return max(0, min(e.Amount, monthlyGross))
+}
+
+// Premium returns the monthly pension premium paid for the exchanged salary.
+func (e SalaryExchange) Premium(monthlyGross float64) float64 {
+	return e.Exchanged(monthlyGross) * (1 + e.Uplift)
+}
+
+// BelowSalaryExchangeThreshold reports whether a monthly salary remaining
+// after löneväxling is below 8.07 IBB a year, where the exchange no longer
+// pays off.
+func BelowSalaryExchangeThreshold(remainingMonthly, inkomstbasbelopp float64) bool {
+	return remainingMonthly*12 < SalaryExchangeMinIBB*inkomstbasbelopp
+}
@@ -0,0 +1,33 @@
+package swe_test
+
+import (
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+func TestSalaryExchange(t *testing.T) {
+	e := swe.SalaryExchange{Amount: 5000, Uplift: swe.DefaultSalaryExchangeUplift}
+
+	if got := e.Exchanged(60000); got != 5000 {
+		t.Errorf("Exchanged(60000) = %v, want 5000", got)
+	}
+	if got := e.Exchanged(3000); got != 3000 {
+		t.Errorf("Exchanged(3000) = %v, want the whole salary", got)
+	}
+	if got := e.Premium(60000); !approxEqual(got, 5290, 0.01) {
+		t.Errorf("Premium(60000) = %v, want 5290", got)
+	}
+}
+
+func TestBelowSalaryExchangeThreshold(t *testing.T) {
+	const ibb = 76200.0
+	threshold := swe.SalaryExchangeMinIBB * ibb / 12
+
+	if swe.BelowSalaryExchangeThreshold(threshold+1, ibb) {
+		t.Error("salary above 8.07 IBB reported below threshold")
+	}
+	if !swe.BelowSalaryExchangeThreshold(threshold-1, ibb) {
+		t.Error("salary below 8.07 IBB not reported below threshold")
+	}
+}
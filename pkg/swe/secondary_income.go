@@ -0,0 +1,29 @@
+package swe
+
+import (
+	"time"
+
+	"github.com/SimonSchneider/goslu/date"
+)
+
+// SecondaryIncomeTaxRate is the flat preliminary tax on salary from a
+// secondary employer (sidoinkomst) without a jämkning decision.
+const SecondaryIncomeTaxRate = 0.30
+
+// NewFlatTaxFunc returns a monthly tax function withholding rate of the
+// gross, as used for secondary employers.
+func NewFlatTaxFunc(rate float64) func(grossMonthly float64) (float64, error) {
+	return func(grossMonthly float64) (float64, error) {
+		return grossMonthly * rate, nil
+	}
+}
+
+// FinalTaxSettlementDate returns when the difference between the preliminary
+// tax withheld for year and the final tax is settled: a skatteåterbäring is
+// paid around 7 April the following year, while restskatt is due 12 November.
+func FinalTaxSettlementDate(year int, refund bool) date.Date {
+	if refund {
+		return date.FromTime(time.Date(year+1, time.April, 7, 0, 0, 0, 0, time.UTC))
+	}
+	return date.FromTime(time.Date(year+1, time.November, 12, 0, 0, 0, 0, time.UTC))
+}
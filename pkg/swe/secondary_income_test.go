@@ -0,0 +1,26 @@
+package swe_test
+
+import (
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+func TestNewFlatTaxFunc(t *testing.T) {
+	tax, err := swe.NewFlatTaxFunc(swe.SecondaryIncomeTaxRate)(10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tax != 3000 {
+		t.Errorf("tax = %v, want 3000", tax)
+	}
+}
+
+func TestFinalTaxSettlementDate(t *testing.T) {
+	if got, want := swe.FinalTaxSettlementDate(2025, true), mustParseDate("2026-04-07"); got != want {
+		t.Errorf("refund date = %s, want %s", got, want)
+	}
+	if got, want := swe.FinalTaxSettlementDate(2025, false), mustParseDate("2026-11-12"); got != want {
+		t.Errorf("restskatt date = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package swe
+
+import "math"
+
+const (
+	// SjuklonLastDay is the last day of sickness the employer pays sjuklön.
+	SjuklonLastDay = 14
+	// SjukpenningReducedFromDay is the first day sjukpenning is paid at the
+	// fortsättningsnivå.
+	SjukpenningReducedFromDay = 365
+)
+
+// SickLeaveParams describes a long-term sick leave.
+type SickLeaveParams struct {
+	// Extent is the part of full time on sick leave, such as 0.5.
+	Extent float64
+	// EmployerTopUpRate is the share of the salary the employer pays on top
+	// of sjukpenning from day 15, as under many collective agreements.
+	EmployerTopUpRate float64
+	// EmployerTopUpDays is the last day of sickness with a top-up, or 0 to
+	// top up the whole leave.
+	EmployerTopUpDays int
+	Prisbasbelopp     float64
+}
+
+// SickLeaveIncome is the taxable income for a period of sick leave.
+type SickLeaveIncome struct {
+	// Salary is paid for the part of full time still worked.
+	Salary        float64
+	Sjuklon       float64
+	Sjukpenning   float64
+	EmployerTopUp float64
+}
+
+func (i SickLeaveIncome) Taxable() float64 {
+	return i.Salary + i.Sjuklon + i.Sjukpenning + i.EmployerTopUp
+}
+
+// WorkIncome is the part of the income paid by the employer. Sjukpenning is
+// not work income and is taxed without jobbskatteavdrag.
+func (i SickLeaveIncome) WorkIncome() float64 {
+	return i.Salary + i.Sjuklon + i.EmployerTopUp
+}
+
+// TaxSickLeaveIncome returns the monthly tax on the income, the work income
+// with workTax and sjukpenning on top of it with benefitTax.
+func TaxSickLeaveIncome(i SickLeaveIncome, workTax, benefitTax func(float64) (float64, error)) (float64, error) {
+	work, err := workTax(i.WorkIncome())
+	if err != nil {
+		return 0, err
+	}
+	if i.Sjukpenning == 0 {
+		return work, nil
+	}
+	base, err := benefitTax(i.WorkIncome())
+	if err != nil {
+		return 0, err
+	}
+	total, err := benefitTax(i.Taxable())
+	if err != nil {
+		return 0, err
+	}
+	return work + total - base, nil
+}
+
+// SjukpenningDaily returns Försäkringskassan's sjukpenning for a calendar day
+// on day of sickness: 80% of the SGI capped at 10 PBB, and from day 365 75%
+// of the SGI capped at 8 PBB, both reduced to 97% of the capped SGI.
+func SjukpenningDaily(monthlyGross float64, day int, prisbasbelopp float64) float64 {
+	rate, ceiling := 0.80, 10.0
+	if day >= SjukpenningReducedFromDay {
+		rate, ceiling = 0.75, 8.0
+	}
+	sgi := math.Min(monthlyGross*12, ceiling*prisbasbelopp)
+	return sgi * 0.97 * rate / 365
+}
+
+// CalculateSickLeaveIncome returns the income for days calendar days of sick
+// leave, the first being day firstDay of sickness (1-based). Day 1 is a
+// karensdag, the employer pays sjuklön at 80% of the salary for days 2-14, and
+// Försäkringskassan pays sjukpenning from day 15.
+func CalculateSickLeaveIncome(monthlyGross float64, firstDay, days int, p SickLeaveParams) SickLeaveIncome {
+	dailySalary := monthlyGross * 12 / 365
+	income := SickLeaveIncome{Salary: monthlyGross * (1 - p.Extent)}
+	for day := firstDay; day < firstDay+days; day++ {
+		switch {
+		case day <= 1:
+		case day <= SjuklonLastDay:
+			income.Sjuklon += p.Extent * 0.80 * dailySalary
+		default:
+			income.Sjukpenning += p.Extent * SjukpenningDaily(monthlyGross, day, p.Prisbasbelopp)
+			if p.EmployerTopUpDays == 0 || day <= p.EmployerTopUpDays {
+				income.EmployerTopUp += p.Extent * p.EmployerTopUpRate * dailySalary
+			}
+		}
+	}
+	return income
+}
@@ -0,0 +1,72 @@
+package swe_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+func TestCalculateSickLeaveIncome(t *testing.T) {
+	const gross, pbb = 36500.0, 58800.0
+	daily := gross * 12 / 365
+
+	t.Run("first month", func(t *testing.T) {
+		got := swe.CalculateSickLeaveIncome(gross, 1, 30, swe.SickLeaveParams{Extent: 1, Prisbasbelopp: pbb})
+		if got.Salary != 0 {
+			t.Errorf("Salary = %f, want 0", got.Salary)
+		}
+		if want := 13 * 0.8 * daily; math.Abs(got.Sjuklon-want) > 0.01 {
+			t.Errorf("Sjuklon = %f, want %f", got.Sjuklon, want)
+		}
+		if want := 16 * swe.SjukpenningDaily(gross, 15, pbb); math.Abs(got.Sjukpenning-want) > 0.01 {
+			t.Errorf("Sjukpenning = %f, want %f", got.Sjukpenning, want)
+		}
+	})
+
+	t.Run("sjukpenning capped at 10 PBB", func(t *testing.T) {
+		if got, want := swe.SjukpenningDaily(100000, 15, pbb), 10*pbb*0.97*0.8/365; math.Abs(got-want) > 0.001 {
+			t.Errorf("SjukpenningDaily() = %f, want %f", got, want)
+		}
+		if got, want := swe.SjukpenningDaily(100000, 365, pbb), 8*pbb*0.97*0.75/365; math.Abs(got-want) > 0.001 {
+			t.Errorf("SjukpenningDaily() at fortsättningsnivå = %f, want %f", got, want)
+		}
+	})
+
+	t.Run("sjukpenning at the ceiling", func(t *testing.T) {
+		atCeiling := 10 * pbb / 12
+		if got, want := swe.SjukpenningDaily(atCeiling, 15, pbb), 10*pbb*0.97*0.8/365; math.Abs(got-want) > 0.001 {
+			t.Errorf("SjukpenningDaily() at the ceiling = %f, want %f", got, want)
+		}
+		if below, at := swe.SjukpenningDaily(atCeiling-1000, 15, pbb), swe.SjukpenningDaily(atCeiling, 15, pbb); below >= at {
+			t.Errorf("SjukpenningDaily() below the ceiling = %f, want less than %f", below, at)
+		}
+	})
+
+	t.Run("sjukpenning taxed as non-work income", func(t *testing.T) {
+		income := swe.SickLeaveIncome{Salary: 10000, Sjukpenning: 20000}
+		work := swe.NewFlatTaxFunc(0.3)
+		benefit := swe.NewFlatTaxFunc(0.4)
+		got, err := swe.TaxSickLeaveIncome(income, work, benefit)
+		if err != nil {
+			t.Fatalf("TaxSickLeaveIncome() error: %v", err)
+		}
+		if want := 10000*0.3 + 20000*0.4; math.Abs(got-want) > 0.001 {
+			t.Errorf("TaxSickLeaveIncome() = %f, want %f", got, want)
+		}
+	})
+
+	t.Run("half time with employer top-up", func(t *testing.T) {
+		p := swe.SickLeaveParams{Extent: 0.5, EmployerTopUpRate: 0.1, EmployerTopUpDays: 90, Prisbasbelopp: pbb}
+		got := swe.CalculateSickLeaveIncome(gross, 81, 30, p)
+		if got.Salary != gross/2 {
+			t.Errorf("Salary = %f, want %f", got.Salary, gross/2)
+		}
+		if got.Sjuklon != 0 {
+			t.Errorf("Sjuklon = %f, want 0", got.Sjuklon)
+		}
+		if want := 10 * 0.5 * 0.1 * daily; math.Abs(got.EmployerTopUp-want) > 0.01 {
+			t.Errorf("EmployerTopUp = %f, want %f", got.EmployerTopUp, want)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package swe
+
+import "math"
+
+const (
+	// EgenavgiftRate is the full social security contribution a sole
+	// proprietor pays on the surplus of the business.
+	EgenavgiftRate = 0.2897
+	// EgenavgiftSchablonavdragRate is the deduction for the egenavgifter of
+	// the year, made before they are known as a share of the surplus.
+	EgenavgiftSchablonavdragRate = 0.25
+	// EgenavgiftReductionRate is the generell nedsättning of the egenavgifter,
+	// a share of the surplus up to EgenavgiftReductionMax kr a year.
+	EgenavgiftReductionRate = 0.075
+	EgenavgiftReductionMax  = 15_000
+	// PeriodiseringsfondMaxShare is the largest share of the surplus that can
+	// be allocated to a periodiseringsfond, which is reversed after at most
+	// PeriodiseringsfondYears years.
+	PeriodiseringsfondMaxShare = 0.30
+	PeriodiseringsfondYears    = 6
+	// ExpansionsfondTaxRate is the tax on allocations to an expansionsfond,
+	// the same as the corporate tax.
+	ExpansionsfondTaxRate = CorporateTaxRate
+)
+
+// SoleProprietorInput is a year of an enskild firma: the surplus of the
+// business before allocations, the share of it allocated to a
+// periodiseringsfond, the amount allocated to the expansionsfond and the
+// earlier allocations reversed this year.
+type SoleProprietorInput struct {
+	Profit                  float64
+	PeriodiseringsfondShare float64
+	Expansionsfond          float64
+	Reversal                float64
+}
+
+// SoleProprietorResult is an itemized year of an enskild firma. Income is
+// the income from näringsverksamhet taxed as earned income, and Net what the
+// owner keeps of the year's profit after tax.
+type SoleProprietorResult struct {
+	Profit             float64
+	Reversal           float64
+	Periodiseringsfond float64
+	Expansionsfond     float64
+	ExpansionsfondTax  float64
+	Schablonavdrag     float64
+	Income             float64
+	Egenavgifter       float64
+	IncomeTax          float64
+	Net                float64
+}
+
+// Tax returns everything paid to Skatteverket for the year, as F-skatt.
+func (r SoleProprietorResult) Tax() float64 {
+	return r.Egenavgifter + r.IncomeTax + r.ExpansionsfondTax
+}
+
+// CalculateSoleProprietor returns the taxes of an enskild firma for a year.
+// The surplus, with reversed periodiseringsfonder added, is first reduced by
+// the allocations to the periodiseringsfond and expansionsfond. The
+// schablonavdrag of 25% stands in for the deductible egenavgifter, which are
+// then paid on what remains less the generell nedsättning. incomeTax returns
+// the yearly income tax on the remaining income. Allocations move no money,
+// so the owner keeps the profit less the taxes, while the periodiseringsfond
+// is taxed when reversed. A loss is not carried forward.
+func CalculateSoleProprietor(in SoleProprietorInput, incomeTax func(annual float64) float64) SoleProprietorResult {
+	r := SoleProprietorResult{Profit: in.Profit, Reversal: in.Reversal}
+	surplus := in.Profit + in.Reversal
+	if surplus > 0 {
+		share := math.Min(math.Max(0, in.PeriodiseringsfondShare), PeriodiseringsfondMaxShare)
+		r.Periodiseringsfond = surplus * share
+		r.Expansionsfond = math.Min(math.Max(0, in.Expansionsfond), surplus-r.Periodiseringsfond)
+		r.ExpansionsfondTax = r.Expansionsfond * ExpansionsfondTaxRate
+		base := surplus - r.Periodiseringsfond - r.Expansionsfond
+		r.Schablonavdrag = base * EgenavgiftSchablonavdragRate
+		r.Income = base - r.Schablonavdrag
+		reduction := math.Min(r.Income*EgenavgiftReductionRate, EgenavgiftReductionMax)
+		r.Egenavgifter = math.Max(0, r.Income*EgenavgiftRate-reduction)
+		if r.Income > 0 {
+			r.IncomeTax = incomeTax(r.Income)
+		}
+	}
+	r.Net = in.Profit - r.Tax()
+	return r
+}
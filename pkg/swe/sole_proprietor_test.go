@@ -0,0 +1,74 @@
+package swe_test
+
+import (
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+func TestCalculateSoleProprietor(t *testing.T) {
+	flat := func(annual float64) float64 { return annual * 0.3 }
+	tests := []struct {
+		name       string
+		in         swe.SoleProprietorInput
+		income     float64
+		egenavgift float64
+		fund       float64
+		expansion  float64
+	}{
+		{
+			name:       "plain surplus",
+			in:         swe.SoleProprietorInput{Profit: 100000},
+			income:     75000,
+			egenavgift: 75000*swe.EgenavgiftRate - 75000*swe.EgenavgiftReductionRate,
+		},
+		{
+			name:       "reduction capped",
+			in:         swe.SoleProprietorInput{Profit: 400000},
+			income:     300000,
+			egenavgift: 300000*swe.EgenavgiftRate - swe.EgenavgiftReductionMax,
+		},
+		{
+			name:       "periodiseringsfond capped at 30%",
+			in:         swe.SoleProprietorInput{Profit: 100000, PeriodiseringsfondShare: 0.5},
+			income:     52500,
+			egenavgift: 52500*swe.EgenavgiftRate - 52500*swe.EgenavgiftReductionRate,
+			fund:       30000,
+		},
+		{
+			name:       "reversal and expansionsfond",
+			in:         swe.SoleProprietorInput{Profit: 100000, Reversal: 20000, Expansionsfond: 40000},
+			income:     60000,
+			egenavgift: 60000*swe.EgenavgiftRate - 60000*swe.EgenavgiftReductionRate,
+			expansion:  40000,
+		},
+		{
+			name: "loss",
+			in:   swe.SoleProprietorInput{Profit: -50000, PeriodiseringsfondShare: 0.3, Expansionsfond: 10000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := swe.CalculateSoleProprietor(tt.in, flat)
+			if !approxEqual(r.Income, tt.income, 0.01) {
+				t.Errorf("Income = %v, want %v", r.Income, tt.income)
+			}
+			if !approxEqual(r.Egenavgifter, tt.egenavgift, 0.01) {
+				t.Errorf("Egenavgifter = %v, want %v", r.Egenavgifter, tt.egenavgift)
+			}
+			if !approxEqual(r.Periodiseringsfond, tt.fund, 0.01) {
+				t.Errorf("Periodiseringsfond = %v, want %v", r.Periodiseringsfond, tt.fund)
+			}
+			if !approxEqual(r.Expansionsfond, tt.expansion, 0.01) {
+				t.Errorf("Expansionsfond = %v, want %v", r.Expansionsfond, tt.expansion)
+			}
+			wantTax := tt.egenavgift + tt.income*0.3 + tt.expansion*swe.ExpansionsfondTaxRate
+			if !approxEqual(r.Tax(), wantTax, 0.01) {
+				t.Errorf("Tax() = %v, want %v", r.Tax(), wantTax)
+			}
+			if !approxEqual(r.Net, tt.in.Profit-wantTax, 0.01) {
+				t.Errorf("Net = %v, want %v", r.Net, tt.in.Profit-wantTax)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package swe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TaxDataset identifies one of the Skatteverket datasets kept in the cache.
+type TaxDataset string
+
+const (
+	TaxDatasetRates  TaxDataset = "tax_rates"
+	TaxDatasetTables TaxDataset = "tax_tables"
+)
+
+// ListableCache is a Cache that can also enumerate its entries by key prefix.
+// It is required for exporting cached datasets.
+type ListableCache interface {
+	Cache
+	List(ctx context.Context, prefix string) (map[string]string, error)
+}
+
+// ParseTaxData reads rows from an official Skatteverket export. Both the
+// rowstore JSON format ({"results": [...]}), a plain JSON array of rows and
+// CSV with a header row (comma or semicolon separated) are accepted. Column
+// names are lowercased to match the rowstore API.
+func ParseTaxData(r io.Reader) ([]map[string]string, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		br.Discard(3)
+	}
+	for {
+		b, err := br.Peek(1)
+		if err == io.EOF {
+			return nil, fmt.Errorf("empty tax data")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading tax data: %w", err)
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			br.Discard(1)
+			continue
+		case '[', '{':
+			return parseTaxDataJSON(br)
+		default:
+			return parseTaxDataCSV(br)
+		}
+	}
+}
+
+func parseTaxDataJSON(r io.Reader) ([]map[string]string, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decoding tax data: %w", err)
+	}
+	var rows []map[string]any
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		var resp struct {
+			Results []map[string]any `json:"results"`
+		}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return nil, fmt.Errorf("decoding tax data: %w", err)
+		}
+		rows = resp.Results
+	} else if err := json.Unmarshal(raw, &rows); err != nil {
+		return nil, fmt.Errorf("decoding tax data: %w", err)
+	}
+
+	results := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		out := make(map[string]string, len(row))
+		for k, v := range row {
+			var s string
+			switch v := v.(type) {
+			case nil:
+			case string:
+				s = v
+			case float64:
+				s = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				s = fmt.Sprint(v)
+			}
+			out[normalizeTaxDataColumn(k)] = s
+		}
+		results = append(results, out)
+	}
+	return results, nil
+}
+
+func parseTaxDataCSV(br *bufio.Reader) ([]map[string]string, error) {
+	// A short read only means the input is smaller than the buffer.
+	header, _ := br.Peek(br.Size())
+	if i := bytes.IndexByte(header, '\n'); i >= 0 {
+		header = header[:i]
+	}
+	cr := csv.NewReader(br)
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("decoding tax data: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty tax data")
+	}
+	columns := make([]string, len(records[0]))
+	for i, c := range records[0] {
+		columns[i] = normalizeTaxDataColumn(c)
+	}
+	results := make([]map[string]string, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		row := make(map[string]string, len(columns))
+		for i, c := range columns {
+			if i < len(rec) {
+				row[c] = strings.TrimSpace(rec[i])
+			}
+		}
+		results = append(results, row)
+	}
+	return results, nil
+}
+
+func normalizeTaxDataColumn(c string) string {
+	return strings.ToLower(strings.TrimSpace(c))
+}
+
+// DetectTaxDataset determines which dataset the rows belong to from their columns.
+func DetectTaxDataset(rows []map[string]string) (TaxDataset, error) {
+	if len(rows) == 0 {
+		return "", fmt.Errorf("no rows in tax data")
+	}
+	row := rows[0]
+	if _, ok := row["tabellnr"]; ok {
+		if _, ok := row["inkomst fr.o.m."]; ok {
+			return TaxDatasetTables, nil
+		}
+	}
+	if _, ok := row["kommun"]; ok {
+		if _, ok := row["församling"]; ok {
+			return TaxDatasetRates, nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized tax data columns")
+}
+
+// ImportTaxData parses an official export and stores it in the cache under
+// the same keys the client uses for API responses. It returns the detected
+// dataset and the number of cache entries written.
+func (c *Client) ImportTaxData(ctx context.Context, r io.Reader) (TaxDataset, int, error) {
+	rows, err := ParseTaxData(r)
+	if err != nil {
+		return "", 0, err
+	}
+	dataset, err := DetectTaxDataset(rows)
+	if err != nil {
+		return "", 0, err
+	}
+	var n int
+	switch dataset {
+	case TaxDatasetRates:
+		n, err = c.ImportTaxRates(ctx, rows)
+	case TaxDatasetTables:
+		n, err = c.ImportTaxTables(ctx, rows)
+	}
+	return dataset, n, err
+}
+
+// ImportTaxRates stores tax rate rows per kommun/församling/year, together
+// with the kommun and församling listings for each year. The listings are
+// merged with what is already cached, so partial files can be imported one
+// after another.
+func (c *Client) ImportTaxRates(ctx context.Context, rows []map[string]string) (int, error) {
+	entries := make(map[string][]map[string]string)
+	var listings []string
+	addListing := func(key string, row map[string]string) error {
+		if _, ok := entries[key]; !ok {
+			cached, err := c.cachedRows(ctx, key)
+			if err != nil {
+				return err
+			}
+			entries[key] = cached
+			listings = append(listings, key)
+		}
+		entries[key] = append(entries[key], row)
+		return nil
+	}
+	for _, row := range rows {
+		kommun, forsamling, year := row["kommun"], row["församling"], row["år"]
+		if kommun == "" || forsamling == "" || year == "" {
+			continue
+		}
+		key := fmt.Sprintf("tax_rate:%s:%s:%s", kommun, forsamling, year)
+		entries[key] = append(entries[key], row)
+		if err := addListing(fmt.Sprintf("kommuner:%s", year), row); err != nil {
+			return 0, err
+		}
+		if err := addListing(fmt.Sprintf("forsamlingar:%s:%s", kommun, year), row); err != nil {
+			return 0, err
+		}
+	}
+	for _, key := range listings {
+		merged, err := appendNewRows(nil, make(map[string]struct{}), entries[key])
+		if err != nil {
+			return 0, err
+		}
+		entries[key] = merged
+	}
+	return c.storeEntries(ctx, entries)
+}
+
+// cachedRows returns the rows cached under key, or nil if there are none.
+func (c *Client) cachedRows(ctx context.Context, key string) ([]map[string]string, error) {
+	raw, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("cache get: %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	var rows []map[string]string
+	if err := json.Unmarshal([]byte(raw), &rows); err != nil {
+		return nil, fmt.Errorf("decoding cached data for %s: %w", key, err)
+	}
+	return rows, nil
+}
+
+// appendNewRows appends the rows not yet in seen to dst and marks them seen.
+func appendNewRows(dst []map[string]string, seen map[string]struct{}, rows []map[string]string) ([]map[string]string, error) {
+	for _, row := range rows {
+		// Maps are encoded with sorted keys, so the encoding identifies the row.
+		id, err := json.Marshal(row)
+		if err != nil {
+			return nil, fmt.Errorf("encoding row: %w", err)
+		}
+		if _, ok := seen[string(id)]; ok {
+			continue
+		}
+		seen[string(id)] = struct{}{}
+		dst = append(dst, row)
+	}
+	return dst, nil
+}
+
+// ImportTaxTables stores the monthly (30B) tax table rows per table number and year.
+func (c *Client) ImportTaxTables(ctx context.Context, rows []map[string]string) (int, error) {
+	entries := make(map[string][]map[string]string)
+	for _, row := range rows {
+		tabellnr, year := row["tabellnr"], row["år"]
+		if tabellnr == "" || year == "" {
+			continue
+		}
+		if days, ok := row["antal dgr"]; ok && days != "30B" {
+			continue
+		}
+		key := fmt.Sprintf("tax_table:%s:%s", tabellnr, year)
+		entries[key] = append(entries[key], row)
+	}
+	return c.storeEntries(ctx, entries)
+}
+
+func (c *Client) storeEntries(ctx context.Context, entries map[string][]map[string]string) (int, error) {
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("no importable rows in tax data")
+	}
+	for key, rows := range entries {
+		raw, err := json.Marshal(rows)
+		if err != nil {
+			return 0, fmt.Errorf("encoding for cache: %w", err)
+		}
+		if err := c.cache.Set(ctx, key, string(raw)); err != nil {
+			return 0, fmt.Errorf("cache set: %w", err)
+		}
+	}
+	return len(entries), nil
+}
+
+// ExportTaxData returns all cached rows of the dataset in the rowstore JSON
+// format, so the result can be imported again with ImportTaxData.
+func (c *Client) ExportTaxData(ctx context.Context, dataset TaxDataset) (RowStoreResponse, error) {
+	lc, ok := c.cache.(ListableCache)
+	if !ok {
+		return RowStoreResponse{}, fmt.Errorf("cache does not support listing")
+	}
+	var prefixes []string
+	switch dataset {
+	case TaxDatasetRates:
+		prefixes = []string{"tax_rate:", "kommuner:", "forsamlingar:"}
+	case TaxDatasetTables:
+		prefixes = []string{"tax_table:"}
+	default:
+		return RowStoreResponse{}, fmt.Errorf("unknown tax dataset %q", dataset)
+	}
+
+	seen := make(map[string]struct{})
+	var results []map[string]string
+	for _, prefix := range prefixes {
+		entries, err := lc.List(ctx, prefix)
+		if err != nil {
+			return RowStoreResponse{}, fmt.Errorf("listing cache: %w", err)
+		}
+		keys := make([]string, 0, len(entries))
+		for k := range entries {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			var rows []map[string]string
+			if err := json.Unmarshal([]byte(entries[k]), &rows); err != nil {
+				return RowStoreResponse{}, fmt.Errorf("decoding cached data for %s: %w", k, err)
+			}
+			if results, err = appendNewRows(results, seen, rows); err != nil {
+				return RowStoreResponse{}, err
+			}
+		}
+	}
+	if results == nil {
+		results = []map[string]string{}
+	}
+	return RowStoreResponse{ResultCount: len(results), Results: results}, nil
+}
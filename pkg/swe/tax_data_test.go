@@ -0,0 +1,215 @@
+package swe_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+// offlineClient returns a client whose API endpoints always fail, so every
+// lookup has to be served from imported data.
+func offlineClient(cache swe.Cache) *swe.Client {
+	return swe.NewClient(cache,
+		swe.WithTaxRateURL("http://127.0.0.1:0/rates"),
+		swe.WithTaxTableURL("http://127.0.0.1:0/tables"),
+	)
+}
+
+func TestParseTaxData(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"rowstore json", `{"resultCount":1,"results":[{"kommun":"STOCKHOLM","församling":"KATARINA FÖRSAMLING","år":"2025"}]}`},
+		{"json array", `[{"Kommun":"STOCKHOLM","Församling":"KATARINA FÖRSAMLING","År":2025}]`},
+		{"csv comma", "kommun,församling,år\nSTOCKHOLM,KATARINA FÖRSAMLING,2025\n"},
+		{"csv semicolon with bom", "\xef\xbb\xbfKommun;Församling;År\r\nSTOCKHOLM;KATARINA FÖRSAMLING;2025\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := swe.ParseTaxData(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("expected 1 row, got %d", len(rows))
+			}
+			if rows[0]["kommun"] != "STOCKHOLM" || rows[0]["församling"] != "KATARINA FÖRSAMLING" || rows[0]["år"] != "2025" {
+				t.Errorf("unexpected row: %v", rows[0])
+			}
+			ds, err := swe.DetectTaxDataset(rows)
+			if err != nil || ds != swe.TaxDatasetRates {
+				t.Errorf("expected tax rates dataset, got %q (%v)", ds, err)
+			}
+		})
+	}
+}
+
+func TestImportTaxRates_ServesLookupsOffline(t *testing.T) {
+	csv := "år;kommun;församling;summa, exkl. kyrkoavgift;summa, inkl. kyrkoavgift\n" +
+		"2025;STOCKHOLM;ADOLF FREDRIKS FÖRSAMLING;30,67;31,85\n" +
+		"2025;STOCKHOLM;KATARINA FÖRSAMLING;30,67;31,72\n" +
+		"2025;UPPSALA;DOMKYRKOFÖRSAMLINGEN;33,20;34,60\n"
+	client := offlineClient(newFakeCache())
+	ctx := context.Background()
+
+	ds, n, err := client.ImportTaxData(ctx, strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ds != swe.TaxDatasetRates {
+		t.Errorf("expected %q, got %q", swe.TaxDatasetRates, ds)
+	}
+	// 3 tax rate entries, 1 kommun list and 2 församling lists.
+	if n != 6 {
+		t.Errorf("expected 6 cache entries, got %d", n)
+	}
+
+	table, err := client.GetTaxTableNumber(ctx, "STOCKHOLM", "KATARINA FÖRSAMLING", "2025", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if table != 32 {
+		t.Errorf("expected table 32, got %d", table)
+	}
+	kommuner, err := client.ListKommuner(ctx, "2025")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(kommuner) != 2 || kommuner[0] != "STOCKHOLM" || kommuner[1] != "UPPSALA" {
+		t.Errorf("unexpected kommuner: %v", kommuner)
+	}
+	forsamlingar, err := client.ListForsamlingar(ctx, "STOCKHOLM", "2025")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(forsamlingar) != 2 {
+		t.Errorf("expected 2 forsamlingar, got %v", forsamlingar)
+	}
+}
+
+func TestImportTaxRates_MergesPartialImports(t *testing.T) {
+	header := "år;kommun;församling;summa, exkl. kyrkoavgift;summa, inkl. kyrkoavgift\n"
+	first := header +
+		"2025;STOCKHOLM;ADOLF FREDRIKS FÖRSAMLING;30,67;31,85\n" +
+		"2025;UPPSALA;DOMKYRKOFÖRSAMLINGEN;33,20;34,60\n"
+	second := header +
+		"2025;STOCKHOLM;KATARINA FÖRSAMLING;30,67;31,72\n" +
+		"2025;STOCKHOLM;ADOLF FREDRIKS FÖRSAMLING;30,67;31,85\n"
+	client := offlineClient(newFakeCache())
+	ctx := context.Background()
+
+	for _, data := range []string{first, second} {
+		if _, _, err := client.ImportTaxData(ctx, strings.NewReader(data)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	kommuner, err := client.ListKommuner(ctx, "2025")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(kommuner) != 2 || kommuner[0] != "STOCKHOLM" || kommuner[1] != "UPPSALA" {
+		t.Errorf("unexpected kommuner: %v", kommuner)
+	}
+	forsamlingar, err := client.ListForsamlingar(ctx, "STOCKHOLM", "2025")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(forsamlingar) != 2 {
+		t.Errorf("expected 2 forsamlingar, got %v", forsamlingar)
+	}
+	if _, err := client.GetTaxTableNumber(ctx, "UPPSALA", "DOMKYRKOFÖRSAMLINGEN", "2025", false); err != nil {
+		t.Errorf("expected rates from the first import to remain: %v", err)
+	}
+}
+
+func TestImportTaxTables_OnlyMonthlyRows(t *testing.T) {
+	data := `{"results":[
+		{"år":"2025","antal dgr":"30B","tabellnr":"32","inkomst fr.o.m.":"40001","inkomst t.o.m.":"40200","kolumn 1":"9000"},
+		{"år":"2025","antal dgr":"30","tabellnr":"32","inkomst fr.o.m.":"40001","inkomst t.o.m.":"40200","kolumn 1":"31"}
+	]}`
+	cache := newFakeCache()
+	client := offlineClient(cache)
+	ctx := context.Background()
+
+	ds, n, err := client.ImportTaxData(ctx, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ds != swe.TaxDatasetTables || n != 1 {
+		t.Fatalf("expected 1 tax table entry, got %q/%d", ds, n)
+	}
+	tax, err := client.LookupTax(ctx, 32, "2025", 40100, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tax != 9000 {
+		t.Errorf("expected tax 9000, got %f", tax)
+	}
+}
+
+func TestExportTaxData_RoundTrips(t *testing.T) {
+	rates := `[
+		{"år":"2025","kommun":"STOCKHOLM","församling":"KATARINA FÖRSAMLING","summa, exkl. kyrkoavgift":"30.67","summa, inkl. kyrkoavgift":"31.72"},
+		{"år":"2025","kommun":"UPPSALA","församling":"DOMKYRKOFÖRSAMLINGEN","summa, exkl. kyrkoavgift":"33.20","summa, inkl. kyrkoavgift":"34.60"}
+	]`
+	ctx := context.Background()
+	src := offlineClient(newFakeCache())
+	if _, _, err := src.ImportTaxData(ctx, strings.NewReader(rates)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exported, err := src.ExportTaxData(ctx, swe.TaxDatasetRates)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Rows are cached under several keys but exported once.
+	if exported.ResultCount != 2 || len(exported.Results) != 2 {
+		t.Fatalf("expected 2 exported rows, got %d", len(exported.Results))
+	}
+	tables, err := src.ExportTaxData(ctx, swe.TaxDatasetTables)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tables.Results) != 0 {
+		t.Errorf("expected no tax table rows, got %d", len(tables.Results))
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(exported); err != nil {
+		t.Fatal(err)
+	}
+	dst := offlineClient(newFakeCache())
+	if _, _, err := dst.ImportTaxData(ctx, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	table, err := dst.GetTaxTableNumber(ctx, "UPPSALA", "DOMKYRKOFÖRSAMLINGEN", "2025", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if table != 33 {
+		t.Errorf("expected table 33, got %d", table)
+	}
+}
+
+func TestExportTaxData_RequiresListableCache(t *testing.T) {
+	client := swe.NewClient(getOnlyCache{newFakeCache()})
+	if _, err := client.ExportTaxData(context.Background(), swe.TaxDatasetRates); err == nil {
+		t.Error("expected error for cache without listing")
+	}
+}
+
+type getOnlyCache struct{ c *fakeCache }
+
+func (g getOnlyCache) Get(ctx context.Context, key string) (string, bool, error) {
+	return g.c.Get(ctx, key)
+}
+
+func (g getOnlyCache) Set(ctx context.Context, key string, value string) error {
+	return g.c.Set(ctx, key, value)
+}
@@ -0,0 +1,172 @@
+package swe
+
+import (
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+const (
+	// CapitalIncomeTaxRate is the tax on the surplus of capital income.
+	CapitalIncomeTaxRate = 0.30
+	// CapitalLossDeductibleShare is the part of a capital loss on listed
+	// shares that can be offset against other capital income.
+	CapitalLossDeductibleShare = 0.70
+)
+
+// EarnedIncome is a person's income from employment in a calendar year,
+// including taxable benefits, with the final tax on it and the preliminary
+// tax withheld by the employers.
+type EarnedIncome struct {
+	Income   float64
+	Withheld float64
+	FinalTax float64
+}
+
+// ISKTaxRecorder is notified of the tax withdrawn from an ISK for the
+// schablonintäkt of year.
+type ISKTaxRecorder interface {
+	OnISKTax(year int, tax uncertain.Value)
+}
+
+type ISKTaxRecorderFunc func(year int, tax uncertain.Value)
+
+func (f ISKTaxRecorderFunc) OnISKTax(year int, tax uncertain.Value) {
+	f(year, tax)
+}
+
+// TaxReturn simulates one person's slutskattebesked. The final tax for a
+// calendar year is the tax on earned income plus the tax on the capital
+// surplus, where a capital deficit instead gives a skattereduktion of 30% up
+// to 100 000 kr and 21% above. The difference to the preliminary tax paid
+// during the year is refunded on 7 April or due as restskatt on 12 November
+// the following year, as a negative or positive tax on the account it is
+// attached to (finance.TaxModel).
+//
+// Earned income is known up front, while capital income, deductible interest,
+// realised capital gains and the tax withdrawn for ISK are recorded as the
+// forecast runs. ISK tax is counted as preliminary tax on a schablonintäkt of
+// tax / 30%, so it only changes the result when it offsets a deficit.
+type TaxReturn struct {
+	Earned map[int]EarnedIncome
+
+	years   map[int]*taxReturnYear
+	pending map[int]uncertain.Value
+	settled map[int]bool
+}
+
+type taxReturnYear struct {
+	capital uncertain.Value
+	gains   uncertain.Value
+	iskTax  uncertain.Value
+}
+
+func (r *TaxReturn) year(year int) *taxReturnYear {
+	if r.years == nil {
+		r.years = make(map[int]*taxReturnYear)
+	}
+	y, ok := r.years[year]
+	if !ok {
+		y = &taxReturnYear{}
+		r.years[year] = y
+	}
+	return y
+}
+
+func addTo(ucfg *uncertain.Config, sum *uncertain.Value, v uncertain.Value) {
+	if sum.Zero() {
+		*sum = v
+	} else {
+		*sum = sum.Add(ucfg, v)
+	}
+}
+
+// AddCapitalIncome records capital income such as interest on savings, or a
+// deductible expense such as interest on loans when amount is negative.
+func (r *TaxReturn) AddCapitalIncome(ucfg *uncertain.Config, day date.Date, amount uncertain.Value) {
+	addTo(ucfg, &r.year(day.Year()).capital, amount)
+}
+
+// AddCapitalGain records a realised capital gain, or a loss when negative.
+func (r *TaxReturn) AddCapitalGain(ucfg *uncertain.Config, day date.Date, gain uncertain.Value) {
+	addTo(ucfg, &r.year(day.Year()).gains, gain)
+}
+
+// AddISKTax records the tax already withdrawn for the ISK schablonintäkt of
+// year.
+func (r *TaxReturn) AddISKTax(ucfg *uncertain.Config, year int, tax uncertain.Value) {
+	addTo(ucfg, &r.year(year).iskTax, tax)
+}
+
+// CalculateCapitalTax returns the tax on a year's net capital income, which
+// is negative for the skattereduktion on a deficit.
+func CalculateCapitalTax(capital float64) float64 {
+	if capital >= 0 {
+		return capital * CapitalIncomeTaxRate
+	}
+	return -CalculateInterestDeduction(-capital)
+}
+
+// difference returns the final tax minus the preliminary tax paid for year,
+// positive for restskatt and negative for a skatteåterbäring.
+func (r *TaxReturn) difference(year int) uncertain.Value {
+	earned := r.Earned[year]
+	y := r.years[year]
+	if y == nil {
+		y = &taxReturnYear{}
+	}
+	sample := func(v uncertain.Value, cfg *uncertain.Config) float64 {
+		if v.Zero() {
+			return 0
+		}
+		return v.Sample(cfg)
+	}
+	fixed := earned.FinalTax - earned.Withheld
+	if y.capital.Zero() && y.gains.Zero() && y.iskTax.Zero() {
+		return uncertain.NewFixed(fixed)
+	}
+	return uncertain.NewMapped(func(cfg *uncertain.Config) float64 {
+		iskTax := sample(y.iskTax, cfg)
+		gains := sample(y.gains, cfg)
+		if gains < 0 {
+			gains *= CapitalLossDeductibleShare
+		}
+		capital := sample(y.capital, cfg) + gains + iskTax/iskTaxRate
+		return fixed + CalculateCapitalTax(capital) - iskTax
+	})
+}
+
+func (r *TaxReturn) Apply(ucfg *uncertain.Config, day date.Date, balance uncertain.Value, dayDeposits uncertain.Value) uncertain.Value {
+	prev := day.Year() - 1
+	if r.settled[prev] {
+		return uncertain.Value{}
+	}
+	if day == FinalTaxSettlementDate(prev, true) {
+		if _, ok := r.Earned[prev]; !ok && r.years[prev] == nil {
+			return uncertain.Value{}
+		}
+		diff := r.difference(prev).Materialize(ucfg)
+		if diff.Mean() < 0 {
+			r.settle(prev)
+			return diff
+		}
+		if r.pending == nil {
+			r.pending = make(map[int]uncertain.Value)
+		}
+		r.pending[prev] = diff
+		return uncertain.Value{}
+	}
+	if diff, ok := r.pending[prev]; ok && day == FinalTaxSettlementDate(prev, false) {
+		r.settle(prev)
+		return diff
+	}
+	return uncertain.Value{}
+}
+
+func (r *TaxReturn) settle(year int) {
+	if r.settled == nil {
+		r.settled = make(map[int]bool)
+	}
+	r.settled[year] = true
+	delete(r.pending, year)
+	delete(r.years, year)
+}
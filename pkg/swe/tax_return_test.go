@@ -0,0 +1,82 @@
+package swe_test
+
+import (
+	"testing"
+
+	"github.com/SimonSchneider/goslu/date"
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+	"github.com/SimonSchneider/pefigo/pkg/uncertain"
+)
+
+func TestCalculateCapitalTax(t *testing.T) {
+	tests := []struct {
+		capital float64
+		want    float64
+	}{
+		{capital: 10000, want: 3000},
+		{capital: 0, want: 0},
+		{capital: -10000, want: -3000},
+		{capital: -150000, want: -(30000 + 50000*0.21)},
+	}
+	for _, tt := range tests {
+		if got := swe.CalculateCapitalTax(tt.capital); !approxEqual(got, tt.want, 0.01) {
+			t.Errorf("CalculateCapitalTax(%v) = %v, want %v", tt.capital, got, tt.want)
+		}
+	}
+}
+
+func TestTaxReturn_RestskattDueInNovember(t *testing.T) {
+	ucfg := uncertain.NewConfig(1, 10)
+	r := &swe.TaxReturn{Earned: map[int]swe.EarnedIncome{2025: {Income: 600000, Withheld: 150000, FinalTax: 155000}}}
+	r.AddCapitalIncome(ucfg, mustParseDate("2025-06-30"), uncertain.NewFixed(2000))
+
+	if tax := r.Apply(ucfg, mustParseDate("2026-04-07"), uncertain.Value{}, uncertain.Value{}); !tax.Zero() {
+		t.Errorf("restskatt charged in April: %v", tax.Mean())
+	}
+	tax := r.Apply(ucfg, mustParseDate("2026-11-12"), uncertain.Value{}, uncertain.Value{})
+	if !approxEqual(tax.Mean(), 5600, 0.01) {
+		t.Errorf("restskatt = %v, want 5600", tax.Mean())
+	}
+	if tax := r.Apply(ucfg, mustParseDate("2026-11-12"), uncertain.Value{}, uncertain.Value{}); !tax.Zero() {
+		t.Errorf("year settled twice: %v", tax.Mean())
+	}
+}
+
+func TestTaxReturn_RefundsDeficitInApril(t *testing.T) {
+	ucfg := uncertain.NewConfig(1, 10)
+	r := &swe.TaxReturn{Earned: map[int]swe.EarnedIncome{2025: {Income: 600000, Withheld: 150000, FinalTax: 150000}}}
+	// 10 000 kr interest on loans, 3 000 kr ISK schablonintäkt and a
+	// 2 000 kr loss on shares of which 70% is deductible.
+	r.AddCapitalIncome(ucfg, mustParseDate("2025-03-31"), uncertain.NewFixed(-10000))
+	r.AddISKTax(ucfg, 2025, uncertain.NewFixed(900))
+	r.AddCapitalGain(ucfg, mustParseDate("2025-09-01"), uncertain.NewFixed(-2000))
+
+	tax := r.Apply(ucfg, mustParseDate("2026-04-07"), uncertain.Value{}, uncertain.Value{})
+	// The deficit is 10 000 - 3 000 + 1 400 = 8 400 kr, reducing the tax by
+	// 2 520 kr, and the 900 kr ISK tax already paid is refunded.
+	if !approxEqual(tax.Mean(), -3420, 0.01) {
+		t.Errorf("refund = %v, want -3420", tax.Mean())
+	}
+	if tax := r.Apply(ucfg, mustParseDate("2026-11-12"), uncertain.Value{}, uncertain.Value{}); !tax.Zero() {
+		t.Errorf("refunded year charged again in November: %v", tax.Mean())
+	}
+}
+
+func TestISKTax_RecordsTaxForPreviousYear(t *testing.T) {
+	ucfg := uncertain.NewConfig(1, 10)
+	var recorded int
+	var recordedTax float64
+	isk := &swe.ISKTax{
+		ParamsFunc: func(_ date.Date) swe.ISKParams { return swe.ISKParams{SchablonRanta: 0.03} },
+		Recorder: swe.ISKTaxRecorderFunc(func(year int, tax uncertain.Value) {
+			recorded, recordedTax = year, tax.Mean()
+		}),
+	}
+	balance := uncertain.NewFixed(100000)
+	for d := mustParseDate("2025-01-01"); d <= mustParseDate("2026-01-01"); d = d.Add(date.Day) {
+		isk.Apply(ucfg, d, balance, uncertain.NewFixed(0))
+	}
+	if recorded != 2025 || !approxEqual(recordedTax, 900, 0.01) {
+		t.Errorf("recorded %v for %d, want 900 for 2025", recordedTax, recorded)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/SimonSchneider/pefigo/pkg/swe"
@@ -28,6 +29,16 @@ func (c *fakeCache) Set(_ context.Context, key string, value string) error {
 	return nil
 }
 
+func (c *fakeCache) List(_ context.Context, prefix string) (map[string]string, error) {
+	out := make(map[string]string)
+	for k, v := range c.data {
+		if strings.HasPrefix(k, prefix) {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
 func taxRateServer() *httptest.Server {
 	allRows := []map[string]string{
 		{
@@ -0,0 +1,76 @@
+package swe
+
+const (
+	// AKassaDaysPerMonth is the number of benefit days paid per month, five
+	// days per week.
+	AKassaDaysPerMonth = 22
+	// AKassaKarensDays is the number of unpaid waiting days at the start of
+	// an unemployment period.
+	AKassaKarensDays = 6
+	// AKassaMaxDays is the length of a benefit period in benefit days.
+	AKassaMaxDays = 300
+	// AKassaDailyCapFirst is the highest daily benefit for days 1-100.
+	AKassaDailyCapFirst = 1200
+	// AKassaDailyCap is the highest daily benefit from day 101.
+	AKassaDailyCap = 1000
+	// AKassaBasicDaily is the grundbelopp paid to non-members working full time.
+	AKassaBasicDaily = 510
+	// IncomeInsuranceRate is the share of salary union income insurance
+	// tops the benefit up to.
+	IncomeInsuranceRate = 0.80
+)
+
+// IncomeInsurance is a union inkomstförsäkring topping a-kassa up to 80% of
+// the salary, up to a monthly salary Ceiling, for the first Days benefit days.
+type IncomeInsurance struct {
+	Ceiling float64
+	Days    int
+}
+
+// AKassaDailyBenefit returns the a-kassa paid on benefit day (1-based, counted
+// after karensdagar) for a monthly gross salary. Members get 80% of their daily
+// income for the first 200 days and 70% after, capped at 1200 kr for the first
+// 100 days and 1000 kr after. Non-members get the grundbelopp. Nothing is paid
+// after the 300-day benefit period.
+func AKassaDailyBenefit(monthlyGross float64, day int, member bool) float64 {
+	if day < 1 || day > AKassaMaxDays {
+		return 0
+	}
+	if !member {
+		return AKassaBasicDaily
+	}
+	rate, dailyCap := 0.80, float64(AKassaDailyCapFirst)
+	if day > 200 {
+		rate = 0.70
+	}
+	if day > 100 {
+		dailyCap = AKassaDailyCap
+	}
+	return min(dailyCap, rate*monthlyGross/AKassaDaysPerMonth)
+}
+
+// IncomeInsuranceDailyTopUp returns the income insurance paid on benefit day on
+// top of akassa.
+func IncomeInsuranceDailyTopUp(monthlyGross float64, day int, akassa float64, ins IncomeInsurance) float64 {
+	if ins.Ceiling <= 0 || day < 1 || day > ins.Days {
+		return 0
+	}
+	target := IncomeInsuranceRate * min(monthlyGross, ins.Ceiling) / AKassaDaysPerMonth
+	return max(0, target-akassa)
+}
+
+// UnemploymentMonthlyBenefit returns the taxable a-kassa and income insurance
+// top-up paid for month (0-based) of an unemployment period. The first month
+// is reduced by the karensdagar.
+func UnemploymentMonthlyBenefit(monthlyGross float64, month int, member bool, ins IncomeInsurance) (akassa, topUp float64) {
+	if month < 0 {
+		return 0, 0
+	}
+	for d := month*AKassaDaysPerMonth + 1; d <= (month+1)*AKassaDaysPerMonth; d++ {
+		day := d - AKassaKarensDays
+		daily := AKassaDailyBenefit(monthlyGross, day, member)
+		akassa += daily
+		topUp += IncomeInsuranceDailyTopUp(monthlyGross, day, daily, ins)
+	}
+	return akassa, topUp
+}
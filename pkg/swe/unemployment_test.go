@@ -0,0 +1,56 @@
+package swe_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/SimonSchneider/pefigo/pkg/swe"
+)
+
+func TestAKassaDailyBenefit(t *testing.T) {
+	tests := []struct {
+		name         string
+		monthlyGross float64
+		day          int
+		member       bool
+		want         float64
+	}{
+		{"karens", 30000, 0, true, 0},
+		{"80% below cap", 22000, 1, true, 800},
+		{"capped first 100 days", 50000, 100, true, 1200},
+		{"lower cap from day 101", 50000, 101, true, 1000},
+		{"80% from day 101", 22000, 150, true, 800},
+		{"70% from day 201", 22000, 201, true, 700},
+		{"benefit period over", 22000, 301, true, 0},
+		{"grundbelopp for non-members", 50000, 1, false, 510},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := swe.AKassaDailyBenefit(tt.monthlyGross, tt.day, tt.member); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("AKassaDailyBenefit() = %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnemploymentMonthlyBenefit(t *testing.T) {
+	ins := swe.IncomeInsurance{Ceiling: 100000, Days: 120}
+
+	akassa, topUp := swe.UnemploymentMonthlyBenefit(50000, 0, true, swe.IncomeInsurance{})
+	if want := 16.0 * 1200; akassa != want || topUp != 0 {
+		t.Errorf("first month = %f + %f, want %f without top-up", akassa, topUp, want)
+	}
+
+	akassa, topUp = swe.UnemploymentMonthlyBenefit(50000, 1, true, ins)
+	if want := 22.0 * 1200; akassa != want {
+		t.Errorf("second month a-kassa = %f, want %f", akassa, want)
+	}
+	if want := 0.8*50000 - 22.0*1200; math.Abs(topUp-want) > 0.01 {
+		t.Errorf("second month top-up = %f, want %f", topUp, want)
+	}
+
+	_, topUp = swe.UnemploymentMonthlyBenefit(50000, 7, true, ins)
+	if topUp != 0 {
+		t.Errorf("top-up after insurance days = %f, want 0", topUp)
+	}
+}
@@ -263,6 +263,29 @@ func (u Value) ApplyFixed(cfg *Config, fixed float64, op func(a, b float64) floa
 	return u.sampleWithFixed(cfg, fixed, op)
 }
 
+// Combine applies op to u and v. Like Add and the other operations, the two
+// values are sampled independently of each other, so a draw of u is not paired
+// with the draw of v it was derived from.
+func (u Value) Combine(cfg *Config, v Value, op func(a, b float64) float64) Value {
+	return u.operate(cfg, v, op)
+}
+
+// Materialize samples a mapped value into an empirical distribution, so the
+// mapping function is evaluated now rather than on every later sample.
+func (u Value) Materialize(cfg *Config) Value {
+	if u.Distribution != DistMapped {
+		return u
+	}
+	res := make([]float64, cfg.Samples)
+	for i := range res {
+		res[i] = u.SampleFun(cfg)
+	}
+	return Value{
+		Distribution: DistEmpirical,
+		Samples:      res,
+	}
+}
+
 func (u Value) Add(cfg *Config, v Value) Value {
 	return u.operate(cfg, v, func(a, b float64) float64 { return a + b })
 }
@@ -67,3 +67,31 @@ func TestMappedMeanWithUncertain(t *testing.T) {
 		t.Errorf("Mean() = %v, want ~200", got)
 	}
 }
+
+func TestMaterializeEvaluatesOnce(t *testing.T) {
+	cfg := NewConfig(42, 100)
+	calls := 0
+	mapped := NewMapped(func(cfg *Config) float64 {
+		calls++
+		return 5
+	})
+	got := mapped.Materialize(cfg)
+	if got.Distribution != DistEmpirical || len(got.Samples) != 100 || calls != 100 {
+		t.Fatalf("Materialize() = %v with %d calls, want 100 empirical samples", got, calls)
+	}
+	got.Mean()
+	if calls != 100 {
+		t.Errorf("mapping function called %d times after materializing, want 100", calls)
+	}
+	if fixed := NewFixed(1); !reflect.DeepEqual(fixed.Materialize(cfg), fixed) {
+		t.Errorf("Materialize() changed a fixed value")
+	}
+}
+
+func TestCombine(t *testing.T) {
+	cfg := NewConfig(42, 10)
+	got := NewFixed(3).Combine(cfg, NewFixed(5), math.Min)
+	if got.Mean() != 3 {
+		t.Errorf("Combine(min) = %v, want 3", got.Mean())
+	}
+}
@@ -11,7 +11,9 @@ SET name = ?,
   cash_flow_destination_id = ?,
   type_id = ?,
   budget_category_id = ?,
-  is_isk = ?
+  is_isk = ?,
+  owner_id = ?,
+  currency = ?
 WHERE id = ?
 RETURNING *;
 -- name: DeleteAccount :one
@@ -33,10 +35,12 @@ INSERT INTO account (
     type_id,
     budget_category_id,
     is_isk,
+    owner_id,
+    currency,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 -- name: GetSnapshotsByAccount :many
 SELECT *
@@ -80,16 +84,20 @@ INSERT INTO growth_model (
     annual_volatility,
     start_date,
     end_date,
+    rate_model_id,
+    binding_years,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET model_type = EXCLUDED.model_type,
   annual_growth_rate = EXCLUDED.annual_growth_rate,
   annual_volatility = EXCLUDED.annual_volatility,
   start_date = EXCLUDED.start_date,
   end_date = EXCLUDED.end_date,
+  rate_model_id = EXCLUDED.rate_model_id,
+  binding_years = EXCLUDED.binding_years,
   updated_at = EXCLUDED.updated_at
 RETURNING *;
 -- name: DeleteGrowthModel :exec
@@ -123,10 +131,15 @@ INSERT INTO transfer_template (
     end_date,
     enabled,
     budget_category_id,
+    start_person_id,
+    start_age,
+    end_person_id,
+    end_age,
+    currency,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET name = EXCLUDED.name,
   from_account_id = EXCLUDED.from_account_id,
@@ -140,6 +153,11 @@ SET name = EXCLUDED.name,
   end_date = EXCLUDED.end_date,
   enabled = EXCLUDED.enabled,
   budget_category_id = EXCLUDED.budget_category_id,
+  start_person_id = EXCLUDED.start_person_id,
+  start_age = EXCLUDED.start_age,
+  end_person_id = EXCLUDED.end_person_id,
+  end_age = EXCLUDED.end_age,
+  currency = EXCLUDED.currency,
   updated_at = EXCLUDED.updated_at
 RETURNING *;
 -- name: DeleteTransferTemplate :exec
@@ -161,7 +179,8 @@ FROM account_snapshot s
 SELECT s.account_id,
   s.date,
   s.balance,
-  COALESCE(a.type_id, '') AS type_id
+  COALESCE(a.type_id, '') AS type_id,
+  a.currency
 FROM account_snapshot s
   JOIN account a ON a.id = s.account_id
 ORDER BY s.date,
@@ -175,6 +194,9 @@ SELECT ir.account_id,
   ir.valuation,
   ir.pre_money_shares,
   ir.investment,
+  ir.liquidation_preference,
+  ir.participating,
+  ir.seniority,
   ssa.tax_rate,
   ssa.valuation_discount_factor,
   COALESCE(a.type_id, '') AS type_id
@@ -224,12 +246,14 @@ SELECT *
 FROM special_date
 WHERE id = ?;
 -- name: UpsertSpecialDate :one
-INSERT INTO special_date (id, name, date, color)
-VALUES (?, ?, ?, ?) ON CONFLICT (id) DO
+INSERT INTO special_date (id, name, date, color, person_id, person_age)
+VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET name = EXCLUDED.name,
   date = EXCLUDED.date,
-  color = EXCLUDED.color
+  color = EXCLUDED.color,
+  person_id = EXCLUDED.person_id,
+  person_age = EXCLUDED.person_age
 RETURNING *;
 -- name: DeleteSpecialDate :exec
 DELETE FROM special_date
@@ -255,11 +279,17 @@ RETURNING *;
 DELETE FROM transfer_template_category
 WHERE id = ?;
 -- name: UpsertStartupShareAccount :one
-INSERT INTO startup_share_account (account_id, tax_rate, valuation_discount_factor)
-VALUES (?, ?, ?) ON CONFLICT (account_id) DO
+INSERT INTO startup_share_account (
+    account_id,
+    tax_rate,
+    valuation_discount_factor,
+    income_tax_rate
+  )
+VALUES (?, ?, ?, ?) ON CONFLICT (account_id) DO
 UPDATE
 SET tax_rate = EXCLUDED.tax_rate,
-  valuation_discount_factor = EXCLUDED.valuation_discount_factor
+  valuation_discount_factor = EXCLUDED.valuation_discount_factor,
+  income_tax_rate = EXCLUDED.income_tax_rate
 RETURNING *;
 -- name: GetStartupShareAccount :one
 SELECT *
@@ -307,14 +337,20 @@ INSERT INTO investment_round (
     valuation,
     pre_money_shares,
     investment,
+    liquidation_preference,
+    participating,
+    seniority,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id, date) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id, date) DO
 UPDATE
 SET valuation = EXCLUDED.valuation,
   pre_money_shares = EXCLUDED.pre_money_shares,
   investment = EXCLUDED.investment,
+  liquidation_preference = EXCLUDED.liquidation_preference,
+  participating = EXCLUDED.participating,
+  seniority = EXCLUDED.seniority,
   updated_at = EXCLUDED.updated_at
 RETURNING *;
 -- name: GetInvestmentRound :one
@@ -346,10 +382,16 @@ INSERT INTO startup_share_option (
     strike_price_per_share,
     grant_date,
     end_date,
+    cliff_months,
+    vesting_months,
+    vesting_interval_months,
+    accelerate_on_exit,
+    exercise_policy,
+    tax_treatment,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET account_id = EXCLUDED.account_id,
   source_account_id = EXCLUDED.source_account_id,
@@ -357,6 +399,12 @@ SET account_id = EXCLUDED.account_id,
   strike_price_per_share = EXCLUDED.strike_price_per_share,
   grant_date = EXCLUDED.grant_date,
   end_date = EXCLUDED.end_date,
+  cliff_months = EXCLUDED.cliff_months,
+  vesting_months = EXCLUDED.vesting_months,
+  vesting_interval_months = EXCLUDED.vesting_interval_months,
+  accelerate_on_exit = EXCLUDED.accelerate_on_exit,
+  exercise_policy = EXCLUDED.exercise_policy,
+  tax_treatment = EXCLUDED.tax_treatment,
   updated_at = EXCLUDED.updated_at
 RETURNING *;
 -- name: GetStartupShareOption :one
@@ -372,6 +420,65 @@ ORDER BY grant_date,
 -- name: DeleteStartupShareOption :exec
 DELETE FROM startup_share_option
 WHERE id = ?;
+-- name: UpsertProjectedInvestmentRound :one
+INSERT INTO projected_investment_round (
+    id,
+    account_id,
+    date,
+    probability,
+    pre_money_valuation,
+    investment,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET date = EXCLUDED.date,
+  probability = EXCLUDED.probability,
+  pre_money_valuation = EXCLUDED.pre_money_valuation,
+  investment = EXCLUDED.investment,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+-- name: GetProjectedInvestmentRound :one
+SELECT *
+FROM projected_investment_round
+WHERE id = ?;
+-- name: ListProjectedInvestmentRounds :many
+SELECT *
+FROM projected_investment_round
+WHERE account_id = ?
+ORDER BY date,
+  id;
+-- name: DeleteProjectedInvestmentRound :exec
+DELETE FROM projected_investment_round
+WHERE id = ?;
+-- name: UpsertStartupExit :one
+INSERT INTO startup_exit (
+    account_id,
+    date,
+    kind,
+    multiple,
+    failure_probability,
+    payout_account_id,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id) DO
+UPDATE
+SET date = EXCLUDED.date,
+  kind = EXCLUDED.kind,
+  multiple = EXCLUDED.multiple,
+  failure_probability = EXCLUDED.failure_probability,
+  payout_account_id = EXCLUDED.payout_account_id,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+-- name: GetStartupExit :one
+SELECT *
+FROM startup_exit
+WHERE account_id = ?;
+-- name: DeleteStartupExit :exec
+DELETE FROM startup_exit
+WHERE account_id = ?;
 -- name: GetBudgetAccounts :many
 SELECT *
 FROM account
@@ -0,0 +1,20 @@
+-- name: ListAccountOwners :many
+SELECT *
+FROM account_owner
+ORDER BY account_id,
+  person_id;
+-- name: ListAccountOwnersByAccount :many
+SELECT *
+FROM account_owner
+WHERE account_id = ?
+ORDER BY person_id;
+-- name: UpsertAccountOwner :one
+INSERT INTO account_owner (account_id, person_id, share)
+VALUES (?, ?, ?) ON CONFLICT (account_id, person_id) DO
+UPDATE
+SET share = EXCLUDED.share
+RETURNING *;
+-- name: DeleteAccountOwner :exec
+DELETE FROM account_owner
+WHERE account_id = ?
+  AND person_id = ?;
@@ -0,0 +1,34 @@
+-- name: ListAmortizationRequirements :many
+SELECT *
+FROM amortization_requirement
+ORDER BY account_id;
+
+-- name: GetAmortizationRequirement :one
+SELECT *
+FROM amortization_requirement
+WHERE account_id = ?;
+
+-- name: UpsertAmortizationRequirement :one
+INSERT INTO amortization_requirement (
+    account_id,
+    property_account_id,
+    from_account_id,
+    recurrence,
+    valuation_date,
+    revaluation_years,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id) DO
+UPDATE
+SET property_account_id = EXCLUDED.property_account_id,
+  from_account_id = EXCLUDED.from_account_id,
+  recurrence = EXCLUDED.recurrence,
+  valuation_date = EXCLUDED.valuation_date,
+  revaluation_years = EXCLUDED.revaluation_years,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteAmortizationRequirement :exec
+DELETE FROM amortization_requirement
+WHERE account_id = ?;
@@ -16,3 +16,9 @@ ON CONFLICT (cache_key) DO UPDATE SET value = EXCLUDED.value, created_at = EXCLU
 -- name: DeleteCacheEntry :exec
 DELETE FROM api_cache
 WHERE cache_key = ?;
+
+-- name: ListCacheEntriesByPrefix :many
+SELECT cache_key, value
+FROM api_cache
+WHERE cache_key LIKE ?
+ORDER BY cache_key;
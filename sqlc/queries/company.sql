@@ -0,0 +1,52 @@
+-- name: ListCompanies :many
+SELECT *
+FROM company
+ORDER BY name, id;
+
+-- name: GetCompany :one
+SELECT *
+FROM company
+WHERE id = ?;
+
+-- name: UpsertCompany :one
+INSERT INTO company (
+    id,
+    name,
+    owner_id,
+    ownership_share,
+    account_id,
+    dividend_account_id,
+    salary_id,
+    revenue,
+    expenses,
+    gransbelopp_rule,
+    acquisition_cost,
+    capital_rate,
+    saved_dividend_space,
+    dividend_date,
+    start_date,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET name = EXCLUDED.name,
+  owner_id = EXCLUDED.owner_id,
+  ownership_share = EXCLUDED.ownership_share,
+  account_id = EXCLUDED.account_id,
+  dividend_account_id = EXCLUDED.dividend_account_id,
+  salary_id = EXCLUDED.salary_id,
+  revenue = EXCLUDED.revenue,
+  expenses = EXCLUDED.expenses,
+  gransbelopp_rule = EXCLUDED.gransbelopp_rule,
+  acquisition_cost = EXCLUDED.acquisition_cost,
+  capital_rate = EXCLUDED.capital_rate,
+  saved_dividend_space = EXCLUDED.saved_dividend_space,
+  dividend_date = EXCLUDED.dividend_date,
+  start_date = EXCLUDED.start_date,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteCompany :exec
+DELETE FROM company
+WHERE id = ?;
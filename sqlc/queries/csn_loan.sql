@@ -0,0 +1,36 @@
+-- name: ListCsnLoans :many
+SELECT *
+FROM csn_loan
+ORDER BY account_id;
+
+-- name: GetCsnLoan :one
+SELECT *
+FROM csn_loan
+WHERE account_id = ?;
+
+-- name: UpsertCsnLoan :one
+INSERT INTO csn_loan (
+    account_id,
+    from_account_id,
+    salary_id,
+    birth_date,
+    final_year,
+    recurrence,
+    reduction,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id) DO
+UPDATE
+SET from_account_id = EXCLUDED.from_account_id,
+  salary_id = EXCLUDED.salary_id,
+  birth_date = EXCLUDED.birth_date,
+  final_year = EXCLUDED.final_year,
+  recurrence = EXCLUDED.recurrence,
+  reduction = EXCLUDED.reduction,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteCsnLoan :exec
+DELETE FROM csn_loan
+WHERE account_id = ?;
@@ -0,0 +1,56 @@
+-- name: ListEquityGrants :many
+SELECT *
+FROM equity_grant
+ORDER BY name, id;
+
+-- name: GetEquityGrant :one
+SELECT *
+FROM equity_grant
+WHERE id = ?;
+
+-- name: UpsertEquityGrant :one
+INSERT INTO equity_grant (
+    id,
+    name,
+    owner_id,
+    kind,
+    holding_account_id,
+    salary_account_id,
+    share_price,
+    shares,
+    cliff_months,
+    vesting_months,
+    vesting_interval_months,
+    contribution,
+    discount,
+    recurrence,
+    municipal_tax_rate,
+    start_date,
+    enabled,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET name = EXCLUDED.name,
+  owner_id = EXCLUDED.owner_id,
+  kind = EXCLUDED.kind,
+  holding_account_id = EXCLUDED.holding_account_id,
+  salary_account_id = EXCLUDED.salary_account_id,
+  share_price = EXCLUDED.share_price,
+  shares = EXCLUDED.shares,
+  cliff_months = EXCLUDED.cliff_months,
+  vesting_months = EXCLUDED.vesting_months,
+  vesting_interval_months = EXCLUDED.vesting_interval_months,
+  contribution = EXCLUDED.contribution,
+  discount = EXCLUDED.discount,
+  recurrence = EXCLUDED.recurrence,
+  municipal_tax_rate = EXCLUDED.municipal_tax_rate,
+  start_date = EXCLUDED.start_date,
+  enabled = EXCLUDED.enabled,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteEquityGrant :exec
+DELETE FROM equity_grant
+WHERE id = ?;
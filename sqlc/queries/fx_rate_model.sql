@@ -0,0 +1,33 @@
+-- name: ListFXRateModels :many
+SELECT *
+FROM fx_rate_model
+ORDER BY currency;
+
+-- name: UpsertFXRateModel :one
+INSERT INTO fx_rate_model (
+    currency,
+    initial_rate,
+    annual_drift,
+    annual_volatility,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (currency) DO
+UPDATE
+SET initial_rate = EXCLUDED.initial_rate,
+  annual_drift = EXCLUDED.annual_drift,
+  annual_volatility = EXCLUDED.annual_volatility,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteFXRateModel :exec
+DELETE FROM fx_rate_model
+WHERE currency = ?;
+
+-- name: ListAccountCurrencies :many
+SELECT DISTINCT currency
+FROM account;
+
+-- name: ListTransferTemplateCurrencies :many
+SELECT DISTINCT currency
+FROM transfer_template;
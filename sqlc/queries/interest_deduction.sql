@@ -0,0 +1,30 @@
+-- name: ListInterestDeductions :many
+SELECT *
+FROM interest_deduction
+ORDER BY account_id;
+
+-- name: GetInterestDeduction :one
+SELECT *
+FROM interest_deduction
+WHERE account_id = ?;
+
+-- name: UpsertInterestDeduction :one
+INSERT INTO interest_deduction (
+    account_id,
+    to_account_id,
+    settlement_date,
+    borrowers,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (account_id) DO
+UPDATE
+SET to_account_id = EXCLUDED.to_account_id,
+  settlement_date = EXCLUDED.settlement_date,
+  borrowers = EXCLUDED.borrowers,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteInterestDeduction :exec
+DELETE FROM interest_deduction
+WHERE account_id = ?;
@@ -0,0 +1,36 @@
+-- name: ListInterestRateModels :many
+SELECT *
+FROM interest_rate_model
+ORDER BY name, id;
+
+-- name: GetInterestRateModel :one
+SELECT *
+FROM interest_rate_model
+WHERE id = ?;
+
+-- name: UpsertInterestRateModel :one
+INSERT INTO interest_rate_model (
+    id,
+    name,
+    model_type,
+    initial_rate,
+    long_term_rate,
+    reversion_speed,
+    volatility,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET name = EXCLUDED.name,
+  model_type = EXCLUDED.model_type,
+  initial_rate = EXCLUDED.initial_rate,
+  long_term_rate = EXCLUDED.long_term_rate,
+  reversion_speed = EXCLUDED.reversion_speed,
+  volatility = EXCLUDED.volatility,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteInterestRateModel :exec
+DELETE FROM interest_rate_model
+WHERE id = ?;
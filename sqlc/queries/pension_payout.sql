@@ -0,0 +1,40 @@
+-- name: ListPensionPayouts :many
+SELECT *
+FROM pension_payout
+ORDER BY account_id;
+
+-- name: GetPensionPayout :one
+SELECT *
+FROM pension_payout
+WHERE account_id = ?;
+
+-- name: UpsertPensionPayout :one
+INSERT INTO pension_payout (
+    account_id,
+    to_account_id,
+    start_date,
+    birth_date,
+    start_age,
+    period_years,
+    kommun,
+    forsamling,
+    church_member,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_id) DO
+UPDATE
+SET to_account_id = EXCLUDED.to_account_id,
+  start_date = EXCLUDED.start_date,
+  birth_date = EXCLUDED.birth_date,
+  start_age = EXCLUDED.start_age,
+  period_years = EXCLUDED.period_years,
+  kommun = EXCLUDED.kommun,
+  forsamling = EXCLUDED.forsamling,
+  church_member = EXCLUDED.church_member,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeletePensionPayout :exec
+DELETE FROM pension_payout
+WHERE account_id = ?;
@@ -0,0 +1,36 @@
+-- name: ListPersons :many
+SELECT *
+FROM user
+ORDER BY birth_date,
+  name,
+  id;
+-- name: GetPerson :one
+SELECT *
+FROM user
+WHERE id = ?;
+-- name: UpsertPerson :one
+INSERT INTO user (
+    id,
+    name,
+    birth_date,
+    is_child,
+    tax_account_id,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET name = EXCLUDED.name,
+  birth_date = EXCLUDED.birth_date,
+  is_child = EXCLUDED.is_child,
+  tax_account_id = EXCLUDED.tax_account_id,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+-- name: ClearAccountOwner :exec
+-- account.owner_id cascades on delete, so owned accounts are released first.
+UPDATE account
+SET owner_id = NULL
+WHERE owner_id = ?;
+-- name: DeletePerson :exec
+DELETE FROM user
+WHERE id = ?;
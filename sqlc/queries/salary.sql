@@ -22,10 +22,20 @@ INSERT INTO salary (
     forsamling,
     church_member,
     is_gross,
+    tax_method,
+    municipal_tax_rate,
+    owner_id,
+    parental_pay_rate,
+    parental_pay_above_ceiling_rate,
+    parental_pay_max_days,
+    salary_exchange_amount,
+    salary_exchange_uplift,
+    secondary_employer,
+    secondary_tax_rate,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET name = EXCLUDED.name,
   to_account_id = EXCLUDED.to_account_id,
@@ -38,6 +48,16 @@ SET name = EXCLUDED.name,
   forsamling = EXCLUDED.forsamling,
   church_member = EXCLUDED.church_member,
   is_gross = EXCLUDED.is_gross,
+  tax_method = EXCLUDED.tax_method,
+  municipal_tax_rate = EXCLUDED.municipal_tax_rate,
+  owner_id = EXCLUDED.owner_id,
+  parental_pay_rate = EXCLUDED.parental_pay_rate,
+  parental_pay_above_ceiling_rate = EXCLUDED.parental_pay_above_ceiling_rate,
+  parental_pay_max_days = EXCLUDED.parental_pay_max_days,
+  salary_exchange_amount = EXCLUDED.salary_exchange_amount,
+  salary_exchange_uplift = EXCLUDED.salary_exchange_uplift,
+  secondary_employer = EXCLUDED.secondary_employer,
+  secondary_tax_rate = EXCLUDED.secondary_tax_rate,
   updated_at = EXCLUDED.updated_at
 RETURNING *;
 
@@ -0,0 +1,37 @@
+-- name: ListSalaryBonuses :many
+SELECT *
+FROM salary_bonus
+WHERE salary_id = ?
+ORDER BY pay_date, id;
+
+-- name: UpsertSalaryBonus :one
+INSERT INTO salary_bonus (
+    id,
+    salary_id,
+    name,
+    amount,
+    probability,
+    pay_date,
+    yearly,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET salary_id = EXCLUDED.salary_id,
+  name = EXCLUDED.name,
+  amount = EXCLUDED.amount,
+  probability = EXCLUDED.probability,
+  pay_date = EXCLUDED.pay_date,
+  yearly = EXCLUDED.yearly,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteSalaryBonus :exec
+DELETE FROM salary_bonus
+WHERE id = ?;
+
+-- name: ListAllSalaryBonuses :many
+SELECT *
+FROM salary_bonus
+ORDER BY salary_id, pay_date, id;
@@ -0,0 +1,37 @@
+-- name: ListSickLeaves :many
+SELECT *
+FROM sick_leave
+WHERE salary_id = ?
+ORDER BY start_date, id;
+
+-- name: UpsertSickLeave :one
+INSERT INTO sick_leave (
+    id,
+    salary_id,
+    start_date,
+    end_date,
+    extent,
+    employer_top_up_rate,
+    employer_top_up_days,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET salary_id = EXCLUDED.salary_id,
+  start_date = EXCLUDED.start_date,
+  end_date = EXCLUDED.end_date,
+  extent = EXCLUDED.extent,
+  employer_top_up_rate = EXCLUDED.employer_top_up_rate,
+  employer_top_up_days = EXCLUDED.employer_top_up_days,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteSickLeave :exec
+DELETE FROM sick_leave
+WHERE id = ?;
+
+-- name: ListAllSickLeaves :many
+SELECT *
+FROM sick_leave
+ORDER BY salary_id, start_date, id;
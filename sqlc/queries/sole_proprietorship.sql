@@ -0,0 +1,44 @@
+-- name: ListSoleProprietorships :many
+SELECT *
+FROM sole_proprietorship
+ORDER BY name, id;
+
+-- name: GetSoleProprietorship :one
+SELECT *
+FROM sole_proprietorship
+WHERE id = ?;
+
+-- name: UpsertSoleProprietorship :one
+INSERT INTO sole_proprietorship (
+    id,
+    name,
+    owner_id,
+    to_account_id,
+    profit,
+    recurrence,
+    periodiseringsfond_share,
+    expansionsfond,
+    municipal_tax_rate,
+    start_date,
+    enabled,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET name = EXCLUDED.name,
+  owner_id = EXCLUDED.owner_id,
+  to_account_id = EXCLUDED.to_account_id,
+  profit = EXCLUDED.profit,
+  recurrence = EXCLUDED.recurrence,
+  periodiseringsfond_share = EXCLUDED.periodiseringsfond_share,
+  expansionsfond = EXCLUDED.expansionsfond,
+  municipal_tax_rate = EXCLUDED.municipal_tax_rate,
+  start_date = EXCLUDED.start_date,
+  enabled = EXCLUDED.enabled,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteSoleProprietorship :exec
+DELETE FROM sole_proprietorship
+WHERE id = ?;
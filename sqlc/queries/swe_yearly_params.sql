@@ -15,16 +15,24 @@ INSERT INTO swe_yearly_params (
     prisbasbelopp,
     schablon_ranta,
     isk_fribelopp,
+    state_tax_threshold,
+    public_service_fee_max,
+    csn_ranta,
+    statslaneranta,
     valid_from,
     created_at,
     updated_at
   )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
 UPDATE
 SET amount = EXCLUDED.amount,
   prisbasbelopp = EXCLUDED.prisbasbelopp,
   schablon_ranta = EXCLUDED.schablon_ranta,
   isk_fribelopp = EXCLUDED.isk_fribelopp,
+  state_tax_threshold = EXCLUDED.state_tax_threshold,
+  public_service_fee_max = EXCLUDED.public_service_fee_max,
+  csn_ranta = EXCLUDED.csn_ranta,
+  statslaneranta = EXCLUDED.statslaneranta,
   valid_from = EXCLUDED.valid_from,
   updated_at = EXCLUDED.updated_at
 RETURNING *;
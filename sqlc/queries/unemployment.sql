@@ -0,0 +1,37 @@
+-- name: ListUnemployments :many
+SELECT *
+FROM unemployment
+WHERE salary_id = ?
+ORDER BY start_date, id;
+
+-- name: UpsertUnemployment :one
+INSERT INTO unemployment (
+    id,
+    salary_id,
+    start_date,
+    end_date,
+    akassa_member,
+    income_insurance_ceiling,
+    income_insurance_days,
+    created_at,
+    updated_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO
+UPDATE
+SET salary_id = EXCLUDED.salary_id,
+  start_date = EXCLUDED.start_date,
+  end_date = EXCLUDED.end_date,
+  akassa_member = EXCLUDED.akassa_member,
+  income_insurance_ceiling = EXCLUDED.income_insurance_ceiling,
+  income_insurance_days = EXCLUDED.income_insurance_days,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteUnemployment :exec
+DELETE FROM unemployment
+WHERE id = ?;
+
+-- name: ListAllUnemployments :many
+SELECT *
+FROM unemployment
+ORDER BY salary_id, start_date, id;
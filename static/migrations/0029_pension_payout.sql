@@ -0,0 +1,16 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS pension_payout (
+    account_id    TEXT    NOT NULL PRIMARY KEY,
+    to_account_id TEXT,
+    start_date    INTEGER NOT NULL DEFAULT 0,
+    birth_date    INTEGER,
+    start_age     INTEGER,
+    period_years  INTEGER NOT NULL DEFAULT 0,
+    kommun        TEXT    NOT NULL DEFAULT '',
+    forsamling    TEXT    NOT NULL DEFAULT '',
+    church_member BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at    INTEGER NOT NULL,
+    updated_at    INTEGER NOT NULL,
+    FOREIGN KEY (account_id)    REFERENCES account(id) ON DELETE CASCADE,
+    FOREIGN KEY (to_account_id) REFERENCES account(id) ON DELETE SET NULL
+);
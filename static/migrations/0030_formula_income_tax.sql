@@ -0,0 +1,5 @@
+-- migrate:up
+ALTER TABLE swe_yearly_params ADD COLUMN state_tax_threshold REAL NOT NULL DEFAULT 625800;
+ALTER TABLE swe_yearly_params ADD COLUMN public_service_fee_max REAL NOT NULL DEFAULT 1249;
+ALTER TABLE salary ADD COLUMN tax_method TEXT NOT NULL DEFAULT 'table';
+ALTER TABLE salary ADD COLUMN municipal_tax_rate REAL NOT NULL DEFAULT 0;
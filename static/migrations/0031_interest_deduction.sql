@@ -0,0 +1,11 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS interest_deduction (
+    account_id      TEXT    NOT NULL PRIMARY KEY,
+    to_account_id   TEXT,
+    settlement_date TEXT    NOT NULL DEFAULT '*-04-07',
+    borrowers       INTEGER NOT NULL DEFAULT 1,
+    created_at      INTEGER NOT NULL,
+    updated_at      INTEGER NOT NULL,
+    FOREIGN KEY (account_id)    REFERENCES account(id) ON DELETE CASCADE,
+    FOREIGN KEY (to_account_id) REFERENCES account(id) ON DELETE SET NULL
+);
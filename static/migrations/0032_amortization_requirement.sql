@@ -0,0 +1,14 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS amortization_requirement (
+    account_id          TEXT    NOT NULL PRIMARY KEY,
+    property_account_id TEXT,
+    from_account_id     TEXT,
+    recurrence          TEXT    NOT NULL DEFAULT '*-*-25',
+    valuation_date      INTEGER NOT NULL DEFAULT 0,
+    revaluation_years   INTEGER NOT NULL DEFAULT 5,
+    created_at          INTEGER NOT NULL,
+    updated_at          INTEGER NOT NULL,
+    FOREIGN KEY (account_id)          REFERENCES account(id) ON DELETE CASCADE,
+    FOREIGN KEY (property_account_id) REFERENCES account(id) ON DELETE SET NULL,
+    FOREIGN KEY (from_account_id)     REFERENCES account(id) ON DELETE SET NULL
+);
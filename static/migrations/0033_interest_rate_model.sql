@@ -0,0 +1,14 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS interest_rate_model (
+    id              TEXT    NOT NULL PRIMARY KEY,
+    name            TEXT    NOT NULL,
+    model_type      TEXT    NOT NULL DEFAULT 'vasicek',
+    initial_rate    REAL    NOT NULL,
+    long_term_rate  REAL    NOT NULL,
+    reversion_speed REAL    NOT NULL,
+    volatility      REAL    NOT NULL,
+    created_at      INTEGER NOT NULL,
+    updated_at      INTEGER NOT NULL
+);
+ALTER TABLE growth_model ADD COLUMN rate_model_id TEXT REFERENCES interest_rate_model(id) ON DELETE SET NULL;
+ALTER TABLE growth_model ADD COLUMN binding_years INTEGER NOT NULL DEFAULT 0;
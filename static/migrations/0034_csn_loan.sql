@@ -0,0 +1,16 @@
+-- migrate:up
+ALTER TABLE swe_yearly_params ADD COLUMN csn_ranta REAL NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS csn_loan (
+    account_id      TEXT    NOT NULL PRIMARY KEY,
+    from_account_id TEXT,
+    salary_id       TEXT,
+    birth_date      INTEGER NOT NULL DEFAULT 0,
+    final_year      INTEGER NOT NULL DEFAULT 0,
+    recurrence      TEXT    NOT NULL DEFAULT '*-*-27',
+    reduction       INTEGER NOT NULL DEFAULT 0,
+    created_at      INTEGER NOT NULL,
+    updated_at      INTEGER NOT NULL,
+    FOREIGN KEY (account_id)      REFERENCES account(id) ON DELETE CASCADE,
+    FOREIGN KEY (from_account_id) REFERENCES account(id) ON DELETE SET NULL,
+    FOREIGN KEY (salary_id)       REFERENCES salary(id) ON DELETE SET NULL
+);
@@ -0,0 +1,9 @@
+-- migrate:up
+ALTER TABLE user ADD COLUMN birth_date INTEGER;
+ALTER TABLE salary ADD COLUMN owner_id TEXT REFERENCES user(id) ON DELETE SET NULL;
+ALTER TABLE special_date ADD COLUMN person_id TEXT REFERENCES user(id) ON DELETE SET NULL;
+ALTER TABLE special_date ADD COLUMN person_age INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transfer_template ADD COLUMN start_person_id TEXT REFERENCES user(id) ON DELETE SET NULL;
+ALTER TABLE transfer_template ADD COLUMN start_age INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transfer_template ADD COLUMN end_person_id TEXT REFERENCES user(id) ON DELETE SET NULL;
+ALTER TABLE transfer_template ADD COLUMN end_age INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS account_owner (
+    account_id TEXT NOT NULL,
+    person_id  TEXT NOT NULL,
+    share      REAL NOT NULL,
+    PRIMARY KEY (account_id, person_id),
+    FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES user (id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- migrate:up
+ALTER TABLE user ADD COLUMN is_child BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,13 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS unemployment (
+    id                       TEXT    NOT NULL PRIMARY KEY,
+    salary_id                TEXT    NOT NULL,
+    start_date               INTEGER NOT NULL,
+    end_date                 INTEGER NOT NULL,
+    akassa_member            BOOLEAN NOT NULL DEFAULT 1,
+    income_insurance_ceiling REAL    NOT NULL DEFAULT 0,
+    income_insurance_days    INTEGER NOT NULL DEFAULT 0,
+    created_at               INTEGER NOT NULL,
+    updated_at               INTEGER NOT NULL,
+    FOREIGN KEY (salary_id) REFERENCES salary(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+-- migrate:up
+ALTER TABLE salary ADD COLUMN parental_pay_rate REAL NOT NULL DEFAULT 0;
+ALTER TABLE salary ADD COLUMN parental_pay_above_ceiling_rate REAL NOT NULL DEFAULT 0;
+ALTER TABLE salary ADD COLUMN parental_pay_max_days INTEGER NOT NULL DEFAULT 0;
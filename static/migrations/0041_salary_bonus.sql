@@ -0,0 +1,13 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS salary_bonus (
+    id          TEXT    NOT NULL PRIMARY KEY,
+    salary_id   TEXT    NOT NULL,
+    name        TEXT    NOT NULL DEFAULT '',
+    amount      TEXT    NOT NULL,
+    probability REAL    NOT NULL DEFAULT 1,
+    pay_date    INTEGER NOT NULL,
+    yearly      BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at  INTEGER NOT NULL,
+    updated_at  INTEGER NOT NULL,
+    FOREIGN KEY (salary_id) REFERENCES salary(id) ON DELETE CASCADE
+);
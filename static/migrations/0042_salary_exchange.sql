@@ -0,0 +1,3 @@
+-- migrate:up
+ALTER TABLE salary ADD COLUMN salary_exchange_amount REAL NOT NULL DEFAULT 0;
+ALTER TABLE salary ADD COLUMN salary_exchange_uplift REAL NOT NULL DEFAULT 0.058;
@@ -0,0 +1,3 @@
+-- migrate:up
+ALTER TABLE salary ADD COLUMN secondary_employer BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE salary ADD COLUMN secondary_tax_rate REAL NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- migrate:up
+ALTER TABLE user ADD COLUMN tax_account_id TEXT REFERENCES account(id) ON DELETE SET NULL;
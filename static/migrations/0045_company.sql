@@ -0,0 +1,24 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS company (
+    id                   TEXT    NOT NULL PRIMARY KEY,
+    name                 TEXT    NOT NULL,
+    owner_id             TEXT,
+    ownership_share      REAL    NOT NULL DEFAULT 1,
+    account_id           TEXT    NOT NULL,
+    dividend_account_id  TEXT,
+    salary_id            TEXT,
+    revenue              TEXT    NOT NULL,
+    expenses             TEXT    NOT NULL,
+    gransbelopp_rule     TEXT    NOT NULL DEFAULT 'simplified',
+    acquisition_cost     REAL    NOT NULL DEFAULT 25000,
+    capital_rate         REAL    NOT NULL DEFAULT 0.1162,
+    saved_dividend_space REAL    NOT NULL DEFAULT 0,
+    dividend_date        TEXT    NOT NULL DEFAULT '*-06-30',
+    start_date           INTEGER NOT NULL,
+    created_at           INTEGER NOT NULL,
+    updated_at           INTEGER NOT NULL,
+    FOREIGN KEY (owner_id)            REFERENCES user(id)    ON DELETE SET NULL,
+    FOREIGN KEY (account_id)          REFERENCES account(id) ON DELETE CASCADE,
+    FOREIGN KEY (dividend_account_id) REFERENCES account(id) ON DELETE SET NULL,
+    FOREIGN KEY (salary_id)           REFERENCES salary(id)  ON DELETE SET NULL
+);
@@ -0,0 +1,18 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS sole_proprietorship (
+    id                       TEXT    NOT NULL PRIMARY KEY,
+    name                     TEXT    NOT NULL,
+    owner_id                 TEXT,
+    to_account_id            TEXT    NOT NULL,
+    profit                   TEXT    NOT NULL,
+    recurrence               TEXT    NOT NULL DEFAULT '*-*-25',
+    periodiseringsfond_share REAL    NOT NULL DEFAULT 0,
+    expansionsfond           REAL    NOT NULL DEFAULT 0,
+    municipal_tax_rate       REAL    NOT NULL DEFAULT 0,
+    start_date               INTEGER NOT NULL,
+    enabled                  BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at               INTEGER NOT NULL,
+    updated_at               INTEGER NOT NULL,
+    FOREIGN KEY (owner_id)      REFERENCES user(id)    ON DELETE SET NULL,
+    FOREIGN KEY (to_account_id) REFERENCES account(id) ON DELETE CASCADE
+);
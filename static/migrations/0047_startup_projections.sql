@@ -0,0 +1,27 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS projected_investment_round (
+    id TEXT NOT NULL PRIMARY KEY,
+    account_id TEXT NOT NULL,
+    date INTEGER NOT NULL,
+    probability REAL NOT NULL DEFAULT 1,
+    pre_money_valuation TEXT NOT NULL,
+    investment TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_projected_investment_round_account_date ON projected_investment_round (account_id, date);
+
+CREATE TABLE IF NOT EXISTS startup_exit (
+    account_id TEXT NOT NULL PRIMARY KEY,
+    date INTEGER NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'acquisition',
+    multiple TEXT NOT NULL,
+    failure_probability REAL NOT NULL DEFAULT 0,
+    payout_account_id TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE,
+    FOREIGN KEY (payout_account_id) REFERENCES account (id) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+-- migrate:up
+ALTER TABLE startup_share_option ADD COLUMN cliff_months INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE startup_share_option ADD COLUMN vesting_months INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE startup_share_option ADD COLUMN vesting_interval_months INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE startup_share_option ADD COLUMN accelerate_on_exit BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE startup_share_option ADD COLUMN exercise_policy TEXT NOT NULL DEFAULT 'in_the_money';
@@ -0,0 +1,4 @@
+-- migrate:up
+ALTER TABLE investment_round ADD COLUMN liquidation_preference REAL NOT NULL DEFAULT 0;
+ALTER TABLE investment_round ADD COLUMN participating BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE investment_round ADD COLUMN seniority INTEGER NOT NULL DEFAULT 0;
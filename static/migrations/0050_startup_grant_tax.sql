@@ -0,0 +1,3 @@
+-- migrate:up
+ALTER TABLE startup_share_account ADD COLUMN income_tax_rate REAL NOT NULL DEFAULT 0.5;
+ALTER TABLE startup_share_option ADD COLUMN tax_treatment TEXT NOT NULL DEFAULT 'qualified';
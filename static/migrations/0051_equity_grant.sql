@@ -0,0 +1,25 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS equity_grant (
+    id                      TEXT    NOT NULL PRIMARY KEY,
+    name                    TEXT    NOT NULL,
+    owner_id                TEXT,
+    kind                    TEXT    NOT NULL DEFAULT 'rsu',
+    holding_account_id      TEXT    NOT NULL,
+    salary_account_id       TEXT,
+    share_price             REAL    NOT NULL,
+    shares                  REAL    NOT NULL DEFAULT 0,
+    cliff_months            INTEGER NOT NULL DEFAULT 0,
+    vesting_months          INTEGER NOT NULL DEFAULT 0,
+    vesting_interval_months INTEGER NOT NULL DEFAULT 3,
+    contribution            TEXT    NOT NULL DEFAULT 'fixed(0)',
+    discount                REAL    NOT NULL DEFAULT 0,
+    recurrence              TEXT    NOT NULL DEFAULT '*-*-25',
+    municipal_tax_rate      REAL    NOT NULL DEFAULT 0,
+    start_date              INTEGER NOT NULL,
+    enabled                 BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at              INTEGER NOT NULL,
+    updated_at              INTEGER NOT NULL,
+    FOREIGN KEY (owner_id)           REFERENCES user(id)    ON DELETE SET NULL,
+    FOREIGN KEY (holding_account_id) REFERENCES account(id) ON DELETE CASCADE,
+    FOREIGN KEY (salary_account_id)  REFERENCES account(id) ON DELETE SET NULL
+);
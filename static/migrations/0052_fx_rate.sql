@@ -0,0 +1,11 @@
+-- migrate:up
+ALTER TABLE account ADD COLUMN currency TEXT NOT NULL DEFAULT '';
+ALTER TABLE transfer_template ADD COLUMN currency TEXT NOT NULL DEFAULT '';
+CREATE TABLE IF NOT EXISTS fx_rate_model (
+    currency          TEXT    NOT NULL PRIMARY KEY,
+    initial_rate      REAL    NOT NULL DEFAULT 0,
+    annual_drift      REAL    NOT NULL DEFAULT 0,
+    annual_volatility REAL    NOT NULL DEFAULT 0,
+    created_at        INTEGER NOT NULL,
+    updated_at        INTEGER NOT NULL
+);
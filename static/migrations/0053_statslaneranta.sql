@@ -0,0 +1,2 @@
+-- migrate:up
+ALTER TABLE swe_yearly_params ADD COLUMN statslaneranta REAL NOT NULL DEFAULT 0;